@@ -15,7 +15,11 @@ import (
 
 func main() {
 	// 解析命令行参数
-	var task = flag.String("task", "", "要执行的任务名称 (例如: scan)")
+	var task = flag.String("task", "", "要执行的任务名称 (例如: scan, enrich, trending, alert, rollup, flush, report, export, import, reindex, subscription, noise, author)")
+	var file = flag.String("file", "", "import 任务要导入的本地文件，不指定时执行等待中的导入任务")
+	var format = flag.String("format", "", "import 任务的文件格式 (csv, xlsx, ndjson)，默认按扩展名判断")
+	var source = flag.String("source", "", "import 任务的默认来源，文件中没有来源列时使用")
+	var mapping = flag.String("mapping", "", "import 任务的列映射，JSON 对象，如 {\"正文\":\"content\"}")
 	flag.Parse()
 
 	if *task == "" {
@@ -54,6 +58,42 @@ func main() {
 	case "scan":
 		logger.Get().Info("执行舆情扫描任务")
 		job.ScanOpinionJob()
+	case "enrich":
+		logger.Get().Info("执行舆情富化重算任务")
+		job.EnrichOpinionJob()
+	case "trending":
+		logger.Get().Info("执行热词和话题计算任务")
+		job.TrendingJob()
+	case "alert":
+		logger.Get().Info("执行告警评估任务")
+		job.AlertJob()
+	case "rollup":
+		logger.Get().Info("执行统计汇总任务")
+		job.StatsRollupJob()
+	case "flush":
+		logger.Get().Info("执行实时计数落库任务")
+		job.LiveFlushJob()
+	case "report":
+		logger.Get().Info("执行场景简报任务")
+		job.ReportJob()
+	case "export":
+		logger.Get().Info("执行舆情导出任务")
+		job.ExportJob()
+	case "import":
+		logger.Get().Info("执行舆情导入任务")
+		job.ImportJob(*file, *format, *source, *mapping)
+	case "reindex":
+		logger.Get().Info("执行检索索引重建任务")
+		job.ReindexJob()
+	case "subscription":
+		logger.Get().Info("执行检索订阅推送任务")
+		job.SubscriptionJob()
+	case "noise":
+		logger.Get().Info("执行噪音过滤器训练任务")
+		job.NoiseTrainJob()
+	case "author":
+		logger.Get().Info("执行作者影响力计算任务")
+		job.AuthorInfluenceJob()
 	default:
 		logger.Get().Error("未知的任务", zap.String("task", *task))
 		os.Exit(1)
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"sentinel-opinion-monitor/internal/analysis/modelstub"
+)
+
+// 本地联调用的外部 NLP 服务替身，配置 analysis.analyzer=http 后即可对接
+func main() {
+	addr := flag.String("addr", ":9100", "监听地址")
+	token := flag.String("token", "", "校验的 Bearer Token，为空不校验")
+	flag.Parse()
+
+	handler := modelstub.NewHandler(modelstub.Options{
+		Token: *token,
+		Topics: map[string]string{
+			"退款": "售后",
+			"客服": "服务",
+			"物流": "物流",
+			"价格": "价格",
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/analyze", handler)
+
+	log.Printf("model stub listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
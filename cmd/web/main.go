@@ -5,7 +5,9 @@ import (
 	"sentinel-opinion-monitor/internal/pkg/logger"
 	"sentinel-opinion-monitor/internal/pkg/mysql"
 	"sentinel-opinion-monitor/internal/pkg/redis"
+	"sentinel-opinion-monitor/internal/realtime"
 	"sentinel-opinion-monitor/internal/router"
+	"sentinel-opinion-monitor/internal/search"
 	"sentinel-opinion-monitor/internal/server"
 
 	"go.uber.org/zap"
@@ -38,13 +40,23 @@ func main() {
 	}
 	defer redis.Close()
 
-	// 5. 注册路由
+	// 5. 初始化实时推送（通过 Redis Pub/Sub 在多个实例间广播）
+	hub := realtime.Init()
+
+	// 6. 打开内嵌检索索引（search.backend 为 embedded 时），打开失败时使用 MySQL 检索
+	if err := search.Init(&cfg.Search); err != nil {
+		logger.Get().Error("打开内嵌检索索引失败，舆情检索使用 MySQL", zap.Error(err))
+	}
+	defer search.Close()
+
+	// 7. 注册路由
 	r := router.SetupRouter()
 
-	// 6. 启动 Gin Server
+	// 8. 启动 Gin Server
 	srv := server.NewServer(cfg, r)
+	srv.RegisterOnShutdown(hub.Close)
 
-	// 7. 优雅退出（graceful shutdown）
+	// 9. 优雅退出（graceful shutdown）
 	go func() {
 		if err := srv.Start(); err != nil {
 			logger.Get().Fatal("服务器启动失败", zap.Error(err))
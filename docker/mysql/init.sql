@@ -118,6 +118,12 @@ CREATE TABLE IF NOT EXISTS monitoring_groups (
     name VARCHAR(100) NOT NULL COMMENT '监测组名称',
     sort INT NOT NULL DEFAULT 0 COMMENT '排序',
     status TINYINT NOT NULL DEFAULT 1 COMMENT '状态：1-正常，2-禁用',
+    scan_interval INT NOT NULL DEFAULT 60 COMMENT '采集间隔(分钟)',
+    active_hours VARCHAR(20) NOT NULL DEFAULT '' COMMENT '生效时段,如09:00-18:00,为空表示全天',
+    active_weekdays VARCHAR(20) NOT NULL DEFAULT '' COMMENT '生效星期,如1,2,3,4,5(0为周日),为空表示每天',
+    start_date DATE NULL COMMENT '监测开始日期',
+    end_date DATE NULL COMMENT '监测结束日期',
+    last_scanned_at DATETIME NULL COMMENT '最近一次扫描时间',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     INDEX idx_scenario_id (scenario_id),
@@ -149,22 +155,526 @@ CREATE TABLE IF NOT EXISTS group_exclusion_words (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     group_id BIGINT UNSIGNED NOT NULL COMMENT '监测组ID',
     word VARCHAR(255) NOT NULL COMMENT '排除词',
+    filtered_count BIGINT NOT NULL DEFAULT 0 COMMENT '累计排除的舆情数',
+    last_filtered_at DATETIME NULL COMMENT '最近一次排除舆情的时间',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     INDEX idx_group_id (group_id),
     INDEX idx_word (word)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='监测组排除词表';
 
+-- 创建监测组来源过滤规则表
+CREATE TABLE IF NOT EXISTS group_source_filters (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    group_id BIGINT UNSIGNED NOT NULL COMMENT '监测组ID',
+    type VARCHAR(10) NOT NULL COMMENT '类型:author,domain,url',
+    mode VARCHAR(10) NOT NULL COMMENT '模式:block,allow',
+    author_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '作者ID,类型为author时有效',
+    value VARCHAR(512) NOT NULL DEFAULT '' COMMENT '域名或链接模式,类型为domain,url时有效',
+    note VARCHAR(255) NOT NULL DEFAULT '' COMMENT '备注',
+    created_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '添加人用户ID',
+    matched_count BIGINT NOT NULL DEFAULT 0 COMMENT '累计匹配的舆情数:屏蔽规则为排除数,允许规则为放行数',
+    last_matched_at DATETIME NULL COMMENT '最近一次匹配舆情的时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_group_id (group_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='监测组来源过滤规则表';
+
 -- 创建舆情表
 CREATE TABLE IF NOT EXISTS opinions (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     content TEXT NOT NULL COMMENT '舆情内容',
     source VARCHAR(255) NOT NULL COMMENT '来源',
+    author VARCHAR(100) NOT NULL DEFAULT '' COMMENT '作者（发布账号）',
+    author_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '作者ID,0表示没有作者信息',
+    url VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '原文链接',
+    content_hash CHAR(40) NOT NULL DEFAULT '' COMMENT '来源和内容的SHA1',
+    import_task_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '导入任务ID,0表示非导入',
+    like_count BIGINT NOT NULL DEFAULT 0 COMMENT '点赞数',
+    comment_count BIGINT NOT NULL DEFAULT 0 COMMENT '评论数',
+    share_count BIGINT NOT NULL DEFAULT 0 COMMENT '转发数',
+    sentiment_score DECIMAL(6,4) NOT NULL DEFAULT 0 COMMENT '情感得分(-1~1)',
+    sentiment_label VARCHAR(10) NOT NULL DEFAULT 'neutral' COMMENT '情感标签:positive,neutral,negative',
+    entities JSON NULL COMMENT '实体列表',
+    topics JSON NULL COMMENT '主题标签',
+    keywords JSON NULL COMMENT '提取的关键词及权重',
+    analyzer VARCHAR(50) NOT NULL DEFAULT '' COMMENT '产生分析结果的分析器',
+    handling_status VARCHAR(20) NOT NULL DEFAULT 'new' COMMENT '处置状态:new,in_progress,responded,ignored,escalated',
+    assignee_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '处理人用户ID,0表示未分配',
+    priority VARCHAR(10) NOT NULL DEFAULT 'medium' COMMENT '优先级:low,medium,high,urgent',
+    due_at DATETIME NULL COMMENT '处置截止时间(SLA)',
+    handled_at DATETIME NULL COMMENT '最近一次回应或忽略的时间',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     INDEX idx_source (source),
-    INDEX idx_created_at (created_at)
+    INDEX idx_author (author),
+    INDEX idx_author_id (author_id),
+    INDEX idx_content_hash (content_hash),
+    INDEX idx_import_task (import_task_id),
+    INDEX idx_sentiment_label (sentiment_label),
+    INDEX idx_handling_status (handling_status),
+    INDEX idx_assignee (assignee_id, handling_status),
+    INDEX idx_created_at (created_at),
+    INDEX idx_updated_at (updated_at),
+    FULLTEXT INDEX ft_content (content) WITH PARSER ngram
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情表';
 
+-- 创建作者表
+CREATE TABLE IF NOT EXISTS authors (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    channel VARCHAR(255) NOT NULL COMMENT '渠道（舆情来源）',
+    external_id VARCHAR(100) NOT NULL COMMENT '渠道内的账号ID,没有时为账号名称',
+    name VARCHAR(100) NOT NULL DEFAULT '' COMMENT '账号名称',
+    follower_count BIGINT NOT NULL DEFAULT 0 COMMENT '粉丝数',
+    verified TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否认证账号',
+    opinion_count BIGINT NOT NULL DEFAULT 0 COMMENT '入库的舆情数',
+    avg_engagement DECIMAL(12,2) NOT NULL DEFAULT 0 COMMENT '最近舆情的平均互动量',
+    influence_score DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT '影响力得分(0~100)',
+    first_seen_at DATETIME NOT NULL COMMENT '首次出现时间',
+    last_seen_at DATETIME NOT NULL COMMENT '最近一次出现时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_channel_external (channel, external_id),
+    INDEX idx_name (name),
+    INDEX idx_influence_score (influence_score),
+    INDEX idx_last_seen_at (last_seen_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作者表';
+
+-- 创建场景关注作者表
+CREATE TABLE IF NOT EXISTS scenario_author_watches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    author_id BIGINT UNSIGNED NOT NULL COMMENT '作者ID',
+    note VARCHAR(255) NOT NULL DEFAULT '' COMMENT '备注',
+    created_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '添加人用户ID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY uk_scenario_author (scenario_id, author_id),
+    INDEX idx_author_id (author_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景关注作者表';
+
+-- 创建舆情内部评论表
+CREATE TABLE IF NOT EXISTS opinion_comments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    opinion_id BIGINT UNSIGNED NOT NULL COMMENT '舆情ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '评论用户ID',
+    content TEXT NOT NULL COMMENT '评论内容',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_opinion_id (opinion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情内部评论表';
+
+-- 创建舆情处置记录表
+CREATE TABLE IF NOT EXISTS opinion_handling_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    opinion_id BIGINT UNSIGNED NOT NULL COMMENT '舆情ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '操作用户ID',
+    action VARCHAR(20) NOT NULL COMMENT '动作:transition,assign,triage',
+    from_status VARCHAR(20) NOT NULL DEFAULT '' COMMENT '原状态',
+    to_status VARCHAR(20) NOT NULL DEFAULT '' COMMENT '新状态',
+    assignee_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '操作后的处理人用户ID',
+    note VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '备注',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_opinion_id (opinion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情处置记录表';
+
+-- 创建舆情命中记录表
+CREATE TABLE IF NOT EXISTS opinion_hits (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    opinion_id BIGINT UNSIGNED NOT NULL COMMENT '舆情ID',
+    group_id BIGINT UNSIGNED NOT NULL COMMENT '监测组ID',
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    keyword VARCHAR(255) COMMENT '命中的关键词',
+    watched TINYINT(1) NOT NULL DEFAULT 0 COMMENT '作者是否为场景关注的作者（关注作者的舆情不要求命中关键词）',
+    sentiment_score DECIMAL(6,4) NOT NULL DEFAULT 0 COMMENT '情感得分(-1~1)',
+    sentiment_label VARCHAR(10) NOT NULL DEFAULT 'neutral' COMMENT '情感标签:positive,neutral,negative',
+    noise_score DECIMAL(6,4) NOT NULL DEFAULT 0 COMMENT '噪音概率(0~1)',
+    suppressed TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否被噪音过滤器屏蔽',
+    relevance VARCHAR(20) NOT NULL DEFAULT '' COMMENT '相关性标注:relevant,irrelevant,为空表示未标注',
+    relevance_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '标注用户ID',
+    relevance_at DATETIME NULL COMMENT '标注时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY uk_opinion_group (opinion_id, group_id),
+    INDEX idx_group_keyword (group_id, keyword),
+    INDEX idx_group_relevance (group_id, relevance, relevance_at),
+    INDEX idx_group_suppressed (group_id, suppressed),
+    INDEX idx_scenario_sentiment (scenario_id, sentiment_label),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情命中记录表';
+
+-- 创建监测组噪音过滤器表
+CREATE TABLE IF NOT EXISTS noise_filters (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    group_id BIGINT UNSIGNED NOT NULL COMMENT '监测组ID',
+    enabled TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否自动屏蔽疑似噪音的命中',
+    threshold DECIMAL(4,3) NOT NULL DEFAULT 0 COMMENT '屏蔽阈值(0~1),0表示使用全局配置',
+    relevant_docs INT NOT NULL DEFAULT 0 COMMENT '训练使用的相关样本数',
+    noise_docs INT NOT NULL DEFAULT 0 COMMENT '训练使用的噪音样本数',
+    terms INT NOT NULL DEFAULT 0 COMMENT '词表大小',
+    model MEDIUMTEXT COMMENT '模型(JSON)',
+    trained_at DATETIME NULL COMMENT '最近一次训练时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_group_id (group_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='监测组噪音过滤器表';
+
+-- 创建自定义情感词典表
+CREATE TABLE IF NOT EXISTS sentiment_lexicons (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '场景ID,0表示全局',
+    word VARCHAR(100) NOT NULL COMMENT '词语',
+    type VARCHAR(20) NOT NULL DEFAULT 'sentiment' COMMENT '类型:sentiment-情感词,negation-否定词,degree-程度副词',
+    weight DECIMAL(6,2) NOT NULL DEFAULT 0 COMMENT '权重:情感词正负表示极性,程度副词为倍数',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_scenario_word (scenario_id, word)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='自定义情感词典表';
+
+-- 创建场景热词表（每次计算替换场景的快照）
+CREATE TABLE IF NOT EXISTS trending_terms (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    term VARCHAR(100) NOT NULL COMMENT '词语',
+    count INT NOT NULL DEFAULT 0 COMMENT '当前窗口出现该词的舆情数',
+    baseline_mean DECIMAL(10,4) NOT NULL DEFAULT 0 COMMENT '基线窗口平均值',
+    baseline_std DECIMAL(10,4) NOT NULL DEFAULT 0 COMMENT '基线窗口标准差',
+    score DECIMAL(10,4) NOT NULL DEFAULT 0 COMMENT '突发得分(z-score)',
+    window_start DATETIME NOT NULL COMMENT '当前窗口开始时间',
+    window_end DATETIME NOT NULL COMMENT '当前窗口结束时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_scenario_score (scenario_id, score)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景热词表';
+
+-- 创建场景话题表（每次计算替换场景的快照）
+CREATE TABLE IF NOT EXISTS opinion_topics (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    label VARCHAR(255) NOT NULL COMMENT '话题名称(中心词拼接)',
+    keywords JSON NULL COMMENT '中心词及权重',
+    size INT NOT NULL DEFAULT 0 COMMENT '话题包含的舆情数',
+    score DECIMAL(10,4) NOT NULL DEFAULT 0 COMMENT '热度得分',
+    avg_sentiment DECIMAL(6,4) NOT NULL DEFAULT 0 COMMENT '平均情感得分',
+    representative_ids JSON NULL COMMENT '代表性舆情ID',
+    window_start DATETIME NOT NULL COMMENT '聚类窗口开始时间',
+    window_end DATETIME NOT NULL COMMENT '聚类窗口结束时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_scenario_score (scenario_id, score)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景话题表';
+
+-- 创建场景统计小时汇总表
+CREATE TABLE IF NOT EXISTS scenario_stat_rollups (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    dimension VARCHAR(20) NOT NULL COMMENT '维度:total,channel,group,sentiment,author,keyword',
+    dim_key VARCHAR(255) NOT NULL DEFAULT '' COMMENT '维度取值',
+    bucket DATETIME NOT NULL COMMENT '小时桶开始时间',
+    count BIGINT NOT NULL DEFAULT 0 COMMENT '命中舆情数(同一舆情只计一次)',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_scenario_dim_bucket (scenario_id, dimension, dim_key, bucket),
+    INDEX idx_scenario_bucket (scenario_id, bucket)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='场景统计小时汇总表';
+
+-- 创建竞品对比组表
+CREATE TABLE IF NOT EXISTS comparison_sets (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '对比组名称',
+    description VARCHAR(255) DEFAULT '' COMMENT '描述',
+    scenario_ids JSON COMMENT '参与对比的场景ID(按展示顺序)',
+    `interval` VARCHAR(10) NOT NULL DEFAULT 'day' COMMENT '默认统计粒度:hour,day',
+    created_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人用户ID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_created_by (created_by)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='竞品对比组表';
+
+-- 创建监测组分钟计数表（Redis 实时计数落库）
+CREATE TABLE IF NOT EXISTS group_minute_stats (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    group_id BIGINT UNSIGNED NOT NULL COMMENT '监测组ID',
+    minute DATETIME NOT NULL COMMENT '分钟桶开始时间',
+    dimension VARCHAR(20) NOT NULL COMMENT '维度:total,channel,sentiment',
+    dim_key VARCHAR(255) NOT NULL DEFAULT '' COMMENT '维度取值',
+    count BIGINT NOT NULL DEFAULT 0 COMMENT '命中数',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_group_minute_dim (group_id, minute, dimension, dim_key),
+    INDEX idx_minute (minute)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='监测组分钟计数表';
+
+-- 创建简报模板表
+CREATE TABLE IF NOT EXISTS report_templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    name VARCHAR(100) NOT NULL COMMENT '模板名称',
+    frequency VARCHAR(10) NOT NULL COMMENT '周期:daily,weekly,monthly',
+    sections JSON COMMENT '包含的章节:summary,trend,channels,negative,topics',
+    formats JSON COMMENT '生成的文件格式:xlsx,pdf',
+    top_n INT NOT NULL DEFAULT 10 COMMENT '负面舆情、话题和关键词列出的数量',
+    emails JSON COMMENT '邮件收件人(附带简报文件)',
+    channel_ids JSON COMMENT '通知渠道ID(发送下载链接)',
+    status TINYINT DEFAULT 1 COMMENT '1-正常,2-禁用',
+    last_period_end DATETIME NULL COMMENT '最近一次自动生成的周期结束时间',
+    created_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人用户ID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_scenario_id (scenario_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='简报模板表';
+
+-- 创建简报表（文件保存在本地存储目录）
+CREATE TABLE IF NOT EXISTS reports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    template_id BIGINT UNSIGNED NOT NULL COMMENT '简报模板ID',
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    title VARCHAR(255) NOT NULL COMMENT '简报标题',
+    frequency VARCHAR(10) NOT NULL DEFAULT '' COMMENT '周期:daily,weekly,monthly,空表示自定义时间范围',
+    period_start DATETIME NOT NULL COMMENT '统计周期开始时间',
+    period_end DATETIME NOT NULL COMMENT '统计周期结束时间(不含)',
+    status VARCHAR(20) NOT NULL DEFAULT 'generating' COMMENT '状态:generating,success,failed',
+    error VARCHAR(1000) DEFAULT '' COMMENT '失败原因',
+    xlsx_file VARCHAR(255) DEFAULT '' COMMENT 'XLSX文件相对路径',
+    xlsx_size BIGINT NOT NULL DEFAULT 0 COMMENT 'XLSX文件大小(字节)',
+    pdf_file VARCHAR(255) DEFAULT '' COMMENT 'PDF文件相对路径',
+    pdf_size BIGINT NOT NULL DEFAULT 0 COMMENT 'PDF文件大小(字节)',
+    generated_at DATETIME NULL COMMENT '生成完成时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_template_id (template_id),
+    INDEX idx_scenario_created (scenario_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='简报表';
+
+-- 创建舆情导出任务表（文件保存在本地存储目录）
+CREATE TABLE IF NOT EXISTS export_tasks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '创建人用户ID',
+    format VARCHAR(10) NOT NULL COMMENT '文件格式:csv,xlsx,ndjson',
+    filter JSON COMMENT '筛选条件',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态:pending,running,success,failed,expired',
+    total BIGINT NOT NULL DEFAULT 0 COMMENT '创建时符合条件的舆情数',
+    processed BIGINT NOT NULL DEFAULT 0 COMMENT '已写入的行数',
+    file VARCHAR(255) DEFAULT '' COMMENT '文件相对路径',
+    file_size BIGINT NOT NULL DEFAULT 0 COMMENT '文件大小(字节)',
+    error VARCHAR(1000) DEFAULT '' COMMENT '失败原因',
+    started_at DATETIME NULL COMMENT '开始执行时间',
+    finished_at DATETIME NULL COMMENT '完成时间',
+    expires_at DATETIME NULL COMMENT '文件过期时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情导出任务表';
+
+-- 创建舆情导入任务表（上传文件和结果文件保存在本地存储目录）
+CREATE TABLE IF NOT EXISTS import_tasks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '创建人用户ID,0表示命令行导入',
+    filename VARCHAR(255) NOT NULL DEFAULT '' COMMENT '原始文件名',
+    format VARCHAR(10) NOT NULL COMMENT '文件格式:csv,xlsx,ndjson',
+    source VARCHAR(255) NOT NULL DEFAULT '' COMMENT '文件中没有来源列时使用的默认来源',
+    mapping JSON COMMENT '自定义列映射',
+    file VARCHAR(255) DEFAULT '' COMMENT '上传文件相对路径',
+    result_file VARCHAR(255) DEFAULT '' COMMENT '结果文件相对路径',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态:pending,running,success,failed,expired',
+    total BIGINT NOT NULL DEFAULT 0 COMMENT '文件中的数据行数',
+    processed BIGINT NOT NULL DEFAULT 0 COMMENT '已处理的行数',
+    imported BIGINT NOT NULL DEFAULT 0 COMMENT '成功导入的行数',
+    duplicates BIGINT NOT NULL DEFAULT 0 COMMENT '重复跳过的行数',
+    invalid BIGINT NOT NULL DEFAULT 0 COMMENT '校验失败的行数',
+    hits BIGINT NOT NULL DEFAULT 0 COMMENT '新增的监测组命中数',
+    error VARCHAR(1000) DEFAULT '' COMMENT '失败原因',
+    started_at DATETIME NULL COMMENT '开始执行时间',
+    finished_at DATETIME NULL COMMENT '完成时间',
+    expires_at DATETIME NULL COMMENT '文件过期时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='舆情导入任务表';
+
+-- 创建保存的检索表
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '名称',
+    query VARCHAR(200) DEFAULT '' COMMENT '检索词,为空时只按筛选条件查询',
+    mode VARCHAR(10) DEFAULT '' COMMENT '检索模式:natural,boolean,phrase',
+    sort VARCHAR(10) DEFAULT '' COMMENT '排序方式:relevance,time',
+    filter JSON COMMENT '筛选条件',
+    recent_hours INT NOT NULL DEFAULT 0 COMMENT '只查询最近多少小时入库的舆情,0表示不限',
+    visibility VARCHAR(10) NOT NULL DEFAULT 'private' COMMENT '可见范围:private,team',
+    created_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人用户ID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_created_by (created_by)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='保存的检索表';
+
+-- 创建检索订阅表
+CREATE TABLE IF NOT EXISTS saved_search_subscriptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    saved_search_id BIGINT UNSIGNED NOT NULL COMMENT '保存的检索ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '订阅用户ID',
+    frequency VARCHAR(10) NOT NULL COMMENT '推送方式:realtime,hourly,daily',
+    channels JSON COMMENT '用户渠道:inbox,email',
+    channel_ids JSON COMMENT '通知渠道ID',
+    last_checked_at DATETIME NULL COMMENT '最近一次检查新结果的时间',
+    last_notified_at DATETIME NULL COMMENT '最近一次推送的时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY uk_search_user (saved_search_id, user_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='检索订阅表';
+
+-- 创建检索订阅推送记录表（用于去重，定期清理）
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED NOT NULL COMMENT '订阅ID',
+    opinion_id BIGINT UNSIGNED NOT NULL COMMENT '舆情ID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '推送时间',
+    UNIQUE KEY uk_subscription_opinion (subscription_id, opinion_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='检索订阅推送记录表';
+
+-- 创建告警规则表
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '规则名称',
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    group_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '监测组ID,0表示整个场景',
+    type VARCHAR(20) NOT NULL COMMENT '规则类型:volume,negative_ratio,engagement,keyword,growth',
+    threshold DECIMAL(12,4) NOT NULL COMMENT '阈值',
+    window_minutes INT NOT NULL DEFAULT 60 COMMENT '统计窗口(分钟)',
+    min_count INT NOT NULL DEFAULT 0 COMMENT '占比和增长率规则的最小样本数',
+    keywords JSON NULL COMMENT '关键词规则的关键词',
+    severity VARCHAR(20) NOT NULL DEFAULT 'warning' COMMENT '级别:info,warning,critical',
+    cooldown_minutes INT NOT NULL DEFAULT 30 COMMENT '恢复后再次触发的冷却时间(分钟)',
+    channel_ids JSON NULL COMMENT '接收告警的通知渠道ID',
+    escalation_policy_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '升级策略ID,0表示不升级',
+    silence_until DATETIME NULL COMMENT '静默截止时间',
+    status TINYINT NOT NULL DEFAULT 1 COMMENT '1-正常,2-禁用',
+    last_evaluated_at DATETIME NULL COMMENT '最近评估时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_scenario_id (scenario_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='告警规则表';
+
+-- 创建告警事件表
+CREATE TABLE IF NOT EXISTS alert_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    rule_id BIGINT UNSIGNED NOT NULL COMMENT '规则ID',
+    scenario_id BIGINT UNSIGNED NOT NULL COMMENT '场景ID',
+    group_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '监测组ID',
+    dedup_key VARCHAR(255) NOT NULL COMMENT '去重键',
+    severity VARCHAR(20) NOT NULL COMMENT '级别',
+    status VARCHAR(20) NOT NULL DEFAULT 'firing' COMMENT '状态:firing,acknowledged,resolved',
+    title VARCHAR(255) NOT NULL COMMENT '标题',
+    value DECIMAL(12,4) NOT NULL DEFAULT 0 COMMENT '触发时的指标值',
+    threshold DECIMAL(12,4) NOT NULL DEFAULT 0 COMMENT '阈值',
+    opinion_ids JSON NULL COMMENT '相关舆情ID',
+    fire_count INT NOT NULL DEFAULT 1 COMMENT '持续触发的评估次数',
+    fired_at DATETIME NOT NULL COMMENT '首次触发时间',
+    last_seen_at DATETIME NOT NULL COMMENT '最近一次满足条件的时间',
+    resolved_at DATETIME NULL COMMENT '恢复时间',
+    resolved_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '手动恢复的用户ID,0表示自动恢复',
+    acknowledged_at DATETIME NULL COMMENT '认领时间',
+    acknowledged_by BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '认领用户ID',
+    escalation_level INT NOT NULL DEFAULT 0 COMMENT '已通知到的升级层级数',
+    escalated_at DATETIME NULL COMMENT '最近一次升级时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    INDEX idx_rule_dedup (rule_id, dedup_key),
+    INDEX idx_scenario_id (scenario_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='告警事件表';
+
+-- 创建告警时间线表
+CREATE TABLE IF NOT EXISTS alert_event_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id BIGINT UNSIGNED NOT NULL COMMENT '告警事件ID',
+    action VARCHAR(20) NOT NULL COMMENT '动作:fired,notified,escalated,acknowledged,resolved',
+    level INT NOT NULL DEFAULT 0 COMMENT '升级层级,0表示规则自身的通知',
+    user_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '操作用户ID,0表示系统',
+    delivery_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '通知发送记录ID',
+    detail VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '说明',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_event_id (event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='告警时间线表';
+
+-- 创建升级策略表
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '策略名称',
+    description VARCHAR(255) NOT NULL DEFAULT '' COMMENT '描述',
+    tiers JSON NULL COMMENT '升级层级',
+    status TINYINT NOT NULL DEFAULT 1 COMMENT '1-正常,2-禁用',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='升级策略表';
+
+-- 创建值班表
+CREATE TABLE IF NOT EXISTS oncall_schedules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '值班表名称',
+    user_ids JSON NULL COMMENT '轮值用户ID(按顺序)',
+    rotation_hours INT NOT NULL DEFAULT 168 COMMENT '轮换周期(小时)',
+    handoff_at DATETIME NOT NULL COMMENT '轮换起点(第一位用户开始值班的时间)',
+    status TINYINT NOT NULL DEFAULT 1 COMMENT '1-正常,2-禁用',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='值班表';
+
+-- 创建替班表
+CREATE TABLE IF NOT EXISTS oncall_overrides (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    schedule_id BIGINT UNSIGNED NOT NULL COMMENT '值班表ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '替班用户ID',
+    start_at DATETIME NOT NULL COMMENT '开始时间',
+    end_at DATETIME NOT NULL COMMENT '结束时间',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_schedule_time (schedule_id, start_at, end_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='替班表';
+
+-- 创建通知渠道表
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '渠道名称',
+    type VARCHAR(20) NOT NULL COMMENT '类型:webhook,email,dingtalk,wecom,feishu',
+    url VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'Webhook或机器人地址',
+    secret VARCHAR(255) NOT NULL DEFAULT '' COMMENT '签名密钥',
+    emails JSON NULL COMMENT '邮件收件人',
+    at_mobiles JSON NULL COMMENT '机器人@的手机号',
+    at_all TINYINT(1) NOT NULL DEFAULT 0 COMMENT '机器人是否@所有人',
+    template TEXT NULL COMMENT '消息模板(Go text/template),为空使用默认模板',
+    status TINYINT NOT NULL DEFAULT 1 COMMENT '1-正常,2-禁用',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='通知渠道表';
+
+-- 创建通知发送记录表
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    channel_id BIGINT UNSIGNED NOT NULL COMMENT '通知渠道ID,0表示直接发送给用户邮箱',
+    channel_type VARCHAR(20) NOT NULL COMMENT '渠道类型',
+    source VARCHAR(20) NOT NULL COMMENT '来源:alert,report,test,saved_search',
+    source_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '来源ID(如告警事件ID)',
+    title VARCHAR(255) NOT NULL COMMENT '消息标题',
+    status VARCHAR(20) NOT NULL COMMENT '结果:success,failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '尝试次数',
+    error VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '失败原因',
+    duration_ms BIGINT NOT NULL DEFAULT 0 COMMENT '耗时(毫秒)',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_channel_id (channel_id),
+    INDEX idx_source (source, source_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='通知发送记录表';
+
+-- 创建用户站内通知表
+CREATE TABLE IF NOT EXISTS user_notifications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '接收用户ID',
+    type VARCHAR(20) NOT NULL COMMENT '类型:assignment,mention,alert,saved_search',
+    title VARCHAR(255) NOT NULL COMMENT '标题',
+    content VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '内容',
+    source_type VARCHAR(20) NOT NULL DEFAULT '' COMMENT '关联对象类型:opinion,alert_event,saved_search',
+    source_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '关联对象ID',
+    actor_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '触发通知的用户ID,0表示系统',
+    read_at DATETIME NULL COMMENT '已读时间,NULL表示未读',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    INDEX idx_user_read (user_id, read_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户站内通知表';
+
 -- 插入默认管理员角色
 INSERT INTO roles (name, code, description, status) VALUES
 ('管理员', 'admin', '系统管理员，拥有所有权限', 1),
@@ -0,0 +1,90 @@
+// Package analysis 定义舆情富化阶段的分析器接口和注册表。
+//
+// 内置分析器基于词典规则（见 sentiment 子包），也可以通过 HTTP 接入外部 NLP 服务，
+// 外部服务不可用时由 FallbackAnalyzer 回退到内置分析器。
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Document 待分析文本
+type Document struct {
+	ID         uint64 `json:"id"`
+	ScenarioID uint64 `json:"scenario_id,omitempty"` // 场景ID，0表示不区分场景
+	Text       string `json:"text"`
+}
+
+// Sentiment 情感分析结果
+type Sentiment struct {
+	Score float64 `json:"score"`
+	Label string  `json:"label"`
+}
+
+// Entity 实体
+type Entity struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// Result 分析结果
+type Result struct {
+	DocumentID uint64     `json:"id"`
+	Sentiment  *Sentiment `json:"sentiment,omitempty"`
+	Entities   []Entity   `json:"entities,omitempty"`
+	Topics     []string   `json:"topics,omitempty"`
+	Analyzer   string     `json:"analyzer"` // 产生结果的分析器名称，为空表示该文档未分析成功
+}
+
+// Analyzer 分析器接口
+// Analyze 返回与 docs 一一对应的结果；部分失败时返回错误，失败文档对应结果的 Analyzer 为空
+type Analyzer interface {
+	Name() string
+	Analyze(ctx context.Context, docs []Document) ([]Result, error)
+}
+
+// Registry 分析器注册表
+type Registry struct {
+	mu        sync.RWMutex
+	analyzers map[string]Analyzer
+}
+
+// NewRegistry 创建分析器注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		analyzers: make(map[string]Analyzer),
+	}
+}
+
+// Register 注册分析器，同名分析器会被覆盖
+func (r *Registry) Register(analyzer Analyzer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.analyzers[analyzer.Name()] = analyzer
+}
+
+// Get 根据名称获取分析器
+func (r *Registry) Get(name string) (Analyzer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	analyzer, ok := r.analyzers[name]
+	if !ok {
+		return nil, fmt.Errorf("分析器未注册: %s", name)
+	}
+	return analyzer, nil
+}
+
+// Names 获取已注册的分析器名称
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.analyzers))
+	for name := range r.analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
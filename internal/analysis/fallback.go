@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"context"
+)
+
+// FallbackAnalyzer 主分析器失败时回退到备用分析器
+// 主分析器未返回情感结果的文档，也会使用备用分析器补全情感
+type FallbackAnalyzer struct {
+	primary  Analyzer
+	fallback Analyzer
+	onError  func(error)
+}
+
+// NewFallbackAnalyzer 创建带回退的分析器，onError 可为 nil
+func NewFallbackAnalyzer(primary, fallback Analyzer, onError func(error)) *FallbackAnalyzer {
+	return &FallbackAnalyzer{
+		primary:  primary,
+		fallback: fallback,
+		onError:  onError,
+	}
+}
+
+// Name 分析器名称
+func (a *FallbackAnalyzer) Name() string {
+	return a.primary.Name()
+}
+
+// Analyze 先调用主分析器，再对失败或缺少情感结果的文档调用备用分析器
+func (a *FallbackAnalyzer) Analyze(ctx context.Context, docs []Document) ([]Result, error) {
+	results, err := a.primary.Analyze(ctx, docs)
+	if err != nil && a.onError != nil {
+		a.onError(err)
+	}
+	if len(results) != len(docs) {
+		results = make([]Result, len(docs))
+	}
+
+	var pending []int
+	for i := range docs {
+		if results[i].Analyzer == "" || results[i].Sentiment == nil {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	retry := make([]Document, len(pending))
+	for j, i := range pending {
+		retry[j] = docs[i]
+	}
+	fallbackResults, err := a.fallback.Analyze(ctx, retry)
+	if err != nil {
+		return results, err
+	}
+
+	for j, i := range pending {
+		if results[i].Analyzer == "" {
+			results[i] = fallbackResults[j]
+			continue
+		}
+		// 主分析器成功但未给出情感，保留其实体和主题，仅补全情感
+		results[i].Sentiment = fallbackResults[j].Sentiment
+	}
+	return results, nil
+}
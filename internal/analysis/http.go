@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPName HTTP 分析器名称
+const HTTPName = "http"
+
+// HTTPOptions HTTP 分析器配置
+type HTTPOptions struct {
+	Endpoint         string        // 分析服务地址，如 http://nlp.internal/v1/analyze
+	Token            string        // 可选的 Bearer Token
+	Timeout          time.Duration // 单次请求超时
+	BatchSize        int           // 每次请求的最大文档数
+	FailureThreshold int           // 连续失败多少次后熔断
+	OpenDuration     time.Duration // 熔断持续时间
+	Client           *http.Client  // 可选，默认使用 http.DefaultClient
+}
+
+// HTTPAnalyzer 调用外部 NLP 服务的分析器
+//
+// 请求：POST {endpoint}，{"documents":[{"id":1,"scenario_id":2,"text":"..."}]}
+// 响应：{"results":[{"id":1,"sentiment":{"score":-0.6,"label":"negative"},"entities":[{"text":"某品牌","type":"brand"}],"topics":["售后"]}]}
+type HTTPAnalyzer struct {
+	opts    HTTPOptions
+	client  *http.Client
+	breaker *circuitBreaker
+}
+
+type httpAnalyzeRequest struct {
+	Documents []Document `json:"documents"`
+}
+
+type httpAnalyzeResponse struct {
+	Results []Result `json:"results"`
+}
+
+// NewHTTPAnalyzer 创建 HTTP 分析器
+func NewHTTPAnalyzer(opts HTTPOptions) *HTTPAnalyzer {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 32
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPAnalyzer{
+		opts:    opts,
+		client:  client,
+		breaker: newCircuitBreaker(opts.FailureThreshold, opts.OpenDuration),
+	}
+}
+
+// Name 分析器名称
+func (a *HTTPAnalyzer) Name() string {
+	return HTTPName
+}
+
+// Analyze 按批次调用外部服务，单个批次失败不影响其他批次
+func (a *HTTPAnalyzer) Analyze(ctx context.Context, docs []Document) ([]Result, error) {
+	results := make([]Result, len(docs))
+	var firstErr error
+
+	for start := 0; start < len(docs); start += a.opts.BatchSize {
+		end := start + a.opts.BatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		if !a.breaker.allow() {
+			if firstErr == nil {
+				firstErr = ErrCircuitOpen
+			}
+			continue
+		}
+
+		batch, err := a.analyzeBatch(ctx, docs[start:end])
+		if err != nil {
+			a.breaker.failure()
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		a.breaker.success()
+		copy(results[start:end], batch)
+	}
+
+	return results, firstErr
+}
+
+// analyzeBatch 请求一个批次，按文档 ID 对齐结果
+func (a *HTTPAnalyzer) analyzeBatch(ctx context.Context, docs []Document) ([]Result, error) {
+	body, err := json.Marshal(httpAnalyzeRequest{Documents: docs})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.opts.Token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求分析服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("分析服务返回状态码 %d", resp.StatusCode)
+	}
+
+	var payload httpAnalyzeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("解析分析服务响应失败: %w", err)
+	}
+
+	byID := make(map[uint64]Result, len(payload.Results))
+	for _, r := range payload.Results {
+		byID[r.DocumentID] = r
+	}
+
+	results := make([]Result, len(docs))
+	for i, doc := range docs {
+		r, ok := byID[doc.ID]
+		if !ok {
+			return nil, errors.New("分析服务响应缺少文档结果")
+		}
+		r.DocumentID = doc.ID
+		r.Analyzer = HTTPName
+		results[i] = r
+	}
+	return results, nil
+}
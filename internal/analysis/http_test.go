@@ -0,0 +1,260 @@
+package analysis_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis"
+	"sentinel-opinion-monitor/internal/analysis/modelstub"
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+)
+
+// batchRecorder 记录每次请求的文档数后交给替身服务处理
+type batchRecorder struct {
+	mu      sync.Mutex
+	sizes   []int
+	handler http.Handler
+}
+
+func (r *batchRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		Documents []json.RawMessage `json:"documents"`
+	}
+	json.Unmarshal(data, &body)
+	r.mu.Lock()
+	r.sizes = append(r.sizes, len(body.Documents))
+	r.mu.Unlock()
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	r.handler.ServeHTTP(w, req)
+}
+
+// switchable 可在运行中切换为失败或正常的替身服务
+type switchable struct {
+	failing  atomic.Bool
+	requests atomic.Int64
+	ok       http.Handler
+	fail     http.Handler
+}
+
+func (s *switchable) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.requests.Add(1)
+	if s.failing.Load() {
+		s.fail.ServeHTTP(w, req)
+		return
+	}
+	s.ok.ServeHTTP(w, req)
+}
+
+func documents(n int) []analysis.Document {
+	docs := make([]analysis.Document, n)
+	for i := range docs {
+		docs[i] = analysis.Document{ID: uint64(i + 1), Text: "申请退款好几天了还没处理，太失望了"}
+	}
+	return docs
+}
+
+func TestHTTPAnalyzerBatches(t *testing.T) {
+	recorder := &batchRecorder{handler: modelstub.NewHandler(modelstub.Options{
+		Token:  "secret",
+		Topics: map[string]string{"退款": "售后"},
+	})}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	analyzer := analysis.NewHTTPAnalyzer(analysis.HTTPOptions{
+		Endpoint:  server.URL,
+		Token:     "secret",
+		BatchSize: 2,
+	})
+	results, err := analyzer.Analyze(context.Background(), documents(5))
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if got, want := recorder.sizes, []int{2, 2, 1}; !equalInts(got, want) {
+		t.Fatalf("batch sizes = %v, want %v", got, want)
+	}
+	if len(results) != 5 {
+		t.Fatalf("len(results) = %d, want 5", len(results))
+	}
+	for i, r := range results {
+		if r.DocumentID != uint64(i+1) {
+			t.Errorf("results[%d].DocumentID = %d, want %d", i, r.DocumentID, i+1)
+		}
+		if r.Analyzer != analysis.HTTPName {
+			t.Errorf("results[%d].Analyzer = %q, want %q", i, r.Analyzer, analysis.HTTPName)
+		}
+		if r.Sentiment == nil {
+			t.Errorf("results[%d].Sentiment = nil", i)
+		}
+		if len(r.Topics) != 1 || r.Topics[0] != "售后" {
+			t.Errorf("results[%d].Topics = %v, want [售后]", i, r.Topics)
+		}
+	}
+}
+
+func TestHTTPAnalyzerUnauthorized(t *testing.T) {
+	server := httptest.NewServer(modelstub.NewHandler(modelstub.Options{Token: "secret"}))
+	defer server.Close()
+
+	analyzer := analysis.NewHTTPAnalyzer(analysis.HTTPOptions{Endpoint: server.URL, Token: "wrong"})
+	results, err := analyzer.Analyze(context.Background(), documents(1))
+	if err == nil {
+		t.Fatal("Analyze() error = nil, want unauthorized error")
+	}
+	if results[0].Analyzer != "" {
+		t.Errorf("failed document Analyzer = %q, want empty", results[0].Analyzer)
+	}
+}
+
+func TestHTTPAnalyzerTimeout(t *testing.T) {
+	server := httptest.NewServer(modelstub.NewHandler(modelstub.Options{Delay: 300 * time.Millisecond}))
+	defer server.Close()
+
+	analyzer := analysis.NewHTTPAnalyzer(analysis.HTTPOptions{
+		Endpoint: server.URL,
+		Timeout:  50 * time.Millisecond,
+	})
+	start := time.Now()
+	results, err := analyzer.Analyze(context.Background(), documents(1))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Analyze() error = nil, want timeout error")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Analyze() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 250*time.Millisecond {
+		t.Errorf("Analyze() took %v, want it to stop at the request timeout", elapsed)
+	}
+	if results[0].Analyzer != "" {
+		t.Errorf("timed out document Analyzer = %q, want empty", results[0].Analyzer)
+	}
+}
+
+func TestHTTPAnalyzerCircuitBreaker(t *testing.T) {
+	stub := &switchable{
+		ok:   modelstub.NewHandler(modelstub.Options{}),
+		fail: modelstub.NewHandler(modelstub.Options{FailAll: true}),
+	}
+	stub.failing.Store(true)
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	const openFor = 100 * time.Millisecond
+	analyzer := analysis.NewHTTPAnalyzer(analysis.HTTPOptions{
+		Endpoint:         server.URL,
+		FailureThreshold: 2,
+		OpenDuration:     openFor,
+	})
+	ctx := context.Background()
+	analyze := func() error {
+		_, err := analyzer.Analyze(ctx, documents(1))
+		return err
+	}
+
+	// 连续失败达到阈值后打开
+	for i := 0; i < 2; i++ {
+		if err := analyze(); err == nil || errors.Is(err, analysis.ErrCircuitOpen) {
+			t.Fatalf("call %d error = %v, want upstream failure", i+1, err)
+		}
+	}
+	if err := analyze(); !errors.Is(err, analysis.ErrCircuitOpen) {
+		t.Fatalf("error after threshold = %v, want ErrCircuitOpen", err)
+	}
+	if got := stub.requests.Load(); got != 2 {
+		t.Fatalf("requests while open = %d, want 2", got)
+	}
+
+	// 半开：冷却后放行一次试探请求，失败则重新打开
+	time.Sleep(openFor + 20*time.Millisecond)
+	if err := analyze(); err == nil || errors.Is(err, analysis.ErrCircuitOpen) {
+		t.Fatalf("half-open probe error = %v, want upstream failure", err)
+	}
+	if got := stub.requests.Load(); got != 3 {
+		t.Fatalf("requests after failed probe = %d, want 3", got)
+	}
+	if err := analyze(); !errors.Is(err, analysis.ErrCircuitOpen) {
+		t.Fatalf("error after failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// 半开试探成功后关闭
+	stub.failing.Store(false)
+	time.Sleep(openFor + 20*time.Millisecond)
+	if err := analyze(); err != nil {
+		t.Fatalf("half-open probe error = %v, want nil", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := analyze(); err != nil {
+			t.Fatalf("call %d after close error = %v, want nil", i+1, err)
+		}
+	}
+	if got := stub.requests.Load(); got != 7 {
+		t.Errorf("requests after close = %d, want 7", got)
+	}
+}
+
+func TestFallbackAnalyzerUsesLexiconWhenModelFails(t *testing.T) {
+	server := httptest.NewServer(modelstub.NewHandler(modelstub.Options{FailAll: true}))
+	defer server.Close()
+
+	lexicon := sentiment.NewAnalyzer(nil)
+	builtin := analysis.NewLexiconAnalyzer(func(scenarioID uint64) (*sentiment.Analyzer, error) {
+		return lexicon, nil
+	})
+	var primaryErr error
+	analyzer := analysis.NewFallbackAnalyzer(
+		analysis.NewHTTPAnalyzer(analysis.HTTPOptions{Endpoint: server.URL}),
+		builtin,
+		func(err error) { primaryErr = err },
+	)
+
+	docs := documents(3)
+	results, err := analyzer.Analyze(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if primaryErr == nil {
+		t.Error("onError was not called for the failed model request")
+	}
+	want := lexicon.Analyze(docs[0].Text)
+	for i, r := range results {
+		if r.Analyzer != analysis.BuiltinName {
+			t.Errorf("results[%d].Analyzer = %q, want %q", i, r.Analyzer, analysis.BuiltinName)
+		}
+		if r.DocumentID != docs[i].ID {
+			t.Errorf("results[%d].DocumentID = %d, want %d", i, r.DocumentID, docs[i].ID)
+		}
+		if r.Sentiment == nil || r.Sentiment.Label != string(want.Label) || r.Sentiment.Score != want.Score {
+			t.Errorf("results[%d].Sentiment = %+v, want label %q score %v", i, r.Sentiment, want.Label, want.Score)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
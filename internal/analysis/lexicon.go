@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"context"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+)
+
+// BuiltinName 内置分析器名称
+const BuiltinName = "builtin"
+
+// LexiconProvider 按场景提供情感分析器
+type LexiconProvider func(scenarioID uint64) (*sentiment.Analyzer, error)
+
+// LexiconAnalyzer 基于词典规则的内置分析器，只产出情感结果
+type LexiconAnalyzer struct {
+	provider LexiconProvider
+}
+
+// NewLexiconAnalyzer 创建内置分析器
+func NewLexiconAnalyzer(provider LexiconProvider) *LexiconAnalyzer {
+	return &LexiconAnalyzer{provider: provider}
+}
+
+// Name 分析器名称
+func (a *LexiconAnalyzer) Name() string {
+	return BuiltinName
+}
+
+// Analyze 使用文档所属场景的词典进行情感分析
+func (a *LexiconAnalyzer) Analyze(ctx context.Context, docs []Document) ([]Result, error) {
+	analyzers := make(map[uint64]*sentiment.Analyzer)
+	results := make([]Result, len(docs))
+	for i, doc := range docs {
+		analyzer, ok := analyzers[doc.ScenarioID]
+		if !ok {
+			var err error
+			analyzer, err = a.provider(doc.ScenarioID)
+			if err != nil {
+				return results, err
+			}
+			analyzers[doc.ScenarioID] = analyzer
+		}
+
+		r := analyzer.Analyze(doc.Text)
+		results[i] = Result{
+			DocumentID: doc.ID,
+			Sentiment:  &Sentiment{Score: r.Score, Label: string(r.Label)},
+			Analyzer:   BuiltinName,
+		}
+	}
+	return results, nil
+}
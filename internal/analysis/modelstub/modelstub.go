@@ -0,0 +1,104 @@
+// Package modelstub 提供外部 NLP 服务的替身实现，协议与 analysis.HTTPAnalyzer 一致。
+//
+// 可通过 httptest.NewServer(modelstub.NewHandler(opts)) 在测试中使用，
+// 也可以运行 cmd/modelstub 作为本地联调用的模型服务。
+package modelstub
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis"
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+)
+
+// Options 替身服务行为配置
+type Options struct {
+	Token   string        // 非空时校验 Bearer Token
+	Delay   time.Duration // 每次请求的人为延迟，用于验证超时
+	FailAll bool          // 始终返回 503，用于验证熔断和回退
+
+	// Topics 关键词到主题的映射，内容包含关键词即打上对应主题，如 {"退款": "售后"}
+	Topics map[string]string
+	// Entities 实体词到类型的映射，如 {"某品牌": "brand"}
+	Entities map[string]string
+}
+
+// Handler 替身服务
+type Handler struct {
+	opts     Options
+	analyzer *sentiment.Analyzer
+	requests int64
+}
+
+// NewHandler 创建替身服务
+func NewHandler(opts Options) *Handler {
+	return &Handler{
+		opts:     opts,
+		analyzer: sentiment.NewAnalyzer(nil),
+	}
+}
+
+// Requests 返回已处理的请求数
+func (h *Handler) Requests() int64 {
+	return atomic.LoadInt64(&h.requests)
+}
+
+// ServeHTTP 处理分析请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&h.requests, 1)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.opts.Token != "" && r.Header.Get("Authorization") != "Bearer "+h.opts.Token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if h.opts.Delay > 0 {
+		select {
+		case <-time.After(h.opts.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if h.opts.FailAll {
+		http.Error(w, "model unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req struct {
+		Documents []analysis.Document `json:"documents"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]analysis.Result, 0, len(req.Documents))
+	for _, doc := range req.Documents {
+		s := h.analyzer.Analyze(doc.Text)
+		result := analysis.Result{
+			DocumentID: doc.ID,
+			Sentiment:  &analysis.Sentiment{Score: s.Score, Label: string(s.Label)},
+		}
+		for word, entityType := range h.opts.Entities {
+			if strings.Contains(doc.Text, word) {
+				result.Entities = append(result.Entities, analysis.Entity{Text: word, Type: entityType})
+			}
+		}
+		for word, topic := range h.opts.Topics {
+			if strings.Contains(doc.Text, word) {
+				result.Topics = append(result.Topics, topic)
+			}
+		}
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+}
@@ -0,0 +1,97 @@
+// Package noise 提供基于朴素贝叶斯的噪音舆情分类，用分析人员标注的相关/不相关命中训练每个监测组的分类器。
+package noise
+
+import (
+	"math"
+	"sort"
+)
+
+// Sample 训练样本，Tokens 为分词结果
+type Sample struct {
+	Tokens []string
+	Noise  bool
+}
+
+// Model 二值化多项式朴素贝叶斯模型（同一样本中重复出现的词语只计一次），可序列化为 JSON 存储
+type Model struct {
+	RelevantDocs   int               `json:"relevant_docs"`   // 相关样本数
+	NoiseDocs      int               `json:"noise_docs"`      // 噪音样本数
+	RelevantTokens int               `json:"relevant_tokens"` // 相关样本中词表内词语的出现次数之和
+	NoiseTokens    int               `json:"noise_tokens"`    // 噪音样本中词表内词语的出现次数之和
+	Terms          map[string][2]int `json:"terms"`           // 词语在相关、噪音样本中出现的样本数
+}
+
+// Train 训练模型，只保留至少出现在 minDocs 个样本中的词语，词表超过 maxTerms 时保留出现样本数最多的词语
+func Train(samples []Sample, minDocs, maxTerms int) *Model {
+	counts := make(map[string][2]int)
+	m := &Model{Terms: make(map[string][2]int)}
+	for _, sample := range samples {
+		class := 0
+		if sample.Noise {
+			class = 1
+			m.NoiseDocs++
+		} else {
+			m.RelevantDocs++
+		}
+		for term := range distinct(sample.Tokens) {
+			count := counts[term]
+			count[class]++
+			counts[term] = count
+		}
+	}
+
+	terms := make([]string, 0, len(counts))
+	for term, count := range counts {
+		if count[0]+count[1] >= minDocs {
+			terms = append(terms, term)
+		}
+	}
+	if maxTerms > 0 && len(terms) > maxTerms {
+		sort.Slice(terms, func(i, j int) bool {
+			a, b := counts[terms[i]], counts[terms[j]]
+			if a[0]+a[1] != b[0]+b[1] {
+				return a[0]+a[1] > b[0]+b[1]
+			}
+			return terms[i] < terms[j]
+		})
+		terms = terms[:maxTerms]
+	}
+
+	for _, term := range terms {
+		count := counts[term]
+		m.Terms[term] = count
+		m.RelevantTokens += count[0]
+		m.NoiseTokens += count[1]
+	}
+	return m
+}
+
+// Score 返回分词结果属于噪音的概率（0~1），不在词表中的词语不参与计算；模型缺少任一类样本时返回 0
+func (m *Model) Score(tokens []string) float64 {
+	if m == nil || m.RelevantDocs == 0 || m.NoiseDocs == 0 {
+		return 0
+	}
+
+	// 对数几率 = 先验对数比 + 各词语条件概率对数比，均使用拉普拉斯平滑
+	vocabulary := float64(len(m.Terms))
+	logOdds := math.Log(float64(m.NoiseDocs) / float64(m.RelevantDocs))
+	for term := range distinct(tokens) {
+		count, ok := m.Terms[term]
+		if !ok {
+			continue
+		}
+		noise := (float64(count[1]) + 1) / (float64(m.NoiseTokens) + vocabulary)
+		relevant := (float64(count[0]) + 1) / (float64(m.RelevantTokens) + vocabulary)
+		logOdds += math.Log(noise / relevant)
+	}
+	return 1 / (1 + math.Exp(-logOdds))
+}
+
+// distinct 去重
+func distinct(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		set[token] = true
+	}
+	return set
+}
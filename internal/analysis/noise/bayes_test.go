@@ -0,0 +1,96 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+func trainingSamples(relevant, noise int) []Sample {
+	samples := make([]Sample, 0, relevant+noise)
+	for i := 0; i < relevant; i++ {
+		samples = append(samples, Sample{Tokens: []string{"小米", "汽车", "发布会"}})
+	}
+	for i := 0; i < noise; i++ {
+		samples = append(samples, Sample{Tokens: []string{"转发", "抽奖", "关注"}, Noise: true})
+	}
+	return samples
+}
+
+func TestModelScore(t *testing.T) {
+	balanced := Train(trainingSamples(5, 5), 1, 0)
+
+	cases := []struct {
+		name   string
+		model  *Model
+		tokens []string
+		want   float64
+	}{
+		{name: "noise terms", model: balanced, tokens: []string{"转发", "抽奖", "关注"}, want: 0.9954},
+		{name: "relevant terms", model: balanced, tokens: []string{"小米", "汽车", "发布会"}, want: 0.0046},
+		{name: "repeated terms count once", model: balanced, tokens: []string{"转发", "转发", "抽奖", "关注"}, want: 0.9954},
+		{name: "mixed terms cancel out", model: balanced, tokens: []string{"小米", "抽奖"}, want: 0.5},
+		{name: "unknown terms use prior", model: balanced, tokens: []string{"天气"}, want: 0.5},
+		{name: "prior follows class balance", model: Train(trainingSamples(3, 1), 1, 0), tokens: nil, want: 0.25},
+		{name: "single class", model: Train(trainingSamples(5, 0), 1, 0), tokens: []string{"小米"}, want: 0},
+		{name: "nil model", model: nil, tokens: []string{"转发"}, want: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := math.Round(c.model.Score(c.tokens)*10000) / 10000
+			if got != c.want {
+				t.Errorf("Score(%v) = %v, want %v", c.tokens, got, c.want)
+			}
+		})
+	}
+}
+
+func TestTrainVocabulary(t *testing.T) {
+	samples := append(trainingSamples(2, 2),
+		Sample{Tokens: []string{"小米", "偶然", "偶然"}},
+		Sample{Tokens: []string{"抽奖", "红包"}, Noise: true},
+	)
+
+	cases := []struct {
+		name     string
+		minDocs  int
+		maxTerms int
+		terms    map[string][2]int
+	}{
+		{
+			name:    "rare terms dropped",
+			minDocs: 2,
+			terms: map[string][2]int{
+				"小米": {3, 0}, "汽车": {2, 0}, "发布会": {2, 0},
+				"转发": {0, 2}, "抽奖": {0, 3}, "关注": {0, 2},
+			},
+		},
+		{
+			name:     "vocabulary capped by document count",
+			minDocs:  1,
+			maxTerms: 2,
+			terms:    map[string][2]int{"小米": {3, 0}, "抽奖": {0, 3}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := Train(samples, c.minDocs, c.maxTerms)
+			if m.RelevantDocs != 3 || m.NoiseDocs != 3 {
+				t.Errorf("docs = %d/%d, want 3/3", m.RelevantDocs, m.NoiseDocs)
+			}
+			if len(m.Terms) != len(c.terms) {
+				t.Fatalf("Terms = %v, want %v", m.Terms, c.terms)
+			}
+			relevantTokens, noiseTokens := 0, 0
+			for term, want := range c.terms {
+				if m.Terms[term] != want {
+					t.Errorf("Terms[%q] = %v, want %v", term, m.Terms[term], want)
+				}
+				relevantTokens += want[0]
+				noiseTokens += want[1]
+			}
+			if m.RelevantTokens != relevantTokens || m.NoiseTokens != noiseTokens {
+				t.Errorf("tokens = %d/%d, want %d/%d", m.RelevantTokens, m.NoiseTokens, relevantTokens, noiseTokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package segment
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed dict.txt
+var builtinDict string
+
+//go:embed stopwords.txt
+var builtinStopwords string
+
+// Dictionary 前缀词典，格式与 jieba 词典兼容：每行 "词语 词频 [词性]"
+type Dictionary struct {
+	freq      map[string]float64 // 词频，前缀条目的词频为 0
+	total     float64
+	logTotal  float64
+	medianIDF float64
+}
+
+// NewDictionary 创建空词典
+func NewDictionary() *Dictionary {
+	return &Dictionary{freq: make(map[string]float64)}
+}
+
+var (
+	defaultDict     *Dictionary
+	defaultDictOnce sync.Once
+	stopwords       map[string]bool
+	stopwordsOnce   sync.Once
+)
+
+// DefaultDictionary 返回内置词典（只读，可并发使用）
+func DefaultDictionary() *Dictionary {
+	defaultDictOnce.Do(func() {
+		d := NewDictionary()
+		d.Load(strings.NewReader(builtinDict))
+		defaultDict = d
+	})
+	return defaultDict
+}
+
+// LoadDictionaryFile 从文件加载词典（如 jieba 的 dict.txt），并合并内置词典
+func LoadDictionaryFile(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开词典文件失败: %w", err)
+	}
+	defer f.Close()
+
+	d := NewDictionary()
+	d.Load(strings.NewReader(builtinDict))
+	if err := d.Load(f); err != nil {
+		return nil, fmt.Errorf("读取词典文件失败: %w", err)
+	}
+	return d, nil
+}
+
+// Load 从 reader 加载词典条目，词频缺省时按 1 处理
+func (d *Dictionary) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		freq := 1.0
+		if len(fields) > 1 {
+			if v, err := strconv.ParseFloat(fields[1], 64); err == nil && v > 0 {
+				freq = v
+			}
+		}
+		d.add(fields[0], freq)
+	}
+	d.refresh()
+	return scanner.Err()
+}
+
+// add 添加词条及其所有前缀
+func (d *Dictionary) add(word string, freq float64) {
+	word = strings.ToLower(word)
+	if old, ok := d.freq[word]; ok {
+		d.total -= old
+	}
+	d.freq[word] = freq
+	d.total += freq
+
+	runes := []rune(word)
+	for i := 1; i < len(runes); i++ {
+		prefix := string(runes[:i])
+		if _, ok := d.freq[prefix]; !ok {
+			d.freq[prefix] = 0
+		}
+	}
+}
+
+// refresh 重新计算总词频和 IDF 中位数
+func (d *Dictionary) refresh() {
+	if d.total <= 0 {
+		d.logTotal = 0
+		d.medianIDF = 0
+		return
+	}
+	d.logTotal = math.Log(d.total)
+
+	// 以词频估算 IDF，未登录词使用中位数
+	idfs := make([]float64, 0, len(d.freq))
+	for _, f := range d.freq {
+		if f > 0 {
+			idfs = append(idfs, d.logTotal-math.Log(f))
+		}
+	}
+	if len(idfs) > 0 {
+		sort.Float64s(idfs)
+		d.medianIDF = idfs[len(idfs)/2]
+	}
+}
+
+// Freq 获取词频，未登录词和前缀返回 0
+func (d *Dictionary) Freq(word string) float64 {
+	return d.freq[word]
+}
+
+// IDF 估算词语的逆文档频率
+func (d *Dictionary) IDF(word string) float64 {
+	if f := d.freq[word]; f > 0 {
+		return d.logTotal - math.Log(f)
+	}
+	return d.medianIDF
+}
+
+// isStopword 判断是否为停用词
+func isStopword(word string) bool {
+	stopwordsOnce.Do(func() {
+		stopwords = make(map[string]bool)
+		for _, line := range strings.Split(builtinStopwords, "\n") {
+			if w := strings.TrimSpace(line); w != "" && !strings.HasPrefix(w, "#") {
+				stopwords[w] = true
+			}
+		}
+	})
+	return stopwords[word]
+}
@@ -0,0 +1,165 @@
+package segment
+
+import (
+	"math"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Keyword 提取出的关键词及权重
+type Keyword struct {
+	Word   string  `json:"word"`
+	Weight float64 `json:"weight"`
+}
+
+// 关键词提取方法
+const (
+	MethodTFIDF    = "tfidf"
+	MethodTextRank = "textrank"
+)
+
+// textRankWindow TextRank 共现窗口大小
+const textRankWindow = 5
+
+// Extract 使用指定方法提取关键词，未知方法按 TF-IDF 处理
+func (s *Segmenter) Extract(text string, topK int, method string) []Keyword {
+	if method == MethodTextRank {
+		return s.ExtractTextRank(text, topK)
+	}
+	return s.ExtractTFIDF(text, topK)
+}
+
+// ExtractTFIDF 基于 TF-IDF 提取关键词，IDF 由词典词频估算
+func (s *Segmenter) ExtractTFIDF(text string, topK int) []Keyword {
+	tokens := s.candidates(s.Cut(text))
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	tf := make(map[string]float64)
+	for _, token := range tokens {
+		tf[token]++
+	}
+
+	keywords := make([]Keyword, 0, len(tf))
+	for word, count := range tf {
+		idf := s.dict.IDF(word)
+		if s.IsUserWord(word) && idf < s.dict.medianIDF {
+			// 用户词以高词频注入，按未登录词的 IDF 计算，避免被低估
+			idf = s.dict.medianIDF
+		}
+		keywords = append(keywords, Keyword{Word: word, Weight: count / float64(len(tokens)) * idf})
+	}
+	return topKeywords(keywords, topK)
+}
+
+// ExtractTextRank 基于 TextRank 提取关键词
+func (s *Segmenter) ExtractTextRank(text string, topK int) []Keyword {
+	tokens := s.Cut(text)
+
+	// 构建共现图：窗口内的候选词两两相连
+	graph := make(map[string]map[string]float64)
+	for i, token := range tokens {
+		if !isCandidate(token) {
+			continue
+		}
+		if graph[token] == nil {
+			graph[token] = make(map[string]float64)
+		}
+		for j := i + 1; j < i+textRankWindow && j < len(tokens); j++ {
+			other := tokens[j]
+			if !isCandidate(other) || other == token {
+				continue
+			}
+			if graph[other] == nil {
+				graph[other] = make(map[string]float64)
+			}
+			graph[token][other]++
+			graph[other][token]++
+		}
+	}
+	if len(graph) == 0 {
+		return nil
+	}
+
+	const damping = 0.85
+	score := make(map[string]float64, len(graph))
+	outWeight := make(map[string]float64, len(graph))
+	for word, edges := range graph {
+		score[word] = 1
+		for _, w := range edges {
+			outWeight[word] += w
+		}
+	}
+
+	// 按词排序迭代，保证结果稳定
+	words := make([]string, 0, len(graph))
+	for word := range graph {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	for iter := 0; iter < 10; iter++ {
+		for _, word := range words {
+			sum := 0.0
+			for other, w := range graph[word] {
+				if outWeight[other] > 0 {
+					sum += w / outWeight[other] * score[other]
+				}
+			}
+			score[word] = (1 - damping) + damping*sum
+		}
+	}
+
+	maxScore := 0.0
+	for _, v := range score {
+		maxScore = math.Max(maxScore, v)
+	}
+	keywords := make([]Keyword, 0, len(score))
+	for word, v := range score {
+		keywords = append(keywords, Keyword{Word: word, Weight: v / maxScore})
+	}
+	return topKeywords(keywords, topK)
+}
+
+// candidates 过滤出可作为关键词的词语
+func (s *Segmenter) candidates(tokens []string) []string {
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if isCandidate(token) {
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// isCandidate 至少两个字符、非停用词、非纯数字/符号
+func isCandidate(word string) bool {
+	if utf8.RuneCountInString(word) < 2 || isStopword(word) {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// topKeywords 按权重降序取前 topK 个，权重保留4位小数
+func topKeywords(keywords []Keyword, topK int) []Keyword {
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Weight == keywords[j].Weight {
+			return keywords[i].Word < keywords[j].Word
+		}
+		return keywords[i].Weight > keywords[j].Weight
+	})
+	if topK > 0 && len(keywords) > topK {
+		keywords = keywords[:topK]
+	}
+	for i := range keywords {
+		keywords[i].Weight = math.Round(keywords[i].Weight*10000) / 10000
+	}
+	return keywords
+}
@@ -0,0 +1,200 @@
+// Package segment 提供基于词典的中文分词（jieba 风格的最大概率路径切分）和关键词提取。
+//
+// 分词时先按词典构建有向无环图，再用动态规划求出最大概率切分路径；未登录的连续
+// 英文字母和数字合并为一个词。用户词典（如监测组关键词）以高词频叠加在基础词典之上，
+// 保证品牌名等不会被切开。
+package segment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// userWordFreq 用户词的默认词频，足够高以保证优先成词
+const userWordFreq = 100000
+
+// Segmenter 分词器，创建后只读，可并发使用
+type Segmenter struct {
+	dict     *Dictionary
+	user     map[string]float64 // 用户词及其前缀，前缀词频为 0
+	logTotal float64
+}
+
+// NewSegmenter 创建分词器，dict 为 nil 时使用内置词典，userWords 为用户词典
+func NewSegmenter(dict *Dictionary, userWords []string) *Segmenter {
+	if dict == nil {
+		dict = DefaultDictionary()
+	}
+	s := &Segmenter{
+		dict: dict,
+		user: make(map[string]float64),
+	}
+
+	total := dict.total
+	for _, word := range userWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || strings.ContainsAny(word, " \t") {
+			continue
+		}
+		if _, ok := s.user[word]; !ok || s.user[word] == 0 {
+			total += userWordFreq
+		}
+		s.user[word] = userWordFreq
+		runes := []rune(word)
+		for i := 1; i < len(runes); i++ {
+			prefix := string(runes[:i])
+			if _, ok := s.user[prefix]; !ok {
+				s.user[prefix] = 0
+			}
+		}
+	}
+	if total > 0 {
+		s.logTotal = math.Log(total)
+	}
+	return s
+}
+
+// Dictionary 返回分词器使用的基础词典
+func (s *Segmenter) Dictionary() *Dictionary {
+	return s.dict
+}
+
+// IsUserWord 判断是否为用户词
+func (s *Segmenter) IsUserWord(word string) bool {
+	return s.user[strings.ToLower(word)] > 0
+}
+
+// lookup 查询词频，第二个返回值表示是否为词或词的前缀
+func (s *Segmenter) lookup(word string) (float64, bool) {
+	if f, ok := s.user[word]; ok {
+		if f > 0 {
+			return f, true
+		}
+		if df := s.dict.freq[word]; df > 0 {
+			return df, true
+		}
+		return 0, true
+	}
+	f, ok := s.dict.freq[word]
+	return f, ok
+}
+
+// Cut 分词，返回的词语均为小写，不包含空白和标点
+func (s *Segmenter) Cut(text string) []string {
+	var tokens []string
+	for _, block := range splitBlocks(strings.ToLower(text)) {
+		if block.han {
+			tokens = append(tokens, s.cutBlock([]rune(block.text))...)
+		}
+	}
+	return tokens
+}
+
+// cutBlock 对连续的汉字/字母数字块进行最大概率切分
+func (s *Segmenter) cutBlock(runes []rune) []string {
+	n := len(runes)
+	if n == 0 {
+		return nil
+	}
+
+	dag := s.buildDAG(runes)
+
+	// route[i] = (从 i 开始的最大对数概率, 第一个词的结束位置)
+	logProb := make([]float64, n+1)
+	next := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		best := math.Inf(-1)
+		bestEnd := i
+		for _, end := range dag[i] {
+			freq, _ := s.lookup(string(runes[i : end+1]))
+			if freq <= 0 {
+				freq = 1
+			}
+			p := math.Log(freq) - s.logTotal + logProb[end+1]
+			if p > best {
+				best = p
+				bestEnd = end
+			}
+		}
+		logProb[i] = best
+		next[i] = bestEnd
+	}
+
+	var tokens []string
+	var buf []rune // 合并连续的单个字母数字
+	flush := func() {
+		if len(buf) > 0 {
+			tokens = append(tokens, string(buf))
+			buf = buf[:0]
+		}
+	}
+	for i := 0; i < n; {
+		end := next[i] + 1
+		if end-i == 1 && isAlnum(runes[i]) {
+			buf = append(buf, runes[i])
+		} else {
+			flush()
+			tokens = append(tokens, string(runes[i:end]))
+		}
+		i = end
+	}
+	flush()
+	return tokens
+}
+
+// buildDAG 构建切分有向无环图，dag[i] 为以 i 开头的所有词的结束位置
+func (s *Segmenter) buildDAG(runes []rune) [][]int {
+	n := len(runes)
+	dag := make([][]int, n)
+	for k := 0; k < n; k++ {
+		var ends []int
+		for i := k; i < n; i++ {
+			freq, ok := s.lookup(string(runes[k : i+1]))
+			if !ok {
+				break
+			}
+			if freq > 0 {
+				ends = append(ends, i)
+			}
+		}
+		if len(ends) == 0 {
+			ends = []int{k}
+		}
+		dag[k] = ends
+	}
+	return dag
+}
+
+type block struct {
+	text string
+	han  bool
+}
+
+// splitBlocks 将文本切分为可分词块（汉字、字母、数字及少量连接符）和其他字符块
+func splitBlocks(text string) []block {
+	var blocks []block
+	var b strings.Builder
+	current := false
+	for _, r := range text {
+		isHan := isSegmentRune(r)
+		if b.Len() > 0 && isHan != current {
+			blocks = append(blocks, block{text: b.String(), han: current})
+			b.Reset()
+		}
+		current = isHan
+		b.WriteRune(r)
+	}
+	if b.Len() > 0 {
+		blocks = append(blocks, block{text: b.String(), han: current})
+	}
+	return blocks
+}
+
+func isSegmentRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) || isAlnum(r) || r == '+' || r == '#' || r == '&' || r == '.' || r == '_' || r == '-'
+}
+
+func isAlnum(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,121 @@
+package segment
+
+import "testing"
+
+func TestSegmenterCut(t *testing.T) {
+	userWords := []string{"小米汽车", "SU7", "雷军", "比亚迪 汉", " "}
+
+	cases := []struct {
+		name      string
+		userWords []string
+		text      string
+		want      []string
+	}{
+		{
+			name: "builtin dictionary",
+			text: "小米汽车发布会",
+			want: []string{"小米", "汽车", "发布会"},
+		},
+		{
+			name:      "user word kept whole",
+			userWords: userWords,
+			text:      "小米汽车发布会",
+			want:      []string{"小米汽车", "发布会"},
+		},
+		{
+			name:      "user words across punctuation",
+			userWords: userWords,
+			text:      "雷军说：小米汽车YYDS！",
+			want:      []string{"雷军", "说", "小米汽车", "yyds"},
+		},
+		{
+			name: "letters and digits merged and lowercased",
+			text: "iPhone15 Pro很好用",
+			want: []string{"iphone15", "pro", "很", "好用"},
+		},
+		{
+			name:      "user word with space is ignored",
+			userWords: userWords,
+			text:      "比亚迪汉很好",
+			want:      []string{"比亚迪", "汉", "很", "好"},
+		},
+		{
+			name: "punctuation only",
+			text: "，。！",
+			want: nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := NewSegmenter(nil, c.userWords).Cut(c.text)
+			if !equalTokens(got, c.want) {
+				t.Errorf("Cut(%q) = %q, want %q", c.text, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSegmenterIsUserWord(t *testing.T) {
+	s := NewSegmenter(nil, []string{"小米汽车", "SU7", "比亚迪 汉"})
+
+	cases := []struct {
+		word string
+		want bool
+	}{
+		{word: "小米汽车", want: true},
+		{word: "su7", want: true},
+		{word: "小米", want: false}, // 用户词的前缀不是用户词
+		{word: "比亚迪 汉", want: false},
+	}
+	for _, c := range cases {
+		if got := s.IsUserWord(c.word); got != c.want {
+			t.Errorf("IsUserWord(%q) = %v, want %v", c.word, got, c.want)
+		}
+	}
+}
+
+func TestSegmenterExtract(t *testing.T) {
+	const text = "小米汽车发布会上，雷军介绍了小米汽车的续航和价格。网友认为小米汽车的价格很有竞争力。"
+	base := NewSegmenter(nil, nil)
+	user := NewSegmenter(nil, []string{"小米汽车", "雷军"})
+
+	cases := []struct {
+		name      string
+		segmenter *Segmenter
+		text      string
+		method    string
+		topK      int
+		want      []string
+	}{
+		{name: "tfidf", segmenter: base, text: text, method: MethodTFIDF, topK: 3, want: []string{"小米", "汽车", "价格"}},
+		{name: "tfidf with user word", segmenter: user, text: text, method: MethodTFIDF, topK: 3, want: []string{"小米汽车", "价格", "发布会"}},
+		{name: "unknown method falls back to tfidf", segmenter: user, text: text, method: "unknown", topK: 2, want: []string{"小米汽车", "价格"}},
+		{name: "textrank", segmenter: user, text: text, method: MethodTextRank, topK: 2, want: []string{"小米汽车", "价格"}},
+		{name: "stopwords only", segmenter: user, text: "的了是", method: MethodTFIDF, topK: 5, want: nil},
+		{name: "textrank punctuation only", segmenter: user, text: "，。", method: MethodTextRank, topK: 5, want: nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			keywords := c.segmenter.Extract(c.text, c.topK, c.method)
+			got := make([]string, 0, len(keywords))
+			for _, keyword := range keywords {
+				got = append(got, keyword.Word)
+			}
+			if !equalTokens(got, c.want) {
+				t.Errorf("Extract(%s) = %q, want %q", c.method, got, c.want)
+			}
+		})
+	}
+}
+
+func equalTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
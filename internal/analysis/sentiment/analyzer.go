@@ -0,0 +1,258 @@
+// Package sentiment 提供基于词典规则的中文情感分析。
+//
+// 分析过程：按标点切分子句，在子句内使用正向最大匹配识别情感词、否定词、
+// 程度副词和表情，情感词的得分受其前面的否定词（奇数个翻转极性）和程度副词
+// （倍数相乘）修饰，感叹号结尾的子句得分加强。整句得分经 tanh 归一化到 (-1, 1)。
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Label 情感标签
+type Label string
+
+const (
+	// LabelPositive 正面
+	LabelPositive Label = "positive"
+	// LabelNeutral 中性
+	LabelNeutral Label = "neutral"
+	// LabelNegative 负面
+	LabelNegative Label = "negative"
+)
+
+// DefaultThreshold 默认正负面判定阈值，|score| 小于该值判定为中性
+const DefaultThreshold = 0.2
+
+// negationWeight 否定词作用于情感词时的衰减系数，"不好" 的负面程度弱于 "差"
+const negationWeight = 0.8
+
+// exclamationWeight 感叹号结尾子句的加强系数
+const exclamationWeight = 1.3
+
+// modifierWindow 否定词/程度副词的作用范围（字符数），超出后修饰失效
+const modifierWindow = 3
+
+// IsValidLabel 判断情感标签是否合法
+func IsValidLabel(label string) bool {
+	switch Label(label) {
+	case LabelPositive, LabelNeutral, LabelNegative:
+		return true
+	}
+	return false
+}
+
+// Result 情感分析结果
+type Result struct {
+	Score    float64  `json:"score"`    // 归一化得分 (-1, 1)
+	Label    Label    `json:"label"`    // 情感标签
+	Positive float64  `json:"positive"` // 正面得分累计
+	Negative float64  `json:"negative"` // 负面得分累计（绝对值）
+	Words    []string `json:"words"`    // 命中的情感词
+}
+
+// Analyzer 基于词典的情感分析器，创建后只读，可并发使用
+type Analyzer struct {
+	lexicon   *Lexicon
+	threshold float64
+}
+
+// NewAnalyzer 创建情感分析器，lexicon 为 nil 时使用内置词典
+func NewAnalyzer(lexicon *Lexicon) *Analyzer {
+	if lexicon == nil {
+		lexicon = DefaultLexicon()
+	}
+	return &Analyzer{
+		lexicon:   lexicon,
+		threshold: DefaultThreshold,
+	}
+}
+
+// WithThreshold 设置正负面判定阈值
+func (a *Analyzer) WithThreshold(threshold float64) *Analyzer {
+	if threshold > 0 && threshold < 1 {
+		a.threshold = threshold
+	}
+	return a
+}
+
+// Analyze 分析文本情感
+func (a *Analyzer) Analyze(text string) Result {
+	var result Result
+	for _, clause := range splitClauses(strings.ToLower(text)) {
+		pos, neg, words := a.scoreClause(clause.text)
+		if clause.exclaim {
+			pos *= exclamationWeight
+			neg *= exclamationWeight
+		}
+		result.Positive += pos
+		result.Negative += neg
+		result.Words = append(result.Words, words...)
+	}
+
+	total := result.Positive - result.Negative
+	result.Score = round4(math.Tanh(total / 2))
+	result.Positive = round4(result.Positive)
+	result.Negative = round4(result.Negative)
+	result.Label = a.label(result.Score)
+	return result
+}
+
+func (a *Analyzer) label(score float64) Label {
+	switch {
+	case score >= a.threshold:
+		return LabelPositive
+	case score <= -a.threshold:
+		return LabelNegative
+	default:
+		return LabelNeutral
+	}
+}
+
+// scoreClause 计算单个子句的正负面得分
+func (a *Analyzer) scoreClause(clause string) (float64, float64, []string) {
+	var pos, neg float64
+	var words []string
+
+	negations := 0
+	degree := 1.0
+	modifierEnd := 0 // 最近一个修饰词结束的位置
+
+	runes := []rune(clause)
+	for i := 0; i < len(runes); {
+		word, wordType, weight, ok := a.match(runes, i)
+		if !ok {
+			i++
+			continue
+		}
+		start := i
+		i += len([]rune(word))
+
+		if start-modifierEnd > modifierWindow {
+			negations = 0
+			degree = 1.0
+		}
+
+		switch wordType {
+		case WordTypeNegation:
+			negations++
+			modifierEnd = i
+		case WordTypeDegree:
+			degree *= weight
+			modifierEnd = i
+		case WordTypeSentiment:
+			if weight == 0 {
+				// 被屏蔽的词，不计分也不影响修饰状态
+				continue
+			}
+			value := weight
+			// 表情不受否定词和程度副词修饰
+			if !isEmoji(word) {
+				value *= degree
+				if negations%2 == 1 {
+					value = -value * negationWeight
+				}
+			}
+			if value > 0 {
+				pos += value
+			} else {
+				neg += -value
+			}
+			words = append(words, word)
+			negations = 0
+			degree = 1.0
+		}
+	}
+	return pos, neg, words
+}
+
+// match 在位置 i 处进行正向最大匹配
+func (a *Analyzer) match(runes []rune, i int) (string, WordType, float64, bool) {
+	maxLen := a.lexicon.maxLen
+	if rest := len(runes) - i; rest < maxLen {
+		maxLen = rest
+	}
+	for n := maxLen; n >= 1; n-- {
+		word := string(runes[i : i+n])
+		if wordType, weight, ok := a.lexicon.lookup(word); ok {
+			// 英文词需要完整单词匹配，避免 "good" 命中 "goodbye"
+			if isASCIIWord(word) && !isWordBoundary(runes, i, i+n) {
+				continue
+			}
+			return word, wordType, weight, true
+		}
+	}
+	return "", "", 0, false
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
+
+type clause struct {
+	text    string
+	exclaim bool
+}
+
+// splitClauses 按标点切分子句，并记录子句是否以感叹号结尾
+func splitClauses(text string) []clause {
+	var clauses []clause
+	var b strings.Builder
+	flush := func(exclaim bool) {
+		if b.Len() > 0 {
+			clauses = append(clauses, clause{text: b.String(), exclaim: exclaim})
+			b.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch r {
+		case '！', '!':
+			flush(true)
+		case '，', ',', '。', '？', '?', '；', ';', '\n', '\r', '…', '~', '～':
+			flush(false)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush(false)
+	return clauses
+}
+
+// isEmoji 判断是否为表情：Unicode 表情符号或 [xx] 形式的平台表情
+func isEmoji(word string) bool {
+	if strings.HasPrefix(word, "[") && strings.HasSuffix(word, "]") {
+		return true
+	}
+	for _, r := range word {
+		if unicode.Is(unicode.So, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCIIWord(word string) bool {
+	for _, r := range word {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordBoundary(runes []rune, start, end int) bool {
+	if start > 0 && isASCIILetter(runes[start-1]) {
+		return false
+	}
+	if end < len(runes) && isASCIILetter(runes[end]) {
+		return false
+	}
+	return true
+}
+
+func isASCIILetter(r rune) bool {
+	return r <= unicode.MaxASCII && unicode.IsLetter(r)
+}
@@ -0,0 +1,136 @@
+package sentiment
+
+import "testing"
+
+// analyzeCase 一条文本的期望分析结果
+type analyzeCase struct {
+	name  string
+	text  string
+	score float64
+	label Label
+	words []string
+}
+
+func runAnalyzeCases(t *testing.T, analyzer *Analyzer, cases []analyzeCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := analyzer.Analyze(c.text)
+			if got.Score != c.score {
+				t.Errorf("Analyze(%q).Score = %v, want %v", c.text, got.Score, c.score)
+			}
+			if got.Label != c.label {
+				t.Errorf("Analyze(%q).Label = %q, want %q", c.text, got.Label, c.label)
+			}
+			if !equalWords(got.Words, c.words) {
+				t.Errorf("Analyze(%q).Words = %v, want %v", c.text, got.Words, c.words)
+			}
+		})
+	}
+}
+
+func TestAnalyzerNegation(t *testing.T) {
+	runAnalyzeCases(t, NewAnalyzer(nil), []analyzeCase{
+		{name: "plain", text: "推荐", score: 0.4621, label: LabelPositive, words: []string{"推荐"}},
+		{name: "negated and weakened", text: "不推荐", score: -0.3799, label: LabelNegative, words: []string{"推荐"}},
+		{name: "double negation", text: "并非不推荐", score: 0.4621, label: LabelPositive, words: []string{"推荐"}},
+		{name: "negation with degree", text: "不会很好", score: -0.537, label: LabelNegative, words: []string{"好"}},
+		{name: "negation word wins over single char", text: "不太好", score: -0.3799, label: LabelNegative, words: []string{"好"}},
+		{name: "does not cross clauses", text: "不，推荐", score: 0.4621, label: LabelPositive, words: []string{"推荐"}},
+		{name: "out of window", text: "不是说这家店的东西推荐", score: 0.4621, label: LabelPositive, words: []string{"推荐"}},
+	})
+}
+
+func TestAnalyzerDegree(t *testing.T) {
+	runAnalyzeCases(t, NewAnalyzer(nil), []analyzeCase{
+		{name: "plain", text: "好", score: 0.4621, label: LabelPositive, words: []string{"好"}},
+		{name: "amplified", text: "很好", score: 0.6351, label: LabelPositive, words: []string{"好"}},
+		{name: "weakened", text: "有点好", score: 0.3799, label: LabelPositive, words: []string{"好"}},
+		{name: "weakened further", text: "稍微好", score: 0.2913, label: LabelPositive, words: []string{"好"}},
+		{name: "amplified negative word", text: "非常不满意", score: -0.7163, label: LabelNegative, words: []string{"不满意"}},
+		{name: "longest match keeps phrase", text: "太棒了", score: 0.7616, label: LabelPositive, words: []string{"太棒了"}},
+		{name: "exclamation", text: "好！", score: 0.5717, label: LabelPositive, words: []string{"好"}},
+	})
+}
+
+func TestAnalyzerEmoji(t *testing.T) {
+	runAnalyzeCases(t, NewAnalyzer(nil), []analyzeCase{
+		{name: "unicode emoji", text: "👍", score: 0.4621, label: LabelPositive, words: []string{"👍"}},
+		{name: "platform emoji", text: "[怒]", score: -0.6351, label: LabelNegative, words: []string{"[怒]"}},
+		{name: "emoji ignores negation", text: "不👍", score: 0.4621, label: LabelPositive, words: []string{"👍"}},
+		{name: "emoji ignores degree", text: "很😡", score: -0.7616, label: LabelNegative, words: []string{"😡"}},
+		{name: "emoji adds to words", text: "服务好👍", score: 0.7616, label: LabelPositive, words: []string{"服务好", "👍"}},
+	})
+}
+
+func TestAnalyzerLexiconOverrides(t *testing.T) {
+	// 与场景分析器相同的叠加顺序：内置词典 -> 全局词条 -> 场景词条，后设置的覆盖前面的
+	type entry struct {
+		word     string
+		wordType WordType
+		weight   float64
+	}
+	global := []entry{
+		{word: "爆款", wordType: WordTypeSentiment, weight: 1},
+		{word: "问题", wordType: WordTypeSentiment, weight: 0},
+	}
+	scenario := []entry{
+		{word: "炸裂", wordType: WordTypeSentiment, weight: 2},
+		{word: "爆款", wordType: WordTypeSentiment, weight: -1},
+		{word: "贼", wordType: WordTypeDegree, weight: 2},
+	}
+
+	base := DefaultLexicon()
+	globalLexicon := base.Clone()
+	for _, e := range global {
+		globalLexicon.Set(e.word, e.wordType, e.weight)
+	}
+	scenarioLexicon := globalLexicon.Clone()
+	for _, e := range scenario {
+		scenarioLexicon.Set(e.word, e.wordType, e.weight)
+	}
+
+	cases := []struct {
+		name     string
+		lexicon  *Lexicon
+		text     string
+		label    Label
+		words    []string
+		positive float64
+	}{
+		{name: "builtin lacks word", lexicon: base, text: "这款车型很炸裂", label: LabelNeutral},
+		{name: "scenario adds word", lexicon: scenarioLexicon, text: "这款车型很炸裂", label: LabelPositive, words: []string{"炸裂"}, positive: 3},
+		{name: "global adds word", lexicon: globalLexicon, text: "爆款", label: LabelPositive, words: []string{"爆款"}, positive: 1},
+		{name: "scenario overrides global", lexicon: scenarioLexicon, text: "爆款", label: LabelNegative, words: []string{"爆款"}},
+		{name: "builtin negative word", lexicon: base, text: "这个问题", label: LabelNegative, words: []string{"问题"}},
+		{name: "weight zero blocks builtin word", lexicon: globalLexicon, text: "这个问题", label: LabelNeutral},
+		{name: "scenario adds degree", lexicon: scenarioLexicon, text: "贼好", label: LabelPositive, words: []string{"好"}, positive: 2},
+		{name: "degree not leaked to base", lexicon: base, text: "贼好", label: LabelPositive, words: []string{"好"}, positive: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := NewAnalyzer(c.lexicon).Analyze(c.text)
+			if got.Label != c.label {
+				t.Errorf("Analyze(%q).Label = %q, want %q", c.text, got.Label, c.label)
+			}
+			if !equalWords(got.Words, c.words) {
+				t.Errorf("Analyze(%q).Words = %v, want %v", c.text, got.Words, c.words)
+			}
+			if got.Positive != c.positive {
+				t.Errorf("Analyze(%q).Positive = %v, want %v", c.text, got.Positive, c.positive)
+			}
+		})
+	}
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package sentiment
+
+// 内置词典：覆盖常见的中文网络舆情表达，可通过自定义词条覆盖或屏蔽
+
+var builtinPositive = []string{
+	"好", "好用", "好看", "好吃", "好评", "不错", "满意", "喜欢", "支持", "推荐",
+	"优秀", "靠谱", "实惠", "划算", "方便", "舒服", "舒适", "漂亮", "美观", "精致",
+	"稳定", "流畅", "快速", "及时", "耐心", "热情", "专业", "贴心", "周到", "友好",
+	"放心", "安心", "开心", "高兴", "愉快", "感谢", "谢谢", "点赞", "给力", "优惠",
+	"清晰", "干净", "新鲜", "正品", "值得", "超值", "性价比高", "质量好", "服务好",
+	"认可", "期待", "惊喜", "赞", "棒", "酷", "牛", "厉害", "成功", "进步",
+	"提升", "改善", "创新", "领先", "可靠", "安全", "健康", "温暖", "感动", "幸福",
+	"顺利", "高效", "便宜", "好感", "yyds", "nice", "good", "great", "like", "love",
+}
+
+var builtinStrongPositive = []string{
+	"完美", "超赞", "太棒了", "非常满意", "强烈推荐", "五星好评", "无可挑剔", "爱了", "绝了", "神器",
+	"惊艳", "一流", "卓越", "良心", "回购", "必买", "excellent", "perfect", "amazing", "awesome",
+}
+
+var builtinNegative = []string{
+	"差", "差评", "不好", "难用", "难看", "难吃", "失望", "不满", "不满意", "讨厌",
+	"问题", "故障", "毛病", "缺陷", "卡顿", "慢", "贵", "坑", "坑人",
+	"退货", "退款", "投诉", "维权", "质疑", "担心", "担忧", "焦虑", "生气", "愤怒",
+	"不爽", "郁闷", "糟糕", "麻烦", "敷衍", "推诿", "拖延", "虚假", "夸大", "误导",
+	"劣质", "残次", "破损", "漏洞", "风险", "隐患", "危险", "事故", "召回", "下架",
+	"亏损", "裁员", "倒闭", "暴雷", "违规", "违法", "处罚", "罚款", "被罚", "翻车",
+	"吐槽", "后悔", "无语", "崩溃", "崩了", "垃圾", "智商税", "割韭菜", "避雷", "踩雷",
+	"bad", "poor", "hate", "worst", "terrible",
+}
+
+var builtinStrongNegative = []string{
+	"骗子", "诈骗", "欺诈", "黑心", "恶心", "极差", "非常失望", "太差了", "千万别买", "坑爹",
+	"曝光", "造假", "有毒", "致癌", "死亡", "爆炸", "起火", "丑闻", "维权无门", "霸王条款",
+}
+
+var builtinEmoji = map[string]float64{
+	"😀": 1, "😁": 1, "😂": 0.5, "😊": 1, "😍": 2, "🥰": 2, "👍": 1, "👏": 1, "❤": 1, "❤️": 1,
+	"💯": 1.5, "🎉": 1, "😄": 1, "😃": 1, "🤩": 2, "🙏": 0.5,
+	"😡": -2, "😠": -1.5, "🤬": -2, "😤": -1, "😞": -1, "😢": -1, "😭": -1, "👎": -1.5, "💔": -1, "🙄": -1,
+	"😓": -0.5, "😒": -1, "🤮": -2, "💩": -1.5,
+	"[赞]": 1, "[good]": 1, "[哈哈]": 0.5, "[爱你]": 1.5, "[心]": 1, "[鼓掌]": 1, "[微笑]": 0.5, "[开心]": 1, "[送花花]": 1,
+	"[怒]": -1.5, "[怒骂]": -2, "[衰]": -1, "[泪]": -1, "[悲伤]": -1, "[鄙视]": -1.5, "[吐]": -1.5, "[弱]": -1, "[抓狂]": -1.5, "[哼]": -0.5,
+}
+
+var builtinNegations = []string{
+	"不", "没", "没有", "无", "非", "未", "别", "莫", "勿", "不是", "并非", "毫无", "绝非", "不太", "不够", "从未", "从不", "不会", "不能", "难以",
+}
+
+var builtinDegrees = map[string]float64{
+	"极其": 2, "极度": 2, "极": 2, "超级": 2, "超": 1.8, "最": 2, "太": 1.8, "特别": 1.8, "非常": 1.8, "十分": 1.8,
+	"相当": 1.5, "很": 1.5, "挺": 1.3, "蛮": 1.3, "真": 1.5, "真的": 1.5, "好不": 1.8, "更": 1.3, "更加": 1.3, "越来越": 1.3,
+	"有点": 0.8, "有些": 0.8, "稍微": 0.6, "略": 0.6, "略微": 0.6, "一点": 0.6, "比较": 1.2, "还算": 0.8, "还": 0.9,
+}
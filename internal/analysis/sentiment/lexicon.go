@@ -0,0 +1,150 @@
+package sentiment
+
+import "strings"
+
+// WordType 词典条目类型
+type WordType string
+
+const (
+	// WordTypeSentiment 情感词，权重为正表示正面，为负表示负面，为 0 表示屏蔽该词
+	WordTypeSentiment WordType = "sentiment"
+	// WordTypeNegation 否定词，翻转其后情感词的极性
+	WordTypeNegation WordType = "negation"
+	// WordTypeDegree 程度副词，权重为对其后情感词的放大/缩小倍数
+	WordTypeDegree WordType = "degree"
+)
+
+// Lexicon 情感词典，包含情感词、否定词和程度副词
+type Lexicon struct {
+	sentiments map[string]float64
+	negations  map[string]bool
+	degrees    map[string]float64
+	maxLen     int // 最长词条的字符数，用于正向最大匹配
+}
+
+// NewLexicon 创建空词典
+func NewLexicon() *Lexicon {
+	return &Lexicon{
+		sentiments: make(map[string]float64),
+		negations:  make(map[string]bool),
+		degrees:    make(map[string]float64),
+	}
+}
+
+// DefaultLexicon 创建包含内置中文情感词典的词典
+func DefaultLexicon() *Lexicon {
+	l := NewLexicon()
+	for _, w := range builtinPositive {
+		l.SetSentiment(w, 1)
+	}
+	for _, w := range builtinStrongPositive {
+		l.SetSentiment(w, 2)
+	}
+	for _, w := range builtinNegative {
+		l.SetSentiment(w, -1)
+	}
+	for _, w := range builtinStrongNegative {
+		l.SetSentiment(w, -2)
+	}
+	for w, score := range builtinEmoji {
+		l.SetSentiment(w, score)
+	}
+	for _, w := range builtinNegations {
+		l.SetNegation(w)
+	}
+	for w, degree := range builtinDegrees {
+		l.SetDegree(w, degree)
+	}
+	return l
+}
+
+// Clone 复制词典，用于在内置词典基础上叠加自定义词条
+func (l *Lexicon) Clone() *Lexicon {
+	c := NewLexicon()
+	for w, v := range l.sentiments {
+		c.sentiments[w] = v
+	}
+	for w := range l.negations {
+		c.negations[w] = true
+	}
+	for w, v := range l.degrees {
+		c.degrees[w] = v
+	}
+	c.maxLen = l.maxLen
+	return c
+}
+
+// Set 按类型设置词条，同一个词只保留最后一次设置的类型
+func (l *Lexicon) Set(word string, wordType WordType, weight float64) {
+	switch wordType {
+	case WordTypeNegation:
+		l.SetNegation(word)
+	case WordTypeDegree:
+		l.SetDegree(word, weight)
+	default:
+		l.SetSentiment(word, weight)
+	}
+}
+
+// SetSentiment 设置情感词权重
+func (l *Lexicon) SetSentiment(word string, weight float64) {
+	word = normalizeWord(word)
+	if word == "" {
+		return
+	}
+	l.remove(word)
+	l.sentiments[word] = weight
+	l.updateMaxLen(word)
+}
+
+// SetNegation 设置否定词
+func (l *Lexicon) SetNegation(word string) {
+	word = normalizeWord(word)
+	if word == "" {
+		return
+	}
+	l.remove(word)
+	l.negations[word] = true
+	l.updateMaxLen(word)
+}
+
+// SetDegree 设置程度副词倍数
+func (l *Lexicon) SetDegree(word string, degree float64) {
+	word = normalizeWord(word)
+	if word == "" || degree <= 0 {
+		return
+	}
+	l.remove(word)
+	l.degrees[word] = degree
+	l.updateMaxLen(word)
+}
+
+// lookup 查询词条类型
+func (l *Lexicon) lookup(word string) (WordType, float64, bool) {
+	if v, ok := l.sentiments[word]; ok {
+		return WordTypeSentiment, v, true
+	}
+	if l.negations[word] {
+		return WordTypeNegation, 0, true
+	}
+	if v, ok := l.degrees[word]; ok {
+		return WordTypeDegree, v, true
+	}
+	return "", 0, false
+}
+
+func (l *Lexicon) remove(word string) {
+	delete(l.sentiments, word)
+	delete(l.negations, word)
+	delete(l.degrees, word)
+}
+
+func (l *Lexicon) updateMaxLen(word string) {
+	if n := len([]rune(word)); n > l.maxLen {
+		l.maxLen = n
+	}
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
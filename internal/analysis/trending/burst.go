@@ -0,0 +1,79 @@
+// Package trending 提供热词突发检测和舆情话题聚类
+package trending
+
+import (
+	"math"
+	"sort"
+)
+
+// minStdDev 基线标准差下限，避免基线几乎为零时得分被无限放大
+const minStdDev = 1.0
+
+// Burst 突发词
+type Burst struct {
+	Term   string  `json:"term"`
+	Count  int     `json:"count"`   // 当前窗口内出现该词的舆情数
+	Mean   float64 `json:"mean"`    // 基线窗口的平均值
+	StdDev float64 `json:"std_dev"` // 基线窗口的标准差
+	Score  float64 `json:"score"`   // z-score
+}
+
+// DetectBursts 将当前窗口的词频与基线窗口比较，按 z-score 从高到低返回突发词
+// 只保留当前窗口出现次数不少于 minCount 且高于基线平均值的词
+func DetectBursts(current map[string]int, baseline []map[string]int, minCount int) []Burst {
+	bursts := make([]Burst, 0)
+	for term, count := range current {
+		if count < minCount {
+			continue
+		}
+
+		mean, std := meanStdDev(term, baseline)
+		if float64(count) <= mean {
+			continue
+		}
+
+		bursts = append(bursts, Burst{
+			Term:   term,
+			Count:  count,
+			Mean:   round4(mean),
+			StdDev: round4(std),
+			Score:  round4((float64(count) - mean) / math.Max(std, minStdDev)),
+		})
+	}
+
+	sort.Slice(bursts, func(i, j int) bool {
+		if bursts[i].Score != bursts[j].Score {
+			return bursts[i].Score > bursts[j].Score
+		}
+		if bursts[i].Count != bursts[j].Count {
+			return bursts[i].Count > bursts[j].Count
+		}
+		return bursts[i].Term < bursts[j].Term
+	})
+	return bursts
+}
+
+// meanStdDev 计算词语在基线各窗口中出现次数的平均值和标准差，未出现的窗口记为 0
+func meanStdDev(term string, baseline []map[string]int) (float64, float64) {
+	if len(baseline) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, window := range baseline {
+		sum += float64(window[term])
+	}
+	mean := sum / float64(len(baseline))
+
+	var variance float64
+	for _, window := range baseline {
+		d := float64(window[term]) - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / float64(len(baseline)))
+}
+
+// round4 保留四位小数
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
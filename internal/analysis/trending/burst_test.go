@@ -0,0 +1,75 @@
+package trending
+
+import "testing"
+
+func TestDetectBursts(t *testing.T) {
+	baseline := []map[string]int{
+		{"小米": 2, "手机": 5, "发布会": 5},
+		{"小米": 4, "手机": 5, "发布会": 5},
+		{"手机": 5, "发布会": 5},
+	}
+
+	cases := []struct {
+		name     string
+		current  map[string]int
+		baseline []map[string]int
+		minCount int
+		want     []Burst
+	}{
+		{
+			name:     "ranked by z-score",
+			current:  map[string]int{"小米": 8, "手机": 9, "召回": 3},
+			baseline: baseline,
+			minCount: 2,
+			want: []Burst{
+				{Term: "手机", Count: 9, Mean: 5, StdDev: 0, Score: 4}, // 基线标准差为 0 时按下限 1 计算
+				{Term: "小米", Count: 8, Mean: 2, StdDev: 1.633, Score: 3.6742},
+				{Term: "召回", Count: 3, Mean: 0, StdDev: 0, Score: 3},
+			},
+		},
+		{
+			name:     "below min count",
+			current:  map[string]int{"召回": 1},
+			baseline: baseline,
+			minCount: 2,
+			want:     []Burst{},
+		},
+		{
+			name:     "not above baseline mean",
+			current:  map[string]int{"发布会": 5, "小米": 2},
+			baseline: baseline,
+			minCount: 1,
+			want:     []Burst{},
+		},
+		{
+			name:     "ties broken by count then term",
+			current:  map[string]int{"降价": 2, "涨价": 2, "停产": 3},
+			baseline: []map[string]int{{"停产": 1}},
+			minCount: 1,
+			want: []Burst{
+				{Term: "停产", Count: 3, Mean: 1, StdDev: 0, Score: 2},
+				{Term: "涨价", Count: 2, Mean: 0, StdDev: 0, Score: 2},
+				{Term: "降价", Count: 2, Mean: 0, StdDev: 0, Score: 2},
+			},
+		},
+		{
+			name:     "no baseline",
+			current:  map[string]int{"召回": 2},
+			minCount: 1,
+			want:     []Burst{{Term: "召回", Count: 2, Mean: 0, StdDev: 0, Score: 2}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := DetectBursts(c.current, c.baseline, c.minCount)
+			if len(got) != len(c.want) {
+				t.Fatalf("DetectBursts() = %+v, want %+v", got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("DetectBursts()[%d] = %+v, want %+v", i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package trending
+
+import (
+	"math"
+	"sort"
+)
+
+// Document 参与聚类的文档，Terms 为关键词及权重
+type Document struct {
+	ID    uint64
+	Terms map[string]float64
+}
+
+// Term 带权重的词语
+type Term struct {
+	Word   string  `json:"word"`
+	Weight float64 `json:"weight"`
+}
+
+// Cluster 聚类结果
+type Cluster struct {
+	Terms   []Term   // 按权重降序的中心词
+	Members []uint64 // 按与中心相似度降序的成员文档
+}
+
+// ClusterOptions 聚类参数
+type ClusterOptions struct {
+	Threshold float64 // 加入已有话题所需的最小余弦相似度
+	MinSize   int     // 话题最少包含的文档数
+	MaxTerms  int     // 每个话题保留的中心词数量
+}
+
+type cluster struct {
+	centroid map[string]float64
+	norm     float64
+	docs     []Document
+}
+
+// ClusterDocuments 按关键词向量的余弦相似度对文档做单遍聚类：
+// 每篇文档加入相似度最高且不低于阈值的话题，否则新建话题；话题中心为成员向量之和。
+// 返回的话题按规模从大到小排列
+func ClusterDocuments(docs []Document, opts ClusterOptions) []Cluster {
+	clusters := make([]*cluster, 0)
+	for _, doc := range docs {
+		vec := normalize(doc.Terms)
+		if len(vec) == 0 {
+			continue
+		}
+
+		var best *cluster
+		bestSim := opts.Threshold
+		for _, c := range clusters {
+			if sim := cosine(vec, 1, c.centroid, c.norm); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+
+		if best == nil {
+			best = &cluster{centroid: make(map[string]float64)}
+			clusters = append(clusters, best)
+		}
+		for term, weight := range vec {
+			best.centroid[term] += weight
+		}
+		best.norm = vectorNorm(best.centroid)
+		best.docs = append(best.docs, Document{ID: doc.ID, Terms: vec})
+	}
+
+	result := make([]Cluster, 0)
+	for _, c := range clusters {
+		if len(c.docs) < opts.MinSize {
+			continue
+		}
+		result = append(result, Cluster{
+			Terms:   topTerms(c.centroid, len(c.docs), opts.MaxTerms),
+			Members: rankMembers(c),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].Members) > len(result[j].Members)
+	})
+	return result
+}
+
+// rankMembers 按与话题中心的相似度对成员排序
+func rankMembers(c *cluster) []uint64 {
+	sims := make(map[uint64]float64, len(c.docs))
+	for _, doc := range c.docs {
+		sims[doc.ID] = cosine(doc.Terms, 1, c.centroid, c.norm)
+	}
+
+	members := make([]uint64, 0, len(c.docs))
+	for _, doc := range c.docs {
+		members = append(members, doc.ID)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return sims[members[i]] > sims[members[j]]
+	})
+	return members
+}
+
+// topTerms 返回话题中心权重最高的词语，权重为成员平均值
+func topTerms(centroid map[string]float64, size, limit int) []Term {
+	terms := make([]Term, 0, len(centroid))
+	for word, weight := range centroid {
+		terms = append(terms, Term{Word: word, Weight: round4(weight / float64(size))})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Weight != terms[j].Weight {
+			return terms[i].Weight > terms[j].Weight
+		}
+		return terms[i].Word < terms[j].Word
+	})
+	if limit > 0 && len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+// normalize 将向量归一化为单位长度
+func normalize(terms map[string]float64) map[string]float64 {
+	norm := vectorNorm(terms)
+	if norm == 0 {
+		return nil
+	}
+	vec := make(map[string]float64, len(terms))
+	for term, weight := range terms {
+		if weight > 0 {
+			vec[term] = weight / norm
+		}
+	}
+	return vec
+}
+
+// vectorNorm 计算向量长度
+func vectorNorm(vec map[string]float64) float64 {
+	var sum float64
+	for _, weight := range vec {
+		if weight > 0 {
+			sum += weight * weight
+		}
+	}
+	return math.Sqrt(sum)
+}
+
+// cosine 计算两个向量的余弦相似度
+func cosine(a map[string]float64, normA float64, b map[string]float64, normB float64) float64 {
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot / (normA * normB)
+}
@@ -0,0 +1,84 @@
+package trending
+
+import "testing"
+
+func TestClusterDocuments(t *testing.T) {
+	docs := []Document{
+		{ID: 1, Terms: map[string]float64{"小米": 1, "汽车": 1}},
+		{ID: 2, Terms: map[string]float64{"小米": 1, "汽车": 1, "发布会": 1}},
+		{ID: 3, Terms: map[string]float64{"天气": 1}},
+		{ID: 4, Terms: map[string]float64{"小米": 1, "汽车": 0.5}},
+		{ID: 5, Terms: map[string]float64{"天气": 1, "降温": 1}},
+		{ID: 6, Terms: map[string]float64{}}, // 没有关键词的文档不参与聚类
+	}
+
+	cases := []struct {
+		name    string
+		opts    ClusterOptions
+		members [][]uint64
+		terms   [][]string
+	}{
+		{
+			name:    "similar documents grouped, ranked by size",
+			opts:    ClusterOptions{Threshold: 0.5, MinSize: 2, MaxTerms: 2},
+			members: [][]uint64{{1, 4, 2}, {3, 5}},
+			terms:   [][]string{{"小米", "汽车"}, {"天气", "降温"}},
+		},
+		{
+			name:    "small topics dropped",
+			opts:    ClusterOptions{Threshold: 0.5, MinSize: 3, MaxTerms: 1},
+			members: [][]uint64{{1, 4, 2}},
+			terms:   [][]string{{"小米"}},
+		},
+		{
+			name:    "high threshold keeps documents apart",
+			opts:    ClusterOptions{Threshold: 0.95, MinSize: 1, MaxTerms: 1},
+			members: [][]uint64{{1}, {2}, {3}, {4}, {5}},
+			terms:   [][]string{{"小米"}, {"发布会"}, {"天气"}, {"小米"}, {"天气"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := ClusterDocuments(docs, c.opts)
+			if len(got) != len(c.members) {
+				t.Fatalf("ClusterDocuments() returned %d topics, want %d: %+v", len(got), len(c.members), got)
+			}
+			for i, cluster := range got {
+				if !equalIDs(cluster.Members, c.members[i]) {
+					t.Errorf("topic %d members = %v, want %v", i, cluster.Members, c.members[i])
+				}
+				words := make([]string, 0, len(cluster.Terms))
+				for _, term := range cluster.Terms {
+					words = append(words, term.Word)
+				}
+				if !equalWords(words, c.terms[i]) {
+					t.Errorf("topic %d terms = %v, want %v", i, words, c.terms[i])
+				}
+			}
+		})
+	}
+}
+
+func equalIDs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -13,6 +13,25 @@ type Config struct {
 	MySQL  MySQLConfig  `mapstructure:"mysql"`
 	Redis  RedisConfig  `mapstructure:"redis"`
 	Log    LogConfig    `mapstructure:"log"`
+
+	Analysis AnalysisConfig `mapstructure:"analysis"`
+	Segment  SegmentConfig  `mapstructure:"segment"`
+	Trending TrendingConfig `mapstructure:"trending"`
+	Notify   NotifyConfig   `mapstructure:"notify"`
+	Workflow WorkflowConfig `mapstructure:"workflow"`
+	Stats    StatsConfig    `mapstructure:"stats"`
+	Live     LiveConfig     `mapstructure:"live"`
+	Stream   StreamConfig   `mapstructure:"stream"`
+	Report   ReportConfig   `mapstructure:"report"`
+	Export   ExportConfig   `mapstructure:"export"`
+	Import   ImportConfig   `mapstructure:"import"`
+	Search   SearchConfig   `mapstructure:"search"`
+
+	SavedSearch SavedSearchConfig `mapstructure:"saved_search"`
+	Preview     PreviewConfig     `mapstructure:"preview"`
+	KeywordStat KeywordStatConfig `mapstructure:"keyword_stat"`
+	NoiseFilter NoiseFilterConfig `mapstructure:"noise_filter"`
+	Author      AuthorConfig      `mapstructure:"author"`
 }
 
 // ServerConfig 服务器配置
@@ -44,6 +63,161 @@ type LogConfig struct {
 	Level string `mapstructure:"level"`
 }
 
+// AnalysisConfig 舆情分析配置
+type AnalysisConfig struct {
+	Analyzer string             `mapstructure:"analyzer"` // 使用的分析器: builtin, http
+	HTTP     HTTPAnalyzerConfig `mapstructure:"http"`
+}
+
+// HTTPAnalyzerConfig 外部 NLP 服务配置
+type HTTPAnalyzerConfig struct {
+	Endpoint         string `mapstructure:"endpoint"`
+	Token            string `mapstructure:"token"`
+	TimeoutMs        int    `mapstructure:"timeout_ms"`        // 单次请求超时（毫秒）
+	BatchSize        int    `mapstructure:"batch_size"`        // 每次请求的最大文档数
+	FailureThreshold int    `mapstructure:"failure_threshold"` // 连续失败多少次后熔断
+	OpenSeconds      int    `mapstructure:"open_seconds"`      // 熔断持续时间（秒）
+}
+
+// SegmentConfig 分词与关键词提取配置
+type SegmentConfig struct {
+	DictPath      string `mapstructure:"dict_path"`      // 额外加载的词典文件（jieba 格式），为空只使用内置词典
+	KeywordMethod string `mapstructure:"keyword_method"` // 关键词提取方法: tfidf, textrank
+	KeywordTopK   int    `mapstructure:"keyword_top_k"`  // 每条舆情保存的关键词数量
+}
+
+// TrendingConfig 热词和话题检测配置
+type TrendingConfig struct {
+	WindowMinutes      int     `mapstructure:"window_minutes"`       // 热词统计窗口（分钟）
+	BaselineWindows    int     `mapstructure:"baseline_windows"`     // 作为基线的历史窗口数量
+	MinCount           int     `mapstructure:"min_count"`            // 当前窗口最少出现次数
+	TopK               int     `mapstructure:"top_k"`                // 每个场景保留的热词数量
+	TopicWindowMinutes int     `mapstructure:"topic_window_minutes"` // 话题聚类窗口（分钟）
+	TopicThreshold     float64 `mapstructure:"topic_threshold"`      // 归入同一话题的最小余弦相似度
+	TopicMinSize       int     `mapstructure:"topic_min_size"`       // 话题最少包含的舆情数
+}
+
+// NotifyConfig 通知配置
+type NotifyConfig struct {
+	TimeoutMs      int        `mapstructure:"timeout_ms"`       // 单次发送超时（毫秒）
+	RetryAttempts  int        `mapstructure:"retry_attempts"`   // 最多尝试次数（含首次）
+	RetryBackoffMs int        `mapstructure:"retry_backoff_ms"` // 首次重试前的等待时间（毫秒），之后按指数增长
+	SMTP           SMTPConfig `mapstructure:"smtp"`
+}
+
+// WorkflowConfig 舆情处置流程配置
+type WorkflowConfig struct {
+	SLAHours map[string]int `mapstructure:"sla_hours"` // 各优先级的处置时限（小时），分配或调整优先级时据此计算截止时间
+}
+
+// StatsConfig 统计汇总配置
+type StatsConfig struct {
+	RollupLookbackHours int `mapstructure:"rollup_lookback_hours"` // 每次汇总重算最近多少小时（覆盖扫描延迟产生的迟到命中）
+	BackfillDays        int `mapstructure:"backfill_days"`         // 场景首次汇总时回溯的天数
+}
+
+// LiveConfig 监测组实时计数配置
+type LiveConfig struct {
+	RedisTTLHours int `mapstructure:"redis_ttl_hours"` // Redis 中分钟计数的保留时长（小时），需大于落库任务的最长中断时间
+	RetentionDays int `mapstructure:"retention_days"`  // MySQL 中分钟计数的保留天数，落库任务会删除更早的数据
+}
+
+// StreamConfig 实时推送连接配置
+type StreamConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`    // 允许建立 WebSocket 连接的页面来源（如 https://monitor.example.com），为空时只允许与服务同源的页面
+	TicketTTLSeconds int      `mapstructure:"ticket_ttl_seconds"` // 推送连接票据的有效期（秒），票据只能使用一次
+}
+
+// ReportConfig 场景简报配置
+type ReportConfig struct {
+	StorageDir      string `mapstructure:"storage_dir"`       // 简报文件的存储目录
+	BaseURL         string `mapstructure:"base_url"`          // 服务对外访问地址，用于在通知中生成下载链接
+	MaxAttachmentMB int    `mapstructure:"max_attachment_mb"` // 邮件附件总大小上限（MB），超过时只发送下载链接
+}
+
+// ExportConfig 舆情导出配置
+type ExportConfig struct {
+	StorageDir     string `mapstructure:"storage_dir"`     // 异步导出文件的存储目录
+	SyncLimit      int    `mapstructure:"sync_limit"`      // 同步导出（直接下载）的最大行数，超过时需要创建导出任务
+	MaxRows        int    `mapstructure:"max_rows"`        // 单个导出任务的最大行数
+	MaxConcurrent  int    `mapstructure:"max_concurrent"`  // Web 服务中同时执行的导出任务数，超出的任务由导出任务脚本执行
+	RetentionHours int    `mapstructure:"retention_hours"` // 导出文件的保留时长（小时）
+	URLTTLMinutes  int    `mapstructure:"url_ttl_minutes"` // 下载地址的有效期（分钟）
+	SigningKey     string `mapstructure:"signing_key"`     // 下载地址的签名密钥，多实例部署时需保持一致
+}
+
+// ImportConfig 舆情批量导入配置
+type ImportConfig struct {
+	StorageDir     string            `mapstructure:"storage_dir"`     // 上传文件和结果文件的存储目录
+	MaxFileMB      int               `mapstructure:"max_file_mb"`     // 上传文件的最大大小（MB）
+	MaxRows        int               `mapstructure:"max_rows"`        // 单个文件的最大数据行数
+	BatchSize      int               `mapstructure:"batch_size"`      // 每批校验、富化和入库的行数
+	MaxConcurrent  int               `mapstructure:"max_concurrent"`  // Web 服务中同时执行的导入任务数，超出的任务由导入任务脚本执行
+	RetentionHours int               `mapstructure:"retention_hours"` // 上传文件和结果文件的保留时长（小时）
+	Mapping        map[string]string `mapstructure:"mapping"`         // 额外的列映射：文件列名 -> 舆情字段，如 "正文内容": content
+}
+
+// SearchConfig 舆情全文检索配置
+type SearchConfig struct {
+	Backend             string `mapstructure:"backend"`               // 检索后端：mysql（默认）或 embedded
+	NgramTokenSize      int    `mapstructure:"ngram_token_size"`      // 与 MySQL 的 ngram_token_size 一致，短于该长度的检索词使用 LIKE 匹配
+	SnippetLength       int    `mapstructure:"snippet_length"`        // 高亮摘要的长度（字符数）
+	FacetSize           int    `mapstructure:"facet_size"`            // 每个分面返回的条目数
+	IndexDir            string `mapstructure:"index_dir"`             // 内嵌索引的存储目录，每个 Web 实例使用独立的目录
+	SyncIntervalSeconds int    `mapstructure:"sync_interval_seconds"` // 内嵌索引同步新增和变更舆情的间隔（秒）
+	BatchSize           int    `mapstructure:"batch_size"`            // 内嵌索引每批写入的舆情数
+}
+
+// SavedSearchConfig 保存的检索和订阅推送配置
+type SavedSearchConfig struct {
+	LookbackMinutes int `mapstructure:"lookback_minutes"` // 检查新结果时回看的时长（分钟），覆盖晚于舆情入库产生的命中记录
+	MaxMatches      int `mapstructure:"max_matches"`      // 每次检查最多处理的新结果数
+	DigestItems     int `mapstructure:"digest_items"`     // 推送消息中列出的舆情数
+	DailyHour       int `mapstructure:"daily_hour"`       // 每日汇总的推送时刻（0~23 点）
+	RetentionHours  int `mapstructure:"retention_hours"`  // 推送去重记录的保留时长（小时），应大于回看时长
+}
+
+// PreviewConfig 监测组规则预览（按历史舆情回测）配置
+type PreviewConfig struct {
+	MaxOpinions int `mapstructure:"max_opinions"` // 单次预览最多扫描的舆情数，超过时需要缩小时间范围
+	DefaultDays int `mapstructure:"default_days"` // 未指定时间范围时预览最近多少天
+	SampleSize  int `mapstructure:"sample_size"`  // 默认返回的样例数
+}
+
+// KeywordStatConfig 关键词效果统计和建议配置
+type KeywordStatConfig struct {
+	SuggestDays  int `mapstructure:"suggest_days"`  // 计算建议时统计最近多少天内标注过的命中舆情
+	MaxDocuments int `mapstructure:"max_documents"` // 计算建议时最多读取的已标注舆情数
+	MinDocuments int `mapstructure:"min_documents"` // 建议词至少出现在多少条已标注舆情中
+	SuggestLimit int `mapstructure:"suggest_limit"` // 默认返回的建议关键词和排除词数量
+}
+
+// NoiseFilterConfig 噪音过滤器（按相关性标注训练的朴素贝叶斯分类器）配置
+type NoiseFilterConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`       // 是否对新命中打分并自动屏蔽疑似噪音，关闭时只打分不屏蔽
+	Threshold   float64 `mapstructure:"threshold"`     // 默认屏蔽阈值，噪音概率不低于该值的命中被屏蔽
+	MinSamples  int     `mapstructure:"min_samples"`   // 相关和不相关样本各自至少多少条才训练模型
+	MaxSamples  int     `mapstructure:"max_samples"`   // 训练时最多使用的样本数（最近标注的）
+	MinTermDocs int     `mapstructure:"min_term_docs"` // 词语至少出现在多少条样本中才进入词表
+	MaxTerms    int     `mapstructure:"max_terms"`     // 词表大小上限
+}
+
+// AuthorConfig 作者影响力计算配置
+type AuthorConfig struct {
+	InfluenceDays int     `mapstructure:"influence_days"` // 按最近多少天的舆情计算平均互动量
+	KOLScore      float64 `mapstructure:"kol_score"`      // 影响力得分不低于该值的作者视为 KOL
+}
+
+// SMTPConfig 邮件服务配置
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	TLS      bool   `mapstructure:"tls"` // 使用隐式 TLS（如 465 端口），否则在服务端支持时使用 STARTTLS
+}
+
 var globalConfig *Config
 
 // Load 加载配置文件
@@ -0,0 +1,263 @@
+// Package export 把舆情逐行写出为 CSV、NDJSON 或 XLSX，供同步下载和异步导出任务使用
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"sentinel-opinion-monitor/internal/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 导出格式
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// MaxXLSXRows XLSX 单个工作表最多的数据行数（不含表头）
+const MaxXLSXRows = 1048576 - 1
+
+// timeLayout 导出文件中的时间格式
+const timeLayout = "2006-01-02 15:04:05"
+
+// Column 导出的列
+type Column struct {
+	Key   string // 列名，与接口返回的字段名一致，CSV 表头使用
+	Title string // 中文标题，XLSX 表头使用
+	value func(o *model.Opinion) interface{}
+}
+
+// Columns CSV 和 XLSX 导出的列，按顺序
+var Columns = []Column{
+	{"id", "ID", func(o *model.Opinion) interface{} { return o.ID }},
+	{"created_at", "入库时间", func(o *model.Opinion) interface{} { return o.CreatedAt.Format(timeLayout) }},
+	{"source", "来源", func(o *model.Opinion) interface{} { return o.Source }},
+	{"author", "作者", func(o *model.Opinion) interface{} { return o.Author }},
+	{"url", "原文链接", func(o *model.Opinion) interface{} { return o.URL }},
+	{"content", "内容", func(o *model.Opinion) interface{} { return o.Content }},
+	{"like_count", "点赞数", func(o *model.Opinion) interface{} { return o.LikeCount }},
+	{"comment_count", "评论数", func(o *model.Opinion) interface{} { return o.CommentCount }},
+	{"share_count", "转发数", func(o *model.Opinion) interface{} { return o.ShareCount }},
+	{"sentiment_label", "情感", func(o *model.Opinion) interface{} { return o.SentimentLabel }},
+	{"sentiment_score", "情感得分", func(o *model.Opinion) interface{} { return o.SentimentScore }},
+	{"keywords", "关键词", func(o *model.Opinion) interface{} { return joinKeywords(o.Keywords) }},
+	{"topics", "主题", func(o *model.Opinion) interface{} { return strings.Join(o.Topics, "|") }},
+	{"handling_status", "处置状态", func(o *model.Opinion) interface{} { return o.HandlingStatus }},
+	{"priority", "优先级", func(o *model.Opinion) interface{} { return o.Priority }},
+	{"assignee_id", "处理人ID", func(o *model.Opinion) interface{} { return o.AssigneeID }},
+}
+
+// IsValidFormat 判断导出格式是否有效
+func IsValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON || format == FormatXLSX
+}
+
+// ContentType 导出格式对应的 MIME 类型
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Writer 逐行写出舆情，Close 时写完剩余内容；Close 不关闭底层的 io.Writer
+type Writer interface {
+	Write(o *model.Opinion) error
+	Flush() error
+	Close() error
+}
+
+// NewWriter 按格式创建 Writer
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// csvWriter CSV 输出，带 UTF-8 BOM 以便 Excel 正确识别编码
+type csvWriter struct {
+	w   *csv.Writer
+	row []string
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w), row: make([]string, len(Columns))}
+	for i, col := range Columns {
+		cw.row[i] = col.Key
+	}
+	if err := cw.w.Write(cw.row); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write 写入一行
+func (cw *csvWriter) Write(o *model.Opinion) error {
+	for i, col := range Columns {
+		cw.row[i] = formatCell(col.value(o))
+	}
+	return cw.w.Write(cw.row)
+}
+
+// Flush 把缓冲的内容写入底层 io.Writer
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Close 写完剩余内容
+func (cw *csvWriter) Close() error {
+	return cw.Flush()
+}
+
+// ndjsonWriter 每行一个 JSON 对象，字段与舆情详情接口一致
+type ndjsonWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return &ndjsonWriter{buf: buf, enc: enc}
+}
+
+// Write 写入一行
+func (nw *ndjsonWriter) Write(o *model.Opinion) error {
+	return nw.enc.Encode(o)
+}
+
+// Flush 把缓冲的内容写入底层 io.Writer
+func (nw *ndjsonWriter) Flush() error {
+	return nw.buf.Flush()
+}
+
+// Close 写完剩余内容
+func (nw *ndjsonWriter) Close() error {
+	return nw.Flush()
+}
+
+// xlsxWriter 以流式方式生成单个工作表的 XLSX，行数据先写入临时文件，Close 时才输出完整的工作簿
+type xlsxWriter struct {
+	w    io.Writer
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	rows int
+	row  []interface{}
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sheet := "舆情"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	cells := make([]interface{}, len(Columns))
+	for i, col := range Columns {
+		cells[i] = excelize.Cell{StyleID: header, Value: col.Title}
+	}
+	if err := sw.SetRow("A1", cells, excelize.RowOpts{}); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &xlsxWriter{w: w, f: f, sw: sw, rows: 1, row: make([]interface{}, len(Columns))}, nil
+}
+
+// Write 写入一行
+func (xw *xlsxWriter) Write(o *model.Opinion) error {
+	if xw.rows > MaxXLSXRows {
+		return fmt.Errorf("XLSX 最多导出 %d 行", MaxXLSXRows)
+	}
+	xw.rows++
+	for i, col := range Columns {
+		xw.row[i] = col.value(o)
+	}
+	cell, err := excelize.CoordinatesToCellName(1, xw.rows)
+	if err != nil {
+		return err
+	}
+	return xw.sw.SetRow(cell, xw.row)
+}
+
+// Flush XLSX 只能在 Close 时整体输出
+func (xw *xlsxWriter) Flush() error {
+	return nil
+}
+
+// Close 输出完整的工作簿并清理临时文件
+func (xw *xlsxWriter) Close() error {
+	defer xw.f.Close()
+	if err := xw.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := xw.f.WriteTo(xw.w)
+	return err
+}
+
+// formatCell 把单元格的值格式化为 CSV 文本
+func formatCell(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// joinKeywords 关键词以竖线分隔
+func joinKeywords(terms model.TermList) string {
+	words := make([]string, len(terms))
+	for i, t := range terms {
+		words[i] = t.Word
+	}
+	return strings.Join(words, "|")
+}
@@ -0,0 +1,483 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertHandler 告警处理器
+type AlertHandler struct {
+	alertService service.AlertService
+}
+
+// NewAlertHandler 创建告警处理器实例
+func NewAlertHandler(alertService service.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// AlertRuleRequest 告警规则请求（更新时未传的字段保持不变）
+type AlertRuleRequest struct {
+	Name               *string  `json:"name" binding:"omitempty,max=100"`
+	ScenarioID         *uint64  `json:"scenario_id" binding:"omitempty"`
+	GroupID            *uint64  `json:"group_id" binding:"omitempty"`
+	Type               *string  `json:"type" binding:"omitempty,oneof=volume negative_ratio engagement keyword growth"`
+	Threshold          *float64 `json:"threshold" binding:"omitempty"`
+	WindowMinutes      *int     `json:"window_minutes" binding:"omitempty"`
+	MinCount           *int     `json:"min_count" binding:"omitempty"`
+	Keywords           []string `json:"keywords" binding:"omitempty"`
+	Severity           *string  `json:"severity" binding:"omitempty,oneof=info warning critical"`
+	CooldownMinutes    *int     `json:"cooldown_minutes" binding:"omitempty"`
+	ChannelIDs         []uint64 `json:"channel_ids" binding:"omitempty"`
+	EscalationPolicyID *uint64  `json:"escalation_policy_id" binding:"omitempty"`
+	Status             *int     `json:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// toParams 转换为服务层告警规则参数
+func (r AlertRuleRequest) toParams() *service.AlertRuleParams {
+	return &service.AlertRuleParams{
+		Name:               r.Name,
+		ScenarioID:         r.ScenarioID,
+		GroupID:            r.GroupID,
+		Type:               r.Type,
+		Threshold:          r.Threshold,
+		WindowMinutes:      r.WindowMinutes,
+		MinCount:           r.MinCount,
+		Keywords:           r.Keywords,
+		Severity:           r.Severity,
+		CooldownMinutes:    r.CooldownMinutes,
+		ChannelIDs:         r.ChannelIDs,
+		EscalationPolicyID: r.EscalationPolicyID,
+		Status:             r.Status,
+	}
+}
+
+// AlertActionRequest 认领/恢复告警请求
+type AlertActionRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+// SilenceRuleRequest 静默告警规则请求
+type SilenceRuleRequest struct {
+	Minutes int `json:"minutes" binding:"min=0"`
+}
+
+// DryRunRangeRequest 试运行时间范围
+type DryRunRangeRequest struct {
+	StartTime   string `json:"start_time" binding:"required"`
+	EndTime     string `json:"end_time" binding:"required"`
+	StepMinutes int    `json:"step_minutes" binding:"omitempty,min=1"`
+}
+
+// DryRunRequest 未保存规则的试运行请求
+type DryRunRequest struct {
+	AlertRuleRequest
+	DryRunRangeRequest
+}
+
+// CreateRule 创建告警规则
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    rule,
+	})
+}
+
+// GetRules 获取告警规则列表（可按 scenario_id 筛选）
+func (h *AlertHandler) GetRules(c *gin.Context) {
+	scenarioID, err := strconv.ParseUint(c.DefaultQuery("scenario_id", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的场景ID",
+		})
+		return
+	}
+
+	rules, err := h.alertService.ListRules(scenarioID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取告警规则列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rules,
+	})
+}
+
+// GetRule 获取告警规则详情
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	rule, err := h.alertService.GetRule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "告警规则不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rule,
+	})
+}
+
+// UpdateRule 更新告警规则
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(id, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    rule,
+	})
+}
+
+// DeleteRule 删除告警规则
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.alertService.DeleteRule(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// SilenceRule 静默告警规则（minutes 为 0 表示取消静默）
+func (h *AlertHandler) SilenceRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req SilenceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	rule, err := h.alertService.SilenceRule(id, req.Minutes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "设置成功",
+		"data":    rule,
+	})
+}
+
+// DryRunRule 在历史数据上试运行已保存的告警规则
+func (h *AlertHandler) DryRunRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req DryRunRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	rule, err := h.alertService.GetRule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "告警规则不存在",
+		})
+		return
+	}
+
+	h.dryRun(c, rule, req)
+}
+
+// DryRun 在历史数据上试运行未保存的告警规则，便于创建前调整阈值
+func (h *AlertHandler) DryRun(c *gin.Context) {
+	var req DryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.alertService.BuildRule(req.AlertRuleRequest.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	h.dryRun(c, rule, req.DryRunRangeRequest)
+}
+
+// dryRun 解析时间范围并执行试运行
+func (h *AlertHandler) dryRun(c *gin.Context, rule *model.AlertRule, req DryRunRangeRequest) {
+	start, end, err := parseDryRunRange(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.alertService.DryRun(rule, start, end, time.Duration(req.StepMinutes)*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// GetEvents 分页获取告警事件（可按 rule_id、scenario_id、status、severity 筛选）
+func (h *AlertHandler) GetEvents(c *gin.Context) {
+	var filter service.AlertEventFilter
+	if v := c.Query("rule_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的规则ID",
+			})
+			return
+		}
+		filter.RuleID = id
+	}
+	if v := c.Query("scenario_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的场景ID",
+			})
+			return
+		}
+		filter.ScenarioID = id
+	}
+	filter.Status = c.Query("status")
+	filter.Severity = c.Query("severity")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	events, total, err := h.alertService.ListEvents(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取告警事件失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      events,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetEvent 获取告警事件详情
+func (h *AlertHandler) GetEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	event, err := h.alertService.GetEvent(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "告警事件不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": event,
+	})
+}
+
+// AcknowledgeEvent 认领告警，认领后停止升级
+func (h *AlertHandler) AcknowledgeEvent(c *gin.Context) {
+	h.eventAction(c, h.alertService.AcknowledgeEvent)
+}
+
+// ResolveEvent 手动恢复告警
+func (h *AlertHandler) ResolveEvent(c *gin.Context) {
+	h.eventAction(c, h.alertService.ResolveEvent)
+}
+
+// eventAction 处理当前用户对告警事件的认领/恢复操作
+func (h *AlertHandler) eventAction(c *gin.Context, action func(id, userID uint64, note string) (*model.AlertEvent, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未登录",
+		})
+		return
+	}
+
+	var req AlertActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "请求参数错误",
+			})
+			return
+		}
+	}
+
+	event, err := action(id, userID.(uint64), req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "操作成功",
+		"data":    event,
+	})
+}
+
+// GetTimeline 获取告警事件时间线（触发、通知、升级、认领、恢复）
+func (h *AlertHandler) GetTimeline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	logs, err := h.alertService.GetTimeline(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+	})
+}
+
+// parseDryRunRange 解析试运行时间范围，最长 31 天
+func parseDryRunRange(req DryRunRangeRequest) (time.Time, time.Time, error) {
+	start, err := parseQueryTime(req.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("无效的开始时间")
+	}
+	end, err := parseQueryTime(req.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("无效的结束时间")
+	}
+	if end.Sub(start) > 31*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("试运行时间范围不能超过31天")
+	}
+	return start, end, nil
+}
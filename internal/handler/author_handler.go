@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorHandler 作者处理器
+type AuthorHandler struct {
+	authorService service.AuthorService
+}
+
+// NewAuthorHandler 创建作者处理器实例
+func NewAuthorHandler(authorService service.AuthorService) *AuthorHandler {
+	return &AuthorHandler{
+		authorService: authorService,
+	}
+}
+
+// AddWatchRequest 关注作者请求
+type AddWatchRequest struct {
+	AuthorID uint64 `json:"author_id" binding:"required"`
+	Note     string `json:"note" binding:"max=255"`
+}
+
+// GetAuthors 分页获取作者列表
+// 支持 channel、keyword（账号名称前缀或账号ID）、verified、min_influence、kol=true（影响力得分达到 KOL 标准）
+// 和 sort（influence、followers、opinions、last_seen）查询参数
+func (h *AuthorHandler) GetAuthors(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	filter := service.AuthorFilter{
+		Channel: c.Query("channel"),
+		Keyword: c.Query("keyword"),
+		Sort:    c.Query("sort"),
+	}
+	switch filter.Sort {
+	case "", repository.AuthorSortInfluence, repository.AuthorSortFollowers, repository.AuthorSortOpinions, repository.AuthorSortLastSeen:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的排序方式，可选值: influence, followers, opinions, last_seen",
+		})
+		return
+	}
+	if v := c.Query("verified"); v != "" {
+		verified, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的认证状态",
+			})
+			return
+		}
+		filter.Verified = &verified
+	}
+	if v := c.Query("min_influence"); v != "" {
+		score, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的影响力得分",
+			})
+			return
+		}
+		filter.MinInfluence = score
+	}
+	if c.Query("kol") == "true" && filter.MinInfluence < h.authorService.KOLScore() {
+		filter.MinInfluence = h.authorService.KOLScore()
+	}
+
+	authors, total, err := h.authorService.ListAuthors(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取作者列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      authors,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetAuthor 获取作者详情
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	author, err := h.authorService.GetAuthor(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": author,
+	})
+}
+
+// GetWatches 获取场景关注的作者
+func (h *AuthorHandler) GetWatches(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	watches, err := h.authorService.GetWatches(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": watches,
+	})
+}
+
+// AddWatch 场景关注作者
+func (h *AuthorHandler) AddWatch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req AddWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	watch, err := h.authorService.AddWatch(id, req.AuthorID, userID, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "关注成功",
+		"data":    watch,
+	})
+}
+
+// RemoveWatch 取消场景对作者的关注
+func (h *AuthorHandler) RemoveWatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	authorID, err := strconv.ParseUint(c.Param("author_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的作者ID",
+		})
+		return
+	}
+
+	if err := h.authorService.RemoveWatch(id, authorID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "取消关注成功",
+	})
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComparisonHandler 竞品对比组处理器
+type ComparisonHandler struct {
+	comparisonService service.ComparisonService
+}
+
+// NewComparisonHandler 创建竞品对比组处理器实例
+func NewComparisonHandler(comparisonService service.ComparisonService) *ComparisonHandler {
+	return &ComparisonHandler{
+		comparisonService: comparisonService,
+	}
+}
+
+// ComparisonSetRequest 对比组请求（更新时未传的字段保持不变）
+type ComparisonSetRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,max=100"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	ScenarioIDs []uint64 `json:"scenario_ids" binding:"omitempty"`
+	Interval    *string  `json:"interval" binding:"omitempty,oneof=hour day"`
+}
+
+// toParams 转换为服务层参数
+func (r *ComparisonSetRequest) toParams() *service.ComparisonSetParams {
+	return &service.ComparisonSetParams{
+		Name:        r.Name,
+		Description: r.Description,
+		ScenarioIDs: r.ScenarioIDs,
+		Interval:    r.Interval,
+	}
+}
+
+// CreateComparison 创建对比组
+func (h *ComparisonHandler) CreateComparison(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ComparisonSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	set, err := h.comparisonService.Create(userID, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    set,
+	})
+}
+
+// GetComparisons 获取对比组列表
+func (h *ComparisonHandler) GetComparisons(c *gin.Context) {
+	sets, err := h.comparisonService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取对比组列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": sets,
+	})
+}
+
+// GetComparison 获取对比组详情
+func (h *ComparisonHandler) GetComparison(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	set, err := h.comparisonService.Get(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "对比组不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": set,
+	})
+}
+
+// UpdateComparison 更新对比组
+func (h *ComparisonHandler) UpdateComparison(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ComparisonSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	set, err := h.comparisonService.Update(id, userID, hasRole(c, "admin"), req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    set,
+	})
+}
+
+// DeleteComparison 删除对比组
+func (h *ComparisonHandler) DeleteComparison(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.comparisonService.Delete(id, userID, hasRole(c, "admin")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// GetComparisonReport 获取对比组的对比数据
+func (h *ComparisonHandler) GetComparisonReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	query, ok := bindStatsQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.comparisonService.Report(id, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/export"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 舆情导出处理器
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler 创建舆情导出处理器实例
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportOpinions 直接下载符合条件的舆情（format 查询参数：csv 或 ndjson，默认 csv；筛选参数同舆情列表）
+// 数量超过同步导出上限时返回 400，需要改为创建导出任务
+func (h *ExportHandler) ExportOpinions(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatNDJSON {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "直接下载只支持 csv 和 ndjson 格式，xlsx 请创建导出任务",
+		})
+		return
+	}
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	total, err := h.exportService.CheckSync(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"total": total,
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("opinions-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.Status(http.StatusOK)
+
+	// 响应头已经发出，中途失败只能中断输出
+	if err := h.exportService.Stream(c.Writer, format, filter); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// CreateExport 创建异步导出任务（format 查询参数：csv、xlsx 或 ndjson，默认 xlsx；筛选参数同舆情列表）
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	task, err := h.exportService.CreateTask(userID, c.DefaultQuery("format", export.FormatXLSX), filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "导出任务已创建",
+		"data":    task,
+	})
+}
+
+// GetExports 分页获取当前用户的导出任务
+func (h *ExportHandler) GetExports(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	tasks, total, err := h.exportService.ListTasks(userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取导出任务列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      tasks,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetExport 获取导出任务的进度和下载地址
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.exportService.GetTask(id, userID, hasRole(c, "admin"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": task,
+	})
+}
+
+// DownloadExport 通过带签名的临时地址下载导出文件，无需登录
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	path, filename, err := h.exportService.Download(id, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.FileAttachment(path, filename)
+}
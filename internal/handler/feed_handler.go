@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/realtime"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// feedBuffer 单个推送连接本地缓冲的消息数，写满说明客户端消费过慢，连接会被断开
+	feedBuffer = 256
+	// wsWriteTimeout WebSocket 单条消息的写超时
+	wsWriteTimeout = 10 * time.Second
+)
+
+// 推送事件类型
+const (
+	feedEventOpinion  = "opinion"  // 新命中的舆情
+	feedEventPing     = "ping"     // 心跳
+	feedEventReset    = "reset"    // 待补发的舆情过多，客户端需重新拉取列表
+	feedEventOverflow = "overflow" // 客户端消费过慢，服务端即将断开连接
+)
+
+// feedEvent 推送给客户端的事件（WebSocket 直接发送 JSON，SSE 中 data 为 Data 字段）
+type feedEvent struct {
+	Type string      `json:"type"`
+	ID   uint64      `json:"id,omitempty"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// FeedHandler 命中舆情实时推送处理器
+type FeedHandler struct {
+	feedService    service.FeedService
+	allowedOrigins map[string]bool
+}
+
+// NewFeedHandler 创建命中舆情实时推送处理器实例，allowedOrigins 为允许建立 WebSocket 连接的页面来源
+func NewFeedHandler(feedService service.FeedService, allowedOrigins []string) *FeedHandler {
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin = normalizeOrigin(origin); origin != "" {
+			origins[origin] = true
+		}
+	}
+	return &FeedHandler{
+		feedService:    feedService,
+		allowedOrigins: origins,
+	}
+}
+
+// Stream 通过 Server-Sent Events 推送订阅范围内新命中的舆情
+func (h *FeedHandler) Stream(c *gin.Context) {
+	subscription, lastID, ok := h.prepare(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	h.pump(c.Request.Context(), subscription, lastID, func(event *feedEvent) error {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+		if event.ID > 0 {
+			fmt.Fprintf(c.Writer, "id: %d\n", event.ID)
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+}
+
+// WebSocket 通过 WebSocket 推送订阅范围内新命中的舆情，客户端发送的消息会被忽略
+func (h *FeedHandler) WebSocket(c *gin.Context) {
+	if !h.originAllowed(c.Request) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "不允许的来源",
+		})
+		return
+	}
+	subscription, lastID, ok := h.prepare(c)
+	if !ok {
+		return
+	}
+
+	// 来源已在升级前校验，跳过 websocket 包默认的 Origin 检查
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = 4096
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// 读取循环用于感知客户端断开
+			go func() {
+				defer cancel()
+				for {
+					var msg string
+					if err := websocket.Message.Receive(ws, &msg); err != nil {
+						return
+					}
+				}
+			}()
+
+			h.pump(ctx, subscription, lastID, func(event *feedEvent) error {
+				if err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+					return err
+				}
+				return websocket.JSON.Send(ws, event)
+			})
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// originAllowed 校验 WebSocket 握手的 Origin：未携带 Origin 的非浏览器客户端直接放行，
+// 浏览器页面必须与服务同源或在允许列表中，防止其他站点的页面冒用用户身份建立连接
+func (h *FeedHandler) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return h.allowedOrigins[normalizeOrigin(origin)]
+}
+
+// normalizeOrigin 把来源统一为小写的 scheme://host[:port]
+func normalizeOrigin(origin string) string {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// prepare 解析订阅参数和续传位置，失败时写入错误响应
+func (h *FeedHandler) prepare(c *gin.Context) (*service.FeedSubscription, uint64, bool) {
+	if realtime.Get() == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "实时推送未启用",
+		})
+		return nil, 0, false
+	}
+
+	scenarioIDs, err := parseIDList(c.Query("scenario_ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的 scenario_ids",
+		})
+		return nil, 0, false
+	}
+	groupIDs, err := parseIDList(c.Query("group_ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的 group_ids",
+		})
+		return nil, 0, false
+	}
+
+	var lastID uint64
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		lastID, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的 last_event_id",
+			})
+			return nil, 0, false
+		}
+	}
+
+	subscription, err := h.feedService.Subscribe(scenarioIDs, groupIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return nil, 0, false
+	}
+	return subscription, lastID, true
+}
+
+// pump 先补发 lastID 之后的命中舆情，再持续推送实时消息和心跳，直到连接断开或发送失败
+func (h *FeedHandler) pump(ctx context.Context, subscription *service.FeedSubscription, lastID uint64, emit func(*feedEvent) error) {
+	// 先订阅再查询补发数据，避免两者之间产生的消息丢失；重复的消息按 ID 跳过
+	sub := realtime.Get().Subscribe(feedBuffer, subscription.Topics()...)
+	defer sub.Close()
+
+	var replayed uint64
+	if lastID > 0 {
+		items, truncated, err := h.feedService.Backlog(subscription, lastID)
+		if err != nil {
+			truncated = true
+		}
+		if truncated {
+			if emit(&feedEvent{Type: feedEventReset, Data: gin.H{"reason": "待补发的舆情过多，请重新获取列表"}}) != nil {
+				return
+			}
+		}
+		for _, item := range items {
+			if emit(&feedEvent{Type: feedEventOpinion, ID: item.ID, Data: item}) != nil {
+				return
+			}
+			replayed = item.ID
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data, ok := <-sub.C():
+			if !ok {
+				if sub.Overflowed() {
+					_ = emit(&feedEvent{Type: feedEventOverflow, Data: gin.H{"reason": "消费过慢，请携带最后收到的 ID 重新连接"}})
+				}
+				return
+			}
+			var item repository.FeedItem
+			if err := json.Unmarshal(data, &item); err != nil || item.ID <= replayed || !subscription.Match(&item) {
+				continue
+			}
+			if emit(&feedEvent{Type: feedEventOpinion, ID: item.ID, Data: &item}) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if emit(&feedEvent{Type: feedEventPing, Data: time.Now().Unix()}) != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseIDList 解析逗号分隔的 ID 列表
+func parseIDList(value string) ([]uint64, error) {
+	var ids []uint64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || id == 0 {
+			return nil, errors.New("无效的 ID")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
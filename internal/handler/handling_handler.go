@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlingHandler 舆情处置处理器
+type HandlingHandler struct {
+	handlingService service.HandlingService
+}
+
+// NewHandlingHandler 创建舆情处置处理器实例
+func NewHandlingHandler(handlingService service.HandlingService) *HandlingHandler {
+	return &HandlingHandler{
+		handlingService: handlingService,
+	}
+}
+
+// AssignRequest 分配处理人请求
+type AssignRequest struct {
+	AssigneeID uint64 `json:"assignee_id"` // 0 表示取消分配
+	Note       string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// TransitionRequest 变更处置状态请求
+type TransitionRequest struct {
+	Status string `json:"status" binding:"required,oneof=new in_progress responded ignored escalated"`
+	Note   string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// TriageRequest 调整优先级和截止时间请求
+type TriageRequest struct {
+	Priority string `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	DueAt    string `json:"due_at" binding:"omitempty"`
+	Note     string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// CommentRequest 评论请求
+type CommentRequest struct {
+	Content string `json:"content" binding:"required,max=5000"`
+}
+
+// Assign 分配处理人
+func (h *HandlingHandler) Assign(c *gin.Context) {
+	id, userID, ok := opinionActionContext(c)
+	if !ok {
+		return
+	}
+
+	var req AssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	opinion, err := h.handlingService.Assign(id, userID, hasRole(c, "admin"), req.AssigneeID, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "分配成功",
+		"data":    opinion,
+	})
+}
+
+// Transition 变更处置状态
+func (h *HandlingHandler) Transition(c *gin.Context) {
+	id, userID, ok := opinionActionContext(c)
+	if !ok {
+		return
+	}
+
+	var req TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	opinion, err := h.handlingService.Transition(id, userID, hasRole(c, "admin"), req.Status, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    opinion,
+	})
+}
+
+// Triage 调整优先级和截止时间
+func (h *HandlingHandler) Triage(c *gin.Context) {
+	id, userID, ok := opinionActionContext(c)
+	if !ok {
+		return
+	}
+
+	var req TriageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var dueAt *time.Time
+	if req.DueAt != "" {
+		t, err := parseQueryTime(req.DueAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的截止时间",
+			})
+			return
+		}
+		dueAt = &t
+	}
+
+	opinion, err := h.handlingService.Triage(id, userID, req.Priority, dueAt, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    opinion,
+	})
+}
+
+// AddComment 添加内部评论
+func (h *HandlingHandler) AddComment(c *gin.Context) {
+	id, userID, ok := opinionActionContext(c)
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	comment, err := h.handlingService.AddComment(id, userID, req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    comment,
+	})
+}
+
+// GetComments 获取内部评论
+func (h *HandlingHandler) GetComments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的 ID",
+		})
+		return
+	}
+
+	comments, err := h.handlingService.GetComments(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": comments,
+	})
+}
+
+// GetHistory 获取处置记录
+func (h *HandlingHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的 ID",
+		})
+		return
+	}
+
+	logs, err := h.handlingService.GetHistory(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+	})
+}
+
+// MyQueue 获取分配给当前用户的舆情（支持与舆情列表相同的筛选条件）
+func (h *HandlingHandler) MyQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	opinions, total, err := h.handlingService.MyQueue(userID, filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取待办列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      opinions,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// opinionActionContext 解析舆情 ID 和当前用户，失败时写入错误响应
+func opinionActionContext(c *gin.Context) (uint64, uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的 ID",
+		})
+		return 0, 0, false
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	return id, userID, true
+}
+
+// hasRole 检查当前用户是否拥有指定角色
+func hasRole(c *gin.Context, roleCode string) bool {
+	roles, exists := c.Get("roles")
+	if !exists {
+		return false
+	}
+	userRoles, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, role := range userRoles {
+		if role == roleCode {
+			return true
+		}
+	}
+	return false
+}
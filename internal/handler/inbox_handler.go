@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/realtime"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat 实时推送连接的心跳间隔，防止代理因空闲断开连接
+const streamHeartbeat = 25 * time.Second
+
+// InboxHandler 站内通知处理器
+type InboxHandler struct {
+	inboxService service.InboxService
+}
+
+// NewInboxHandler 创建站内通知处理器实例
+func NewInboxHandler(inboxService service.InboxService) *InboxHandler {
+	return &InboxHandler{
+		inboxService: inboxService,
+	}
+}
+
+// MarkReadRequest 标记已读请求
+type MarkReadRequest struct {
+	IDs []uint64 `json:"ids" binding:"required,min=1,max=200"`
+}
+
+// List 获取当前用户的站内通知（支持 unread=true 只看未读）
+func (h *InboxHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	notifications, total, err := h.inboxService.List(userID, c.Query("unread") == "true", page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取通知列表失败",
+		})
+		return
+	}
+	unread, _ := h.inboxService.UnreadCount(userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":         notifications,
+			"total":        total,
+			"page":         page,
+			"page_size":    pageSize,
+			"unread_count": unread,
+		},
+	})
+}
+
+// UnreadCount 获取当前用户的未读通知数
+func (h *InboxHandler) UnreadCount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.inboxService.UnreadCount(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"unread_count": count,
+		},
+	})
+}
+
+// MarkRead 标记指定通知为已读
+func (h *InboxHandler) MarkRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	count, err := h.inboxService.MarkRead(userID, req.IDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	h.respondMarked(c, userID, count)
+}
+
+// MarkAllRead 标记所有通知为已读
+func (h *InboxHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.inboxService.MarkAllRead(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	h.respondMarked(c, userID, count)
+}
+
+// Stream 通过 Server-Sent Events 推送当前用户的新通知和未读数变化
+func (h *InboxHandler) Stream(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	hub := realtime.Get()
+	if hub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "实时推送未启用",
+		})
+		return
+	}
+
+	sub := hub.Subscribe(64, service.InboxTopic(userID))
+	defer sub.Close()
+
+	unread, _ := h.inboxService.UnreadCount(userID)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent(service.InboxEventUnread, &service.InboxEvent{Type: service.InboxEventUnread, UnreadCount: unread})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case data, ok := <-sub.C():
+			if !ok {
+				return false
+			}
+			var event service.InboxEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return true
+			}
+			c.SSEvent(event.Type, json.RawMessage(data))
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+// respondMarked 返回标记结果和最新未读数
+func (h *InboxHandler) respondMarked(c *gin.Context, userID uint64, count int64) {
+	unread, _ := h.inboxService.UnreadCount(userID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "标记成功",
+		"data": gin.H{
+			"marked":       count,
+			"unread_count": unread,
+		},
+	})
+}
+
+// currentUserID 获取当前登录用户 ID，未登录时写入错误响应
+func currentUserID(c *gin.Context) (uint64, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "未登录",
+		})
+		return 0, false
+	}
+	return userID.(uint64), true
+}
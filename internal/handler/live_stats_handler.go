@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LiveStatsHandler 监测组实时计数处理器
+type LiveStatsHandler struct {
+	liveStatsService service.LiveStatsService
+}
+
+// NewLiveStatsHandler 创建监测组实时计数处理器实例
+func NewLiveStatsHandler(liveStatsService service.LiveStatsService) *LiveStatsHandler {
+	return &LiveStatsHandler{
+		liveStatsService: liveStatsService,
+	}
+}
+
+// GetGroupLiveStats 获取监测组最近 N 分钟或 N 小时的命中数
+func (h *LiveStatsHandler) GetGroupLiveStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var query service.LiveQuery
+	if v := c.Query("minutes"); v != "" {
+		if query.Minutes, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的 minutes",
+			})
+			return
+		}
+	}
+	if v := c.Query("hours"); v != "" {
+		if query.Hours, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的 hours",
+			})
+			return
+		}
+	}
+
+	stats, err := h.liveStatsService.GetGroupStats(id, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
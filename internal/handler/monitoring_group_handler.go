@@ -11,13 +11,37 @@ import (
 
 // MonitoringGroupHandler 监测组管理处理器
 type MonitoringGroupHandler struct {
-	groupService service.MonitoringGroupService
+	groupService   service.MonitoringGroupService
+	previewService service.GroupPreviewService
+	statService    service.KeywordStatService
 }
 
 // NewMonitoringGroupHandler 创建监测组管理处理器实例
-func NewMonitoringGroupHandler(groupService service.MonitoringGroupService) *MonitoringGroupHandler {
+func NewMonitoringGroupHandler(groupService service.MonitoringGroupService, previewService service.GroupPreviewService, statService service.KeywordStatService) *MonitoringGroupHandler {
 	return &MonitoringGroupHandler{
-		groupService: groupService,
+		groupService:   groupService,
+		previewService: previewService,
+		statService:    statService,
+	}
+}
+
+// GroupScheduleRequest 监测组采集计划请求字段（未传的字段保持不变）
+type GroupScheduleRequest struct {
+	ScanInterval   *int    `json:"scan_interval" binding:"omitempty"`
+	ActiveHours    *string `json:"active_hours" binding:"omitempty"`
+	ActiveWeekdays *string `json:"active_weekdays" binding:"omitempty"`
+	StartDate      *string `json:"start_date" binding:"omitempty"`
+	EndDate        *string `json:"end_date" binding:"omitempty"`
+}
+
+// toParams 转换为服务层采集计划参数
+func (r GroupScheduleRequest) toParams() *service.GroupScheduleParams {
+	return &service.GroupScheduleParams{
+		ScanInterval:   r.ScanInterval,
+		ActiveHours:    r.ActiveHours,
+		ActiveWeekdays: r.ActiveWeekdays,
+		StartDate:      r.StartDate,
+		EndDate:        r.EndDate,
 	}
 }
 
@@ -28,6 +52,7 @@ type CreateGroupRequest struct {
 	Sort           int      `json:"sort" binding:"omitempty"`
 	Keywords       []string `json:"keywords" binding:"omitempty"`
 	ExclusionWords []string `json:"exclusion_words" binding:"omitempty"`
+	GroupScheduleRequest
 }
 
 // UpdateGroupRequest 更新监测组请求
@@ -35,6 +60,7 @@ type UpdateGroupRequest struct {
 	Name   string `json:"name" binding:"omitempty,max=100"`
 	Sort   int    `json:"sort" binding:"omitempty"`
 	Status int    `json:"status" binding:"omitempty,oneof=1 2"`
+	GroupScheduleRequest
 }
 
 // AssignChannelsRequest 分配渠道请求
@@ -52,6 +78,32 @@ type AddExclusionWordRequest struct {
 	Word string `json:"word" binding:"required"`
 }
 
+// AddSourceFilterRequest 添加来源过滤规则请求
+type AddSourceFilterRequest struct {
+	Type     string `json:"type" binding:"required,oneof=author domain url"`
+	Mode     string `json:"mode" binding:"required,oneof=block allow"`
+	AuthorID uint64 `json:"author_id" binding:"required_if=Type author"`
+	Value    string `json:"value" binding:"max=512"`
+	Note     string `json:"note" binding:"max=255"`
+}
+
+// MarkRelevanceRequest 命中相关性标注请求（为空时清除标注）
+type MarkRelevanceRequest struct {
+	Relevance string `json:"relevance" binding:"omitempty,oneof=relevant irrelevant"`
+}
+
+// PreviewGroupRequest 监测组规则预览请求（传 group_id 时，未传的关键词、排除词和渠道沿用该监测组的配置）
+type PreviewGroupRequest struct {
+	GroupID        uint64   `json:"group_id" binding:"omitempty"`
+	ScenarioID     uint64   `json:"scenario_id" binding:"omitempty"`
+	Keywords       []string `json:"keywords" binding:"omitempty"`
+	ExclusionWords []string `json:"exclusion_words" binding:"omitempty"`
+	ChannelIDs     []uint64 `json:"channel_ids" binding:"omitempty"`
+	StartTime      string   `json:"start_time" binding:"omitempty"`
+	EndTime        string   `json:"end_time" binding:"omitempty"`
+	SampleSize     int      `json:"sample_size" binding:"omitempty,min=0"`
+}
+
 // CreateGroup 创建监测组
 func (h *MonitoringGroupHandler) CreateGroup(c *gin.Context) {
 	var req CreateGroupRequest
@@ -69,6 +121,7 @@ func (h *MonitoringGroupHandler) CreateGroup(c *gin.Context) {
 			req.ScenarioID,
 			req.Name,
 			req.Sort,
+			req.GroupScheduleRequest.toParams(),
 			req.Keywords,
 			req.ExclusionWords,
 		)
@@ -87,7 +140,7 @@ func (h *MonitoringGroupHandler) CreateGroup(c *gin.Context) {
 	}
 
 	// 如果没有提供关键词和排除词，使用原来的方法
-	group, err := h.groupService.CreateGroup(req.ScenarioID, req.Name, req.Sort)
+	group, err := h.groupService.CreateGroup(req.ScenarioID, req.Name, req.Sort, req.GroupScheduleRequest.toParams())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -168,7 +221,7 @@ func (h *MonitoringGroupHandler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
-	if err := h.groupService.UpdateGroup(id, req.Name, req.Sort, req.Status); err != nil {
+	if err := h.groupService.UpdateGroup(id, req.Name, req.Sort, req.Status, req.GroupScheduleRequest.toParams()); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
@@ -297,7 +350,7 @@ func (h *MonitoringGroupHandler) RemoveKeyword(c *gin.Context) {
 	})
 }
 
-// GetKeywords 获取关键词列表
+// GetKeywords 获取关键词列表（含各关键词的命中数和不相关占比），同时返回各排除词累计排除的舆情数
 func (h *MonitoringGroupHandler) GetKeywords(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -308,16 +361,86 @@ func (h *MonitoringGroupHandler) GetKeywords(c *gin.Context) {
 		return
 	}
 
-	keywords, err := h.groupService.GetKeywords(id)
+	stats, err := h.statService.GetStats(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "获取关键词列表失败",
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": keywords,
+		"data":            stats.Keywords,
+		"exclusion_words": stats.ExclusionWords,
+	})
+}
+
+// GetKeywordSuggestions 根据命中舆情的相关性标注建议新的关键词和排除词
+func (h *MonitoringGroupHandler) GetKeywordSuggestions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.Query("days"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	suggestions, err := h.statService.Suggest(id, days, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": suggestions,
+	})
+}
+
+// MarkHitRelevance 标注舆情在监测组下的命中是否相关
+func (h *MonitoringGroupHandler) MarkHitRelevance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	opinionID, err := strconv.ParseUint(c.Param("opinion_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的舆情ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MarkRelevanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	hit, err := h.statService.MarkRelevance(id, opinionID, userID, req.Relevance)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "标注成功",
+		"data":    hit,
 	})
 }
 
@@ -407,3 +530,153 @@ func (h *MonitoringGroupHandler) GetExclusionWords(c *gin.Context) {
 		"data": words,
 	})
 }
+
+// GetSourceFilters 获取来源过滤规则列表
+func (h *MonitoringGroupHandler) GetSourceFilters(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	filters, err := h.groupService.GetSourceFilters(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": filters,
+	})
+}
+
+// AddSourceFilter 添加来源过滤规则（作者、域名或链接模式的允许/屏蔽名单）
+func (h *MonitoringGroupHandler) AddSourceFilter(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req AddSourceFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	filter, err := h.groupService.AddSourceFilter(id, &service.SourceFilterParams{
+		Type:     req.Type,
+		Mode:     req.Mode,
+		AuthorID: req.AuthorID,
+		Value:    req.Value,
+		Note:     req.Note,
+		UserID:   userID,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "添加来源过滤规则成功",
+		"data":    filter,
+	})
+}
+
+// RemoveSourceFilter 删除来源过滤规则
+func (h *MonitoringGroupHandler) RemoveSourceFilter(c *gin.Context) {
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的监测组ID",
+		})
+		return
+	}
+	filterID, err := strconv.ParseUint(c.Param("filter_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的规则ID",
+		})
+		return
+	}
+
+	if err := h.groupService.RemoveSourceFilter(groupID, filterID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除来源过滤规则成功",
+	})
+}
+
+// PreviewGroup 用草稿的关键词和排除词匹配历史舆情，返回命中统计和样例（不保存）
+func (h *MonitoringGroupHandler) PreviewGroup(c *gin.Context) {
+	var req PreviewGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	params := &service.GroupPreviewParams{
+		GroupID:        req.GroupID,
+		ScenarioID:     req.ScenarioID,
+		Keywords:       req.Keywords,
+		ExclusionWords: req.ExclusionWords,
+		ChannelIDs:     req.ChannelIDs,
+		SampleSize:     req.SampleSize,
+	}
+	if req.StartTime != "" {
+		t, err := parseQueryTime(req.StartTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的开始时间",
+			})
+			return
+		}
+		params.StartTime = &t
+	}
+	if req.EndTime != "" {
+		t, err := parseQueryTime(req.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的结束时间",
+			})
+			return
+		}
+		params.EndTime = &t
+	}
+
+	result, err := h.previewService.Preview(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoiseFilterHandler 噪音过滤器处理器
+type NoiseFilterHandler struct {
+	noiseFilterService service.NoiseFilterService
+}
+
+// NewNoiseFilterHandler 创建噪音过滤器处理器实例
+func NewNoiseFilterHandler(noiseFilterService service.NoiseFilterService) *NoiseFilterHandler {
+	return &NoiseFilterHandler{
+		noiseFilterService: noiseFilterService,
+	}
+}
+
+// UpdateNoiseFilterRequest 更新噪音过滤器设置请求（未传的字段保持不变）
+type UpdateNoiseFilterRequest struct {
+	Enabled   *bool    `json:"enabled"`
+	Threshold *float64 `json:"threshold" binding:"omitempty,min=0,max=1"`
+}
+
+// GetNoiseFilter 获取监测组噪音过滤器的设置和训练状态
+func (h *NoiseFilterHandler) GetNoiseFilter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	filter, err := h.noiseFilterService.Get(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": filter,
+	})
+}
+
+// UpdateNoiseFilter 更新监测组噪音过滤器的启用状态和屏蔽阈值
+func (h *NoiseFilterHandler) UpdateNoiseFilter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req UpdateNoiseFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	filter, err := h.noiseFilterService.Update(id, &service.NoiseFilterParams{
+		Enabled:   req.Enabled,
+		Threshold: req.Threshold,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    filter,
+	})
+}
+
+// TrainNoiseFilter 立即用监测组的相关性标注训练噪音过滤器
+func (h *NoiseFilterHandler) TrainNoiseFilter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	filter, err := h.noiseFilterService.Train(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "训练完成",
+		"data":    filter,
+	})
+}
+
+// GetSuppressedHits 分页获取被屏蔽的命中舆情（pending=true 时只返回未标注的）
+func (h *NoiseFilterHandler) GetSuppressedHits(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	hits, total, err := h.noiseFilterService.GetSuppressed(id, c.Query("pending") == "true", page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取屏蔽的命中失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      hits,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 通知渠道处理器
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler 创建通知渠道处理器实例
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// NotificationChannelRequest 通知渠道请求（更新时未传的字段保持不变）
+type NotificationChannelRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,max=100"`
+	Type      *string  `json:"type" binding:"omitempty,oneof=webhook email dingtalk wecom feishu"`
+	URL       *string  `json:"url" binding:"omitempty,max=500"`
+	Secret    *string  `json:"secret" binding:"omitempty,max=255"`
+	Emails    []string `json:"emails" binding:"omitempty,dive,email"`
+	AtMobiles []string `json:"at_mobiles" binding:"omitempty"`
+	AtAll     *bool    `json:"at_all" binding:"omitempty"`
+	Template  *string  `json:"template" binding:"omitempty"`
+	Status    *int     `json:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// toParams 转换为服务层通知渠道参数
+func (r NotificationChannelRequest) toParams() *service.NotificationChannelParams {
+	return &service.NotificationChannelParams{
+		Name:      r.Name,
+		Type:      r.Type,
+		URL:       r.URL,
+		Secret:    r.Secret,
+		Emails:    r.Emails,
+		AtMobiles: r.AtMobiles,
+		AtAll:     r.AtAll,
+		Template:  r.Template,
+		Status:    r.Status,
+	}
+}
+
+// CreateChannel 创建通知渠道
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	var req NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.notificationService.CreateChannel(req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    channel,
+	})
+}
+
+// GetChannels 获取通知渠道列表
+func (h *NotificationHandler) GetChannels(c *gin.Context) {
+	channels, err := h.notificationService.ListChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取通知渠道列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": channels,
+	})
+}
+
+// GetChannel 获取通知渠道详情
+func (h *NotificationHandler) GetChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	channel, err := h.notificationService.GetChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "通知渠道不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": channel,
+	})
+}
+
+// UpdateChannel 更新通知渠道
+func (h *NotificationHandler) UpdateChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.notificationService.UpdateChannel(id, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    channel,
+	})
+}
+
+// DeleteChannel 删除通知渠道
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.notificationService.DeleteChannel(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// TestChannel 向通知渠道发送测试消息，返回发送记录
+func (h *NotificationHandler) TestChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	delivery, err := h.notificationService.TestChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": delivery,
+	})
+}
+
+// GetDeliveries 获取通知发送记录（支持按渠道、来源和结果筛选）
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	var filter service.DeliveryFilter
+	if v := c.Query("channel_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的渠道ID",
+			})
+			return
+		}
+		filter.ChannelID = id
+	}
+	if v := c.Query("source_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的来源ID",
+			})
+			return
+		}
+		filter.SourceID = id
+	}
+	filter.Source = c.Query("source")
+	if v := c.Query("status"); v != "" {
+		if v != model.DeliveryStatusSuccess && v != model.DeliveryStatusFailed {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的发送结果，可选值: success, failed",
+			})
+			return
+		}
+		filter.Status = v
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	deliveries, total, err := h.notificationService.ListDeliveries(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取发送记录失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      deliveries,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
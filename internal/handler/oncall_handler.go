@@ -0,0 +1,464 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OnCallHandler 值班和升级策略处理器
+type OnCallHandler struct {
+	onCallService service.OnCallService
+}
+
+// NewOnCallHandler 创建值班和升级策略处理器实例
+func NewOnCallHandler(onCallService service.OnCallService) *OnCallHandler {
+	return &OnCallHandler{
+		onCallService: onCallService,
+	}
+}
+
+// EscalationPolicyRequest 升级策略请求（更新时未传的字段保持不变）
+type EscalationPolicyRequest struct {
+	Name        *string                `json:"name" binding:"omitempty,max=100"`
+	Description *string                `json:"description" binding:"omitempty,max=255"`
+	Tiers       []model.EscalationTier `json:"tiers" binding:"omitempty"`
+	Status      *int                   `json:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// OnCallScheduleRequest 值班表请求（更新时未传的字段保持不变）
+type OnCallScheduleRequest struct {
+	Name          *string  `json:"name" binding:"omitempty,max=100"`
+	UserIDs       []uint64 `json:"user_ids" binding:"omitempty"`
+	RotationHours *int     `json:"rotation_hours" binding:"omitempty"`
+	HandoffAt     string   `json:"handoff_at" binding:"omitempty"`
+	Status        *int     `json:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// OnCallOverrideRequest 替班请求
+type OnCallOverrideRequest struct {
+	UserID    uint64 `json:"user_id" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+// CreatePolicy 创建升级策略
+func (h *OnCallHandler) CreatePolicy(c *gin.Context) {
+	var req EscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.onCallService.CreatePolicy(&service.EscalationPolicyParams{
+		Name:        req.Name,
+		Description: req.Description,
+		Tiers:       req.Tiers,
+		Status:      req.Status,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    policy,
+	})
+}
+
+// GetPolicies 获取升级策略列表
+func (h *OnCallHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.onCallService.ListPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取升级策略列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": policies,
+	})
+}
+
+// GetPolicy 获取升级策略详情
+func (h *OnCallHandler) GetPolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	policy, err := h.onCallService.GetPolicy(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "升级策略不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": policy,
+	})
+}
+
+// UpdatePolicy 更新升级策略
+func (h *OnCallHandler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req EscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.onCallService.UpdatePolicy(id, &service.EscalationPolicyParams{
+		Name:        req.Name,
+		Description: req.Description,
+		Tiers:       req.Tiers,
+		Status:      req.Status,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    policy,
+	})
+}
+
+// DeletePolicy 删除升级策略
+func (h *OnCallHandler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.onCallService.DeletePolicy(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// CreateSchedule 创建值班表
+func (h *OnCallHandler) CreateSchedule(c *gin.Context) {
+	var req OnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	params, err := req.toParams()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.onCallService.CreateSchedule(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    schedule,
+	})
+}
+
+// GetSchedules 获取值班表列表
+func (h *OnCallHandler) GetSchedules(c *gin.Context) {
+	schedules, err := h.onCallService.ListSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取值班表列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedules,
+	})
+}
+
+// GetSchedule 获取值班表详情
+func (h *OnCallHandler) GetSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	schedule, err := h.onCallService.GetSchedule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "值班表不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": schedule,
+	})
+}
+
+// UpdateSchedule 更新值班表
+func (h *OnCallHandler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req OnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	params, err := req.toParams()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.onCallService.UpdateSchedule(id, params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    schedule,
+	})
+}
+
+// DeleteSchedule 删除值班表
+func (h *OnCallHandler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.onCallService.DeleteSchedule(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// GetCurrentOnCall 获取值班表当前（或 at 指定时刻）的值班用户
+func (h *OnCallHandler) GetCurrentOnCall(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	at := time.Now()
+	if v := c.Query("at"); v != "" {
+		if at, err = parseQueryTime(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的时间",
+			})
+			return
+		}
+	}
+
+	user, err := h.onCallService.CurrentOnCall(id, at)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"at":   at,
+			"user": user,
+		},
+	})
+}
+
+// CreateOverride 创建替班
+func (h *OnCallHandler) CreateOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req OnCallOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+	start, err := parseQueryTime(req.StartTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的开始时间",
+		})
+		return
+	}
+	end, err := parseQueryTime(req.EndTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的结束时间",
+		})
+		return
+	}
+
+	override, err := h.onCallService.CreateOverride(id, req.UserID, start, end)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    override,
+	})
+}
+
+// GetOverrides 获取值班表当前和未来的替班
+func (h *OnCallHandler) GetOverrides(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	overrides, err := h.onCallService.ListOverrides(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取替班列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": overrides,
+	})
+}
+
+// DeleteOverride 删除替班
+func (h *OnCallHandler) DeleteOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	overrideID, err := strconv.ParseUint(c.Param("override_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的替班ID",
+		})
+		return
+	}
+
+	if err := h.onCallService.DeleteOverride(id, overrideID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// toParams 转换为服务层值班表参数
+func (r OnCallScheduleRequest) toParams() (*service.OnCallScheduleParams, error) {
+	params := &service.OnCallScheduleParams{
+		Name:          r.Name,
+		UserIDs:       r.UserIDs,
+		RotationHours: r.RotationHours,
+		Status:        r.Status,
+	}
+	if r.HandoffAt != "" {
+		t, err := parseQueryTime(r.HandoffAt)
+		if err != nil {
+			return nil, errors.New("无效的轮换起点时间")
+		}
+		params.HandoffAt = &t
+	}
+	return params, nil
+}
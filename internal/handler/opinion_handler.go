@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
 	"sentinel-opinion-monitor/internal/model"
 	"sentinel-opinion-monitor/internal/service"
 )
@@ -45,9 +49,45 @@ func (h *OpinionHandler) GetOpinion(c *gin.Context) {
 	})
 }
 
-// GetAllOpinions 获取所有舆情
+// GetAllOpinions 获取舆情列表，支持按条件筛选；传入 page 或 page_size 时分页并返回总数
 func (h *OpinionHandler) GetAllOpinions(c *gin.Context) {
-	opinions, err := h.service.GetAllOpinions()
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	_, paged := c.GetQuery("page")
+	if _, ok := c.GetQuery("page_size"); ok {
+		paged = true
+	}
+	if !paged {
+		opinions, err := h.service.GetAllOpinions(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "获取舆情列表失败",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data": opinions,
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	opinions, total, err := h.service.ListOpinions(filter, page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "获取舆情列表失败",
@@ -56,7 +96,41 @@ func (h *OpinionHandler) GetAllOpinions(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": opinions,
+		"data":      opinions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetSentimentStats 按情感标签聚合舆情（支持与列表相同的筛选条件）
+func (h *OpinionHandler) GetSentimentStats(c *gin.Context) {
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	counts, err := h.service.GetSentimentStats(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取情感统计失败",
+		})
+		return
+	}
+
+	var total int64
+	for _, item := range counts {
+		total += item.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"total": total,
+			"items": counts,
+		},
 	})
 }
 
@@ -82,3 +156,89 @@ func (h *OpinionHandler) CreateOpinion(c *gin.Context) {
 	})
 }
 
+// parseOpinionFilter 从查询参数解析舆情筛选条件
+// 支持 scenario_id、group_id、sentiment、source、author_id、start_time、end_time，
+// 以及处置相关的 handling_status（逗号分隔多个）、assignee_id、priority、overdue
+func parseOpinionFilter(c *gin.Context) (service.OpinionFilter, error) {
+	var filter service.OpinionFilter
+
+	if v := c.Query("scenario_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("无效的场景ID")
+		}
+		filter.ScenarioID = id
+	}
+	if v := c.Query("group_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("无效的监测组ID")
+		}
+		filter.GroupID = id
+	}
+	if v := c.Query("sentiment"); v != "" {
+		if !sentiment.IsValidLabel(v) {
+			return filter, errors.New("无效的情感标签，可选值: positive, neutral, negative")
+		}
+		filter.Sentiment = v
+	}
+	filter.Source = c.Query("source")
+	if v := c.Query("author_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("无效的作者ID")
+		}
+		filter.AuthorID = id
+	}
+
+	if v := c.Query("handling_status"); v != "" {
+		for _, status := range strings.Split(v, ",") {
+			if !service.IsValidHandlingStatus(status) {
+				return filter, errors.New("无效的处置状态，可选值: new, in_progress, responded, ignored, escalated")
+			}
+			filter.HandlingStatuses = append(filter.HandlingStatuses, status)
+		}
+	}
+	if v := c.Query("assignee_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, errors.New("无效的处理人ID")
+		}
+		filter.AssigneeID = id
+	}
+	if v := c.Query("priority"); v != "" {
+		if !service.IsValidPriority(v) {
+			return filter, errors.New("无效的优先级，可选值: low, medium, high, urgent")
+		}
+		filter.Priority = v
+	}
+	filter.Overdue = c.Query("overdue") == "true"
+
+	if v := c.Query("start_time"); v != "" {
+		t, err := parseQueryTime(v)
+		if err != nil {
+			return filter, errors.New("无效的开始时间")
+		}
+		filter.StartTime = &t
+	}
+	if v := c.Query("end_time"); v != "" {
+		t, err := parseQueryTime(v)
+		if err != nil {
+			return filter, errors.New("无效的结束时间")
+		}
+		filter.EndTime = &t
+	}
+
+	return filter, nil
+}
+
+// parseQueryTime 解析查询参数中的时间，支持 RFC3339、YYYY-MM-DD HH:MM:SS 和 YYYY-MM-DD
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler 场景简报处理器
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+// NewReportHandler 创建场景简报处理器实例
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// ReportTemplateRequest 简报模板请求（更新时未传的字段保持不变）
+type ReportTemplateRequest struct {
+	ScenarioID *uint64  `json:"scenario_id" binding:"omitempty"`
+	Name       *string  `json:"name" binding:"omitempty,max=100"`
+	Frequency  *string  `json:"frequency" binding:"omitempty,oneof=daily weekly monthly"`
+	Sections   []string `json:"sections" binding:"omitempty"`
+	Formats    []string `json:"formats" binding:"omitempty"`
+	TopN       *int     `json:"top_n" binding:"omitempty"`
+	Emails     []string `json:"emails" binding:"omitempty"`
+	ChannelIDs []uint64 `json:"channel_ids" binding:"omitempty"`
+	Status     *int     `json:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// toParams 转换为服务层参数
+func (r *ReportTemplateRequest) toParams() *service.ReportTemplateParams {
+	return &service.ReportTemplateParams{
+		ScenarioID: r.ScenarioID,
+		Name:       r.Name,
+		Frequency:  r.Frequency,
+		Sections:   r.Sections,
+		Formats:    r.Formats,
+		TopN:       r.TopN,
+		Emails:     r.Emails,
+		ChannelIDs: r.ChannelIDs,
+		Status:     r.Status,
+	}
+}
+
+// RunReportRequest 立即生成简报请求，未指定时间范围时使用模板周期的最近一个完整周期
+type RunReportRequest struct {
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	Deliver   bool       `json:"deliver"` // 生成后发送给模板配置的收件人和通知渠道
+}
+
+// CreateTemplate 创建简报模板
+func (h *ReportHandler) CreateTemplate(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tmpl, err := h.reportService.CreateTemplate(userID, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    tmpl,
+	})
+}
+
+// GetTemplates 获取简报模板列表（支持 scenario_id 查询参数）
+func (h *ReportHandler) GetTemplates(c *gin.Context) {
+	var scenarioID uint64
+	if v := c.Query("scenario_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的场景ID",
+			})
+			return
+		}
+		scenarioID = id
+	}
+
+	templates, err := h.reportService.ListTemplates(scenarioID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取简报模板列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": templates,
+	})
+}
+
+// GetTemplate 获取简报模板详情
+func (h *ReportHandler) GetTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	tmpl, err := h.reportService.GetTemplate(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "简报模板不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tmpl,
+	})
+}
+
+// UpdateTemplate 更新简报模板
+func (h *ReportHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req ReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tmpl, err := h.reportService.UpdateTemplate(id, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    tmpl,
+	})
+}
+
+// DeleteTemplate 删除简报模板
+func (h *ReportHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.reportService.DeleteTemplate(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// RunTemplate 按模板立即生成一份简报
+func (h *ReportHandler) RunTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req RunReportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "请求参数错误",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	var start, end time.Time
+	if req.StartTime != nil {
+		start = *req.StartTime
+	}
+	if req.EndTime != nil {
+		end = *req.EndTime
+	}
+
+	report, err := h.reportService.Run(id, start, end, req.Deliver)
+	if err != nil {
+		resp := gin.H{
+			"error": err.Error(),
+		}
+		if report != nil {
+			resp["data"] = report
+		}
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "生成成功",
+		"data":    report,
+	})
+}
+
+// GetReports 分页获取已生成的简报（支持 scenario_id、template_id、status 查询参数）
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	var filter repository.ReportFilter
+	if v := c.Query("scenario_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的场景ID",
+			})
+			return
+		}
+		filter.ScenarioID = id
+	}
+	if v := c.Query("template_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的模板ID",
+			})
+			return
+		}
+		filter.TemplateID = id
+	}
+	if v := c.Query("status"); v != "" {
+		if v != model.ReportStatusGenerating && v != model.ReportStatusSuccess && v != model.ReportStatusFailed {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的状态，可选值: generating, success, failed",
+			})
+			return
+		}
+		filter.Status = v
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	reports, total, err := h.reportService.ListReports(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取简报列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"list":      reports,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetReport 获取简报详情
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetReport(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "简报不存在",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// DownloadReport 下载简报文件（format 查询参数：pdf 或 xlsx，默认 pdf）
+func (h *ReportHandler) DownloadReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	path, filename, err := h.reportService.ReportFile(id, c.DefaultQuery("format", model.ReportFormatPDF))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.FileAttachment(path, filename)
+}
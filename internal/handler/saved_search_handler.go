@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchHandler 保存的检索处理器
+type SavedSearchHandler struct {
+	savedSearchService service.SavedSearchService
+}
+
+// NewSavedSearchHandler 创建保存的检索处理器实例
+func NewSavedSearchHandler(savedSearchService service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+	}
+}
+
+// SavedSearchRequest 保存的检索请求（更新时未传的字段保持不变）
+type SavedSearchRequest struct {
+	Name        *string                `json:"name" binding:"omitempty,max=100"`
+	Query       *string                `json:"q" binding:"omitempty,max=200"`
+	Mode        *string                `json:"mode" binding:"omitempty,oneof=natural boolean phrase"`
+	Sort        *string                `json:"sort" binding:"omitempty,oneof=relevance time"`
+	Filter      *service.OpinionFilter `json:"filter"`
+	RecentHours *int                   `json:"recent_hours" binding:"omitempty,min=0"`
+	Visibility  *string                `json:"visibility" binding:"omitempty,oneof=private team"`
+}
+
+// toParams 转换为服务层参数
+func (r *SavedSearchRequest) toParams() *service.SavedSearchParams {
+	return &service.SavedSearchParams{
+		Name:        r.Name,
+		Query:       r.Query,
+		Mode:        r.Mode,
+		Sort:        r.Sort,
+		Filter:      r.Filter,
+		RecentHours: r.RecentHours,
+		Visibility:  r.Visibility,
+	}
+}
+
+// SubscriptionRequest 订阅请求
+type SubscriptionRequest struct {
+	Frequency  string   `json:"frequency" binding:"required,oneof=realtime hourly daily"`
+	Channels   []string `json:"channels"`
+	ChannelIDs []uint64 `json:"channel_ids"`
+}
+
+// CreateSavedSearch 保存检索
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	saved, err := h.savedSearchService.Create(userID, req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "保存成功",
+		"data":    saved,
+	})
+}
+
+// GetSavedSearches 获取可见的检索列表（mine=true 时只返回自己创建的）
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	searches, err := h.savedSearchService.List(userID, c.Query("mine") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取保存的检索失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": searches,
+	})
+}
+
+// GetSavedSearch 获取保存的检索详情
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	saved, err := h.savedSearchService.Get(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": saved,
+	})
+}
+
+// UpdateSavedSearch 更新保存的检索
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	saved, err := h.savedSearchService.Update(id, userID, hasRole(c, "admin"), req.toParams())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+		"data":    saved,
+	})
+}
+
+// DeleteSavedSearch 删除保存的检索
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.Delete(id, userID, hasRole(c, "admin")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// RunSavedSearch 执行保存的检索（分页；facets=true 时返回分面统计）
+func (h *SavedSearchHandler) RunSavedSearch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	result, err := h.savedSearchService.Run(id, userID, page, pageSize, c.Query("facets") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// Subscribe 订阅保存的检索，已订阅时更新推送方式和渠道
+func (h *SavedSearchHandler) Subscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	sub, err := h.savedSearchService.Subscribe(id, userID, hasRole(c, "admin"), &service.SubscriptionParams{
+		Frequency:  req.Frequency,
+		Channels:   req.Channels,
+		ChannelIDs: req.ChannelIDs,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "订阅成功",
+		"data":    sub,
+	})
+}
+
+// Unsubscribe 取消订阅
+func (h *SavedSearchHandler) Unsubscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.Unsubscribe(id, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已取消订阅",
+	})
+}
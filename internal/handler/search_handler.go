@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 舆情全文检索处理器
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler 创建舆情全文检索处理器实例
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// SearchOpinions 全文检索舆情内容（q 检索词；mode：natural、boolean 或 phrase；sort：relevance 或 time；
+// facets=true 时返回按来源、情感和监测组的分面统计；筛选参数同舆情列表）
+func (h *SearchHandler) SearchOpinions(c *gin.Context) {
+	filter, err := parseOpinionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	result, err := h.searchService.Search(service.SearchRequest{
+		Query:    c.Query("q"),
+		Mode:     c.Query("mode"),
+		Sort:     c.Query("sort"),
+		Filter:   filter,
+		Facets:   c.Query("facets") == "true",
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
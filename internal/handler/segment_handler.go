@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SegmentHandler 分词处理器
+type SegmentHandler struct {
+	segmentService service.SegmentService
+}
+
+// NewSegmentHandler 创建分词处理器实例
+func NewSegmentHandler(segmentService service.SegmentService) *SegmentHandler {
+	return &SegmentHandler{
+		segmentService: segmentService,
+	}
+}
+
+// SegmentRequest 分词请求
+type SegmentRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// Segment 使用当前词典（含监测组用户词典）分词并提取关键词，便于调试关键词匹配
+func (h *SegmentHandler) Segment(c *gin.Context) {
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	tokens, err := h.segmentService.Cut(req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "分词失败",
+		})
+		return
+	}
+
+	keywords, err := h.segmentService.ExtractKeywords(req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "提取关键词失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"tokens":   tokens,
+			"keywords": keywords,
+		},
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SentimentHandler 情感分析处理器
+type SentimentHandler struct {
+	sentimentService service.SentimentService
+}
+
+// NewSentimentHandler 创建情感分析处理器实例
+func NewSentimentHandler(sentimentService service.SentimentService) *SentimentHandler {
+	return &SentimentHandler{
+		sentimentService: sentimentService,
+	}
+}
+
+// CreateLexiconEntryRequest 创建自定义词条请求
+type CreateLexiconEntryRequest struct {
+	ScenarioID uint64  `json:"scenario_id" binding:"omitempty"`
+	Word       string  `json:"word" binding:"required,max=100"`
+	Type       string  `json:"type" binding:"omitempty,oneof=sentiment negation degree"`
+	Weight     float64 `json:"weight" binding:"omitempty"`
+}
+
+// UpdateLexiconEntryRequest 更新自定义词条请求
+type UpdateLexiconEntryRequest struct {
+	Type   string   `json:"type" binding:"omitempty,oneof=sentiment negation degree"`
+	Weight *float64 `json:"weight" binding:"omitempty"`
+}
+
+// AnalyzeRequest 情感分析请求
+type AnalyzeRequest struct {
+	ScenarioID uint64 `json:"scenario_id" binding:"omitempty"`
+	Text       string `json:"text" binding:"required"`
+}
+
+// CreateLexiconEntry 创建自定义词条
+func (h *SentimentHandler) CreateLexiconEntry(c *gin.Context) {
+	var req CreateLexiconEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "请求参数错误",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.sentimentService.CreateLexiconEntry(req.ScenarioID, req.Word, req.Type, req.Weight)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "创建成功",
+		"data":    entry,
+	})
+}
+
+// GetLexiconEntries 获取自定义词条列表（scenario_id 为空或 0 表示全局词条）
+func (h *SentimentHandler) GetLexiconEntries(c *gin.Context) {
+	scenarioID, err := strconv.ParseUint(c.DefaultQuery("scenario_id", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的场景ID",
+		})
+		return
+	}
+
+	entries, err := h.sentimentService.GetLexiconEntries(scenarioID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "获取词条列表失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+	})
+}
+
+// UpdateLexiconEntry 更新自定义词条
+func (h *SentimentHandler) UpdateLexiconEntry(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	var req UpdateLexiconEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	if err := h.sentimentService.UpdateLexiconEntry(id, req.Type, req.Weight); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "更新成功",
+	})
+}
+
+// DeleteLexiconEntry 删除自定义词条
+func (h *SentimentHandler) DeleteLexiconEntry(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	if err := h.sentimentService.DeleteLexiconEntry(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "删除成功",
+	})
+}
+
+// Analyze 使用当前词典分析文本情感，便于调试自定义词条
+func (h *SentimentHandler) Analyze(c *gin.Context) {
+	var req AnalyzeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "请求参数错误",
+		})
+		return
+	}
+
+	result, err := h.sentimentService.Analyze(req.ScenarioID, req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "情感分析失败",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 场景统计处理器
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+// NewStatsHandler 创建场景统计处理器实例
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetScenarioStats 获取场景统计看板数据
+func (h *StatsHandler) GetScenarioStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	query, ok := bindStatsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.statsService.GetScenarioStats(id, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stats,
+	})
+}
+
+// CompareScenarios 对比多个场景的声量份额、情感、渠道构成和走势
+func (h *StatsHandler) CompareScenarios(c *gin.Context) {
+	ids, err := parseIDList(c.Query("scenario_ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的场景ID",
+		})
+		return
+	}
+
+	query, ok := bindStatsQuery(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.statsService.CompareScenarios(ids, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": comparison,
+	})
+}
+
+// bindStatsQuery 解析统计查询参数（interval、start_time、end_time、top），失败时已写入响应
+func bindStatsQuery(c *gin.Context) (service.StatsQuery, bool) {
+	var err error
+	query := service.StatsQuery{Interval: c.Query("interval")}
+	if v := c.Query("start_time"); v != "" {
+		if query.Start, err = parseQueryTime(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的开始时间",
+			})
+			return query, false
+		}
+	}
+	if v := c.Query("end_time"); v != "" {
+		if query.End, err = parseQueryTime(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的结束时间",
+			})
+			return query, false
+		}
+	}
+	if v := c.Query("top"); v != "" {
+		query.Top, _ = strconv.Atoi(v)
+	}
+	return query, true
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamTicketHandler 实时推送连接票据处理器
+type StreamTicketHandler struct {
+	ticketService service.StreamTicketService
+}
+
+// NewStreamTicketHandler 创建实时推送连接票据处理器实例
+func NewStreamTicketHandler(ticketService service.StreamTicketService) *StreamTicketHandler {
+	return &StreamTicketHandler{
+		ticketService: ticketService,
+	}
+}
+
+// Issue 为当前用户签发一次性的推送连接票据
+func (h *StreamTicketHandler) Issue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	username, _ := c.Get("username")
+	roles, _ := c.Get("roles")
+	ticket := &service.StreamTicket{UserID: userID}
+	ticket.Username, _ = username.(string)
+	ticket.Roles, _ = roles.([]string)
+
+	token, ttl, err := h.ticketService.Issue(ticket)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticket":     token,
+		"expires_in": int(ttl.Seconds()),
+	})
+}
+
+// RedeemTicket 兑换推送连接票据，供 middleware.StreamAuthMiddleware 认证推送连接
+func (h *StreamTicketHandler) RedeemTicket(ticket string) (uint64, string, []string, error) {
+	user, err := h.ticketService.Redeem(ticket)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return user.UserID, user.Username, user.Roles, nil
+}
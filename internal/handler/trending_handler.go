@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"sentinel-opinion-monitor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrendingHandler 热词和话题处理器
+type TrendingHandler struct {
+	trendingService service.TrendingService
+}
+
+// NewTrendingHandler 创建热词和话题处理器实例
+func NewTrendingHandler(trendingService service.TrendingService) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+	}
+}
+
+// GetTrending 获取场景热词（按突发得分降序）
+func (h *TrendingHandler) GetTrending(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	terms, err := h.trendingService.GetTrendingTerms(id, parseLimit(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": terms,
+	})
+}
+
+// GetTopics 获取场景话题及代表性舆情（按热度得分降序）
+func (h *TrendingHandler) GetTopics(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "无效的ID",
+		})
+		return
+	}
+
+	topics, err := h.trendingService.GetTopics(id, parseLimit(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": topics,
+	})
+}
+
+// parseLimit 解析 limit 参数，默认 20，最大 100
+func parseLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		return 20
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
@@ -0,0 +1,316 @@
+// Package importer 读取 CSV、NDJSON 或 XLSX 文件中的舆情，按列映射转换为 model.Opinion 并逐行校验，供批量导入使用
+package importer
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sentinel-opinion-monitor/internal/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 文件格式
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// 可导入的舆情字段
+const (
+	FieldContent      = "content"
+	FieldSource       = "source"
+	FieldAuthor       = "author"
+	FieldURL          = "url"
+	FieldCreatedAt    = "created_at"
+	FieldLikeCount    = "like_count"
+	FieldCommentCount = "comment_count"
+	FieldShareCount   = "share_count"
+
+	FieldAuthorExternalID = "author_external_id"
+	FieldAuthorFollowers  = "author_followers"
+	FieldAuthorVerified   = "author_verified"
+)
+
+// IgnoreColumn 列映射的目标为该值时忽略这一列
+const IgnoreColumn = "-"
+
+// maxContentBytes 舆情内容的最大字节数（TEXT 列）
+const maxContentBytes = 65535
+
+// Field 可导入的舆情字段及默认识别的列名（不区分大小写）
+type Field struct {
+	Key     string
+	Aliases []string
+}
+
+// Fields 可导入的舆情字段；默认列名包含舆情导出文件的表头，导出的文件可以直接导入
+var Fields = []Field{
+	{FieldContent, []string{"内容", "正文", "text"}},
+	{FieldSource, []string{"来源", "渠道", "平台"}},
+	{FieldAuthor, []string{"作者", "发布账号", "用户名"}},
+	{FieldURL, []string{"原文链接", "链接", "网址", "link"}},
+	{FieldCreatedAt, []string{"入库时间", "发布时间", "时间", "published_at"}},
+	{FieldLikeCount, []string{"点赞数", "likes"}},
+	{FieldCommentCount, []string{"评论数", "comments"}},
+	{FieldShareCount, []string{"转发数", "shares", "reposts"}},
+	{FieldAuthorExternalID, []string{"作者ID", "账号ID", "用户ID", "uid", "user_id"}},
+	{FieldAuthorFollowers, []string{"粉丝数", "followers"}},
+	{FieldAuthorVerified, []string{"是否认证", "认证", "verified"}},
+}
+
+// IsValidFormat 判断文件格式是否有效
+func IsValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON || format == FormatXLSX
+}
+
+// FormatFromFilename 根据文件扩展名判断格式，无法识别时返回空字符串
+func FormatFromFilename(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".xlsx":
+		return FormatXLSX
+	default:
+		return ""
+	}
+}
+
+// IsValidField 判断是否为可导入的舆情字段
+func IsValidField(key string) bool {
+	for _, field := range Fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Mapper 把文件的列名映射到舆情字段
+type Mapper struct {
+	columns map[string]string // 小写列名 -> 舆情字段，IgnoreColumn 表示忽略
+}
+
+// NewMapper 创建列映射：先使用字段名和默认列名，再依次应用配置文件和请求中的映射（文件列名 -> 舆情字段）
+func NewMapper(mappings ...map[string]string) (*Mapper, error) {
+	m := &Mapper{columns: make(map[string]string)}
+	for _, field := range Fields {
+		m.columns[field.Key] = field.Key
+		for _, alias := range field.Aliases {
+			m.columns[strings.ToLower(alias)] = field.Key
+		}
+	}
+	for _, mapping := range mappings {
+		for column, field := range mapping {
+			column = strings.ToLower(strings.TrimSpace(column))
+			if column == "" {
+				continue
+			}
+			if field != IgnoreColumn && !IsValidField(field) {
+				return nil, fmt.Errorf("列 %s 映射到了无效的字段 %s", column, field)
+			}
+			m.columns[column] = field
+		}
+	}
+	return m, nil
+}
+
+// Field 返回列对应的舆情字段，未映射或忽略的列返回空字符串
+func (m *Mapper) Field(column string) string {
+	field := m.columns[strings.ToLower(strings.TrimSpace(column))]
+	if field == IgnoreColumn {
+		return ""
+	}
+	return field
+}
+
+// CheckHeader 校验表头：必须有内容列，且不能有多列映射到同一字段
+func (m *Mapper) CheckHeader(headers []string) error {
+	seen := make(map[string]string)
+	for _, header := range headers {
+		field := m.Field(header)
+		if field == "" {
+			continue
+		}
+		if other, ok := seen[field]; ok {
+			return fmt.Errorf("列 %s 和 %s 都映射到了字段 %s", other, header, field)
+		}
+		seen[field] = header
+	}
+	if _, ok := seen[FieldContent]; !ok {
+		return fmt.Errorf("文件中没有映射到 %s 的列", FieldContent)
+	}
+	return nil
+}
+
+// Parse 按列映射把一行转换为舆情并校验；没有来源列或来源为空时使用 defaultSource，
+// 没有发布时间时 CreatedAt 为零值（入库时间为导入时刻）
+func (m *Mapper) Parse(rec *Record, defaultSource string, now time.Time) (*model.Opinion, error) {
+	if rec.Err != nil {
+		return nil, rec.Err
+	}
+
+	values := make(map[string]string)
+	columns := make(map[string]string)
+	for column, value := range rec.Values {
+		field := m.Field(column)
+		if field == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if other, ok := columns[field]; ok && value != "" && values[field] != "" && values[field] != value {
+			return nil, fmt.Errorf("列 %s 和 %s 都映射到了字段 %s", other, column, field)
+		}
+		if value != "" || values[field] == "" {
+			values[field] = value
+			columns[field] = column
+		}
+	}
+
+	opinion := &model.Opinion{
+		Content: values[FieldContent],
+		Source:  values[FieldSource],
+		Author:  values[FieldAuthor],
+		URL:     values[FieldURL],
+	}
+	if opinion.Content == "" {
+		return nil, fmt.Errorf("%s 不能为空", FieldContent)
+	}
+	if len(opinion.Content) > maxContentBytes {
+		return nil, fmt.Errorf("%s 超过 %d 字节", FieldContent, maxContentBytes)
+	}
+	if opinion.Source == "" {
+		opinion.Source = defaultSource
+	}
+	if opinion.Source == "" {
+		return nil, fmt.Errorf("%s 不能为空，可以在导入时指定默认来源", FieldSource)
+	}
+	if utf8.RuneCountInString(opinion.Source) > 255 {
+		return nil, fmt.Errorf("%s 超过 255 个字符", FieldSource)
+	}
+	if utf8.RuneCountInString(opinion.Author) > 100 {
+		return nil, fmt.Errorf("%s 超过 100 个字符", FieldAuthor)
+	}
+	if len(opinion.URL) > 1024 {
+		return nil, fmt.Errorf("%s 超过 1024 个字符", FieldURL)
+	}
+	opinion.AuthorExternalID = values[FieldAuthorExternalID]
+	if utf8.RuneCountInString(opinion.AuthorExternalID) > 100 {
+		return nil, fmt.Errorf("%s 超过 100 个字符", FieldAuthorExternalID)
+	}
+	if v := values[FieldAuthorVerified]; v != "" {
+		verified, err := parseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s 格式无效: %s", FieldAuthorVerified, v)
+		}
+		opinion.AuthorVerified = verified
+	}
+
+	if v := values[FieldCreatedAt]; v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s 格式无效: %s", FieldCreatedAt, v)
+		}
+		if t.After(now) {
+			return nil, fmt.Errorf("%s 晚于当前时间: %s", FieldCreatedAt, v)
+		}
+		opinion.CreatedAt = t
+	}
+
+	counts := []struct {
+		field string
+		dest  *int64
+	}{
+		{FieldLikeCount, &opinion.LikeCount},
+		{FieldCommentCount, &opinion.CommentCount},
+		{FieldShareCount, &opinion.ShareCount},
+		{FieldAuthorFollowers, &opinion.AuthorFollowers},
+	}
+	for _, count := range counts {
+		v := values[count.field]
+		if v == "" {
+			continue
+		}
+		n, err := parseCount(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s 格式无效: %s", count.field, v)
+		}
+		*count.dest = n
+	}
+	return opinion, nil
+}
+
+// timeLayouts 支持的发布时间格式，按本地时区解析
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"2006年01月02日 15:04",
+	"2006年01月02日",
+}
+
+// parseTime 解析发布时间：支持常见的日期时间格式、RFC 3339、10 位秒级或 13 位毫秒级时间戳，
+// 以及 XLSX 中未设置日期格式的日期序列值
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		switch len(v) {
+		case 10:
+			return time.Unix(n, 0), nil
+		case 13:
+			return time.UnixMilli(n), nil
+		}
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f < 100000 {
+		t, err := excelize.ExcelDateToTime(f, false)
+		if err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法识别的时间: %s", v)
+}
+
+// parseBool 解析是否认证：支持 true/false、1/0、yes/no、是/否、已认证/未认证
+func parseBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "1", "yes", "y", "是", "已认证", "认证":
+		return true, nil
+	case "false", "0", "no", "n", "否", "未认证":
+		return false, nil
+	}
+	return false, fmt.Errorf("无效的布尔值: %s", v)
+}
+
+// parseCount 解析互动数和粉丝数：支持千分位逗号和 "1.2万" 这类写法，不能为负数
+func parseCount(v string) (int64, error) {
+	v = strings.ReplaceAll(v, ",", "")
+	multiplier := 1.0
+	if strings.HasSuffix(v, "万") {
+		v = strings.TrimSuffix(v, "万")
+		multiplier = 10000
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("无效的数量: %s", v)
+	}
+	return int64(math.Round(f * multiplier)), nil
+}
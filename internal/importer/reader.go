@@ -0,0 +1,277 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxLineBytes NDJSON 单行的最大字节数
+const maxLineBytes = 1 << 20
+
+// Record 文件中的一行数据
+type Record struct {
+	Row    int               // 行号：CSV 和 XLSX 为表格中的行号（表头为第 1 行），NDJSON 为文件中的行号
+	Values map[string]string // 列名 -> 原始值
+	Err    error             // 该行无法解析时的错误（如 NDJSON 中的无效 JSON），不影响其他行
+}
+
+// Reader 逐行读取文件，读完时 Next 返回 io.EOF
+type Reader interface {
+	Headers() []string // 表头；NDJSON 没有统一的表头，返回 nil
+	Next() (*Record, error)
+	Close() error
+}
+
+// NewReader 按格式创建 Reader
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		return newNDJSONReader(r), nil
+	case FormatXLSX:
+		return newXLSXReader(r)
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", format)
+	}
+}
+
+// csvReader 读取 CSV，自动去掉 UTF-8 BOM
+type csvReader struct {
+	r       *csv.Reader
+	headers []string
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+		_, _ = br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	headers, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("文件为空")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取表头失败: %w", err)
+	}
+	return &csvReader{r: cr, headers: trimHeaders(headers), row: 1}, nil
+}
+
+// Headers 返回表头
+func (cr *csvReader) Headers() []string {
+	return cr.headers
+}
+
+// Next 读取下一行，跳过空行
+func (cr *csvReader) Next() (*Record, error) {
+	for {
+		fields, err := cr.r.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		cr.row++
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行格式错误: %w", cr.row, err)
+		}
+		if isBlank(fields) {
+			continue
+		}
+		return &Record{Row: cr.row, Values: zipRow(cr.headers, fields)}, nil
+	}
+}
+
+// Close CSV 不需要释放资源
+func (cr *csvReader) Close() error {
+	return nil
+}
+
+// ndjsonReader 读取 NDJSON，每行一个 JSON 对象
+type ndjsonReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxLineBytes)
+	return &ndjsonReader{s: s}
+}
+
+// Headers NDJSON 没有统一的表头
+func (nr *ndjsonReader) Headers() []string {
+	return nil
+}
+
+// Next 读取下一行，跳过空行；无效的 JSON 记录在 Record.Err 中
+func (nr *ndjsonReader) Next() (*Record, error) {
+	for nr.s.Scan() {
+		nr.line++
+		line := bytes.TrimSpace(nr.s.Bytes())
+		if nr.line == 1 {
+			line = bytes.TrimPrefix(line, []byte("\xEF\xBB\xBF"))
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); err != nil {
+			return &Record{Row: nr.line, Err: errors.New("不是有效的 JSON 对象")}, nil
+		}
+		values := make(map[string]string, len(obj))
+		for key, value := range obj {
+			values[key] = jsonValueString(value)
+		}
+		return &Record{Row: nr.line, Values: values}, nil
+	}
+	if err := nr.s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("第 %d 行超过 %d 字节", nr.line+1, maxLineBytes)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Close NDJSON 不需要释放资源
+func (nr *ndjsonReader) Close() error {
+	return nil
+}
+
+// xlsxReader 流式读取 XLSX 的第一个工作表
+type xlsxReader struct {
+	f       *excelize.File
+	rows    *excelize.Rows
+	headers []string
+	row     int
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开 XLSX 文件: %w", err)
+	}
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		f.Close()
+		return nil, errors.New("文件中没有工作表")
+	}
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("读取工作表失败: %w", err)
+	}
+
+	xr := &xlsxReader{f: f, rows: rows}
+	for rows.Next() {
+		xr.row++
+		headers, err := rows.Columns()
+		if err != nil {
+			xr.Close()
+			return nil, fmt.Errorf("读取表头失败: %w", err)
+		}
+		// 表头为第一个非空行
+		if !isBlank(headers) {
+			xr.headers = trimHeaders(headers)
+			return xr, nil
+		}
+	}
+	xr.Close()
+	return nil, errors.New("文件为空")
+}
+
+// Headers 返回表头
+func (xr *xlsxReader) Headers() []string {
+	return xr.headers
+}
+
+// Next 读取下一行，跳过空行
+func (xr *xlsxReader) Next() (*Record, error) {
+	for xr.rows.Next() {
+		xr.row++
+		cells, err := xr.rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行读取失败: %w", xr.row, err)
+		}
+		if isBlank(cells) {
+			continue
+		}
+		return &Record{Row: xr.row, Values: zipRow(xr.headers, cells)}, nil
+	}
+	if err := xr.rows.Error(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Close 清理读取时产生的临时文件
+func (xr *xlsxReader) Close() error {
+	_ = xr.rows.Close()
+	return xr.f.Close()
+}
+
+// trimHeaders 去掉表头两端的空白
+func trimHeaders(headers []string) []string {
+	trimmed := make([]string, len(headers))
+	for i, header := range headers {
+		trimmed[i] = strings.TrimSpace(header)
+	}
+	return trimmed
+}
+
+// zipRow 把一行的单元格按表头组合为 列名 -> 值，没有表头的列忽略
+func zipRow(headers, fields []string) map[string]string {
+	values := make(map[string]string, len(headers))
+	for i, header := range headers {
+		if header == "" || i >= len(fields) {
+			continue
+		}
+		values[header] = fields[i]
+	}
+	return values
+}
+
+// isBlank 判断一行是否全部为空
+func isBlank(fields []string) bool {
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonValueString 把 JSON 值转换为文本，嵌套的对象和数组保留 JSON 格式
+func jsonValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		if val {
+			return "true"
+		}
+		return "false"
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// 未导入的原因
+const (
+	ResultInvalid   = "invalid"   // 校验失败
+	ResultDuplicate = "duplicate" // 与已有舆情或文件中前面的行重复
+)
+
+// ResultWriter 以 CSV 写出未导入的行（行号、原因、重复的舆情ID、说明），带 UTF-8 BOM 以便 Excel 正确识别编码
+type ResultWriter struct {
+	w *csv.Writer
+}
+
+// NewResultWriter 创建结果文件并写入表头
+func NewResultWriter(w io.Writer) (*ResultWriter, error) {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+	rw := &ResultWriter{w: csv.NewWriter(w)}
+	if err := rw.w.Write([]string{"row", "result", "opinion_id", "message"}); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// Write 写入一行；opinionID 为重复时已存在的舆情ID，没有时为 0
+func (rw *ResultWriter) Write(row int, result string, opinionID uint64, message string) error {
+	id := ""
+	if opinionID > 0 {
+		id = strconv.FormatUint(opinionID, 10)
+	}
+	return rw.w.Write([]string{strconv.Itoa(row), result, id, message})
+}
+
+// Flush 把缓冲的内容写入底层 io.Writer
+func (rw *ResultWriter) Flush() error {
+	rw.w.Flush()
+	return rw.w.Error()
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// newAlertService 创建告警服务
+func newAlertService() service.AlertService {
+	groupRepo := repository.NewMonitoringGroupRepository()
+	return service.NewAlertService(
+		repository.NewAlertRuleRepository(),
+		repository.NewAlertEventRepository(),
+		repository.NewAlertEventLogRepository(),
+		repository.NewEscalationPolicyRepository(),
+		repository.NewOpinionHitRepository(),
+		repository.NewScenarioRepository(),
+		groupRepo,
+		newSegmentService(groupRepo),
+	)
+}
+
+// newNotificationService 创建通知服务
+func newNotificationService() service.NotificationService {
+	var notifyCfg *config.NotifyConfig
+	if cfg := config.Get(); cfg != nil {
+		notifyCfg = &cfg.Notify
+	}
+	return service.NewNotificationService(notifyCfg, repository.NewNotificationChannelRepository(), repository.NewNotificationDeliveryRepository())
+}
+
+// newEscalationService 创建告警通知和升级服务
+func newEscalationService() service.EscalationService {
+	channelRepo := repository.NewNotificationChannelRepository()
+	policyRepo := repository.NewEscalationPolicyRepository()
+	onCallService := service.NewOnCallService(policyRepo, repository.NewOnCallScheduleRepository(), repository.NewUserRepository(), channelRepo)
+	return service.NewEscalationService(
+		repository.NewAlertRuleRepository(),
+		repository.NewAlertEventRepository(),
+		repository.NewAlertEventLogRepository(),
+		policyRepo,
+		onCallService,
+		newNotificationService(),
+		service.NewInboxService(repository.NewInboxRepository()),
+	)
+}
+
+// AlertJob 告警评估任务
+// 评估所有启用的告警规则：满足条件时按去重键产生告警事件（冷却期和静默期内不重复产生），
+// 不再满足条件的事件自动恢复。新产生的事件推送到规则配置的通知渠道，
+// 配置了升级策略的规则在事件超时未认领时逐级通知下一层级。建议通过 cron 每分钟执行一次。
+func AlertJob() {
+	ruleRepo := repository.NewAlertRuleRepository()
+	alertService := newAlertService()
+	escalationService := newEscalationService()
+
+	rules, err := ruleRepo.GetEnabled()
+	if err != nil {
+		appLogger.Get().Error("获取告警规则失败", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	firedTotal := 0
+	for _, rule := range rules {
+		fired, err := alertService.EvaluateRule(rule, now)
+		if err != nil {
+			appLogger.Get().Error("评估告警规则失败", zap.Uint64("rule_id", rule.ID), zap.Error(err))
+			continue
+		}
+		for _, event := range fired {
+			appLogger.Get().Warn("告警触发",
+				zap.Uint64("rule_id", rule.ID),
+				zap.Uint64("event_id", event.ID),
+				zap.String("severity", event.Severity),
+				zap.String("title", event.Title),
+			)
+			escalationService.Dispatch(rule, event, now)
+		}
+		firedTotal += len(fired)
+	}
+
+	escalated, err := escalationService.Escalate(now)
+	if err != nil {
+		appLogger.Get().Error("告警升级失败", zap.Error(err))
+	}
+
+	appLogger.Get().Info("告警评估完成", zap.Int("rules", len(rules)), zap.Int("fired", firedTotal), zap.Int("escalated", escalated))
+}
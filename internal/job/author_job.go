@@ -0,0 +1,41 @@
+package job
+
+import (
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// newAuthorService 按配置创建作者服务
+func newAuthorService(authorRepo repository.AuthorRepository) service.AuthorService {
+	var authorCfg *config.AuthorConfig
+	if cfg := config.Get(); cfg != nil {
+		authorCfg = &cfg.Author
+	}
+	return service.NewAuthorService(authorCfg, authorRepo, repository.NewScenarioRepository())
+}
+
+// AuthorInfluenceJob 作者影响力计算任务
+// 先为有作者名称但未关联作者的舆情（作者功能上线前入库的）创建作者并关联，
+// 再按粉丝数、认证状态和最近 influence_days 天舆情的平均互动量重新计算所有作者的影响力得分。
+// 建议通过 cron 每小时执行一次。
+func AuthorInfluenceJob() {
+	authorService := newAuthorService(repository.NewAuthorRepository())
+
+	linked, err := authorService.LinkExisting()
+	if err != nil {
+		appLogger.Get().Error("关联舆情作者失败", zap.Int("linked", linked), zap.Error(err))
+		return
+	}
+
+	updated, err := authorService.RefreshInfluence()
+	if err != nil {
+		appLogger.Get().Error("计算作者影响力失败", zap.Int("updated", updated), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("作者影响力计算完成", zap.Int("linked", linked), zap.Int("updated", updated))
+}
@@ -0,0 +1,69 @@
+package job
+
+import (
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// enrichBatchSize 富化重算每批处理的舆情数量
+const enrichBatchSize = 500
+
+// newSegmentService 按配置创建分词服务
+func newSegmentService(groupRepo repository.MonitoringGroupRepository) service.SegmentService {
+	var segmentCfg *config.SegmentConfig
+	if cfg := config.Get(); cfg != nil {
+		segmentCfg = &cfg.Segment
+	}
+	return service.NewSegmentService(groupRepo, segmentCfg)
+}
+
+// newEnrichmentService 按配置创建舆情富化服务
+func newEnrichmentService(scenarioRepo repository.ScenarioRepository, segmentService service.SegmentService) service.EnrichmentService {
+	sentimentService := service.NewSentimentService(repository.NewSentimentLexiconRepository(), scenarioRepo)
+
+	var analysisCfg *config.AnalysisConfig
+	if cfg := config.Get(); cfg != nil {
+		analysisCfg = &cfg.Analysis
+	}
+	return service.NewEnrichmentService(analysisCfg, sentimentService, segmentService)
+}
+
+// EnrichOpinionJob 使用当前分析器和全局词典重新富化所有舆情（情感、实体、主题、关键词），
+// 适用于历史数据回填、调整自定义词条或切换分析器后
+func EnrichOpinionJob() {
+	opinionRepo := repository.NewOpinionRepository()
+	segmentService := newSegmentService(repository.NewMonitoringGroupRepository())
+	enrichmentService := newEnrichmentService(repository.NewScenarioRepository(), segmentService)
+
+	var lastID uint64
+	updated := 0
+	for {
+		opinions, err := opinionRepo.GetBatchAfterID(lastID, enrichBatchSize)
+		if err != nil {
+			appLogger.Get().Error("获取舆情失败", zap.Uint64("after_id", lastID), zap.Error(err))
+			return
+		}
+		if len(opinions) == 0 {
+			break
+		}
+		lastID = opinions[len(opinions)-1].ID
+
+		if err := enrichmentService.EnrichOpinions(opinions); err != nil {
+			appLogger.Get().Error("富化舆情失败", zap.Uint64("after_id", lastID), zap.Error(err))
+			continue
+		}
+		for _, opinion := range opinions {
+			if err := opinionRepo.UpdateAnalysis(opinion); err != nil {
+				appLogger.Get().Error("更新舆情富化结果失败", zap.Uint64("opinion_id", opinion.ID), zap.Error(err))
+				continue
+			}
+			updated++
+		}
+	}
+
+	appLogger.Get().Info("舆情富化重算完成", zap.String("analyzer", enrichmentService.AnalyzerName()), zap.Int("updated", updated))
+}
@@ -0,0 +1,32 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// ExportJob 舆情导出任务
+// Web 服务创建导出任务后会在后台立即执行，并发数已满或服务重启时任务保持等待，由本任务依次执行；
+// 同时把长时间没有进度的执行中任务标记为失败，并删除超过保留时长的导出文件。
+// 建议通过 cron 每分钟执行一次。
+func ExportJob() {
+	var exportCfg *config.ExportConfig
+	if cfg := config.Get(); cfg != nil {
+		exportCfg = &cfg.Export
+	}
+	exportService := service.NewExportService(exportCfg, repository.NewExportTaskRepository(), repository.NewOpinionRepository())
+
+	processed, err := exportService.RunPending(time.Now())
+	if err != nil {
+		appLogger.Get().Error("执行导出任务失败", zap.Int("processed", processed), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("导出任务完成", zap.Int("processed", processed))
+}
@@ -0,0 +1,85 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// ImportJob 舆情导入任务
+// 指定 file 时导入该本地文件并等待完成（format 为空时按扩展名判断，mapping 为 JSON 格式的列映射），
+// 未导入的行及原因写入结果文件；导入的舆情经过富化后按所有启用的监测组匹配，完成后补算涉及场景的统计汇总。
+// 不指定 file 时执行 Web 服务中积压的导入任务，把长时间没有进度的执行中任务标记为失败，
+// 并删除超过保留时长的上传文件和结果文件，建议通过 cron 每分钟执行一次。
+func ImportJob(file, format, source, mapping string) {
+	groupRepo := repository.NewMonitoringGroupRepository()
+	hitRepo := repository.NewOpinionHitRepository()
+	authorRepo := repository.NewAuthorRepository()
+	scenarioRepo := repository.NewScenarioRepository()
+	segmentService := newSegmentService(groupRepo)
+	var importCfg *config.ImportConfig
+	var statsCfg *config.StatsConfig
+	if cfg := config.Get(); cfg != nil {
+		importCfg = &cfg.Import
+		statsCfg = &cfg.Stats
+	}
+	importService := service.NewImportService(
+		importCfg,
+		repository.NewImportTaskRepository(),
+		repository.NewOpinionRepository(),
+		groupRepo,
+		hitRepo,
+		newEnrichmentService(scenarioRepo, segmentService),
+		service.NewMatchService(segmentService, authorRepo),
+		newNoiseFilterService(groupRepo, hitRepo, segmentService),
+		newAuthorService(authorRepo),
+		service.NewStatsService(statsCfg, repository.NewStatsRepository(), scenarioRepo, groupRepo, repository.NewChannelRepository()),
+	)
+
+	if file == "" {
+		processed, err := importService.RunPending(time.Now())
+		if err != nil {
+			appLogger.Get().Error("执行导入任务失败", zap.Int("processed", processed), zap.Error(err))
+			return
+		}
+		appLogger.Get().Info("导入任务完成", zap.Int("processed", processed))
+		return
+	}
+
+	var columns map[string]string
+	if mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &columns); err != nil {
+			appLogger.Get().Error("列映射必须是 JSON 对象", zap.String("mapping", mapping), zap.Error(err))
+			return
+		}
+	}
+
+	task, err := importService.ImportFile(file, format, source, columns)
+	if task == nil {
+		appLogger.Get().Error("导入文件失败", zap.String("file", file), zap.Error(err))
+		return
+	}
+	fields := []zap.Field{
+		zap.Uint64("task_id", task.ID),
+		zap.String("file", file),
+		zap.Int64("total", task.Total),
+		zap.Int64("imported", task.Imported),
+		zap.Int64("duplicates", task.Duplicates),
+		zap.Int64("invalid", task.Invalid),
+		zap.Int64("hits", task.Hits),
+	}
+	if path, _, err := importService.ResultFile(task.ID); err == nil {
+		fields = append(fields, zap.String("result_file", path))
+	}
+	if err != nil {
+		appLogger.Get().Error("导入文件失败", append(fields, zap.Error(err))...)
+		return
+	}
+	appLogger.Get().Info("导入文件完成", fields...)
+}
@@ -0,0 +1,36 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// LiveFlushJob 实时计数落库任务
+// 把扫描任务累加在 Redis 中、已经结束的分钟计数（按监测组的总数、渠道、情感）写入 MySQL，
+// 并删除超过保留天数的分钟计数。实时计数接口最近两小时读取 Redis，更早的读取落库数据。
+// 建议通过 cron 每分钟执行一次。
+func LiveFlushJob() {
+	var liveCfg *config.LiveConfig
+	if cfg := config.Get(); cfg != nil {
+		liveCfg = &cfg.Live
+	}
+	liveStatsService := service.NewLiveStatsService(
+		liveCfg,
+		repository.NewLiveStatsRepository(),
+		repository.NewMonitoringGroupRepository(),
+	)
+
+	minutes, err := liveStatsService.Flush(time.Now())
+	if err != nil {
+		appLogger.Get().Error("实时计数落库失败", zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("实时计数落库完成", zap.Int("minutes", minutes))
+}
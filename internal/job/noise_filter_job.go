@@ -0,0 +1,35 @@
+package job
+
+import (
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// newNoiseFilterService 按配置创建噪音过滤器服务
+func newNoiseFilterService(groupRepo repository.MonitoringGroupRepository, hitRepo repository.OpinionHitRepository, segmentService service.SegmentService) service.NoiseFilterService {
+	var noiseCfg *config.NoiseFilterConfig
+	if cfg := config.Get(); cfg != nil {
+		noiseCfg = &cfg.NoiseFilter
+	}
+	return service.NewNoiseFilterService(noiseCfg, repository.NewNoiseFilterRepository(), groupRepo, hitRepo, segmentService)
+}
+
+// NoiseTrainJob 噪音过滤器训练任务
+// 对相关和不相关标注均达到 min_samples 条、且自上次训练后标注有变化的监测组重新训练朴素贝叶斯模型，
+// 新模型在下一次扫描时生效。建议通过 cron 每小时执行一次。
+func NoiseTrainJob() {
+	groupRepo := repository.NewMonitoringGroupRepository()
+	noiseFilterService := newNoiseFilterService(groupRepo, repository.NewOpinionHitRepository(), newSegmentService(groupRepo))
+
+	trained, err := noiseFilterService.TrainDue()
+	if err != nil {
+		appLogger.Get().Error("训练噪音过滤器失败", zap.Int("trained", trained), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("噪音过滤器训练完成", zap.Int("trained", trained))
+}
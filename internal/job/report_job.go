@@ -0,0 +1,66 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// ReportJob 场景简报任务
+// 为每个启用的简报模板生成最近一个尚未生成的完整周期（日报为前一天，周报为上周，月报为上个月）的
+// XLSX 和 PDF 简报，保存到简报存储目录，并通过邮件（附带文件）和通知渠道（附带下载链接）发送。
+// 生成失败的周期在下次执行时重试。建议通过 cron 每小时执行一次。
+func ReportJob() {
+	scenarioRepo := repository.NewScenarioRepository()
+	groupRepo := repository.NewMonitoringGroupRepository()
+	opinionRepo := repository.NewOpinionRepository()
+
+	var (
+		statsCfg    *config.StatsConfig
+		trendingCfg *config.TrendingConfig
+		reportCfg   *config.ReportConfig
+	)
+	if cfg := config.Get(); cfg != nil {
+		statsCfg = &cfg.Stats
+		trendingCfg = &cfg.Trending
+		reportCfg = &cfg.Report
+	}
+	statsService := service.NewStatsService(
+		statsCfg,
+		repository.NewStatsRepository(),
+		scenarioRepo,
+		groupRepo,
+		repository.NewChannelRepository(),
+	)
+	trendingService := service.NewTrendingService(
+		trendingCfg,
+		repository.NewTrendingRepository(),
+		repository.NewOpinionHitRepository(),
+		opinionRepo,
+		scenarioRepo,
+		newSegmentService(groupRepo),
+	)
+	reportService := service.NewReportService(
+		reportCfg,
+		repository.NewReportTemplateRepository(),
+		repository.NewReportRepository(),
+		scenarioRepo,
+		opinionRepo,
+		statsService,
+		trendingService,
+		newNotificationService(),
+	)
+
+	generated, err := reportService.RunDue(time.Now())
+	if err != nil {
+		appLogger.Get().Error("生成简报失败", zap.Int("generated", generated), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("简报任务完成", zap.Int("generated", generated))
+}
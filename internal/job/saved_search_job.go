@@ -0,0 +1,48 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// SubscriptionJob 检索订阅推送任务
+// 检查到期的检索订阅：实时订阅每次执行都检查，每小时汇总在每个整点后、每日汇总在每天的推送时刻后检查一次。
+// 按保存的检索查询订阅之后入库且尚未推送过的舆情，推送到订阅用户选择的站内通知、邮箱和通知渠道，
+// 并清理过期的推送去重记录。建议通过 cron 每分钟执行一次（紧随 scan 任务之后）。
+func SubscriptionJob() {
+	opinionRepo := repository.NewOpinionRepository()
+
+	var (
+		searchCfg      *config.SearchConfig
+		savedSearchCfg *config.SavedSearchConfig
+	)
+	if cfg := config.Get(); cfg != nil {
+		searchCfg = &cfg.Search
+		savedSearchCfg = &cfg.SavedSearch
+	}
+	// 任务脚本中不打开内嵌索引，检索使用 MySQL
+	searchService := service.NewSearchService(searchCfg, opinionRepo, repository.NewOpinionHitRepository(), repository.NewMonitoringGroupRepository())
+	savedSearchService := service.NewSavedSearchService(
+		savedSearchCfg,
+		repository.NewSavedSearchRepository(),
+		opinionRepo,
+		repository.NewUserRepository(),
+		searchService,
+		newNotificationService(),
+		service.NewInboxService(repository.NewInboxRepository()),
+	)
+
+	delivered, err := savedSearchService.DeliverDue(time.Now())
+	if err != nil {
+		appLogger.Get().Error("检索订阅推送失败", zap.Int("delivered", delivered), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("检索订阅推送完成", zap.Int("delivered", delivered))
+}
@@ -1,13 +1,195 @@
 package job
 
 import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
 	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
 )
 
-// ScanOpinionJob 扫描舆情任务
+// ScanOpinionJob 扫描舆情任务，建议通过 cron 每分钟执行一次
 func ScanOpinionJob() {
-	appLogger.Get().Info("scanning opinion...")
-	// 这里可以添加具体的舆情扫描逻辑
-	// 例如：从外部 API 抓取舆情数据、分析关键词等
+	groupRepo := repository.NewMonitoringGroupRepository()
+	scenarioRepo := repository.NewScenarioRepository()
+	opinionRepo := repository.NewOpinionRepository()
+	hitRepo := repository.NewOpinionHitRepository()
+	authorRepo := repository.NewAuthorRepository()
+
+	groupService := service.NewMonitoringGroupService(groupRepo, scenarioRepo, authorRepo)
+	segmentService := newSegmentService(groupRepo)
+	matchService := service.NewMatchService(segmentService, authorRepo)
+	enrichmentService := newEnrichmentService(scenarioRepo, segmentService)
+	feedService := service.NewFeedService(hitRepo, scenarioRepo, groupRepo)
+	var liveCfg *config.LiveConfig
+	if cfg := config.Get(); cfg != nil {
+		liveCfg = &cfg.Live
+	}
+	liveStatsService := service.NewLiveStatsService(liveCfg, repository.NewLiveStatsRepository(), groupRepo)
+	noiseFilterService := newNoiseFilterService(groupRepo, hitRepo, segmentService)
+
+	now := time.Now()
+	groups, err := groupRepo.GetActiveWithDetails()
+	if err != nil {
+		appLogger.Get().Error("获取监测组失败", zap.Error(err))
+		return
+	}
+
+	scanner := &groupScanner{
+		opinionRepo:        opinionRepo,
+		hitRepo:            hitRepo,
+		groupRepo:          groupRepo,
+		matchService:       matchService,
+		enrichmentService:  enrichmentService,
+		noiseFilterService: noiseFilterService,
+		feedService:        feedService,
+		liveStatsService:   liveStatsService,
+	}
+	scanned := 0
+	for _, group := range groups {
+		// 按采集计划（采集间隔、生效时段、生效星期、起止日期）跳过未到期的监测组
+		if !groupService.IsGroupDue(group, now) {
+			continue
+		}
+
+		// 首次扫描时回溯一个采集间隔
+		since := now.Add(-time.Duration(group.ScanInterval) * time.Minute)
+		if group.LastScannedAt != nil {
+			since = *group.LastScannedAt
+		}
+
+		result, ok := scanner.scan(group, since, now)
+		if !ok {
+			continue
+		}
+
+		scanned++
+		appLogger.Get().Info("监测组扫描完成",
+			zap.Uint64("group_id", group.ID),
+			zap.String("group_name", group.Name),
+			zap.Int("opinions", result.opinions),
+			zap.Int("hits", result.hits),
+			zap.Int("suppressed", result.suppressed),
+		)
+	}
+
+	appLogger.Get().Info("舆情扫描完成", zap.Int("groups", len(groups)), zap.Int("scanned", scanned))
+}
+
+// scanPageSize 扫描时每页读取的舆情数，避免长时间停止后一次性加载积压的全部舆情
+const scanPageSize = 1000
+
+// groupScanner 逐页匹配监测组的待扫描舆情
+type groupScanner struct {
+	opinionRepo        repository.OpinionRepository
+	hitRepo            repository.OpinionHitRepository
+	groupRepo          repository.MonitoringGroupRepository
+	matchService       service.MatchService
+	enrichmentService  service.EnrichmentService
+	noiseFilterService service.NoiseFilterService
+	feedService        service.FeedService
+	liveStatsService   service.LiveStatsService
 }
 
+// groupScanResult 一个监测组本次扫描的舆情数、命中数和屏蔽数
+type groupScanResult struct {
+	opinions   int
+	hits       int
+	suppressed int
+}
+
+// scan 按 (created_at, id) 分页匹配 [since, now) 内入库的舆情，每页处理完后推进扫描时间，
+// 中途失败时下次从最后推进到的位置继续（入库时间与该位置相同的舆情会重新匹配，命中记录按唯一键去重）；出错时记录日志并返回 false
+func (s *groupScanner) scan(group *model.MonitoringGroup, since, now time.Time) (*groupScanResult, bool) {
+	result := &groupScanResult{}
+	cursor, afterID := since, uint64(0)
+	for {
+		opinions, err := s.opinionRepo.GetCreatedBetween(cursor, afterID, now, scanPageSize)
+		if err != nil {
+			appLogger.Get().Error("获取待扫描舆情失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			return nil, false
+		}
+
+		// 匹配渠道、关键词、来源过滤规则（作者、域名、链接模式的屏蔽/允许名单）和排除词，
+		// 场景关注的作者发布的舆情不要求命中关键词
+		hits, counts, err := s.matchService.MatchGroup(group, opinions)
+		if err != nil {
+			appLogger.Get().Error("匹配舆情失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			return nil, false
+		}
+		// 按场景词典计算命中舆情的情感
+		if err := s.enrichmentService.EnrichHits(hits, opinions); err != nil {
+			appLogger.Get().Error("命中舆情情感分析失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			return nil, false
+		}
+		// 已训练噪音过滤器的监测组对命中打分并屏蔽疑似噪音（屏蔽的命中保存但不推送、不计数），失败时不屏蔽
+		suppressed, err := s.noiseFilterService.Apply(group.ID, hits, opinions)
+		if err != nil {
+			appLogger.Get().Warn("噪音过滤失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+		}
+
+		if err := s.hitRepo.CreateBatch(hits); err != nil {
+			appLogger.Get().Error("保存命中记录失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			return nil, false
+		}
+
+		// 整页时推进到本页最后一条舆情的入库时间，最后一页推进到本次扫描时间
+		scannedAt := now
+		full := len(opinions) == scanPageSize
+		if full {
+			last := opinions[len(opinions)-1]
+			cursor, afterID = last.CreatedAt, last.ID
+			scannedAt = last.CreatedAt
+		}
+		if err := s.groupRepo.UpdateLastScannedAt(group.ID, scannedAt); err != nil {
+			appLogger.Get().Error("更新扫描时间失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			return nil, false
+		}
+		// 扫描时间更新后再累加排除数，避免重复扫描时重复计数
+		if err := s.groupRepo.AddFilteredCounts(counts, now); err != nil {
+			appLogger.Get().Warn("更新排除词和来源过滤统计失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+		}
+
+		s.publish(group, hits, opinions)
+
+		result.opinions += len(opinions)
+		result.hits += len(hits)
+		result.suppressed += suppressed
+		if !full {
+			return result, true
+		}
+	}
+}
+
+// publish 通过 Redis Pub/Sub 推送给订阅了该场景或监测组的在线用户，并累加到 Redis 的分钟计数（不含屏蔽的命中），失败不影响扫描结果
+func (s *groupScanner) publish(group *model.MonitoringGroup, hits []*model.OpinionHit, opinions []*model.Opinion) {
+	visible := make([]*model.OpinionHit, 0, len(hits))
+	for _, hit := range hits {
+		if !hit.Suppressed {
+			visible = append(visible, hit)
+		}
+	}
+	if len(visible) == 0 {
+		return
+	}
+
+	opinionIDs := make([]uint64, len(visible))
+	for i, hit := range visible {
+		opinionIDs[i] = hit.OpinionID
+	}
+	if _, err := s.feedService.Publish(group.ID, opinionIDs); err != nil {
+		appLogger.Get().Warn("推送命中舆情失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+	}
+
+	sources := make(map[uint64]string, len(opinions))
+	for _, opinion := range opinions {
+		sources[opinion.ID] = opinion.Source
+	}
+	if err := s.liveStatsService.Record(group.ID, visible, sources, time.Now()); err != nil {
+		appLogger.Get().Warn("更新实时计数失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,54 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/search"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// ReindexJob 检索索引重建任务
+// 在 search.index_dir 下创建新的内嵌索引版本，写入全部舆情及其命中的监测组，完成后设为当前版本；
+// 使用该目录的 Web 服务在下次同步时切换到新版本并删除旧版本，重建期间旧版本照常提供检索。
+// 需要在 Web 服务所在的机器上执行，多个 Web 实例时每个实例的索引目录分别执行一次。
+func ReindexJob() {
+	var searchCfg *config.SearchConfig
+	if cfg := config.Get(); cfg != nil {
+		searchCfg = &cfg.Search
+	}
+	if searchCfg == nil || searchCfg.Backend != search.BackendEmbedded {
+		appLogger.Get().Info("检索后端不是内嵌索引，无需重建；MySQL 全文索引可以通过 ALTER TABLE 重建")
+		return
+	}
+
+	dir := search.IndexDir(searchCfg)
+	index, err := search.Create(dir)
+	if err != nil {
+		appLogger.Get().Error("创建检索索引失败", zap.String("dir", dir), zap.Error(err))
+		return
+	}
+
+	start := time.Now()
+	indexer := service.NewSearchIndexer(searchCfg, index, repository.NewOpinionRepository(), repository.NewOpinionHitRepository())
+	written, err := indexer.Rebuild()
+	if err != nil {
+		index.Close()
+		appLogger.Get().Error("重建检索索引失败", zap.String("generation", index.Generation()), zap.Int("written", written), zap.Error(err))
+		return
+	}
+	if err := index.Publish(); err != nil {
+		appLogger.Get().Error("发布检索索引失败", zap.String("generation", index.Generation()), zap.Error(err))
+		return
+	}
+
+	appLogger.Get().Info("检索索引重建完成",
+		zap.String("generation", index.Generation()),
+		zap.Int("written", written),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+}
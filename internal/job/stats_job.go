@@ -0,0 +1,52 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// StatsRollupJob 统计汇总任务
+// 对每个启用的场景，按小时重算最近一段时间（首次为回溯天数）的命中舆情数，
+// 包括总声量以及按渠道、监测组、情感、作者、关键词的分布，供统计看板查询。
+// 建议通过 cron 每 5 分钟执行一次。
+func StatsRollupJob() {
+	scenarioRepo := repository.NewScenarioRepository()
+
+	var statsCfg *config.StatsConfig
+	if cfg := config.Get(); cfg != nil {
+		statsCfg = &cfg.Stats
+	}
+	statsService := service.NewStatsService(
+		statsCfg,
+		repository.NewStatsRepository(),
+		scenarioRepo,
+		repository.NewMonitoringGroupRepository(),
+		repository.NewChannelRepository(),
+	)
+
+	scenarios, err := scenarioRepo.GetByStatus(1)
+	if err != nil {
+		appLogger.Get().Error("获取场景失败", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	rolled := 0
+	for _, scenario := range scenarios {
+		rows, err := statsService.Rollup(scenario.ID, now)
+		if err != nil {
+			appLogger.Get().Error("汇总场景统计失败", zap.Uint64("scenario_id", scenario.ID), zap.Error(err))
+			continue
+		}
+		rolled++
+		appLogger.Get().Debug("场景统计汇总完成", zap.Uint64("scenario_id", scenario.ID), zap.Int("rows", rows))
+	}
+
+	appLogger.Get().Info("统计汇总完成", zap.Int("scenarios", len(scenarios)), zap.Int("rolled", rolled))
+}
@@ -0,0 +1,51 @@
+package job
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// TrendingJob 热词和话题计算任务
+// 对每个启用的场景，按滑动窗口统计词频并与基线比较得到突发词，同时对近期命中舆情聚类生成话题。
+// 建议通过 cron 每 10 分钟执行一次。
+func TrendingJob() {
+	scenarioRepo := repository.NewScenarioRepository()
+	groupRepo := repository.NewMonitoringGroupRepository()
+
+	var trendingCfg *config.TrendingConfig
+	if cfg := config.Get(); cfg != nil {
+		trendingCfg = &cfg.Trending
+	}
+	trendingService := service.NewTrendingService(
+		trendingCfg,
+		repository.NewTrendingRepository(),
+		repository.NewOpinionHitRepository(),
+		repository.NewOpinionRepository(),
+		scenarioRepo,
+		newSegmentService(groupRepo),
+	)
+
+	scenarios, err := scenarioRepo.GetByStatus(1)
+	if err != nil {
+		appLogger.Get().Error("获取场景失败", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	refreshed := 0
+	for _, scenario := range scenarios {
+		if err := trendingService.RefreshScenario(scenario.ID, now); err != nil {
+			appLogger.Get().Error("计算场景热词失败", zap.Uint64("scenario_id", scenario.ID), zap.Error(err))
+			continue
+		}
+		refreshed++
+	}
+
+	appLogger.Get().Info("热词和话题计算完成", zap.Int("scenarios", len(scenarios)), zap.Int("refreshed", refreshed))
+}
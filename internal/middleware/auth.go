@@ -50,3 +50,44 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// TicketRedeemer 兑换推送连接票据，返回票据对应的用户信息
+type TicketRedeemer interface {
+	RedeemTicket(ticket string) (userID uint64, username string, roles []string, err error)
+}
+
+// StreamAuthMiddleware 实时推送接口的认证中间件
+// 浏览器的 EventSource 和 WebSocket 无法设置请求头，因此在未提供 Authorization 时
+// 通过 ticket 查询参数携带一次性的推送连接票据（由 POST /api/v1/auth/stream-ticket 签发）
+func StreamAuthMiddleware(tickets TicketRedeemer) gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			auth(c)
+			return
+		}
+
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "未提供认证token或票据",
+			})
+			c.Abort()
+			return
+		}
+
+		userID, username, roles, err := tickets.RedeemTicket(ticket)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("username", username)
+		c.Set("roles", roles)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"time"
+)
+
+// 告警规则类型
+const (
+	AlertRuleVolume        = "volume"         // 窗口内命中舆情数量达到阈值
+	AlertRuleNegativeRatio = "negative_ratio" // 窗口内负面舆情占比达到阈值
+	AlertRuleEngagement    = "engagement"     // 单条舆情互动量达到阈值
+	AlertRuleKeyword       = "keyword"        // 窗口内命中指定关键词的舆情数量达到阈值
+	AlertRuleGrowth        = "growth"         // 窗口内舆情数量相对上一窗口的增长率达到阈值
+)
+
+// 告警事件状态：firing → acknowledged → resolved，firing 也可直接 resolved
+const (
+	AlertStatusFiring       = "firing"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
+
+// 告警时间线动作
+const (
+	AlertActionFired        = "fired"        // 触发
+	AlertActionNotified     = "notified"     // 发送通知
+	AlertActionEscalated    = "escalated"    // 升级到下一级
+	AlertActionAcknowledged = "acknowledged" // 认领
+	AlertActionResolved     = "resolved"     // 恢复（自动或手动）
+)
+
+// AlertRule 告警规则，挂在场景上，指定监测组时只统计该监测组的命中舆情
+type AlertRule struct {
+	ID                 uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name               string     `gorm:"type:varchar(100);not null;comment:规则名称" json:"name"`
+	ScenarioID         uint64     `gorm:"type:bigint;not null;index;comment:场景ID" json:"scenario_id"`
+	GroupID            uint64     `gorm:"type:bigint;not null;default:0;comment:监测组ID,0表示整个场景" json:"group_id"`
+	Type               string     `gorm:"type:varchar(20);not null;comment:规则类型:volume,negative_ratio,engagement,keyword,growth" json:"type"`
+	Threshold          float64    `gorm:"type:decimal(12,4);not null;comment:阈值" json:"threshold"`
+	WindowMinutes      int        `gorm:"type:int;not null;default:60;comment:统计窗口(分钟)" json:"window_minutes"`
+	MinCount           int        `gorm:"type:int;not null;default:0;comment:占比和增长率规则的最小样本数" json:"min_count"`
+	Keywords           StringList `gorm:"type:json;comment:关键词规则的关键词" json:"keywords"`
+	Severity           string     `gorm:"type:varchar(20);not null;default:'warning';comment:级别:info,warning,critical" json:"severity"`
+	CooldownMinutes    int        `gorm:"type:int;not null;default:30;comment:恢复后再次触发的冷却时间(分钟)" json:"cooldown_minutes"`
+	ChannelIDs         IDList     `gorm:"type:json;comment:接收告警的通知渠道ID" json:"channel_ids"`
+	EscalationPolicyID uint64     `gorm:"type:bigint;not null;default:0;comment:升级策略ID,0表示不升级" json:"escalation_policy_id"`
+	SilenceUntil       *time.Time `gorm:"type:datetime;comment:静默截止时间" json:"silence_until"`
+	Status             int        `gorm:"type:tinyint;default:1;comment:1-正常,2-禁用" json:"status"`
+	LastEvaluatedAt    *time.Time `gorm:"type:datetime;comment:最近评估时间" json:"last_evaluated_at"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// AlertEvent 告警事件，同一去重键同时只有一个未恢复的事件
+type AlertEvent struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RuleID          uint64     `gorm:"type:bigint;not null;index:idx_rule_dedup;comment:规则ID" json:"rule_id"`
+	ScenarioID      uint64     `gorm:"type:bigint;not null;index;comment:场景ID" json:"scenario_id"`
+	GroupID         uint64     `gorm:"type:bigint;not null;default:0;comment:监测组ID" json:"group_id"`
+	DedupKey        string     `gorm:"type:varchar(255);not null;index:idx_rule_dedup;comment:去重键" json:"dedup_key"`
+	Severity        string     `gorm:"type:varchar(20);not null;comment:级别" json:"severity"`
+	Status          string     `gorm:"type:varchar(20);not null;default:'firing';index;comment:状态:firing,acknowledged,resolved" json:"status"`
+	Title           string     `gorm:"type:varchar(255);not null;comment:标题" json:"title"`
+	Value           float64    `gorm:"type:decimal(12,4);not null;default:0;comment:触发时的指标值" json:"value"`
+	Threshold       float64    `gorm:"type:decimal(12,4);not null;default:0;comment:阈值" json:"threshold"`
+	OpinionIDs      IDList     `gorm:"type:json;comment:相关舆情ID" json:"opinion_ids"`
+	FireCount       int        `gorm:"type:int;not null;default:1;comment:持续触发的评估次数" json:"fire_count"`
+	FiredAt         time.Time  `gorm:"type:datetime;not null;comment:首次触发时间" json:"fired_at"`
+	LastSeenAt      time.Time  `gorm:"type:datetime;not null;comment:最近一次满足条件的时间" json:"last_seen_at"`
+	ResolvedAt      *time.Time `gorm:"type:datetime;comment:恢复时间" json:"resolved_at"`
+	ResolvedBy      uint64     `gorm:"type:bigint;not null;default:0;comment:手动恢复的用户ID,0表示自动恢复" json:"resolved_by"`
+	AcknowledgedAt  *time.Time `gorm:"type:datetime;comment:认领时间" json:"acknowledged_at"`
+	AcknowledgedBy  uint64     `gorm:"type:bigint;not null;default:0;comment:认领用户ID" json:"acknowledged_by"`
+	EscalationLevel int        `gorm:"type:int;not null;default:0;comment:已通知到的升级层级数" json:"escalation_level"`
+	EscalatedAt     *time.Time `gorm:"type:datetime;comment:最近一次升级时间" json:"escalated_at"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AlertEvent) TableName() string {
+	return "alert_events"
+}
+
+// AlertEventLog 告警时间线，记录事件的每次通知和处理动作
+type AlertEventLog struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID    uint64    `gorm:"type:bigint;not null;index;comment:告警事件ID" json:"event_id"`
+	Action     string    `gorm:"type:varchar(20);not null;comment:动作:fired,notified,escalated,acknowledged,resolved" json:"action"`
+	Level      int       `gorm:"type:int;not null;default:0;comment:升级层级,0表示规则自身的通知" json:"level"`
+	UserID     uint64    `gorm:"type:bigint;not null;default:0;comment:操作用户ID,0表示系统" json:"user_id"`
+	DeliveryID uint64    `gorm:"type:bigint;not null;default:0;comment:通知发送记录ID" json:"delivery_id"`
+	Detail     string    `gorm:"type:varchar(1000);default:'';comment:说明" json:"detail"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AlertEventLog) TableName() string {
+	return "alert_event_logs"
+}
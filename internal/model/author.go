@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// Author 作者（发布账号），按渠道和账号ID唯一；舆情入库时自动创建或更新
+type Author struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Channel        string    `gorm:"type:varchar(255);not null;uniqueIndex:uk_channel_external;comment:渠道（舆情来源）" json:"channel"`
+	ExternalID     string    `gorm:"type:varchar(100);not null;uniqueIndex:uk_channel_external;comment:渠道内的账号ID,没有时为账号名称" json:"external_id"`
+	Name           string    `gorm:"type:varchar(100);not null;default:'';index;comment:账号名称" json:"name"`
+	FollowerCount  int64     `gorm:"type:bigint;not null;default:0;comment:粉丝数" json:"follower_count"`
+	Verified       bool      `gorm:"not null;default:false;comment:是否认证账号" json:"verified"`
+	OpinionCount   int64     `gorm:"type:bigint;not null;default:0;comment:入库的舆情数" json:"opinion_count"`
+	AvgEngagement  float64   `gorm:"type:decimal(12,2);not null;default:0;comment:最近舆情的平均互动量" json:"avg_engagement"`
+	InfluenceScore float64   `gorm:"type:decimal(5,2);not null;default:0;index;comment:影响力得分(0~100)" json:"influence_score"`
+	FirstSeenAt    time.Time `gorm:"type:datetime;not null;comment:首次出现时间" json:"first_seen_at"`
+	LastSeenAt     time.Time `gorm:"type:datetime;not null;index;comment:最近一次出现时间" json:"last_seen_at"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Author) TableName() string {
+	return "authors"
+}
+
+// ScenarioAuthorWatch 场景关注的作者：关注作者发布的舆情不要求命中关键词，直接命中该场景下的监测组
+type ScenarioAuthorWatch struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_scenario_author;comment:场景ID" json:"scenario_id"`
+	AuthorID   uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_scenario_author;index;comment:作者ID" json:"author_id"`
+	Note       string    `gorm:"type:varchar(255);default:'';comment:备注" json:"note"`
+	CreatedBy  uint64    `gorm:"type:bigint;not null;default:0;comment:添加人用户ID" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Author *Author `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+// TableName 指定表名
+func (ScenarioAuthorWatch) TableName() string {
+	return "scenario_author_watches"
+}
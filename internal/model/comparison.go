@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// ComparisonSet 竞品对比组：把多个场景（如本品牌与竞品品牌）放在一起比较声量份额、情感、渠道和走势
+type ComparisonSet struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null;comment:对比组名称" json:"name"`
+	Description string    `gorm:"type:varchar(255);default:'';comment:描述" json:"description"`
+	ScenarioIDs IDList    `gorm:"type:json;comment:参与对比的场景ID(按展示顺序)" json:"scenario_ids"`
+	Interval    string    `gorm:"type:varchar(10);not null;default:'day';comment:默认统计粒度:hour,day" json:"interval"`
+	CreatedBy   uint64    `gorm:"type:bigint;not null;default:0;index;comment:创建人用户ID" json:"created_by"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ComparisonSet) TableName() string {
+	return "comparison_sets"
+}
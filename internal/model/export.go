@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// 导出任务状态
+const (
+	ExportStatusPending = "pending" // 等待执行
+	ExportStatusRunning = "running" // 执行中
+	ExportStatusSuccess = "success" // 已完成，文件可下载
+	ExportStatusFailed  = "failed"  // 失败
+	ExportStatusExpired = "expired" // 文件已过期删除
+)
+
+// ExportTask 舆情异步导出任务，结果文件保存在本地存储目录
+type ExportTask struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64     `gorm:"type:bigint;not null;index;comment:创建人用户ID" json:"user_id"`
+	Format     string     `gorm:"type:varchar(10);not null;comment:文件格式:csv,xlsx,ndjson" json:"format"`
+	Filter     string     `gorm:"type:json;comment:筛选条件" json:"-"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending';index;comment:状态:pending,running,success,failed,expired" json:"status"`
+	Total      int64      `gorm:"type:bigint;not null;default:0;comment:创建时符合条件的舆情数" json:"total"`
+	Processed  int64      `gorm:"type:bigint;not null;default:0;comment:已写入的行数" json:"processed"`
+	File       string     `gorm:"type:varchar(255);default:'';comment:文件相对路径" json:"-"`
+	FileSize   int64      `gorm:"type:bigint;not null;default:0;comment:文件大小(字节)" json:"file_size"`
+	Error      string     `gorm:"type:varchar(1000);default:'';comment:失败原因" json:"error"`
+	StartedAt  *time.Time `gorm:"type:datetime;comment:开始执行时间" json:"started_at"`
+	FinishedAt *time.Time `gorm:"type:datetime;comment:完成时间" json:"finished_at"`
+	ExpiresAt  *time.Time `gorm:"type:datetime;comment:文件过期时间" json:"expires_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Progress    float64                `gorm:"-" json:"progress"`               // 进度 0~1
+	Query       map[string]interface{} `gorm:"-" json:"filter"`                 // 筛选条件
+	DownloadURL string                 `gorm:"-" json:"download_url,omitempty"` // 带签名的临时下载地址
+}
+
+// TableName 指定表名
+func (ExportTask) TableName() string {
+	return "export_tasks"
+}
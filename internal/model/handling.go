@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+)
+
+// 舆情处置状态
+const (
+	HandlingStatusNew        = "new"         // 待处理
+	HandlingStatusInProgress = "in_progress" // 处理中
+	HandlingStatusResponded  = "responded"   // 已回应
+	HandlingStatusIgnored    = "ignored"     // 已忽略
+	HandlingStatusEscalated  = "escalated"   // 已上报
+)
+
+// 舆情优先级
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// 舆情处置记录动作
+const (
+	HandlingActionTransition = "transition" // 状态流转
+	HandlingActionAssign     = "assign"     // 分配处理人
+	HandlingActionTriage     = "triage"     // 调整优先级和截止时间
+)
+
+// OpinionComment 舆情内部评论
+type OpinionComment struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	OpinionID uint64    `gorm:"type:bigint;not null;index;comment:舆情ID" json:"opinion_id"`
+	UserID    uint64    `gorm:"type:bigint;not null;comment:评论用户ID" json:"user_id"`
+	Content   string    `gorm:"type:text;not null;comment:评论内容" json:"content"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (OpinionComment) TableName() string {
+	return "opinion_comments"
+}
+
+// OpinionHandlingLog 舆情处置记录（状态流转、分配和优先级调整）
+type OpinionHandlingLog struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	OpinionID  uint64    `gorm:"type:bigint;not null;index;comment:舆情ID" json:"opinion_id"`
+	UserID     uint64    `gorm:"type:bigint;not null;comment:操作用户ID" json:"user_id"`
+	Action     string    `gorm:"type:varchar(20);not null;comment:动作:transition,assign,triage" json:"action"`
+	FromStatus string    `gorm:"type:varchar(20);default:'';comment:原状态" json:"from_status"`
+	ToStatus   string    `gorm:"type:varchar(20);default:'';comment:新状态" json:"to_status"`
+	AssigneeID uint64    `gorm:"type:bigint;not null;default:0;comment:操作后的处理人用户ID" json:"assignee_id"`
+	Note       string    `gorm:"type:varchar(1000);default:'';comment:备注" json:"note"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (OpinionHandlingLog) TableName() string {
+	return "opinion_handling_logs"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+// 导入任务状态
+const (
+	ImportStatusPending = "pending" // 等待执行
+	ImportStatusRunning = "running" // 执行中
+	ImportStatusSuccess = "success" // 已完成（可能有部分行未导入，见结果文件）
+	ImportStatusFailed  = "failed"  // 失败（文件无法解析等）
+	ImportStatusExpired = "expired" // 上传文件和结果文件已过期删除
+)
+
+// ImportTask 舆情批量导入任务，上传的文件和结果文件保存在本地存储目录
+type ImportTask struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64     `gorm:"type:bigint;not null;index;comment:创建人用户ID,0表示命令行导入" json:"user_id"`
+	Filename   string     `gorm:"type:varchar(255);not null;default:'';comment:原始文件名" json:"filename"`
+	Format     string     `gorm:"type:varchar(10);not null;comment:文件格式:csv,xlsx,ndjson" json:"format"`
+	Source     string     `gorm:"type:varchar(255);not null;default:'';comment:文件中没有来源列时使用的默认来源" json:"source"`
+	Mapping    string     `gorm:"type:json;comment:自定义列映射" json:"-"`
+	File       string     `gorm:"type:varchar(255);default:'';comment:上传文件相对路径" json:"-"`
+	ResultFile string     `gorm:"type:varchar(255);default:'';comment:结果文件相对路径" json:"-"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending';index;comment:状态:pending,running,success,failed,expired" json:"status"`
+	Total      int64      `gorm:"type:bigint;not null;default:0;comment:文件中的数据行数" json:"total"`
+	Processed  int64      `gorm:"type:bigint;not null;default:0;comment:已处理的行数" json:"processed"`
+	Imported   int64      `gorm:"type:bigint;not null;default:0;comment:成功导入的行数" json:"imported"`
+	Duplicates int64      `gorm:"type:bigint;not null;default:0;comment:重复跳过的行数" json:"duplicates"`
+	Invalid    int64      `gorm:"type:bigint;not null;default:0;comment:校验失败的行数" json:"invalid"`
+	Hits       int64      `gorm:"type:bigint;not null;default:0;comment:新增的监测组命中数" json:"hits"`
+	Error      string     `gorm:"type:varchar(1000);default:'';comment:失败原因" json:"error"`
+	StartedAt  *time.Time `gorm:"type:datetime;comment:开始执行时间" json:"started_at"`
+	FinishedAt *time.Time `gorm:"type:datetime;comment:完成时间" json:"finished_at"`
+	ExpiresAt  *time.Time `gorm:"type:datetime;comment:文件过期时间" json:"expires_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Progress      float64           `gorm:"-" json:"progress"`             // 进度 0~1
+	ColumnMapping map[string]string `gorm:"-" json:"mapping"`              // 自定义列映射：文件列名 -> 舆情字段
+	ResultURL     string            `gorm:"-" json:"result_url,omitempty"` // 结果文件下载地址（有未导入的行时返回）
+}
+
+// TableName 指定表名
+func (ImportTask) TableName() string {
+	return "import_tasks"
+}
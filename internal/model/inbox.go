@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// 站内通知类型
+const (
+	InboxTypeAssignment  = "assignment"   // 舆情分配给我
+	InboxTypeMention     = "mention"      // 在舆情评论中 @ 我
+	InboxTypeAlert       = "alert"        // 告警通知
+	InboxTypeSavedSearch = "saved_search" // 订阅的检索有新结果
+)
+
+// 站内通知关联对象类型
+const (
+	InboxSourceOpinion     = "opinion"
+	InboxSourceAlertEvent  = "alert_event"
+	InboxSourceSavedSearch = "saved_search"
+)
+
+// UserNotification 用户站内通知
+type UserNotification struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64     `gorm:"type:bigint;not null;index:idx_user_read;comment:接收用户ID" json:"user_id"`
+	Type       string     `gorm:"type:varchar(20);not null;comment:类型:assignment,mention,alert,saved_search" json:"type"`
+	Title      string     `gorm:"type:varchar(255);not null;comment:标题" json:"title"`
+	Content    string     `gorm:"type:varchar(1000);default:'';comment:内容" json:"content"`
+	SourceType string     `gorm:"type:varchar(20);default:'';comment:关联对象类型:opinion,alert_event,saved_search" json:"source_type"`
+	SourceID   uint64     `gorm:"type:bigint;not null;default:0;comment:关联对象ID" json:"source_id"`
+	ActorID    uint64     `gorm:"type:bigint;not null;default:0;comment:触发通知的用户ID,0表示系统" json:"actor_id"`
+	ReadAt     *time.Time `gorm:"type:datetime;index:idx_user_read;comment:已读时间,NULL表示未读" json:"read_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (UserNotification) TableName() string {
+	return "user_notifications"
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// GroupMinuteStat 监测组按分钟的命中数，由 Redis 实时计数定期落库
+// Dimension 取 StatDimTotal、StatDimChannel、StatDimSentiment
+type GroupMinuteStat struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID   uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_group_minute_dim;comment:监测组ID" json:"group_id"`
+	Minute    time.Time `gorm:"type:datetime;not null;uniqueIndex:uk_group_minute_dim;index;comment:分钟桶开始时间" json:"minute"`
+	Dimension string    `gorm:"type:varchar(20);not null;uniqueIndex:uk_group_minute_dim;comment:维度:total,channel,sentiment" json:"dimension"`
+	DimKey    string    `gorm:"type:varchar(255);not null;default:'';uniqueIndex:uk_group_minute_dim;comment:维度取值" json:"dim_key"`
+	Count     int64     `gorm:"type:bigint;not null;default:0;comment:命中数" json:"count"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (GroupMinuteStat) TableName() string {
+	return "group_minute_stats"
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// NoiseFilter 监测组噪音过滤器：设置和由相关性标注训练出的朴素贝叶斯模型
+type NoiseFilter struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID      uint64     `gorm:"type:bigint;not null;uniqueIndex:uk_group_id;comment:监测组ID" json:"group_id"`
+	Enabled      bool       `gorm:"not null;default:true;comment:是否自动屏蔽疑似噪音的命中" json:"enabled"`
+	Threshold    float64    `gorm:"type:decimal(4,3);not null;default:0;comment:屏蔽阈值(0~1),0表示使用全局配置" json:"threshold"`
+	RelevantDocs int        `gorm:"type:int;not null;default:0;comment:训练使用的相关样本数" json:"relevant_docs"`
+	NoiseDocs    int        `gorm:"type:int;not null;default:0;comment:训练使用的噪音样本数" json:"noise_docs"`
+	Terms        int        `gorm:"type:int;not null;default:0;comment:词表大小" json:"terms"`
+	Model        string     `gorm:"type:mediumtext;comment:模型(JSON)" json:"-"`
+	TrainedAt    *time.Time `gorm:"type:datetime;comment:最近一次训练时间" json:"trained_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 以下字段不存储，查询时填充
+	EffectiveThreshold float64 `gorm:"-" json:"effective_threshold"` // 实际使用的屏蔽阈值
+	Trained            bool    `gorm:"-" json:"trained"`             // 是否已有可用的模型
+	SuppressedCount    int64   `gorm:"-" json:"suppressed_count"`    // 当前被屏蔽且未标注的命中数
+}
+
+// TableName 指定表名
+func (NoiseFilter) TableName() string {
+	return "noise_filters"
+}
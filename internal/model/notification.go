@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// 通知发送结果
+const (
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// NotificationChannel 通知渠道（通用 Webhook、邮件、钉钉、企业微信、飞书机器人）
+type NotificationChannel struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string     `gorm:"type:varchar(100);not null;comment:渠道名称" json:"name"`
+	Type      string     `gorm:"type:varchar(20);not null;comment:类型:webhook,email,dingtalk,wecom,feishu" json:"type"`
+	URL       string     `gorm:"type:varchar(500);default:'';comment:Webhook或机器人地址" json:"url"`
+	Secret    string     `gorm:"type:varchar(255);default:'';comment:签名密钥" json:"secret"`
+	Emails    StringList `gorm:"type:json;comment:邮件收件人" json:"emails"`
+	AtMobiles StringList `gorm:"type:json;comment:机器人@的手机号" json:"at_mobiles"`
+	AtAll     bool       `gorm:"default:false;comment:机器人是否@所有人" json:"at_all"`
+	Template  string     `gorm:"type:text;comment:消息模板(Go text/template),为空使用默认模板" json:"template"`
+	Status    int        `gorm:"type:tinyint;default:1;comment:1-正常,2-禁用" json:"status"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (NotificationChannel) TableName() string {
+	return "notification_channels"
+}
+
+// NotificationDelivery 通知发送记录
+type NotificationDelivery struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ChannelID   uint64    `gorm:"type:bigint;not null;index;comment:通知渠道ID,0表示直接发送给用户邮箱" json:"channel_id"`
+	ChannelType string    `gorm:"type:varchar(20);not null;comment:渠道类型" json:"channel_type"`
+	Source      string    `gorm:"type:varchar(20);not null;index:idx_source;comment:来源:alert,report,test,saved_search" json:"source"`
+	SourceID    uint64    `gorm:"type:bigint;not null;default:0;index:idx_source;comment:来源ID(如告警事件ID)" json:"source_id"`
+	Title       string    `gorm:"type:varchar(255);not null;comment:消息标题" json:"title"`
+	Status      string    `gorm:"type:varchar(20);not null;comment:结果:success,failed" json:"status"`
+	Attempts    int       `gorm:"type:int;not null;default:0;comment:尝试次数" json:"attempts"`
+	Error       string    `gorm:"type:varchar(1000);default:'';comment:失败原因" json:"error"`
+	DurationMs  int64     `gorm:"type:bigint;not null;default:0;comment:耗时(毫秒)" json:"duration_ms"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// EscalationTier 升级层级：告警触发后 DelayMinutes 分钟内仍未被认领，则通知该层级
+type EscalationTier struct {
+	DelayMinutes int      `json:"delay_minutes"`          // 距触发的分钟数，0 表示触发时立即通知
+	ChannelIDs   []uint64 `json:"channel_ids,omitempty"`  // 通知渠道
+	UserIDs      []uint64 `json:"user_ids,omitempty"`     // 直接通知的用户（邮件）
+	ScheduleIDs  []uint64 `json:"schedule_ids,omitempty"` // 通知值班表中当前值班的用户（邮件）
+}
+
+// EscalationTiers 以 JSON 数组存储的升级层级，按 DelayMinutes 升序
+type EscalationTiers []EscalationTier
+
+// Value 实现 driver.Valuer
+func (l EscalationTiers) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (l *EscalationTiers) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// EscalationPolicy 升级策略，告警规则通过 escalation_policy_id 引用
+type EscalationPolicy struct {
+	ID          uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string          `gorm:"type:varchar(100);not null;comment:策略名称" json:"name"`
+	Description string          `gorm:"type:varchar(255);default:'';comment:描述" json:"description"`
+	Tiers       EscalationTiers `gorm:"type:json;comment:升级层级" json:"tiers"`
+	Status      int             `gorm:"type:tinyint;default:1;comment:1-正常,2-禁用" json:"status"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (EscalationPolicy) TableName() string {
+	return "escalation_policies"
+}
+
+// OnCallSchedule 值班表：UserIDs 按顺序轮值，每 RotationHours 小时从 HandoffAt 开始交接一次
+type OnCallSchedule struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name          string    `gorm:"type:varchar(100);not null;comment:值班表名称" json:"name"`
+	UserIDs       IDList    `gorm:"type:json;comment:轮值用户ID(按顺序)" json:"user_ids"`
+	RotationHours int       `gorm:"type:int;not null;default:168;comment:轮换周期(小时)" json:"rotation_hours"`
+	HandoffAt     time.Time `gorm:"type:datetime;not null;comment:轮换起点(第一位用户开始值班的时间)" json:"handoff_at"`
+	Status        int       `gorm:"type:tinyint;default:1;comment:1-正常,2-禁用" json:"status"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (OnCallSchedule) TableName() string {
+	return "oncall_schedules"
+}
+
+// OnCallOverride 临时替班，时间段内优先于轮值
+type OnCallOverride struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleID uint64    `gorm:"type:bigint;not null;index;comment:值班表ID" json:"schedule_id"`
+	UserID     uint64    `gorm:"type:bigint;not null;comment:替班用户ID" json:"user_id"`
+	StartAt    time.Time `gorm:"type:datetime;not null;comment:开始时间" json:"start_at"`
+	EndAt      time.Time `gorm:"type:datetime;not null;comment:结束时间" json:"end_at"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (OnCallOverride) TableName() string {
+	return "oncall_overrides"
+}
@@ -6,11 +6,43 @@ import (
 
 // Opinion 舆情模型
 type Opinion struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	Source    string    `gorm:"type:varchar(255);not null" json:"source"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Content      string    `gorm:"type:text;not null" json:"content"`
+	Source       string    `gorm:"type:varchar(255);not null" json:"source"`
+	Author       string    `gorm:"type:varchar(100);default:'';index;comment:作者（发布账号）" json:"author"`
+	AuthorID     uint64    `gorm:"type:bigint;not null;default:0;index;comment:作者ID,0表示没有作者信息" json:"author_id"`
+	URL          string    `gorm:"type:varchar(1024);not null;default:'';comment:原文链接" json:"url"`
+	ContentHash  string    `gorm:"type:char(40);default:'';index;comment:来源和内容的SHA1" json:"-"`
+	ImportTaskID uint64    `gorm:"type:bigint;not null;default:0;index;comment:导入任务ID,0表示非导入" json:"import_task_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 互动数据（采集时由渠道提供）
+	LikeCount    int64 `gorm:"type:bigint;not null;default:0;comment:点赞数" json:"like_count"`
+	CommentCount int64 `gorm:"type:bigint;not null;default:0;comment:评论数" json:"comment_count"`
+	ShareCount   int64 `gorm:"type:bigint;not null;default:0;comment:转发数" json:"share_count"`
+
+	// 作者账号信息（采集时由渠道提供，不存储，入库时写入作者表）
+	AuthorExternalID string `gorm:"-" json:"author_external_id,omitempty"` // 渠道内的账号ID
+	AuthorFollowers  int64  `gorm:"-" json:"author_followers,omitempty"`   // 粉丝数
+	AuthorVerified   bool   `gorm:"-" json:"author_verified,omitempty"`    // 是否认证账号
+
+	// 情感分析结果
+	SentimentScore float64 `gorm:"type:decimal(6,4);default:0;comment:情感得分(-1~1)" json:"sentiment_score"`
+	SentimentLabel string  `gorm:"type:varchar(10);default:'neutral';comment:情感标签:positive,neutral,negative" json:"sentiment_label"`
+
+	// 富化结果（由分析器产出）
+	Entities EntityList `gorm:"type:json;comment:实体列表" json:"entities"`
+	Topics   StringList `gorm:"type:json;comment:主题标签" json:"topics"`
+	Keywords TermList   `gorm:"type:json;comment:提取的关键词及权重" json:"keywords"`
+	Analyzer string     `gorm:"type:varchar(50);default:'';comment:产生分析结果的分析器" json:"analyzer"`
+
+	// 处置流程
+	HandlingStatus string     `gorm:"type:varchar(20);not null;default:'new';index;comment:处置状态:new,in_progress,responded,ignored,escalated" json:"handling_status"`
+	AssigneeID     uint64     `gorm:"type:bigint;not null;default:0;index;comment:处理人用户ID,0表示未分配" json:"assignee_id"`
+	Priority       string     `gorm:"type:varchar(10);not null;default:'medium';comment:优先级:low,medium,high,urgent" json:"priority"`
+	DueAt          *time.Time `gorm:"type:datetime;comment:处置截止时间(SLA)" json:"due_at"`
+	HandledAt      *time.Time `gorm:"type:datetime;comment:最近一次回应或忽略的时间" json:"handled_at"`
 }
 
 // TableName 指定表名
@@ -18,3 +50,37 @@ func (Opinion) TableName() string {
 	return "opinions"
 }
 
+// 命中记录的相关性标注
+const (
+	HitRelevanceRelevant   = "relevant"   // 相关
+	HitRelevanceIrrelevant = "irrelevant" // 不相关（噪音）
+)
+
+// OpinionHit 舆情命中监测组记录
+type OpinionHit struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	OpinionID  uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_opinion_group;comment:舆情ID" json:"opinion_id"`
+	GroupID    uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_opinion_group;comment:监测组ID" json:"group_id"`
+	ScenarioID uint64    `gorm:"type:bigint;not null;comment:场景ID" json:"scenario_id"`
+	Keyword    string    `gorm:"type:varchar(255);comment:命中的关键词" json:"keyword"`
+	Watched    bool      `gorm:"not null;default:false;comment:作者是否为场景关注的作者（关注作者的舆情不要求命中关键词）" json:"watched"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 按场景自定义词典计算的情感分析结果
+	SentimentScore float64 `gorm:"type:decimal(6,4);default:0;comment:情感得分(-1~1)" json:"sentiment_score"`
+	SentimentLabel string  `gorm:"type:varchar(10);default:'neutral';comment:情感标签:positive,neutral,negative" json:"sentiment_label"`
+
+	// 噪音过滤器打分（未训练模型时为 0），屏蔽的命中仍然保存，可复核后标注为相关以恢复
+	NoiseScore float64 `gorm:"type:decimal(6,4);not null;default:0;comment:噪音概率(0~1)" json:"noise_score"`
+	Suppressed bool    `gorm:"not null;default:false;comment:是否被噪音过滤器屏蔽" json:"suppressed"`
+
+	// 分析人员的相关性标注
+	Relevance   string     `gorm:"type:varchar(20);not null;default:'';comment:相关性标注:relevant,irrelevant,为空表示未标注" json:"relevance"`
+	RelevanceBy uint64     `gorm:"type:bigint;not null;default:0;comment:标注用户ID" json:"relevance_by"`
+	RelevanceAt *time.Time `gorm:"type:datetime;comment:标注时间" json:"relevance_at"`
+}
+
+// TableName 指定表名
+func (OpinionHit) TableName() string {
+	return "opinion_hits"
+}
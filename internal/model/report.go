@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+)
+
+// 简报周期
+const (
+	ReportFrequencyDaily   = "daily"
+	ReportFrequencyWeekly  = "weekly"
+	ReportFrequencyMonthly = "monthly"
+)
+
+// 简报文件格式
+const (
+	ReportFormatXLSX = "xlsx"
+	ReportFormatPDF  = "pdf"
+)
+
+// 简报生成状态
+const (
+	ReportStatusGenerating = "generating"
+	ReportStatusSuccess    = "success"
+	ReportStatusFailed     = "failed"
+)
+
+// ReportTemplate 场景简报模板，按周期由简报任务自动生成并发送
+type ReportTemplate struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID    uint64     `gorm:"type:bigint;not null;index;comment:场景ID" json:"scenario_id"`
+	Name          string     `gorm:"type:varchar(100);not null;comment:模板名称" json:"name"`
+	Frequency     string     `gorm:"type:varchar(10);not null;comment:周期:daily,weekly,monthly" json:"frequency"`
+	Sections      StringList `gorm:"type:json;comment:包含的章节:summary,trend,channels,negative,topics" json:"sections"`
+	Formats       StringList `gorm:"type:json;comment:生成的文件格式:xlsx,pdf" json:"formats"`
+	TopN          int        `gorm:"type:int;not null;default:10;comment:负面舆情、话题和关键词列出的数量" json:"top_n"`
+	Emails        StringList `gorm:"type:json;comment:邮件收件人(附带简报文件)" json:"emails"`
+	ChannelIDs    IDList     `gorm:"type:json;comment:通知渠道ID(发送下载链接)" json:"channel_ids"`
+	Status        int        `gorm:"type:tinyint;default:1;comment:1-正常,2-禁用" json:"status"`
+	LastPeriodEnd *time.Time `gorm:"type:datetime;comment:最近一次自动生成的周期结束时间" json:"last_period_end"`
+	CreatedBy     uint64     `gorm:"type:bigint;not null;default:0;comment:创建人用户ID" json:"created_by"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ReportTemplate) TableName() string {
+	return "report_templates"
+}
+
+// Report 已生成的简报，文件保存在本地存储目录
+type Report struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TemplateID  uint64     `gorm:"type:bigint;not null;index;comment:简报模板ID" json:"template_id"`
+	ScenarioID  uint64     `gorm:"type:bigint;not null;index;comment:场景ID" json:"scenario_id"`
+	Title       string     `gorm:"type:varchar(255);not null;comment:简报标题" json:"title"`
+	Frequency   string     `gorm:"type:varchar(10);not null;default:'';comment:周期:daily,weekly,monthly,空表示自定义时间范围" json:"frequency"`
+	PeriodStart time.Time  `gorm:"type:datetime;not null;comment:统计周期开始时间" json:"period_start"`
+	PeriodEnd   time.Time  `gorm:"type:datetime;not null;comment:统计周期结束时间(不含)" json:"period_end"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'generating';comment:状态:generating,success,failed" json:"status"`
+	Error       string     `gorm:"type:varchar(1000);default:'';comment:失败原因" json:"error"`
+	XLSXFile    string     `gorm:"column:xlsx_file;type:varchar(255);default:'';comment:XLSX文件相对路径" json:"-"`
+	XLSXSize    int64      `gorm:"column:xlsx_size;type:bigint;not null;default:0;comment:XLSX文件大小(字节)" json:"xlsx_size"`
+	PDFFile     string     `gorm:"column:pdf_file;type:varchar(255);default:'';comment:PDF文件相对路径" json:"-"`
+	PDFSize     int64      `gorm:"column:pdf_size;type:bigint;not null;default:0;comment:PDF文件大小(字节)" json:"pdf_size"`
+	GeneratedAt *time.Time `gorm:"type:datetime;comment:生成完成时间" json:"generated_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	Formats []string `gorm:"-" json:"formats"` // 可下载的格式
+}
+
+// TableName 指定表名
+func (Report) TableName() string {
+	return "reports"
+}
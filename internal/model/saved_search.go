@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+)
+
+// 保存的检索可见范围
+const (
+	SavedSearchPrivate = "private" // 仅创建人可见
+	SavedSearchTeam    = "team"    // 所有用户可见
+)
+
+// 订阅推送方式
+const (
+	SubscriptionRealtime = "realtime" // 每次检查发现新结果即推送
+	SubscriptionHourly   = "hourly"   // 每小时汇总推送一次
+	SubscriptionDaily    = "daily"    // 每天汇总推送一次
+)
+
+// 订阅推送的用户渠道
+const (
+	SubscriptionChannelInbox = "inbox" // 站内通知
+	SubscriptionChannelEmail = "email" // 用户邮箱
+)
+
+// SavedSearch 保存的检索：检索词和筛选条件，可以一键执行或订阅新结果
+type SavedSearch struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null;comment:名称" json:"name"`
+	Query       string    `gorm:"type:varchar(200);default:'';comment:检索词,为空时只按筛选条件查询" json:"q"`
+	Mode        string    `gorm:"type:varchar(10);default:'';comment:检索模式:natural,boolean,phrase" json:"mode"`
+	Sort        string    `gorm:"type:varchar(10);default:'';comment:排序方式:relevance,time" json:"sort"`
+	Filter      string    `gorm:"type:json;comment:筛选条件" json:"-"`
+	RecentHours int       `gorm:"type:int;not null;default:0;comment:只查询最近多少小时入库的舆情,0表示不限" json:"recent_hours"`
+	Visibility  string    `gorm:"type:varchar(10);not null;default:'private';comment:可见范围:private,team" json:"visibility"`
+	CreatedBy   uint64    `gorm:"type:bigint;not null;default:0;index;comment:创建人用户ID" json:"created_by"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Conditions   map[string]interface{}   `gorm:"-" json:"filter"`                 // 筛选条件
+	Subscription *SavedSearchSubscription `gorm:"-" json:"subscription,omitempty"` // 当前用户的订阅
+}
+
+// TableName 指定表名
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// SavedSearchSubscription 用户对保存的检索的订阅，新出现的结果推送到用户的站内通知、邮箱和通知渠道
+type SavedSearchSubscription struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	SavedSearchID  uint64     `gorm:"type:bigint;not null;uniqueIndex:uk_search_user;comment:保存的检索ID" json:"saved_search_id"`
+	UserID         uint64     `gorm:"type:bigint;not null;uniqueIndex:uk_search_user;index;comment:订阅用户ID" json:"user_id"`
+	Frequency      string     `gorm:"type:varchar(10);not null;comment:推送方式:realtime,hourly,daily" json:"frequency"`
+	Channels       StringList `gorm:"type:json;comment:用户渠道:inbox,email" json:"channels"`
+	ChannelIDs     IDList     `gorm:"type:json;comment:通知渠道ID" json:"channel_ids"`
+	LastCheckedAt  *time.Time `gorm:"type:datetime;comment:最近一次检查新结果的时间" json:"last_checked_at"`
+	LastNotifiedAt *time.Time `gorm:"type:datetime;comment:最近一次推送的时间" json:"last_notified_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SavedSearchSubscription) TableName() string {
+	return "saved_search_subscriptions"
+}
+
+// SavedSearchMatch 已推送给订阅的舆情，用于去重（命中记录可能晚于舆情入库产生，检查时会回看一段时间）
+type SavedSearchMatch struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_subscription_opinion;comment:订阅ID" json:"subscription_id"`
+	OpinionID      uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_subscription_opinion;comment:舆情ID" json:"opinion_id"`
+	CreatedAt      time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (SavedSearchMatch) TableName() string {
+	return "saved_search_matches"
+}
@@ -34,10 +34,19 @@ type MonitoringGroup struct {
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
+	// 采集计划
+	ScanInterval   int        `gorm:"type:int;default:60;comment:采集间隔(分钟)" json:"scan_interval"`
+	ActiveHours    string     `gorm:"type:varchar(20);default:'';comment:生效时段,如09:00-18:00,为空表示全天" json:"active_hours"`
+	ActiveWeekdays string     `gorm:"type:varchar(20);default:'';comment:生效星期,如1,2,3,4,5(0为周日),为空表示每天" json:"active_weekdays"`
+	StartDate      *time.Time `gorm:"type:date;comment:监测开始日期" json:"start_date"`
+	EndDate        *time.Time `gorm:"type:date;comment:监测结束日期" json:"end_date"`
+	LastScannedAt  *time.Time `gorm:"comment:最近一次扫描时间" json:"last_scanned_at"`
+
 	// 关联关系
 	Channels       []Channel            `gorm:"many2many:group_channels;" json:"channels,omitempty"`
 	Keywords       []GroupKeyword       `gorm:"foreignKey:GroupID" json:"keywords,omitempty"`
 	ExclusionWords []GroupExclusionWord `gorm:"foreignKey:GroupID" json:"exclusion_words,omitempty"`
+	SourceFilters  []GroupSourceFilter  `gorm:"foreignKey:GroupID" json:"source_filters,omitempty"`
 }
 
 // TableName 指定表名
@@ -75,9 +84,50 @@ type GroupExclusionWord struct {
 	GroupID   uint64    `gorm:"type:bigint;not null;comment:监测组ID" json:"group_id"`
 	Word      string    `gorm:"type:varchar(255);not null;comment:排除词" json:"word"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 排除统计（由扫描和导入时累加）
+	FilteredCount  int64      `gorm:"type:bigint;not null;default:0;comment:累计排除的舆情数" json:"filtered_count"`
+	LastFilteredAt *time.Time `gorm:"type:datetime;comment:最近一次排除舆情的时间" json:"last_filtered_at"`
 }
 
 // TableName 指定表名
 func (GroupExclusionWord) TableName() string {
 	return "group_exclusion_words"
 }
+
+// 来源过滤规则类型
+const (
+	SourceFilterAuthor = "author" // 作者（按作者ID）
+	SourceFilterDomain = "domain" // 原文链接的域名，同时匹配子域名
+	SourceFilterURL    = "url"    // 原文链接模式，* 匹配任意字符
+)
+
+// 来源过滤规则模式
+const (
+	SourceFilterBlock = "block" // 屏蔽名单：匹配的舆情不命中
+	SourceFilterAllow = "allow" // 允许名单：监测组有允许规则时，舆情需匹配其中之一才能命中
+)
+
+// GroupSourceFilter 监测组来源过滤规则：按作者、原文链接的域名或模式设置允许/屏蔽名单
+type GroupSourceFilter struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID   uint64    `gorm:"type:bigint;not null;index;comment:监测组ID" json:"group_id"`
+	Type      string    `gorm:"type:varchar(10);not null;comment:类型:author,domain,url" json:"type"`
+	Mode      string    `gorm:"type:varchar(10);not null;comment:模式:block,allow" json:"mode"`
+	AuthorID  uint64    `gorm:"type:bigint;not null;default:0;comment:作者ID,类型为author时有效" json:"author_id"`
+	Value     string    `gorm:"type:varchar(512);not null;default:'';comment:域名或链接模式,类型为domain,url时有效" json:"value"`
+	Note      string    `gorm:"type:varchar(255);default:'';comment:备注" json:"note"`
+	CreatedBy uint64    `gorm:"type:bigint;not null;default:0;comment:添加人用户ID" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 匹配统计（由扫描和导入时累加）
+	MatchedCount  int64      `gorm:"type:bigint;not null;default:0;comment:累计匹配的舆情数:屏蔽规则为排除数,允许规则为放行数" json:"matched_count"`
+	LastMatchedAt *time.Time `gorm:"type:datetime;comment:最近一次匹配舆情的时间" json:"last_matched_at"`
+
+	Author *Author `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+// TableName 指定表名
+func (GroupSourceFilter) TableName() string {
+	return "group_source_filters"
+}
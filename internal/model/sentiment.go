@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// SentimentLexicon 自定义情感词典条目
+type SentimentLexicon struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID uint64    `gorm:"type:bigint;not null;default:0;uniqueIndex:uk_scenario_word;comment:场景ID,0表示全局" json:"scenario_id"`
+	Word       string    `gorm:"type:varchar(100);not null;uniqueIndex:uk_scenario_word;comment:词语" json:"word"`
+	Type       string    `gorm:"type:varchar(20);not null;default:'sentiment';comment:类型:sentiment-情感词,negation-否定词,degree-程度副词" json:"type"`
+	Weight     float64   `gorm:"type:decimal(6,2);not null;default:0;comment:权重:情感词正负表示极性,程度副词为倍数" json:"weight"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SentimentLexicon) TableName() string {
+	return "sentiment_lexicons"
+}
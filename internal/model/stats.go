@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// 统计汇总维度
+const (
+	StatDimTotal     = "total"     // 场景总声量，DimKey 为空
+	StatDimChannel   = "channel"   // 按来源渠道，DimKey 为舆情来源
+	StatDimGroup     = "group"     // 按监测组，DimKey 为监测组ID
+	StatDimSentiment = "sentiment" // 按情感，DimKey 为情感标签
+	StatDimAuthor    = "author"    // 按作者，DimKey 为作者
+	StatDimKeyword   = "keyword"   // 按命中的关键词，DimKey 为关键词
+)
+
+// StatRollup 场景按小时预汇总的命中舆情数，由汇总任务维护
+type StatRollup struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID uint64    `gorm:"type:bigint;not null;uniqueIndex:uk_scenario_dim_bucket;comment:场景ID" json:"scenario_id"`
+	Dimension  string    `gorm:"type:varchar(20);not null;uniqueIndex:uk_scenario_dim_bucket;comment:维度:total,channel,group,sentiment,author,keyword" json:"dimension"`
+	DimKey     string    `gorm:"type:varchar(255);not null;default:'';uniqueIndex:uk_scenario_dim_bucket;comment:维度取值" json:"dim_key"`
+	Bucket     time.Time `gorm:"type:datetime;not null;uniqueIndex:uk_scenario_dim_bucket;comment:小时桶开始时间" json:"bucket"`
+	Count      int64     `gorm:"type:bigint;not null;default:0;comment:命中舆情数(同一舆情只计一次)" json:"count"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StatRollup) TableName() string {
+	return "scenario_stat_rollups"
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// TrendingTerm 场景热词（最近一次计算的突发词快照）
+type TrendingTerm struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID   uint64    `gorm:"type:bigint;not null;index:idx_scenario_score;comment:场景ID" json:"scenario_id"`
+	Term         string    `gorm:"type:varchar(100);not null;comment:词语" json:"term"`
+	Count        int       `gorm:"type:int;not null;default:0;comment:当前窗口出现该词的舆情数" json:"count"`
+	BaselineMean float64   `gorm:"type:decimal(10,4);not null;default:0;comment:基线窗口平均值" json:"baseline_mean"`
+	BaselineStd  float64   `gorm:"type:decimal(10,4);not null;default:0;comment:基线窗口标准差" json:"baseline_std"`
+	Score        float64   `gorm:"type:decimal(10,4);not null;default:0;index:idx_scenario_score;comment:突发得分(z-score)" json:"score"`
+	WindowStart  time.Time `gorm:"type:datetime;not null;comment:当前窗口开始时间" json:"window_start"`
+	WindowEnd    time.Time `gorm:"type:datetime;not null;comment:当前窗口结束时间" json:"window_end"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (TrendingTerm) TableName() string {
+	return "trending_terms"
+}
+
+// OpinionTopic 场景话题（最近一次聚类的结果快照）
+type OpinionTopic struct {
+	ID                uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScenarioID        uint64    `gorm:"type:bigint;not null;index:idx_scenario_score;comment:场景ID" json:"scenario_id"`
+	Label             string    `gorm:"type:varchar(255);not null;comment:话题名称(中心词拼接)" json:"label"`
+	Keywords          TermList  `gorm:"type:json;comment:中心词及权重" json:"keywords"`
+	Size              int       `gorm:"type:int;not null;default:0;comment:话题包含的舆情数" json:"size"`
+	Score             float64   `gorm:"type:decimal(10,4);not null;default:0;index:idx_scenario_score;comment:热度得分" json:"score"`
+	AvgSentiment      float64   `gorm:"type:decimal(6,4);not null;default:0;comment:平均情感得分" json:"avg_sentiment"`
+	RepresentativeIDs IDList    `gorm:"type:json;comment:代表性舆情ID" json:"representative_ids"`
+	WindowStart       time.Time `gorm:"type:datetime;not null;comment:聚类窗口开始时间" json:"window_start"`
+	WindowEnd         time.Time `gorm:"type:datetime;not null;comment:聚类窗口结束时间" json:"window_end"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Opinions []*Opinion `gorm:"-" json:"opinions,omitempty"` // 代表性舆情
+}
+
+// TableName 指定表名
+func (OpinionTopic) TableName() string {
+	return "opinion_topics"
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList 以 JSON 数组存储的字符串列表
+type StringList []string
+
+// Value 实现 driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// NamedEntity 命名实体
+type NamedEntity struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// EntityList 以 JSON 数组存储的实体列表
+type EntityList []NamedEntity
+
+// Value 实现 driver.Valuer
+func (l EntityList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (l *EntityList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// scanJSON 将数据库中的 JSON 文本解析到 dest
+func scanJSON(value interface{}, dest interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("不支持的 JSON 列类型: %T", value)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// WeightedTerm 带权重的词语
+type WeightedTerm struct {
+	Word   string  `json:"word"`
+	Weight float64 `json:"weight"`
+}
+
+// TermList 以 JSON 数组存储的带权重词语列表
+type TermList []WeightedTerm
+
+// Value 实现 driver.Valuer
+func (l TermList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (l *TermList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// IDList 以 JSON 数组存储的 ID 列表
+type IDList []uint64
+
+// Value 实现 driver.Valuer
+func (l IDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan 实现 sql.Scanner
+func (l *IDList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DingTalkNotifier 钉钉群机器人，发送 Markdown 消息
+//
+// 配置了加签密钥时，在地址上附加 timestamp（毫秒）和
+// sign = urlencode(base64(HMAC-SHA256(secret, timestamp + "\n" + secret)))
+type DingTalkNotifier struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewDingTalkNotifier 创建钉钉机器人通知渠道，client 为 nil 时使用 http.DefaultClient
+func NewDingTalkNotifier(client *http.Client) *DingTalkNotifier {
+	return &DingTalkNotifier{client: clientOrDefault(client), now: time.Now}
+}
+
+// Type 渠道类型
+func (n *DingTalkNotifier) Type() string {
+	return TypeDingTalk
+}
+
+// Send 发送消息
+func (n *DingTalkNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	if target.URL == "" {
+		return Permanent(errors.New("未配置钉钉机器人地址"))
+	}
+
+	endpoint := target.URL
+	if target.Secret != "" {
+		timestamp := strconv.FormatInt(n.now().UnixMilli(), 10)
+		endpoint = appendQuery(endpoint, url.Values{
+			"timestamp": {timestamp},
+			"sign":      {SignDingTalk(target.Secret, timestamp)},
+		})
+	}
+
+	// 钉钉要求被 @ 的手机号出现在正文中
+	text := msg.Body
+	for _, mobile := range target.AtMobiles {
+		text += " @" + mobile
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  text,
+		},
+		"at": map[string]interface{}{
+			"atMobiles": target.AtMobiles,
+			"isAtAll":   target.AtAll,
+		},
+	})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	body, err := postJSON(ctx, n.client, endpoint, nil, payload)
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
+
+// SignDingTalk 计算钉钉机器人加签
+func SignDingTalk(secret, timestamp string) string {
+	return base64.StdEncoding.EncodeToString(hmacSHA256([]byte(secret), []byte(timestamp+"\n"+secret)))
+}
+
+// appendQuery 在地址上追加查询参数
+func appendQuery(endpoint string, values url.Values) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return endpoint + sep + values.Encode()
+}
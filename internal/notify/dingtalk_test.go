@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDingTalkSignsURLAndSendsMarkdown(t *testing.T) {
+	server := newCaptureServer(t)
+	n := NewDingTalkNotifier(nil)
+	n.now = func() time.Time { return fixedNow }
+
+	target := Target{
+		URL:       server.URL + "/robot/send?access_token=abc",
+		Secret:    "SECabc",
+		AtMobiles: []string{"13800000000"},
+	}
+	if err := n.Send(context.Background(), target, Message{Title: "告警", Body: "### 告警"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	req := server.Requests()[0]
+	if got := req.Query["access_token"]; len(got) != 1 || got[0] != "abc" {
+		t.Errorf("access_token = %v, want original query kept", got)
+	}
+	timestamp := strconv.FormatInt(fixedNow.UnixMilli(), 10)
+	if got := req.Query["timestamp"]; len(got) != 1 || got[0] != timestamp {
+		t.Errorf("timestamp = %v, want %s (milliseconds)", got, timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte("SECabc"))
+	mac.Write([]byte(timestamp + "\n" + "SECabc"))
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if got := req.Query["sign"]; len(got) != 1 || got[0] != want {
+		t.Errorf("sign = %v, want %s", got, want)
+	}
+
+	var body struct {
+		MsgType  string `json:"msgtype"`
+		Markdown struct {
+			Title string `json:"title"`
+			Text  string `json:"text"`
+		} `json:"markdown"`
+		At struct {
+			AtMobiles []string `json:"atMobiles"`
+			IsAtAll   bool     `json:"isAtAll"`
+		} `json:"at"`
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if body.MsgType != "markdown" || body.Markdown.Title != "告警" {
+		t.Errorf("body = %+v, want markdown message titled 告警", body)
+	}
+	if !strings.HasSuffix(body.Markdown.Text, "@13800000000") {
+		t.Errorf("markdown text = %q, want @mobile appended", body.Markdown.Text)
+	}
+	if len(body.At.AtMobiles) != 1 || body.At.AtMobiles[0] != "13800000000" {
+		t.Errorf("atMobiles = %v", body.At.AtMobiles)
+	}
+}
+
+func TestDingTalkErrorCodeIsPermanent(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, `{"errcode":310000,"errmsg":"sign not match"}`})
+	err := NewDingTalkNotifier(nil).Send(context.Background(), Target{URL: server.URL}, Message{})
+	if !IsPermanent(err) {
+		t.Errorf("error = %v, want permanent robot error", err)
+	}
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPOptions SMTP 服务配置
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // 为空时不认证
+	Password string
+	From     string
+	TLS      bool          // 使用隐式 TLS（如 465 端口）；否则在服务端支持时使用 STARTTLS
+	Timeout  time.Duration // 连接和发送的整体超时
+}
+
+// EmailNotifier SMTP 邮件
+type EmailNotifier struct {
+	opts SMTPOptions
+	now  func() time.Time
+}
+
+// NewEmailNotifier 创建邮件通知渠道
+func NewEmailNotifier(opts SMTPOptions) *EmailNotifier {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &EmailNotifier{opts: opts, now: time.Now}
+}
+
+// Type 渠道类型
+func (n *EmailNotifier) Type() string {
+	return TypeEmail
+}
+
+// Send 发送邮件，收件人为 target.Emails
+func (n *EmailNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	if n.opts.Host == "" || n.opts.From == "" {
+		return Permanent(errors.New("未配置 SMTP 服务"))
+	}
+	if len(target.Emails) == 0 {
+		return Permanent(errors.New("未配置邮件收件人"))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.opts.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(n.opts.Host, strconv.Itoa(n.opts.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务失败: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if n.opts.TLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: n.opts.Host})
+	}
+
+	client, err := smtp.NewClient(conn, n.opts.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接 SMTP 服务失败: %w", err)
+	}
+	defer client.Close()
+
+	if !n.opts.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: n.opts.Host}); err != nil {
+				return fmt.Errorf("STARTTLS 失败: %w", err)
+			}
+		}
+	}
+	if n.opts.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", n.opts.Username, n.opts.Password, n.opts.Host)
+			if err := client.Auth(auth); err != nil {
+				return Permanent(fmt.Errorf("SMTP 认证失败: %w", err))
+			}
+		}
+	}
+
+	if err := client.Mail(n.opts.From); err != nil {
+		return smtpError("MAIL FROM", err)
+	}
+	for _, to := range target.Emails {
+		if err := client.Rcpt(to); err != nil {
+			return smtpError("RCPT TO", err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return smtpError("DATA", err)
+	}
+	if _, err := w.Write(n.buildMessage(target.Emails, msg)); err != nil {
+		return fmt.Errorf("写入邮件内容失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError("DATA", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage 构造 MIME 邮件，主题和正文使用 UTF-8 + Base64 编码；有附件时使用 multipart/mixed
+func (n *EmailNotifier) buildMessage(to []string, msg Message) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + n.opts.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Title) + "\r\n")
+	buf.WriteString("Date: " + n.now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(msg.Attachments) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, []byte(msg.Body))
+		return buf.Bytes()
+	}
+
+	mw := multipart.NewWriter(&buf)
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + mw.Boundary() + "\r\n")
+	buf.WriteString("\r\n")
+
+	body, _ := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	writeBase64(body, []byte(msg.Body))
+
+	for _, a := range msg.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		filename := mime.BEncoding.Encode("UTF-8", a.Filename)
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": filename})},
+			"Content-Disposition":       {`attachment; filename="` + filename + `"`},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		writeBase64(part, a.Data)
+	}
+	mw.Close()
+	return buf.Bytes()
+}
+
+// writeBase64 按每行 76 个字符写入 Base64 编码的内容
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(w, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(w, encoded+"\r\n")
+}
+
+// smtpError 包装 SMTP 命令错误，5xx 永久错误不重试
+func smtpError(command string, err error) error {
+	wrapped := fmt.Errorf("SMTP %s 失败: %w", command, err)
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return Permanent(wrapped)
+	}
+	return wrapped
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"sentinel-opinion-monitor/internal/notify/smtpstub"
+)
+
+func newSMTPStub(t *testing.T, opts smtpstub.Options) *smtpstub.Server {
+	t.Helper()
+	server, err := smtpstub.NewServer(opts)
+	if err != nil {
+		t.Fatalf("start smtpstub: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+func newTestEmailNotifier(server *smtpstub.Server) *EmailNotifier {
+	n := NewEmailNotifier(SMTPOptions{
+		Host:    "127.0.0.1",
+		Port:    server.Addr().Port,
+		From:    "sentinel@example.com",
+		Timeout: 2 * time.Second,
+	})
+	n.now = func() time.Time { return fixedNow }
+	return n
+}
+
+func TestEmailDeliversToAllRecipients(t *testing.T) {
+	server := newSMTPStub(t, smtpstub.Options{})
+	n := newTestEmailNotifier(server)
+
+	target := Target{Emails: []string{"a@example.com", "b@example.com"}}
+	if err := n.Send(context.Background(), target, Message{Title: "舆情告警", Body: "负面声量突增"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	mails := server.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("mails = %d, want 1", len(mails))
+	}
+	got := mails[0]
+	if got.From != "sentinel@example.com" {
+		t.Errorf("MAIL FROM = %q", got.From)
+	}
+	if strings.Join(got.To, ",") != "a@example.com,b@example.com" {
+		t.Errorf("RCPT TO = %v", got.To)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.Data))
+	if err != nil {
+		t.Fatalf("parse mail: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != "舆情告警" {
+		t.Errorf("Subject = %q (%v), want 舆情告警", subject, err)
+	}
+	raw := new(strings.Builder)
+	buf := make([]byte, 4096)
+	for {
+		n, err := parsed.Body.Read(buf)
+		raw.Write(buf[:n])
+		if err != nil {
+			break
+		}
+	}
+	body, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(strings.TrimSpace(raw.String()), "\r\n", ""))
+	if err != nil || string(body) != "负面声量突增" {
+		t.Errorf("body = %q (%v), want 负面声量突增", body, err)
+	}
+}
+
+func TestEmailWithAttachmentIsMultipart(t *testing.T) {
+	server := newSMTPStub(t, smtpstub.Options{})
+	n := newTestEmailNotifier(server)
+
+	msg := Message{
+		Title: "周报",
+		Body:  "见附件",
+		Attachments: []Attachment{
+			{Filename: "report.xlsx", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Data: []byte("xlsx")},
+		},
+	}
+	if err := n.Send(context.Background(), Target{Emails: []string{"a@example.com"}}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	data := server.Mails()[0].Data
+	if !strings.Contains(data, "Content-Type: multipart/mixed; boundary=") {
+		t.Error("mail with attachment is not multipart/mixed")
+	}
+	if !strings.Contains(data, `filename="report.xlsx"`) {
+		t.Error("attachment filename missing")
+	}
+	if !strings.Contains(data, base64.StdEncoding.EncodeToString([]byte("xlsx"))) {
+		t.Error("attachment content missing")
+	}
+}
+
+func TestEmailTemporaryFailureIsRetried(t *testing.T) {
+	server := newSMTPStub(t, smtpstub.Options{FailTimes: 1})
+	n := newTestEmailNotifier(server)
+
+	err := n.Send(context.Background(), Target{Emails: []string{"a@example.com"}}, Message{Title: "t"})
+	if err == nil || IsPermanent(err) {
+		t.Fatalf("first Send() error = %v, want retryable 451", err)
+	}
+
+	attempts, err := SendWithRetry(context.Background(), n, Target{Emails: []string{"a@example.com"}}, Message{Title: "t"},
+		RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	if err != nil || attempts != 1 {
+		t.Fatalf("SendWithRetry() = %d, %v; want delivered on first attempt after the stub recovered", attempts, err)
+	}
+	if len(server.Mails()) != 1 {
+		t.Errorf("mails = %d, want 1", len(server.Mails()))
+	}
+}
+
+func TestEmailRequiresConfiguration(t *testing.T) {
+	if err := NewEmailNotifier(SMTPOptions{}).Send(context.Background(), Target{Emails: []string{"a@example.com"}}, Message{}); !IsPermanent(err) {
+		t.Errorf("missing SMTP config error = %v, want permanent", err)
+	}
+	n := NewEmailNotifier(SMTPOptions{Host: "127.0.0.1", From: "x@example.com"})
+	if err := n.Send(context.Background(), Target{}, Message{}); !IsPermanent(err) {
+		t.Errorf("missing recipients error = %v, want permanent", err)
+	}
+}
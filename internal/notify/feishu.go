@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FeishuNotifier 飞书群机器人，发送文本消息
+//
+// 配置了签名校验密钥时，请求体携带 timestamp（秒）和
+// sign = base64(HMAC-SHA256(key = timestamp + "\n" + secret, data = 空))
+type FeishuNotifier struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewFeishuNotifier 创建飞书机器人通知渠道，client 为 nil 时使用 http.DefaultClient
+func NewFeishuNotifier(client *http.Client) *FeishuNotifier {
+	return &FeishuNotifier{client: clientOrDefault(client), now: time.Now}
+}
+
+// Type 渠道类型
+func (n *FeishuNotifier) Type() string {
+	return TypeFeishu
+}
+
+// Send 发送消息
+func (n *FeishuNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	if target.URL == "" {
+		return Permanent(errors.New("未配置飞书机器人地址"))
+	}
+
+	text := msg.Body
+	if target.AtAll {
+		text += "\n<at user_id=\"all\">所有人</at>"
+	}
+
+	message := map[string]interface{}{
+		"msg_type": "text",
+		"content": map[string]string{
+			"text": text,
+		},
+	}
+	if target.Secret != "" {
+		timestamp := strconv.FormatInt(n.now().Unix(), 10)
+		message["timestamp"] = timestamp
+		message["sign"] = SignFeishu(target.Secret, timestamp)
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	body, err := postJSON(ctx, n.client, target.URL, nil, payload)
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
+
+// SignFeishu 计算飞书机器人签名
+func SignFeishu(secret, timestamp string) string {
+	return base64.StdEncoding.EncodeToString(hmacSHA256([]byte(timestamp+"\n"+secret), nil))
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeishuSignsBody(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, `{"code":0,"msg":"success"}`})
+	n := NewFeishuNotifier(nil)
+	n.now = func() time.Time { return fixedNow }
+
+	target := Target{URL: server.URL, Secret: "feishu-secret", AtAll: true}
+	if err := n.Send(context.Background(), target, Message{Title: "告警", Body: "负面声量突增"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var body struct {
+		MsgType   string            `json:"msg_type"`
+		Content   map[string]string `json:"content"`
+		Timestamp string            `json:"timestamp"`
+		Sign      string            `json:"sign"`
+	}
+	if err := json.Unmarshal(server.Requests()[0].Body, &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(fixedNow.Unix(), 10)
+	if body.Timestamp != timestamp {
+		t.Errorf("timestamp = %q, want %s (seconds)", body.Timestamp, timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+"feishu-secret"))
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if body.Sign != want {
+		t.Errorf("sign = %q, want %q", body.Sign, want)
+	}
+	if body.MsgType != "text" || !strings.HasPrefix(body.Content["text"], "负面声量突增") {
+		t.Errorf("body = %+v, want text message", body)
+	}
+	if !strings.Contains(body.Content["text"], `<at user_id="all">`) {
+		t.Errorf("text = %q, want @all mention", body.Content["text"])
+	}
+}
+
+func TestFeishuWithoutSecretIsUnsigned(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, `{"code":0}`})
+	if err := NewFeishuNotifier(nil).Send(context.Background(), Target{URL: server.URL}, Message{Body: "b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(server.Requests()[0].Body, &body)
+	if _, ok := body["sign"]; ok {
+		t.Errorf("unsigned message carried sign: %v", body)
+	}
+}
+
+func TestFeishuErrorCodeIsPermanent(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, `{"code":19021,"msg":"sign match fail"}`})
+	err := NewFeishuNotifier(nil).Send(context.Background(), Target{URL: server.URL}, Message{})
+	if !IsPermanent(err) {
+		t.Errorf("error = %v, want permanent robot error", err)
+	}
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxResponseBody 读取响应体的上限
+const maxResponseBody = 64 * 1024
+
+// postJSON 发送 JSON 请求并返回响应体；5xx 和 429 可重试，其余非 2xx 状态码不重试
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("返回状态码 %d: %s", resp.StatusCode, truncate(string(body), 200))
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, err
+		}
+		return nil, Permanent(err)
+	}
+	return body, nil
+}
+
+// robotResponse 钉钉、企业微信、飞书机器人的通用响应
+type robotResponse struct {
+	ErrCode    *int   `json:"errcode"`
+	ErrMsg     string `json:"errmsg"`
+	Code       *int   `json:"code"`
+	Msg        string `json:"msg"`
+	StatusCode *int   `json:"StatusCode"`
+}
+
+// checkRobotResponse 检查机器人响应中的错误码
+func checkRobotResponse(body []byte) error {
+	var resp robotResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	switch {
+	case resp.ErrCode != nil && *resp.ErrCode != 0:
+		return Permanent(fmt.Errorf("机器人返回错误 %d: %s", *resp.ErrCode, resp.ErrMsg))
+	case resp.Code != nil && *resp.Code != 0:
+		return Permanent(fmt.Errorf("机器人返回错误 %d: %s", *resp.Code, resp.Msg))
+	case resp.StatusCode != nil && *resp.StatusCode != 0:
+		return Permanent(fmt.Errorf("机器人返回错误 %d", *resp.StatusCode))
+	}
+	return nil
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// truncate 截断字符串
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "..."
+}
+
+// clientOrDefault 返回可用的 HTTP 客户端
+func clientOrDefault(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+	return client
+}
@@ -0,0 +1,122 @@
+// Package notify 提供告警和报表的通知渠道：通用签名 Webhook、SMTP 邮件以及钉钉、企业微信、飞书机器人
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 通知渠道类型
+const (
+	TypeWebhook  = "webhook"
+	TypeEmail    = "email"
+	TypeDingTalk = "dingtalk"
+	TypeWeCom    = "wecom"
+	TypeFeishu   = "feishu"
+)
+
+// Message 通知消息，Body 为按渠道模板渲染后的正文
+type Message struct {
+	Title    string                 `json:"title"`
+	Content  string                 `json:"content"`
+	Body     string                 `json:"body"`
+	Severity string                 `json:"severity,omitempty"`
+	Status   string                 `json:"status,omitempty"`
+	Link     string                 `json:"link,omitempty"`
+	Time     time.Time              `json:"time"`
+	Data     map[string]interface{} `json:"data,omitempty"` // 附加数据，Webhook 原样发送
+
+	Attachments []Attachment `json:"-"` // 附件，只有邮件渠道发送
+}
+
+// Attachment 邮件附件
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Target 发送目标
+type Target struct {
+	URL       string   // Webhook 或机器人地址
+	Secret    string   // 签名密钥，为空时不签名
+	Emails    []string // 邮件收件人
+	AtMobiles []string // 机器人消息 @ 的手机号
+	AtAll     bool     // 机器人消息 @ 所有人
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Type() string
+	Send(ctx context.Context, target Target, msg Message) error
+}
+
+// permanentError 不需要重试的错误（如配置错误、4xx 响应）
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent 将错误标记为不可重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 判断错误是否不可重试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// RetryPolicy 重试策略，退避时间按指数增长
+type RetryPolicy struct {
+	MaxAttempts    int           // 最多尝试次数（含首次）
+	InitialBackoff time.Duration // 首次重试前的等待时间
+	MaxBackoff     time.Duration // 单次等待时间上限
+}
+
+// DefaultRetryPolicy 默认重试策略：最多 3 次，等待 1s、2s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// SendWithRetry 发送消息，失败时按策略重试，返回实际尝试次数
+func SendWithRetry(ctx context.Context, n Notifier, target Target, msg Message, policy RetryPolicy) (int, error) {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	backoff := policy.InitialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = n.Send(ctx, target, msg)
+		if err == nil || IsPermanent(err) || attempt >= policy.MaxAttempts {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("%w（重试已取消: %v）", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
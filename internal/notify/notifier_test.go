@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixedNow 测试中使用的固定时间
+var fixedNow = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// capturedRequest 替身服务收到的请求
+type capturedRequest struct {
+	Header http.Header
+	Query  map[string][]string
+	Body   []byte
+}
+
+// captureServer 记录请求并按顺序返回预设响应的替身服务，响应用完后重复最后一个
+type captureServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []capturedRequest
+	responses []stubResponse
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+func newCaptureServer(t *testing.T, responses ...stubResponse) *captureServer {
+	t.Helper()
+	if len(responses) == 0 {
+		responses = []stubResponse{{http.StatusOK, `{"errcode":0,"errmsg":"ok"}`}}
+	}
+	s := &captureServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, capturedRequest{Header: r.Header.Clone(), Query: r.URL.Query(), Body: body})
+		resp := s.responses[0]
+		if len(s.responses) > 1 {
+			s.responses = s.responses[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(resp.status)
+		io.WriteString(w, resp.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *captureServer) Requests() []capturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]capturedRequest(nil), s.requests...)
+}
+
+// countingNotifier 按预设错误依次返回的通知渠道，记录每次调用的时间
+type countingNotifier struct {
+	errs  []error
+	calls []time.Time
+}
+
+func (n *countingNotifier) Type() string { return "counting" }
+
+func (n *countingNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	n.calls = append(n.calls, time.Now())
+	if len(n.calls) <= len(n.errs) {
+		return n.errs[len(n.calls)-1]
+	}
+	return nil
+}
+
+func TestSendWithRetryBacksOffExponentially(t *testing.T) {
+	transient := errors.New("503")
+	n := &countingNotifier{errs: []error{transient, transient}}
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 20 * time.Millisecond, MaxBackoff: time.Second}
+
+	attempts, err := SendWithRetry(context.Background(), n, Target{}, Message{}, policy)
+	if err != nil {
+		t.Fatalf("SendWithRetry() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	first, second := n.calls[1].Sub(n.calls[0]), n.calls[2].Sub(n.calls[1])
+	if first < 20*time.Millisecond {
+		t.Errorf("first backoff = %v, want >= 20ms", first)
+	}
+	if second < 40*time.Millisecond {
+		t.Errorf("second backoff = %v, want >= 40ms (doubled)", second)
+	}
+}
+
+func TestSendWithRetryCapsBackoff(t *testing.T) {
+	transient := errors.New("503")
+	n := &countingNotifier{errs: []error{transient, transient, transient}}
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: 30 * time.Millisecond, MaxBackoff: 30 * time.Millisecond}
+
+	attempts, err := SendWithRetry(context.Background(), n, Target{}, Message{}, policy)
+	if !errors.Is(err, transient) {
+		t.Fatalf("SendWithRetry() error = %v, want last transient error", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	if second := n.calls[2].Sub(n.calls[1]); second > 200*time.Millisecond {
+		t.Errorf("second backoff = %v, want it capped near 30ms", second)
+	}
+}
+
+func TestSendWithRetryStopsOnPermanentError(t *testing.T) {
+	n := &countingNotifier{errs: []error{Permanent(errors.New("400"))}}
+	attempts, err := SendWithRetry(context.Background(), n, Target{}, Message{}, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if !IsPermanent(err) {
+		t.Fatalf("SendWithRetry() error = %v, want permanent error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestSendWithRetryHonoursContext(t *testing.T) {
+	transient := errors.New("503")
+	n := &countingNotifier{errs: []error{transient, transient}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	attempts, err := SendWithRetry(ctx, n, Target{}, Message{}, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second})
+	if err == nil || !errors.Is(err, transient) {
+		t.Fatalf("SendWithRetry() error = %v, want wrapped transient error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestPostJSONClassifiesStatusCodes(t *testing.T) {
+	cases := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusInternalServerError, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusBadRequest, true},
+		{http.StatusForbidden, true},
+	}
+	for _, c := range cases {
+		server := newCaptureServer(t, stubResponse{c.status, "error"})
+		_, err := postJSON(context.Background(), http.DefaultClient, server.URL, nil, []byte(`{}`))
+		if err == nil {
+			t.Fatalf("status %d: error = nil", c.status)
+		}
+		if IsPermanent(err) != c.permanent {
+			t.Errorf("status %d: IsPermanent = %v, want %v", c.status, IsPermanent(err), c.permanent)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package smtpstub 提供最小化的 SMTP 替身服务，记录收到的邮件，
+// 可在测试中配合 notify.EmailNotifier 使用，也可用于本地联调。
+package smtpstub
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Mail 收到的邮件
+type Mail struct {
+	From string
+	To   []string
+	Data string // 原始邮件内容（含头部）
+}
+
+// Options 替身服务行为配置
+type Options struct {
+	FailTimes int // 前 N 封邮件在 MAIL FROM 时返回 451 临时错误，用于验证重试
+}
+
+// Server SMTP 替身服务
+type Server struct {
+	opts     Options
+	listener net.Listener
+
+	mu       sync.Mutex
+	mails    []Mail
+	failures int
+}
+
+// NewServer 在 127.0.0.1 的随机端口上启动替身服务
+func NewServer(opts Options) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{opts: opts, listener: listener}
+	go s.serve()
+	return s, nil
+}
+
+// Addr 服务地址
+func (s *Server) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Mails 返回已收到的邮件
+func (s *Server) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+// Close 停止服务
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle 处理一个 SMTP 会话，只实现 EHLO/HELO、MAIL、RCPT、DATA、RSET、NOOP、QUIT
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		w.WriteString(line + "\r\n")
+		w.Flush()
+	}
+
+	reply("220 smtpstub ready")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 smtpstub")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			if s.shouldFail() {
+				reply("451 temporary failure")
+				continue
+			}
+			mail = Mail{From: trimAddress(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			mail.To = append(mail.To, trimAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" || l == ".\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			mail = Mail{}
+			reply("250 OK")
+		case cmd == "RSET":
+			mail = Mail{}
+			reply("250 OK")
+		case cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+// shouldFail 判断本次是否返回临时错误
+func (s *Server) shouldFail() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures < s.opts.FailTimes {
+		s.failures++
+		return true
+	}
+	return false
+}
+
+// trimAddress 去掉地址两侧的尖括号和参数
+func trimAddress(v string) string {
+	v = strings.TrimSpace(v)
+	if i := strings.Index(v, ">"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimPrefix(v, "<")
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// 默认模板：机器人使用 Markdown，其余使用纯文本
+const (
+	defaultTextTemplate = `{{.Title}}
+{{if .Severity}}级别：{{.Severity}}
+{{end}}{{if .Status}}状态：{{.Status}}
+{{end}}时间：{{.Time.Format "2006-01-02 15:04:05"}}
+{{.Content}}{{if .Link}}
+详情：{{.Link}}{{end}}`
+
+	defaultMarkdownTemplate = `### {{.Title}}
+{{if .Severity}}- 级别：{{.Severity}}
+{{end}}{{if .Status}}- 状态：{{.Status}}
+{{end}}- 时间：{{.Time.Format "2006-01-02 15:04:05"}}
+
+{{.Content}}{{if .Link}}
+
+[查看详情]({{.Link}}){{end}}`
+)
+
+// DefaultTemplate 渠道类型的默认消息模板
+func DefaultTemplate(channelType string) string {
+	switch channelType {
+	case TypeDingTalk, TypeWeCom:
+		return defaultMarkdownTemplate
+	default:
+		return defaultTextTemplate
+	}
+}
+
+// ParseTemplate 校验模板语法
+func ParseTemplate(text string) error {
+	_, err := template.New("message").Parse(text)
+	return err
+}
+
+// Render 使用模板渲染消息正文，模板为空时使用渠道类型的默认模板
+// 模板可使用 Message 的字段，如 {{.Title}}、{{.Content}}、{{.Severity}}、{{.Data.rule_name}}
+func Render(channelType, text string, msg Message) (string, error) {
+	if text == "" {
+		text = DefaultTemplate(channelType)
+	}
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
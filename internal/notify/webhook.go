@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook 签名请求头
+const (
+	HeaderTimestamp = "X-Sentinel-Timestamp"
+	HeaderSignature = "X-Sentinel-Signature"
+)
+
+// WebhookNotifier 通用 Webhook，POST JSON 消息
+//
+// 配置了密钥时携带签名：X-Sentinel-Timestamp 为 Unix 秒，
+// X-Sentinel-Signature 为 "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+type WebhookNotifier struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookNotifier 创建通用 Webhook 通知渠道，client 为 nil 时使用 http.DefaultClient
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: clientOrDefault(client), now: time.Now}
+}
+
+// Type 渠道类型
+func (n *WebhookNotifier) Type() string {
+	return TypeWebhook
+}
+
+// Send 发送消息
+func (n *WebhookNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	if target.URL == "" {
+		return Permanent(errors.New("未配置 Webhook 地址"))
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	headers := map[string]string{}
+	if target.Secret != "" {
+		timestamp := strconv.FormatInt(n.now().Unix(), 10)
+		headers[HeaderTimestamp] = timestamp
+		headers[HeaderSignature] = SignWebhook(target.Secret, timestamp, payload)
+	}
+
+	_, err = postJSON(ctx, n.client, target.URL, headers, payload)
+	return err
+}
+
+// SignWebhook 计算通用 Webhook 签名，接收方可用同样方法校验
+func SignWebhook(secret, timestamp string, body []byte) string {
+	data := append([]byte(timestamp+"."), body...)
+	return "sha256=" + hex.EncodeToString(hmacSHA256([]byte(secret), data))
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookSignsPayload(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, ""})
+	n := NewWebhookNotifier(nil)
+	n.now = func() time.Time { return fixedNow }
+
+	msg := Message{Title: "告警", Content: "负面声量突增", Time: fixedNow, Data: map[string]interface{}{"rule_id": 7}}
+	if err := n.Send(context.Background(), Target{URL: server.URL, Secret: "s3cret"}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	reqs := server.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1", len(reqs))
+	}
+	req := reqs[0]
+	timestamp := req.Header.Get(HeaderTimestamp)
+	if timestamp != strconv.FormatInt(fixedNow.Unix(), 10) {
+		t.Errorf("%s = %q, want %d", HeaderTimestamp, timestamp, fixedNow.Unix())
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(req.Body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.Header.Get(HeaderSignature); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+
+	var body Message
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatalf("body is not a JSON message: %v", err)
+	}
+	if body.Title != msg.Title || body.Content != msg.Content || body.Data["rule_id"] != float64(7) {
+		t.Errorf("body = %+v, want the original message", body)
+	}
+}
+
+func TestWebhookWithoutSecretIsUnsigned(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, ""})
+	if err := NewWebhookNotifier(nil).Send(context.Background(), Target{URL: server.URL}, Message{Title: "t"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	req := server.Requests()[0]
+	if req.Header.Get(HeaderSignature) != "" || req.Header.Get(HeaderTimestamp) != "" {
+		t.Errorf("unsigned webhook carried signature headers: %v", req.Header)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	n := NewWebhookNotifier(nil)
+	if err := n.Send(context.Background(), Target{}, Message{}); !IsPermanent(err) {
+		t.Errorf("missing URL error = %v, want permanent", err)
+	}
+
+	server := newCaptureServer(t, stubResponse{http.StatusBadGateway, "bad gateway"})
+	err := n.Send(context.Background(), Target{URL: server.URL}, Message{})
+	if err == nil || IsPermanent(err) {
+		t.Errorf("502 error = %v, want retryable error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// WeComNotifier 企业微信群机器人
+//
+// 企业微信机器人以地址中的 key 鉴权，没有加签机制。
+// 正文以 Markdown 消息发送；Markdown 消息不支持按手机号 @，需要 @ 成员时再追加一条带提醒的文本消息
+type WeComNotifier struct {
+	client *http.Client
+}
+
+// NewWeComNotifier 创建企业微信机器人通知渠道，client 为 nil 时使用 http.DefaultClient
+func NewWeComNotifier(client *http.Client) *WeComNotifier {
+	return &WeComNotifier{client: clientOrDefault(client)}
+}
+
+// Type 渠道类型
+func (n *WeComNotifier) Type() string {
+	return TypeWeCom
+}
+
+// Send 发送消息
+func (n *WeComNotifier) Send(ctx context.Context, target Target, msg Message) error {
+	if target.URL == "" {
+		return Permanent(errors.New("未配置企业微信机器人地址"))
+	}
+
+	err := n.post(ctx, target.URL, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": msg.Body,
+		},
+	})
+	if err != nil || (len(target.AtMobiles) == 0 && !target.AtAll) {
+		return err
+	}
+
+	mobiles := append([]string{}, target.AtMobiles...)
+	if target.AtAll {
+		mobiles = append(mobiles, "@all")
+	}
+	return n.post(ctx, target.URL, map[string]interface{}{
+		"msgtype": "text",
+		"text": map[string]interface{}{
+			"content":               msg.Title,
+			"mentioned_mobile_list": mobiles,
+		},
+	})
+}
+
+// post 发送一条机器人消息
+func (n *WeComNotifier) post(ctx context.Context, url string, message map[string]interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	body, err := postJSON(ctx, n.client, url, nil, payload)
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
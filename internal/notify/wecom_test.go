@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// wecomMessage 企业微信机器人消息
+type wecomMessage struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Content string `json:"content"`
+	} `json:"markdown"`
+	Text struct {
+		Content             string   `json:"content"`
+		MentionedMobileList []string `json:"mentioned_mobile_list"`
+	} `json:"text"`
+}
+
+func TestWeComSendsMarkdown(t *testing.T) {
+	server := newCaptureServer(t)
+	target := Target{URL: server.URL + "/cgi-bin/webhook/send?key=k", Secret: "ignored"}
+	if err := NewWeComNotifier(nil).Send(context.Background(), target, Message{Title: "告警", Body: "### 告警"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	reqs := server.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %d, want 1 (no mention message)", len(reqs))
+	}
+	if got := reqs[0].Query["key"]; len(got) != 1 || got[0] != "k" {
+		t.Errorf("key = %v, want k", got)
+	}
+	if _, ok := reqs[0].Query["sign"]; ok {
+		t.Error("WeCom robot request carried a sign parameter")
+	}
+	var msg wecomMessage
+	if err := json.Unmarshal(reqs[0].Body, &msg); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if msg.MsgType != "markdown" || msg.Markdown.Content != "### 告警" {
+		t.Errorf("message = %+v, want markdown with rendered body", msg)
+	}
+}
+
+func TestWeComMentionsInFollowUpText(t *testing.T) {
+	server := newCaptureServer(t)
+	target := Target{URL: server.URL, AtMobiles: []string{"13800000000"}, AtAll: true}
+	if err := NewWeComNotifier(nil).Send(context.Background(), target, Message{Title: "告警", Body: "正文"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	reqs := server.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %d, want markdown + text", len(reqs))
+	}
+	var msg wecomMessage
+	if err := json.Unmarshal(reqs[1].Body, &msg); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if msg.MsgType != "text" || msg.Text.Content != "告警" {
+		t.Errorf("mention message = %+v, want text with title", msg)
+	}
+	want := []string{"13800000000", "@all"}
+	if len(msg.Text.MentionedMobileList) != 2 || msg.Text.MentionedMobileList[0] != want[0] || msg.Text.MentionedMobileList[1] != want[1] {
+		t.Errorf("mentioned_mobile_list = %v, want %v", msg.Text.MentionedMobileList, want)
+	}
+}
+
+func TestWeComSkipsMentionWhenMarkdownFails(t *testing.T) {
+	server := newCaptureServer(t, stubResponse{http.StatusOK, `{"errcode":93000,"errmsg":"invalid webhook url"}`})
+	target := Target{URL: server.URL, AtAll: true}
+	if err := NewWeComNotifier(nil).Send(context.Background(), target, Message{}); !IsPermanent(err) {
+		t.Errorf("error = %v, want permanent robot error", err)
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
@@ -5,10 +5,9 @@ import (
 	"time"
 
 	"go.uber.org/zap"
-	"go
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"go.uber.org/zap"
+	gormLogger "gorm.io/gorm/logger"
 	"sentinel-opinion-monitor/internal/config"
 	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
 )
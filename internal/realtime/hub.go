@@ -0,0 +1,178 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// channelPrefix Redis Pub/Sub 频道前缀，主题 user:1 对应频道 sentinel:rt:user:1
+const channelPrefix = "sentinel:rt:"
+
+// Hub 实时消息分发中心
+// 消息通过 Redis Pub/Sub 广播到所有 Web 实例，每个实例只持有一个模式订阅，再按主题分发给本实例的订阅者。
+// Redis 未初始化时退化为进程内分发。
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[string]map[*Subscription]struct{}
+	cancel context.CancelFunc
+	closed bool
+}
+
+// Subscription 单个连接对一个或多个主题的订阅
+type Subscription struct {
+	hub        *Hub
+	topics     []string
+	ch         chan []byte
+	once       sync.Once
+	overflowed bool
+}
+
+var defaultHub *Hub
+
+// Init 创建全局分发中心并开始监听 Redis 频道
+func Init() *Hub {
+	hub := &Hub{subs: make(map[string]map[*Subscription]struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub.cancel = cancel
+	if client := redis.GetClient(); client != nil {
+		pubsub := client.PSubscribe(ctx, channelPrefix+"*")
+		go func() {
+			defer pubsub.Close()
+			ch := pubsub.Channel()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg, ok := <-ch:
+					if !ok {
+						return
+					}
+					hub.dispatch(strings.TrimPrefix(msg.Channel, channelPrefix), []byte(msg.Payload))
+				}
+			}
+		}()
+	}
+
+	defaultHub = hub
+	return hub
+}
+
+// Get 获取全局分发中心，未初始化时返回 nil
+func Get() *Hub {
+	return defaultHub
+}
+
+// Publish 向主题发布消息（JSON 编码）。Redis 可用时经 Redis 广播，否则只分发给本进程的订阅者
+func Publish(topic string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if client := redis.GetClient(); client != nil {
+		return client.Publish(redis.GetContext(), channelPrefix+topic, data).Err()
+	}
+	if defaultHub != nil {
+		defaultHub.dispatch(topic, data)
+	}
+	return nil
+}
+
+// Subscribe 订阅一个或多个主题，所有主题的消息写入同一个通道；buffer 为本地缓冲的消息数，
+// 消费过慢导致缓冲写满时订阅会被关闭
+func (h *Hub) Subscribe(buffer int, topics ...string) *Subscription {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	sub := &Subscription{hub: h, topics: topics, ch: make(chan []byte, buffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.ch)
+		return sub
+	}
+	for _, topic := range topics {
+		if h.subs[topic] == nil {
+			h.subs[topic] = make(map[*Subscription]struct{})
+		}
+		h.subs[topic][sub] = struct{}{}
+	}
+	return sub
+}
+
+// Close 停止监听并关闭所有订阅，用于服务关闭时让长连接尽快退出
+func (h *Hub) Close() {
+	h.cancel()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for topic, subs := range h.subs {
+		for sub := range subs {
+			sub.once.Do(func() { close(sub.ch) })
+		}
+		delete(h.subs, topic)
+	}
+}
+
+// dispatch 将消息非阻塞地分发给主题的本地订阅者，缓冲已满的订阅者会被断开
+func (h *Hub) dispatch(topic string, data []byte) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for sub := range h.subs[topic] {
+		select {
+		case sub.ch <- data:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		appLogger.Get().Warn("实时订阅消费过慢，已断开", zap.String("topic", topic))
+		sub.close(true)
+	}
+}
+
+// C 返回消息通道，订阅关闭后通道会被关闭
+func (s *Subscription) C() <-chan []byte {
+	return s.ch
+}
+
+// Overflowed 订阅是否因消费过慢被断开
+func (s *Subscription) Overflowed() bool {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+	return s.overflowed
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.close(false)
+}
+
+func (s *Subscription) close(overflowed bool) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.once.Do(func() {
+		s.overflowed = overflowed
+		for _, topic := range s.topics {
+			if subs, ok := s.hub.subs[topic]; ok {
+				delete(subs, s)
+				if len(subs) == 0 {
+					delete(s.hub.subs, topic)
+				}
+			}
+		}
+		close(s.ch)
+	})
+}
@@ -0,0 +1,254 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 纵向页面尺寸（pt）和页边距
+const (
+	pageWidth   = 595.28
+	pageHeight  = 841.89
+	pageMargin  = 48.0
+	contentLeft = pageMargin
+	contentW    = pageWidth - 2*pageMargin
+)
+
+// pdfColor RGB 颜色，分量取值 0~1
+type pdfColor struct {
+	R, G, B float64
+}
+
+var (
+	colorText    = pdfColor{0.13, 0.13, 0.13}
+	colorMuted   = pdfColor{0.45, 0.45, 0.45}
+	colorGrid    = pdfColor{0.85, 0.85, 0.85}
+	colorPrimary = pdfColor{0.27, 0.45, 0.77}
+	colorPos     = pdfColor{0.30, 0.65, 0.35}
+	colorNeu     = pdfColor{0.65, 0.65, 0.65}
+	colorNeg     = pdfColor{0.85, 0.30, 0.25}
+)
+
+// pdfDocument 最小化的 PDF 文档生成器
+//
+// 文字统一使用 Adobe 预定义的中文字体 STSong-Light（UniGB-UCS2-H 编码），
+// 阅读器自带该字体或有替代字体，PDF 中无需嵌入字体文件；只支持基本多文种平面的字符。
+// 坐标以页面左上角为原点，y 轴向下，写入时再转换为 PDF 坐标。
+type pdfDocument struct {
+	title string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64 // 当前页的排版位置（距页面顶部）
+}
+
+// newPDFDocument 创建文档并添加第一页
+func newPDFDocument(title string) *pdfDocument {
+	doc := &pdfDocument{title: title}
+	doc.addPage()
+	return doc
+}
+
+// addPage 添加新页面，排版位置回到页面顶部
+func (d *pdfDocument) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageMargin
+}
+
+// ensure 当前页剩余空间不足 height 时换页
+func (d *pdfDocument) ensure(height float64) {
+	if d.y+height > pageHeight-pageMargin {
+		d.addPage()
+	}
+}
+
+// text 在 (x, y) 处写一行文字，y 为文字基线距页面顶部的距离
+func (d *pdfDocument) text(x, y, size float64, c pdfColor, s string) {
+	fmt.Fprintf(d.page, "BT %.3f %.3f %.3f rg /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET\n",
+		c.R, c.G, c.B, size, x, pageHeight-y, encodeUCS2(s))
+}
+
+// textRight 文字右对齐到 x
+func (d *pdfDocument) textRight(x, y, size float64, c pdfColor, s string) {
+	d.text(x-textWidth(s, size), y, size, c, s)
+}
+
+// line 画线段
+func (d *pdfDocument) line(x1, y1, x2, y2, width float64, c pdfColor) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		c.R, c.G, c.B, width, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// polyline 画折线
+func (d *pdfDocument) polyline(xs, ys []float64, width float64, c pdfColor) {
+	if len(xs) < 2 {
+		return
+	}
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f RG %.2f w 1 J 1 j %.2f %.2f m", c.R, c.G, c.B, width, xs[0], pageHeight-ys[0])
+	for i := 1; i < len(xs); i++ {
+		fmt.Fprintf(d.page, " %.2f %.2f l", xs[i], pageHeight-ys[i])
+	}
+	d.page.WriteString(" S\n")
+}
+
+// rect 填充矩形，(x, y) 为左上角
+func (d *pdfDocument) rect(x, y, w, h float64, c pdfColor) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c.R, c.G, c.B, x, pageHeight-y-h, w, h)
+}
+
+// paragraph 在当前位置按宽度自动换行输出一段文字，最多 maxLines 行（0 表示不限），超出部分以省略号结尾
+func (d *pdfDocument) paragraph(x, width, size float64, c pdfColor, s string, maxLines int) {
+	lineHeight := size * 1.5
+	lines := wrapText(s, size, width)
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncateToWidth(lines[maxLines-1]+"……", size, width)
+	}
+	for _, l := range lines {
+		d.ensure(lineHeight)
+		d.y += lineHeight
+		d.text(x, d.y-size*0.35, size, c, l)
+	}
+}
+
+// bytes 输出 PDF 文件，每页底部加页码
+func (d *pdfDocument) bytes() ([]byte, error) {
+	for i, page := range d.pages {
+		d.page = page
+		d.textRight(pageWidth-pageMargin, pageHeight-pageMargin/2, 8, colorMuted, fmt.Sprintf("第 %d / %d 页", i+1, len(d.pages)))
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	writeObj := func(body string) int {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+		return len(offsets)
+	}
+	writeStream := func(data []byte) (int, error) {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(data); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", len(offsets), compressed.Len())
+		out.Write(compressed.Bytes())
+		out.WriteString("\nendstream\nendobj\n")
+		return len(offsets), nil
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 对象编号固定：1 目录，2 页面树，3~5 字体，6 文档信息，之后每页两个对象（页面和内容流）
+	pageIDs := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageIDs[i] = fmt.Sprintf("%d 0 R", 7+2*i)
+	}
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>",
+		strings.Join(pageIDs, " "), len(d.pages), pageWidth, pageHeight))
+	writeObj("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>")
+	writeObj("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> " +
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>")
+	writeObj("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	writeObj(fmt.Sprintf("<< /Title <FEFF%s> /Producer (sentinel-opinion-monitor) >>", encodeUCS2(d.title)))
+	for i, page := range d.pages {
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 8+2*i))
+		if _, err := writeStream(page.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// encodeUCS2 把文字编码为 UCS-2 大端序的十六进制串；基本多文种平面以外的字符和控制字符替换为问号或空格
+func encodeUCS2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			r = ' '
+		case r < 0x20 || r > 0xFFFF || utf16.IsSurrogate(r):
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// runeWidth 字符宽度（以字号为单位）：ASCII 为半角，其余为全角
+func runeWidth(r rune) float64 {
+	if r < 0x80 {
+		return 0.5
+	}
+	return 1
+}
+
+// textWidth 文字宽度（pt）
+func textWidth(s string, size float64) float64 {
+	w := 0.0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w * size
+}
+
+// wrapText 按宽度把文字折成多行，保留原有换行
+func wrapText(s string, size, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		var (
+			line strings.Builder
+			w    float64
+		)
+		for _, r := range para {
+			rw := runeWidth(r) * size
+			if w+rw > width && line.Len() > 0 {
+				lines = append(lines, line.String())
+				line.Reset()
+				w = 0
+			}
+			line.WriteRune(r)
+			w += rw
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// truncateToWidth 截断文字使其不超过宽度，截断时以省略号结尾
+func truncateToWidth(s string, size, width float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	ellipsis := "…"
+	limit := width - textWidth(ellipsis, size)
+	w := 0.0
+	for i, r := range runes {
+		w += runeWidth(r) * size
+		if w > limit {
+			return string(runes[:i]) + ellipsis
+		}
+	}
+	return s
+}
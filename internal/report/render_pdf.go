@@ -0,0 +1,288 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RenderPDF 把简报渲染为 A4 纵向的 PDF 文件，走势和分布图表以矢量图形绘制
+func RenderPDF(d *Data) ([]byte, error) {
+	doc := newPDFDocument(d.Title)
+
+	// 标题
+	doc.y += 22
+	doc.text(contentLeft, doc.y, 20, colorText, truncateToWidth(d.Title, 20, contentW))
+	doc.y += 20
+	doc.text(contentLeft, doc.y, 10, colorMuted, fmt.Sprintf("场景：%s    统计周期：%s    生成时间：%s",
+		d.ScenarioName, d.PeriodText(), d.GeneratedAt.Format("2006-01-02 15:04")))
+	doc.y += 10
+	doc.line(contentLeft, doc.y, contentLeft+contentW, doc.y, 0.8, colorPrimary)
+
+	renderers := []struct {
+		section string
+		title   string
+		render  func(*pdfDocument, *Data)
+	}{
+		{SectionSummary, "一、概览", renderSummaryPDF},
+		{SectionTrend, "二、声量走势", renderTrendPDF},
+		{SectionChannels, "三、渠道分布", renderChannelsPDF},
+		{SectionNegative, "四、重点负面舆情", renderNegativePDF},
+		{SectionTopics, "五、热门话题", renderTopicsPDF},
+	}
+	for _, r := range renderers {
+		if !d.Has(r.section) {
+			continue
+		}
+		sectionHeading(doc, r.title)
+		r.render(doc, d)
+	}
+	return doc.bytes()
+}
+
+// sectionHeading 章节标题，保证标题后至少还能放下几行内容
+func sectionHeading(doc *pdfDocument, title string) {
+	doc.ensure(100)
+	doc.y += 30
+	doc.rect(contentLeft, doc.y-13, 4, 16, colorPrimary)
+	doc.text(contentLeft+10, doc.y, 14, colorText, title)
+	doc.y += 10
+}
+
+// renderSummaryPDF 概览：关键指标卡片、情感分布条和热门关键词
+func renderSummaryPDF(doc *pdfDocument, d *Data) {
+	sum := &d.Summary
+	cards := []struct {
+		label string
+		value string
+		color pdfColor
+	}{
+		{"总声量", formatCount(sum.Total), colorText},
+		{"环比", sum.changeText(), colorText},
+		{"负面舆情", formatCount(sum.Negative), colorNeg},
+		{"负面占比", formatPercent(sum.sentimentRatio(sum.Negative)), colorNeg},
+	}
+	cardW := contentW / float64(len(cards))
+	doc.y += 8
+	for i, card := range cards {
+		x := contentLeft + float64(i)*cardW
+		doc.rect(x+2, doc.y, cardW-4, 54, pdfColor{0.95, 0.96, 0.98})
+		doc.text(x+12, doc.y+18, 9, colorMuted, card.label)
+		doc.text(x+12, doc.y+42, 18, card.color, card.value)
+	}
+	doc.y += 54
+
+	// 情感分布条
+	doc.y += 18
+	doc.text(contentLeft, doc.y, 10, colorText, "情感分布")
+	doc.y += 8
+	segments := []struct {
+		label string
+		count int64
+		color pdfColor
+	}{
+		{"正面", sum.Positive, colorPos},
+		{"中性", sum.Neutral, colorNeu},
+		{"负面", sum.Negative, colorNeg},
+	}
+	x := contentLeft
+	if sum.Total > 0 {
+		for _, seg := range segments {
+			w := contentW * sum.sentimentRatio(seg.count)
+			doc.rect(x, doc.y, w, 12, seg.color)
+			x += w
+		}
+	} else {
+		doc.rect(contentLeft, doc.y, contentW, 12, colorGrid)
+	}
+	doc.y += 26
+	x = contentLeft
+	for _, seg := range segments {
+		doc.rect(x, doc.y-8, 8, 8, seg.color)
+		label := fmt.Sprintf("%s %s（%s）", seg.label, formatCount(seg.count), formatPercent(sum.sentimentRatio(seg.count)))
+		doc.text(x+12, doc.y, 9, colorText, label)
+		x += textWidth(label, 9) + 30
+	}
+
+	if len(sum.TopKeywords) > 0 {
+		doc.y += 22
+		doc.text(contentLeft, doc.y, 10, colorText, "热门关键词")
+		words := make([]string, len(sum.TopKeywords))
+		for i, item := range sum.TopKeywords {
+			words[i] = fmt.Sprintf("%s(%d)", item.Name, item.Count)
+		}
+		doc.y += 4
+		doc.paragraph(contentLeft, contentW, 9, colorText, strings.Join(words, "  "), 3)
+	}
+}
+
+// renderTrendPDF 声量走势折线图
+func renderTrendPDF(doc *pdfDocument, d *Data) {
+	const chartH = 180.0
+	doc.ensure(chartH + 30)
+	doc.y += 16
+	top := doc.y
+
+	if len(d.Trend) == 0 {
+		doc.y += 14
+		doc.text(contentLeft, doc.y, 10, colorMuted, "统计周期内没有数据")
+		return
+	}
+
+	var max int64
+	for _, p := range d.Trend {
+		if p.Count > max {
+			max = p.Count
+		}
+	}
+	axisMax, step := niceScale(max, 4)
+
+	// 纵轴刻度和网格线
+	left := contentLeft + 40
+	width := contentW - 40
+	for i := 0; i <= 4; i++ {
+		v := step * int64(i)
+		y := top + chartH - chartH*float64(v)/float64(axisMax)
+		doc.line(left, y, left+width, y, 0.5, colorGrid)
+		doc.textRight(left-6, y+3, 8, colorMuted, formatCount(v))
+	}
+
+	// 折线
+	n := len(d.Trend)
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i, p := range d.Trend {
+		if n == 1 {
+			xs[i] = left + width/2
+		} else {
+			xs[i] = left + width*float64(i)/float64(n-1)
+		}
+		ys[i] = top + chartH - chartH*float64(p.Count)/float64(axisMax)
+	}
+	doc.polyline(xs, ys, 1.5, colorPrimary)
+	if n <= 31 {
+		for i := range xs {
+			doc.rect(xs[i]-1.5, ys[i]-1.5, 3, 3, colorPrimary)
+		}
+	}
+
+	// 横轴标签，最多 7 个
+	labels := 7
+	if n < labels {
+		labels = n
+	}
+	for i := 0; i < labels; i++ {
+		idx := 0
+		if labels > 1 {
+			idx = i * (n - 1) / (labels - 1)
+		}
+		label := d.pointLabel(d.Trend[idx].Time)
+		x := xs[idx] - textWidth(label, 8)/2
+		if x < left {
+			x = left
+		}
+		if x+textWidth(label, 8) > left+width {
+			x = left + width - textWidth(label, 8)
+		}
+		doc.text(x, top+chartH+14, 8, colorMuted, label)
+	}
+	doc.y = top + chartH + 20
+}
+
+// renderChannelsPDF 渠道分布横向条形图，最多 10 个渠道
+func renderChannelsPDF(doc *pdfDocument, d *Data) {
+	doc.y += 8
+	if len(d.Channels) == 0 {
+		doc.y += 14
+		doc.text(contentLeft, doc.y, 10, colorMuted, "统计周期内没有数据")
+		return
+	}
+
+	items := d.Channels
+	if len(items) > 10 {
+		items = items[:10]
+	}
+	var max int64
+	for _, item := range items {
+		if item.Count > max {
+			max = item.Count
+		}
+	}
+
+	const labelW, valueW, rowH = 90.0, 100.0, 20.0
+	barMax := contentW - labelW - valueW
+	for _, item := range items {
+		doc.ensure(rowH)
+		doc.y += rowH
+		doc.text(contentLeft, doc.y-5, 9, colorText, truncateToWidth(item.Name, 9, labelW-8))
+		w := 0.0
+		if max > 0 {
+			w = barMax * float64(item.Count) / float64(max)
+		}
+		doc.rect(contentLeft+labelW, doc.y-14, w, 12, colorPrimary)
+		doc.text(contentLeft+labelW+w+6, doc.y-5, 9, colorMuted,
+			fmt.Sprintf("%s（%s）", formatCount(item.Count), formatPercent(item.Ratio)))
+	}
+}
+
+// renderNegativePDF 互动量最高的负面舆情
+func renderNegativePDF(doc *pdfDocument, d *Data) {
+	if len(d.Negatives) == 0 {
+		doc.y += 22
+		doc.text(contentLeft, doc.y, 10, colorMuted, "统计周期内没有负面舆情")
+		return
+	}
+	for i, o := range d.Negatives {
+		doc.ensure(60)
+		doc.y += 20
+		meta := fmt.Sprintf("%d. %s  %s", i+1, o.Time.Format("2006-01-02 15:04"), o.Source)
+		if o.Author != "" {
+			meta += "  @" + o.Author
+		}
+		meta += fmt.Sprintf("  情感 %.2f  互动 %s", o.Score, formatCount(o.Engagement))
+		doc.text(contentLeft, doc.y, 9, colorMuted, truncateToWidth(meta, 9, contentW))
+		doc.y += 2
+		doc.paragraph(contentLeft+12, contentW-12, 10, colorText, o.Content, 4)
+	}
+}
+
+// renderTopicsPDF 热门话题
+func renderTopicsPDF(doc *pdfDocument, d *Data) {
+	if len(d.Topics) == 0 {
+		doc.y += 22
+		doc.text(contentLeft, doc.y, 10, colorMuted, "统计周期内没有话题")
+		return
+	}
+	for i, t := range d.Topics {
+		doc.ensure(44)
+		doc.y += 20
+		title := fmt.Sprintf("%d. %s", i+1, t.Label)
+		stats := fmt.Sprintf("舆情 %d 条  平均情感 %.2f", t.Size, t.AvgSentiment)
+		doc.text(contentLeft, doc.y, 10, colorText, truncateToWidth(title, 10, contentW-textWidth(stats, 9)-12))
+		doc.textRight(contentLeft+contentW, doc.y, 9, colorMuted, stats)
+		if len(t.Keywords) > 0 {
+			doc.paragraph(contentLeft+12, contentW-12, 9, colorMuted, "关键词："+strings.Join(t.Keywords, "、"), 2)
+		}
+	}
+}
+
+// niceScale 计算纵轴上限和刻度间隔，使刻度为 1、2、5 乘以 10 的整数次幂
+func niceScale(max int64, ticks int) (int64, int64) {
+	if max <= 0 {
+		return int64(ticks), 1
+	}
+	raw := float64(max) / float64(ticks)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := magnitude
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*magnitude >= raw {
+			step = m * magnitude
+			break
+		}
+	}
+	s := int64(math.Ceil(step))
+	if s < 1 {
+		s = 1
+	}
+	return s * int64(ticks), s
+}
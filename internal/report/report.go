@@ -0,0 +1,173 @@
+// Package report 把场景舆情简报渲染为 XLSX 和 PDF 文件，图表在服务端用纯 Go 绘制，不依赖外部程序
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 简报章节
+const (
+	SectionSummary  = "summary"  // 概览：声量、情感分布、环比和热门关键词
+	SectionTrend    = "trend"    // 声量走势图
+	SectionNegative = "negative" // 互动量最高的负面舆情
+	SectionTopics   = "topics"   // 热门话题
+	SectionChannels = "channels" // 渠道分布
+)
+
+// AllSections 全部章节，按渲染顺序
+var AllSections = []string{SectionSummary, SectionTrend, SectionChannels, SectionNegative, SectionTopics}
+
+// IsValidSection 判断章节是否有效
+func IsValidSection(section string) bool {
+	for _, s := range AllSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Point 走势中的一个时间桶
+type Point struct {
+	Time  time.Time
+	Count int64
+}
+
+// Item 分布中的一项
+type Item struct {
+	Name  string
+	Count int64
+	Ratio float64
+}
+
+// Summary 概览数据
+type Summary struct {
+	Total         int64
+	Positive      int64
+	Neutral       int64
+	Negative      int64
+	PreviousTotal int64
+	ChangeRate    *float64 // 上一周期为 0 时为 nil
+	TopKeywords   []Item
+}
+
+// Opinion 简报中列出的舆情
+type Opinion struct {
+	Time       time.Time
+	Source     string
+	Author     string
+	Content    string
+	Score      float64
+	Engagement int64 // 点赞、评论、转发之和
+}
+
+// Topic 简报中列出的话题
+type Topic struct {
+	Label        string
+	Size         int
+	AvgSentiment float64
+	Keywords     []string
+}
+
+// Data 一份简报的全部内容，未包含在 Sections 中的章节不渲染
+type Data struct {
+	Title        string
+	ScenarioName string
+	PeriodStart  time.Time
+	PeriodEnd    time.Time // 不含
+	GeneratedAt  time.Time
+	Sections     []string
+
+	Summary    Summary
+	Trend      []Point
+	TrendDaily bool // 走势按天分桶，否则按小时
+	Channels   []Item
+	Negatives  []Opinion
+	Topics     []Topic
+}
+
+// Has 判断是否包含章节
+func (d *Data) Has(section string) bool {
+	for _, s := range d.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// PeriodText 统计周期的展示文本，结束时间按含当天展示
+func (d *Data) PeriodText() string {
+	const layout = "2006-01-02 15:04"
+	start, end := d.PeriodStart, d.PeriodEnd
+	if isMidnight(start) && isMidnight(end) {
+		last := end.AddDate(0, 0, -1)
+		if last.Equal(start) {
+			return start.Format("2006-01-02")
+		}
+		return start.Format("2006-01-02") + " ~ " + last.Format("2006-01-02")
+	}
+	return start.Format(layout) + " ~ " + end.Format(layout)
+}
+
+// pointLabel 走势时间桶的坐标轴文本
+func (d *Data) pointLabel(t time.Time) string {
+	if d.TrendDaily {
+		return t.Format("01-02")
+	}
+	return t.Format("01-02 15:04")
+}
+
+// sentimentRatio 情感占总声量的比例
+func (s *Summary) sentimentRatio(count int64) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(count) / float64(s.Total)
+}
+
+// changeText 环比的展示文本
+func (s *Summary) changeText() string {
+	if s.ChangeRate == nil {
+		return "-"
+	}
+	return formatSignedPercent(*s.ChangeRate)
+}
+
+// formatPercent 把比例格式化为百分比文本
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+// formatSignedPercent 把变化率格式化为带正负号的百分比文本
+func formatSignedPercent(rate float64) string {
+	return fmt.Sprintf("%+.1f%%", rate*100)
+}
+
+// formatCount 为整数加千分位分隔符
+func formatCount(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if negative {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// isMidnight 判断是否为本地时区的零点
+func isMidnight(t time.Time) bool {
+	t = t.In(time.Local)
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
@@ -0,0 +1,303 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 工作表名称
+const (
+	sheetSummary  = "概览"
+	sheetTrend    = "声量走势"
+	sheetChannels = "渠道分布"
+	sheetNegative = "负面舆情"
+	sheetTopics   = "热门话题"
+)
+
+// xlsxStyles 工作簿中使用的单元格样式
+type xlsxStyles struct {
+	title   int
+	header  int
+	label   int
+	percent int
+	wrap    int
+}
+
+// RenderXLSX 把简报渲染为 XLSX 工作簿：概览一个工作表，其余每个章节一个工作表，走势和渠道分布附带原生图表
+func RenderXLSX(d *Data) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// 默认工作表作为概览
+	if err := f.SetSheetName("Sheet1", sheetSummary); err != nil {
+		return nil, err
+	}
+	if err := writeSummarySheet(f, d, styles); err != nil {
+		return nil, err
+	}
+
+	writers := []struct {
+		section string
+		sheet   string
+		write   func(*excelize.File, *Data, *xlsxStyles) error
+	}{
+		{SectionTrend, sheetTrend, writeTrendSheet},
+		{SectionChannels, sheetChannels, writeChannelsSheet},
+		{SectionNegative, sheetNegative, writeNegativeSheet},
+		{SectionTopics, sheetTopics, writeTopicsSheet},
+	}
+	for _, w := range writers {
+		if !d.Has(w.section) {
+			continue
+		}
+		if _, err := f.NewSheet(w.sheet); err != nil {
+			return nil, err
+		}
+		if err := w.write(f, d, styles); err != nil {
+			return nil, fmt.Errorf("生成工作表「%s」失败: %w", w.sheet, err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newXLSXStyles 创建单元格样式
+func newXLSXStyles(f *excelize.File) (*xlsxStyles, error) {
+	var (
+		s   xlsxStyles
+		err error
+	)
+	if s.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}}); err != nil {
+		return nil, err
+	}
+	if s.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+	}); err != nil {
+		return nil, err
+	}
+	if s.label, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return nil, err
+	}
+	if s.percent, err = f.NewStyle(&excelize.Style{NumFmt: 10}); err != nil { // 0.00%
+		return nil, err
+	}
+	if s.wrap, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"}}); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// writeSummarySheet 写入概览：简报信息，以及概览章节的声量、情感和热门关键词
+func writeSummarySheet(f *excelize.File, d *Data, s *xlsxStyles) error {
+	sheet := sheetSummary
+	rows := [][]interface{}{
+		{d.Title},
+		{},
+		{"场景", d.ScenarioName},
+		{"统计周期", d.PeriodText()},
+		{"生成时间", d.GeneratedAt.Format("2006-01-02 15:04:05")},
+	}
+	if d.Has(SectionSummary) {
+		sum := &d.Summary
+		rows = append(rows,
+			[]interface{}{},
+			[]interface{}{"总声量", sum.Total},
+			[]interface{}{"上期声量", sum.PreviousTotal},
+			[]interface{}{"环比", sum.changeText()},
+			[]interface{}{},
+			[]interface{}{"情感", "舆情数", "占比"},
+			[]interface{}{"正面", sum.Positive, sum.sentimentRatio(sum.Positive)},
+			[]interface{}{"中性", sum.Neutral, sum.sentimentRatio(sum.Neutral)},
+			[]interface{}{"负面", sum.Negative, sum.sentimentRatio(sum.Negative)},
+		)
+	}
+	for i, row := range rows {
+		if err := f.SetSheetRow(sheet, cellName(1, i+1), &row); err != nil {
+			return err
+		}
+	}
+	if err := f.SetCellStyle(sheet, "A1", "A1", s.title); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A3", cellName(1, len(rows)), s.label); err != nil {
+		return err
+	}
+
+	if d.Has(SectionSummary) {
+		// 情感表头和占比列
+		sentimentHeader := len(rows) - 3
+		if err := f.SetCellStyle(sheet, cellName(1, sentimentHeader), cellName(3, sentimentHeader), s.header); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, cellName(3, sentimentHeader+1), cellName(3, len(rows)), s.percent); err != nil {
+			return err
+		}
+
+		if len(d.Summary.TopKeywords) > 0 {
+			start := len(rows) + 2
+			if err := writeTable(f, sheet, start, []string{"热门关键词", "舆情数", "占比"}, s, func(add func(...interface{})) {
+				for _, item := range d.Summary.TopKeywords {
+					add(item.Name, item.Count, item.Ratio)
+				}
+			}); err != nil {
+				return err
+			}
+			if err := f.SetCellStyle(sheet, cellName(3, start+1), cellName(3, start+len(d.Summary.TopKeywords)), s.percent); err != nil {
+				return err
+			}
+		}
+	}
+	return f.SetColWidth(sheet, "A", "C", 18)
+}
+
+// writeTrendSheet 写入声量走势和折线图
+func writeTrendSheet(f *excelize.File, d *Data, s *xlsxStyles) error {
+	sheet := sheetTrend
+	if err := writeTable(f, sheet, 1, []string{"时间", "声量"}, s, func(add func(...interface{})) {
+		for _, p := range d.Trend {
+			add(d.pointLabel(p.Time), p.Count)
+		}
+	}); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", "A", 18); err != nil {
+		return err
+	}
+	if len(d.Trend) == 0 {
+		return nil
+	}
+
+	last := len(d.Trend) + 1
+	return f.AddChart(sheet, "D2", &excelize.Chart{
+		Type: excelize.Line,
+		Series: []excelize.ChartSeries{{
+			Name:       fmt.Sprintf("'%s'!$B$1", sheet),
+			Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", sheet, last),
+			Values:     fmt.Sprintf("'%s'!$B$2:$B$%d", sheet, last),
+		}},
+		Title:     []excelize.RichTextRun{{Text: "声量走势"}},
+		Legend:    excelize.ChartLegend{Position: "none"},
+		Dimension: excelize.ChartDimension{Width: 720, Height: 320},
+	})
+}
+
+// writeChannelsSheet 写入渠道分布和柱状图
+func writeChannelsSheet(f *excelize.File, d *Data, s *xlsxStyles) error {
+	sheet := sheetChannels
+	if err := writeTable(f, sheet, 1, []string{"渠道", "舆情数", "占比"}, s, func(add func(...interface{})) {
+		for _, item := range d.Channels {
+			add(item.Name, item.Count, item.Ratio)
+		}
+	}); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", "A", 18); err != nil {
+		return err
+	}
+	if len(d.Channels) == 0 {
+		return nil
+	}
+
+	last := len(d.Channels) + 1
+	if err := f.SetCellStyle(sheet, "C2", cellName(3, last), s.percent); err != nil {
+		return err
+	}
+	return f.AddChart(sheet, "E2", &excelize.Chart{
+		Type: excelize.Bar,
+		Series: []excelize.ChartSeries{{
+			Name:       fmt.Sprintf("'%s'!$B$1", sheet),
+			Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", sheet, last),
+			Values:     fmt.Sprintf("'%s'!$B$2:$B$%d", sheet, last),
+		}},
+		Title:     []excelize.RichTextRun{{Text: "渠道分布"}},
+		Legend:    excelize.ChartLegend{Position: "none"},
+		Dimension: excelize.ChartDimension{Width: 560, Height: 320},
+	})
+}
+
+// writeNegativeSheet 写入负面舆情列表
+func writeNegativeSheet(f *excelize.File, d *Data, s *xlsxStyles) error {
+	sheet := sheetNegative
+	if err := writeTable(f, sheet, 1, []string{"时间", "来源", "作者", "情感得分", "互动量", "内容"}, s, func(add func(...interface{})) {
+		for _, o := range d.Negatives {
+			add(o.Time.Format("2006-01-02 15:04"), o.Source, o.Author, o.Score, o.Engagement, o.Content)
+		}
+	}); err != nil {
+		return err
+	}
+	if len(d.Negatives) > 0 {
+		if err := f.SetCellStyle(sheet, "F2", cellName(6, len(d.Negatives)+1), s.wrap); err != nil {
+			return err
+		}
+	}
+	widths := map[string]float64{"A": 17, "B": 12, "C": 14, "D": 10, "E": 10, "F": 80}
+	for col, width := range widths {
+		if err := f.SetColWidth(sheet, col, col, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTopicsSheet 写入热门话题列表
+func writeTopicsSheet(f *excelize.File, d *Data, s *xlsxStyles) error {
+	sheet := sheetTopics
+	if err := writeTable(f, sheet, 1, []string{"话题", "舆情数", "平均情感", "关键词"}, s, func(add func(...interface{})) {
+		for _, t := range d.Topics {
+			add(t.Label, t.Size, t.AvgSentiment, strings.Join(t.Keywords, "、"))
+		}
+	}); err != nil {
+		return err
+	}
+	widths := map[string]float64{"A": 30, "B": 10, "C": 10, "D": 50}
+	for col, width := range widths {
+		if err := f.SetColWidth(sheet, col, col, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable 从第 startRow 行写入带表头的表格，fill 通过 add 逐行追加数据
+func writeTable(f *excelize.File, sheet string, startRow int, header []string, s *xlsxStyles, fill func(add func(...interface{}))) error {
+	headerRow := make([]interface{}, len(header))
+	for i, h := range header {
+		headerRow[i] = h
+	}
+	if err := f.SetSheetRow(sheet, cellName(1, startRow), &headerRow); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, cellName(1, startRow), cellName(len(header), startRow), s.header); err != nil {
+		return err
+	}
+
+	row := startRow
+	var err error
+	fill(func(values ...interface{}) {
+		if err != nil {
+			return
+		}
+		row++
+		err = f.SetSheetRow(sheet, cellName(1, row), &values)
+	})
+	return err
+}
+
+// cellName 把列号和行号（均从 1 开始）转换为单元格名称
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
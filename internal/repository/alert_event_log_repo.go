@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// AlertEventLogRepository 告警时间线数据访问接口
+type AlertEventLogRepository interface {
+	Create(log *model.AlertEventLog) error
+	GetByEventID(eventID uint64) ([]*model.AlertEventLog, error)
+}
+
+type alertEventLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertEventLogRepository 创建告警时间线数据访问实例
+func NewAlertEventLogRepository() AlertEventLogRepository {
+	return &alertEventLogRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 写入时间线记录
+func (r *alertEventLogRepository) Create(log *model.AlertEventLog) error {
+	return r.db.Create(log).Error
+}
+
+// GetByEventID 获取事件的时间线，按时间先后排序
+func (r *alertEventLogRepository) GetByEventID(eventID uint64) ([]*model.AlertEventLog, error) {
+	var logs []*model.AlertEventLog
+	err := r.db.Where("event_id = ?", eventID).Order("id ASC").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
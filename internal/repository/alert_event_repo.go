@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// AlertEventFilter 告警事件查询条件，零值字段表示不限
+type AlertEventFilter struct {
+	RuleID     uint64
+	ScenarioID uint64
+	Status     string
+	Severity   string
+}
+
+// AlertEventRepository 告警事件数据访问接口
+type AlertEventRepository interface {
+	Create(event *model.AlertEvent) error
+	GetByID(id uint64) (*model.AlertEvent, error)
+	List(filter AlertEventFilter, page, pageSize int) ([]*model.AlertEvent, int64, error)
+	GetOpenByRule(ruleID uint64) ([]*model.AlertEvent, error)
+	GetLatestByDedupKey(ruleID uint64, dedupKey string) (*model.AlertEvent, error)
+	GetByStatus(status string) ([]*model.AlertEvent, error)
+	Update(event *model.AlertEvent) error
+	UpdateObservation(event *model.AlertEvent) error
+	Acknowledge(id, userID uint64, at time.Time) (bool, error)
+	Resolve(id, userID uint64, at time.Time) (bool, error)
+	AdvanceEscalation(id uint64, fromLevel, toLevel int, at time.Time) (bool, error)
+}
+
+type alertEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertEventRepository 创建告警事件数据访问实例
+func NewAlertEventRepository() AlertEventRepository {
+	return &alertEventRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建告警事件
+func (r *alertEventRepository) Create(event *model.AlertEvent) error {
+	return r.db.Create(event).Error
+}
+
+// GetByID 根据 ID 获取告警事件
+func (r *alertEventRepository) GetByID(id uint64) (*model.AlertEvent, error) {
+	var event model.AlertEvent
+	err := r.db.First(&event, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// List 按条件分页获取告警事件，最新的在前
+func (r *alertEventRepository) List(filter AlertEventFilter, page, pageSize int) ([]*model.AlertEvent, int64, error) {
+	var events []*model.AlertEvent
+	var total int64
+
+	query := r.db.Model(&model.AlertEvent{})
+	if filter.RuleID > 0 {
+		query = query.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.ScenarioID > 0 {
+		query = query.Where("scenario_id = ?", filter.ScenarioID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetOpenByRule 获取规则下所有未恢复的事件
+func (r *alertEventRepository) GetOpenByRule(ruleID uint64) ([]*model.AlertEvent, error) {
+	var events []*model.AlertEvent
+	err := r.db.Where("rule_id = ? AND status <> ?", ruleID, model.AlertStatusResolved).Order("id ASC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetLatestByDedupKey 获取规则下指定去重键的最新事件
+func (r *alertEventRepository) GetLatestByDedupKey(ruleID uint64, dedupKey string) (*model.AlertEvent, error) {
+	var event model.AlertEvent
+	err := r.db.Where("rule_id = ? AND dedup_key = ?", ruleID, dedupKey).Order("id DESC").First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Update 更新告警事件
+func (r *alertEventRepository) Update(event *model.AlertEvent) error {
+	return r.db.Save(event).Error
+}
+
+// GetByStatus 获取指定状态的所有事件
+func (r *alertEventRepository) GetByStatus(status string) ([]*model.AlertEvent, error) {
+	var events []*model.AlertEvent
+	err := r.db.Where("status = ?", status).Order("id ASC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// UpdateObservation 只更新评估得到的指标字段，不覆盖并发写入的状态、认领和升级字段
+func (r *alertEventRepository) UpdateObservation(event *model.AlertEvent) error {
+	return r.db.Model(event).
+		Select("title", "value", "opinion_ids", "fire_count", "last_seen_at").
+		Updates(event).Error
+}
+
+// Acknowledge 认领告警，仅 firing 状态可认领，返回是否认领成功
+func (r *alertEventRepository) Acknowledge(id, userID uint64, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AlertEvent{}).
+		Where("id = ? AND status = ?", id, model.AlertStatusFiring).
+		Updates(map[string]interface{}{
+			"status":          model.AlertStatusAcknowledged,
+			"acknowledged_at": at,
+			"acknowledged_by": userID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Resolve 恢复告警（userID 为 0 表示自动恢复），已恢复的事件不会重复恢复，返回是否恢复成功
+func (r *alertEventRepository) Resolve(id, userID uint64, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AlertEvent{}).
+		Where("id = ? AND status <> ?", id, model.AlertStatusResolved).
+		Updates(map[string]interface{}{
+			"status":      model.AlertStatusResolved,
+			"resolved_at": at,
+			"resolved_by": userID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AdvanceEscalation 将未认领事件的升级层级从 fromLevel 推进到 toLevel，
+// 以条件更新抢占，避免并发任务重复通知，返回是否推进成功
+func (r *alertEventRepository) AdvanceEscalation(id uint64, fromLevel, toLevel int, at time.Time) (bool, error) {
+	result := r.db.Model(&model.AlertEvent{}).
+		Where("id = ? AND status = ? AND escalation_level = ?", id, model.AlertStatusFiring, fromLevel).
+		Updates(map[string]interface{}{
+			"escalation_level": toLevel,
+			"escalated_at":     at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
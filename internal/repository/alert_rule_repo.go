@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// AlertRuleRepository 告警规则数据访问接口
+type AlertRuleRepository interface {
+	Create(rule *model.AlertRule) error
+	GetByID(id uint64) (*model.AlertRule, error)
+	List(scenarioID uint64) ([]*model.AlertRule, error)
+	GetEnabled() ([]*model.AlertRule, error)
+	Update(rule *model.AlertRule) error
+	UpdateLastEvaluatedAt(id uint64, t time.Time) error
+	Delete(id uint64) error
+}
+
+type alertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertRuleRepository 创建告警规则数据访问实例
+func NewAlertRuleRepository() AlertRuleRepository {
+	return &alertRuleRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建告警规则
+func (r *alertRuleRepository) Create(rule *model.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetByID 根据 ID 获取告警规则
+func (r *alertRuleRepository) GetByID(id uint64) (*model.AlertRule, error) {
+	var rule model.AlertRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List 获取告警规则列表（scenarioID 为 0 表示全部）
+func (r *alertRuleRepository) List(scenarioID uint64) ([]*model.AlertRule, error) {
+	var rules []*model.AlertRule
+	query := r.db.Model(&model.AlertRule{})
+	if scenarioID > 0 {
+		query = query.Where("scenario_id = ?", scenarioID)
+	}
+	err := query.Order("id ASC").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetEnabled 获取启用的告警规则（所属场景也需为启用状态）
+func (r *alertRuleRepository) GetEnabled() ([]*model.AlertRule, error) {
+	var rules []*model.AlertRule
+	err := r.db.Joins("JOIN scenarios ON scenarios.id = alert_rules.scenario_id AND scenarios.status = 1").
+		Where("alert_rules.status = ?", 1).
+		Order("alert_rules.id ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update 更新告警规则
+func (r *alertRuleRepository) Update(rule *model.AlertRule) error {
+	return r.db.Save(rule).Error
+}
+
+// UpdateLastEvaluatedAt 更新最近评估时间
+func (r *alertRuleRepository) UpdateLastEvaluatedAt(id uint64, t time.Time) error {
+	return r.db.Model(&model.AlertRule{}).Where("id = ?", id).Update("last_evaluated_at", t).Error
+}
+
+// Delete 删除告警规则
+func (r *alertRuleRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.AlertRule{}, id).Error
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 作者列表排序方式
+const (
+	AuthorSortInfluence = "influence" // 按影响力得分倒序
+	AuthorSortFollowers = "followers" // 按粉丝数倒序
+	AuthorSortOpinions  = "opinions"  // 按舆情数倒序
+	AuthorSortLastSeen  = "last_seen" // 按最近出现时间倒序
+)
+
+// AuthorFilter 作者查询条件
+type AuthorFilter struct {
+	Channel      string  // 渠道
+	Keyword      string  // 账号名称前缀或账号ID
+	Verified     *bool   // 是否认证账号
+	MinInfluence float64 // 影响力得分下限（含）
+	Sort         string  // 排序方式，默认按影响力得分
+}
+
+// AuthorKey 作者的唯一标识
+type AuthorKey struct {
+	Channel    string
+	ExternalID string
+}
+
+// AuthorEngagement 作者在统计窗口内的舆情数和平均互动量
+type AuthorEngagement struct {
+	AuthorID      uint64
+	Opinions      int64
+	AvgEngagement float64
+}
+
+// AuthorRepository 作者及场景关注作者的数据访问接口
+type AuthorRepository interface {
+	GetByID(id uint64) (*model.Author, error)
+	GetByKeys(keys []AuthorKey) ([]*model.Author, error)
+	Touch(authors []*model.Author) error
+	List(filter AuthorFilter, page, pageSize int) ([]*model.Author, int64, error)
+	ListAfterID(afterID uint64, limit int) ([]*model.Author, error)
+	GetEngagement(since time.Time) ([]*AuthorEngagement, error)
+	UpdateInfluence(id uint64, avgEngagement, score float64) error
+	GetUnlinkedOpinions(afterID uint64, limit int) ([]*model.Opinion, error)
+	LinkOpinions(authorID uint64, opinionIDs []uint64) error
+
+	GetWatches(scenarioID uint64) ([]*model.ScenarioAuthorWatch, error)
+	GetWatchedAuthorIDs(scenarioID uint64) ([]uint64, error)
+	AddWatch(watch *model.ScenarioAuthorWatch) error
+	RemoveWatch(scenarioID, authorID uint64) (int64, error)
+}
+
+type authorRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorRepository 创建作者数据访问实例
+func NewAuthorRepository() AuthorRepository {
+	return &authorRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// GetByID 根据 ID 获取作者
+func (r *authorRepository) GetByID(id uint64) (*model.Author, error) {
+	var author model.Author
+	err := r.db.First(&author, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// GetByKeys 按渠道和账号ID批量获取作者
+func (r *authorRepository) GetByKeys(keys []AuthorKey) ([]*model.Author, error) {
+	var authors []*model.Author
+	if len(keys) == 0 {
+		return authors, nil
+	}
+	tuples := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		tuples[i] = []interface{}{key.Channel, key.ExternalID}
+	}
+	err := r.db.Where("(channel, external_id) IN ?", tuples).Find(&authors).Error
+	if err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// Touch 批量写入作者：不存在时创建；已存在时累加舆情数，扩展首次/最近出现时间，
+// 并用非空的账号名称、非零的粉丝数更新账号信息（认证状态只会由未认证变为已认证）
+func (r *authorRepository) Touch(authors []*model.Author) error {
+	if len(authors) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"name":           gorm.Expr("IF(VALUES(name) <> '', VALUES(name), name)"),
+			"follower_count": gorm.Expr("IF(VALUES(follower_count) > 0, VALUES(follower_count), follower_count)"),
+			"verified":       gorm.Expr("verified OR VALUES(verified)"),
+			"opinion_count":  gorm.Expr("opinion_count + VALUES(opinion_count)"),
+			"first_seen_at":  gorm.Expr("LEAST(first_seen_at, VALUES(first_seen_at))"),
+			"last_seen_at":   gorm.Expr("GREATEST(last_seen_at, VALUES(last_seen_at))"),
+			"updated_at":     gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).CreateInBatches(authors, 200).Error
+}
+
+// List 按条件分页获取作者
+func (r *authorRepository) List(filter AuthorFilter, page, pageSize int) ([]*model.Author, int64, error) {
+	var authors []*model.Author
+	var total int64
+
+	query := r.db.Model(&model.Author{})
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ? OR external_id = ?", filter.Keyword+"%", filter.Keyword)
+	}
+	if filter.Verified != nil {
+		query = query.Where("verified = ?", *filter.Verified)
+	}
+	if filter.MinInfluence > 0 {
+		query = query.Where("influence_score >= ?", filter.MinInfluence)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "influence_score DESC, id DESC"
+	switch filter.Sort {
+	case AuthorSortFollowers:
+		order = "follower_count DESC, id DESC"
+	case AuthorSortOpinions:
+		order = "opinion_count DESC, id DESC"
+	case AuthorSortLastSeen:
+		order = "last_seen_at DESC, id DESC"
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Order(order).Offset(offset).Limit(pageSize).Find(&authors).Error; err != nil {
+		return nil, 0, err
+	}
+	return authors, total, nil
+}
+
+// ListAfterID 按 ID 顺序获取 afterID 之后的作者，用于分批遍历
+func (r *authorRepository) ListAfterID(afterID uint64, limit int) ([]*model.Author, error) {
+	var authors []*model.Author
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&authors).Error
+	if err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// GetEngagement 统计各作者 since 之后入库的舆情数和平均互动量（点赞 + 评论×2 + 转发×3）
+func (r *authorRepository) GetEngagement(since time.Time) ([]*AuthorEngagement, error) {
+	var stats []*AuthorEngagement
+	err := r.db.Model(&model.Opinion{}).
+		Select("author_id, COUNT(*) AS opinions, AVG(like_count + comment_count * 2 + share_count * 3) AS avg_engagement").
+		Where("author_id > 0 AND created_at >= ?", since).
+		Group("author_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// UpdateInfluence 更新作者的平均互动量和影响力得分
+func (r *authorRepository) UpdateInfluence(id uint64, avgEngagement, score float64) error {
+	return r.db.Model(&model.Author{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"avg_engagement":  avgEngagement,
+		"influence_score": score,
+	}).Error
+}
+
+// GetUnlinkedOpinions 按 ID 顺序获取 afterID 之后有作者名称但未关联作者的舆情（只包含 ID、来源、作者和入库时间）
+func (r *authorRepository) GetUnlinkedOpinions(afterID uint64, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := r.db.Select("id, source, author, created_at").
+		Where("id > ? AND author_id = 0 AND author <> ''", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// LinkOpinions 把舆情关联到作者（不更新舆情的更新时间）
+func (r *authorRepository) LinkOpinions(authorID uint64, opinionIDs []uint64) error {
+	if len(opinionIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.Opinion{}).Where("id IN ?", opinionIDs).UpdateColumn("author_id", authorID).Error
+}
+
+// GetWatches 获取场景关注的作者（包含作者信息），按添加时间倒序
+func (r *authorRepository) GetWatches(scenarioID uint64) ([]*model.ScenarioAuthorWatch, error) {
+	var watches []*model.ScenarioAuthorWatch
+	err := r.db.Preload("Author").Where("scenario_id = ?", scenarioID).Order("id DESC").Find(&watches).Error
+	if err != nil {
+		return nil, err
+	}
+	return watches, nil
+}
+
+// GetWatchedAuthorIDs 获取场景关注的作者ID
+func (r *authorRepository) GetWatchedAuthorIDs(scenarioID uint64) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&model.ScenarioAuthorWatch{}).Where("scenario_id = ?", scenarioID).Pluck("author_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// AddWatch 添加场景关注的作者
+func (r *authorRepository) AddWatch(watch *model.ScenarioAuthorWatch) error {
+	return r.db.Create(watch).Error
+}
+
+// RemoveWatch 取消场景对作者的关注，返回删除的记录数
+func (r *authorRepository) RemoveWatch(scenarioID, authorID uint64) (int64, error) {
+	result := r.db.Where("scenario_id = ? AND author_id = ?", scenarioID, authorID).Delete(&model.ScenarioAuthorWatch{})
+	return result.RowsAffected, result.Error
+}
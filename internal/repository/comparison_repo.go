@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// ComparisonSetRepository 竞品对比组数据访问接口
+type ComparisonSetRepository interface {
+	Create(set *model.ComparisonSet) error
+	GetByID(id uint64) (*model.ComparisonSet, error)
+	GetAll() ([]*model.ComparisonSet, error)
+	Update(set *model.ComparisonSet) error
+	Delete(id uint64) error
+}
+
+type comparisonSetRepository struct {
+	db *gorm.DB
+}
+
+// NewComparisonSetRepository 创建竞品对比组数据访问实例
+func NewComparisonSetRepository() ComparisonSetRepository {
+	return &comparisonSetRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建对比组
+func (r *comparisonSetRepository) Create(set *model.ComparisonSet) error {
+	return r.db.Create(set).Error
+}
+
+// GetByID 根据 ID 获取对比组
+func (r *comparisonSetRepository) GetByID(id uint64) (*model.ComparisonSet, error) {
+	var set model.ComparisonSet
+	err := r.db.First(&set, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// GetAll 获取所有对比组
+func (r *comparisonSetRepository) GetAll() ([]*model.ComparisonSet, error) {
+	var sets []*model.ComparisonSet
+	err := r.db.Order("id DESC").Find(&sets).Error
+	if err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// Update 更新对比组
+func (r *comparisonSetRepository) Update(set *model.ComparisonSet) error {
+	return r.db.Save(set).Error
+}
+
+// Delete 删除对比组
+func (r *comparisonSetRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.ComparisonSet{}, id).Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// ExportTaskRepository 导出任务数据访问接口
+type ExportTaskRepository interface {
+	Create(task *model.ExportTask) error
+	GetByID(id uint64) (*model.ExportTask, error)
+	List(userID uint64, page, pageSize int) ([]*model.ExportTask, int64, error)
+	GetByStatus(status string) ([]*model.ExportTask, error)
+	GetExpired(now time.Time) ([]*model.ExportTask, error)
+	Claim(id uint64, now time.Time) (bool, error)
+	UpdateProgress(id uint64, processed int64) error
+	Update(task *model.ExportTask) error
+}
+
+type exportTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewExportTaskRepository 创建导出任务数据访问实例
+func NewExportTaskRepository() ExportTaskRepository {
+	return &exportTaskRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建导出任务
+func (r *exportTaskRepository) Create(task *model.ExportTask) error {
+	return r.db.Create(task).Error
+}
+
+// GetByID 根据 ID 获取导出任务
+func (r *exportTaskRepository) GetByID(id uint64) (*model.ExportTask, error) {
+	var task model.ExportTask
+	err := r.db.First(&task, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// List 分页获取用户的导出任务，userID 为 0 时返回全部
+func (r *exportTaskRepository) List(userID uint64, page, pageSize int) ([]*model.ExportTask, int64, error) {
+	var tasks []*model.ExportTask
+	var total int64
+
+	query := r.db.Model(&model.ExportTask{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+// GetByStatus 根据状态获取导出任务，按创建顺序
+func (r *exportTaskRepository) GetByStatus(status string) ([]*model.ExportTask, error) {
+	var tasks []*model.ExportTask
+	err := r.db.Where("status = ?", status).Order("id ASC").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetExpired 获取文件已过期但尚未清理的导出任务
+func (r *exportTaskRepository) GetExpired(now time.Time) ([]*model.ExportTask, error) {
+	var tasks []*model.ExportTask
+	err := r.db.Where("status = ? AND expires_at <= ?", model.ExportStatusSuccess, now).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Claim 把等待执行的任务标记为执行中，返回是否抢到（多个进程同时执行时只有一个成功）
+func (r *exportTaskRepository) Claim(id uint64, now time.Time) (bool, error) {
+	result := r.db.Model(&model.ExportTask{}).
+		Where("id = ? AND status = ?", id, model.ExportStatusPending).
+		Updates(map[string]interface{}{"status": model.ExportStatusRunning, "started_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateProgress 更新已写入的行数
+func (r *exportTaskRepository) UpdateProgress(id uint64, processed int64) error {
+	return r.db.Model(&model.ExportTask{}).Where("id = ?", id).Update("processed", processed).Error
+}
+
+// Update 更新导出任务
+func (r *exportTaskRepository) Update(task *model.ExportTask) error {
+	return r.db.Save(task).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// OpinionHandlingRepository 舆情处置数据访问接口
+type OpinionHandlingRepository interface {
+	UpdateHandling(opinionID uint64, fromStatus string, fields map[string]interface{}) (bool, error)
+	ListQueue(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error)
+	CreateLog(log *model.OpinionHandlingLog) error
+	GetLogs(opinionID uint64) ([]*model.OpinionHandlingLog, error)
+	CreateComment(comment *model.OpinionComment) error
+	GetComments(opinionID uint64) ([]*model.OpinionComment, error)
+}
+
+type opinionHandlingRepository struct {
+	db *gorm.DB
+}
+
+// NewOpinionHandlingRepository 创建舆情处置数据访问实例
+func NewOpinionHandlingRepository() OpinionHandlingRepository {
+	return &opinionHandlingRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// UpdateHandling 在处置状态仍为 fromStatus 时更新处置字段，返回是否更新成功（状态已被他人修改时返回 false）
+func (r *opinionHandlingRepository) UpdateHandling(opinionID uint64, fromStatus string, fields map[string]interface{}) (bool, error) {
+	result := r.db.Model(&model.Opinion{}).
+		Where("id = ? AND handling_status = ?", opinionID, fromStatus).
+		Updates(fields)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListQueue 按处置优先顺序分页获取舆情：优先级从高到低，截止时间早的在前，未设置截止时间的在后
+func (r *opinionHandlingRepository) ListQueue(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error) {
+	var opinions []*model.Opinion
+	var total int64
+
+	query := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Order("FIELD(opinions.priority, 'urgent', 'high', 'medium', 'low')").
+		Order("opinions.due_at IS NULL").
+		Order("opinions.due_at ASC").
+		Order("opinions.id ASC").
+		Offset(offset).Limit(pageSize).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return opinions, total, nil
+}
+
+// CreateLog 写入处置记录
+func (r *opinionHandlingRepository) CreateLog(log *model.OpinionHandlingLog) error {
+	return r.db.Create(log).Error
+}
+
+// GetLogs 获取舆情的处置记录，按时间先后排序
+func (r *opinionHandlingRepository) GetLogs(opinionID uint64) ([]*model.OpinionHandlingLog, error) {
+	var logs []*model.OpinionHandlingLog
+	err := r.db.Where("opinion_id = ?", opinionID).Order("id ASC").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// CreateComment 创建评论
+func (r *opinionHandlingRepository) CreateComment(comment *model.OpinionComment) error {
+	return r.db.Create(comment).Error
+}
+
+// GetComments 获取舆情的评论，按时间先后排序
+func (r *opinionHandlingRepository) GetComments(opinionID uint64) ([]*model.OpinionComment, error) {
+	var comments []*model.OpinionComment
+	err := r.db.Where("opinion_id = ?", opinionID).Order("id ASC").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
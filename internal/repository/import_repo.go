@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// ImportTaskRepository 导入任务数据访问接口
+type ImportTaskRepository interface {
+	Create(task *model.ImportTask) error
+	GetByID(id uint64) (*model.ImportTask, error)
+	List(userID uint64, page, pageSize int) ([]*model.ImportTask, int64, error)
+	GetByStatus(status string) ([]*model.ImportTask, error)
+	GetExpired(now time.Time) ([]*model.ImportTask, error)
+	Claim(id uint64, now time.Time) (bool, error)
+	UpdateProgress(task *model.ImportTask) error
+	Update(task *model.ImportTask) error
+}
+
+type importTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewImportTaskRepository 创建导入任务数据访问实例
+func NewImportTaskRepository() ImportTaskRepository {
+	return &importTaskRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建导入任务
+func (r *importTaskRepository) Create(task *model.ImportTask) error {
+	return r.db.Create(task).Error
+}
+
+// GetByID 根据 ID 获取导入任务
+func (r *importTaskRepository) GetByID(id uint64) (*model.ImportTask, error) {
+	var task model.ImportTask
+	err := r.db.First(&task, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// List 分页获取用户的导入任务，userID 为 0 时返回全部
+func (r *importTaskRepository) List(userID uint64, page, pageSize int) ([]*model.ImportTask, int64, error) {
+	var tasks []*model.ImportTask
+	var total int64
+
+	query := r.db.Model(&model.ImportTask{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+// GetByStatus 根据状态获取导入任务，按创建顺序
+func (r *importTaskRepository) GetByStatus(status string) ([]*model.ImportTask, error) {
+	var tasks []*model.ImportTask
+	err := r.db.Where("status = ?", status).Order("id ASC").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetExpired 获取已结束、文件已过期但尚未清理的导入任务
+func (r *importTaskRepository) GetExpired(now time.Time) ([]*model.ImportTask, error) {
+	var tasks []*model.ImportTask
+	err := r.db.Where("status IN ? AND expires_at <= ?", []string{model.ImportStatusSuccess, model.ImportStatusFailed}, now).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Claim 把等待执行的任务标记为执行中，返回是否抢到（多个进程同时执行时只有一个成功）
+func (r *importTaskRepository) Claim(id uint64, now time.Time) (bool, error) {
+	result := r.db.Model(&model.ImportTask{}).
+		Where("id = ? AND status = ?", id, model.ImportStatusPending).
+		Updates(map[string]interface{}{"status": model.ImportStatusRunning, "started_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateProgress 更新总行数、已处理的行数和各项计数
+func (r *importTaskRepository) UpdateProgress(task *model.ImportTask) error {
+	return r.db.Model(&model.ImportTask{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+		"total":      task.Total,
+		"processed":  task.Processed,
+		"imported":   task.Imported,
+		"duplicates": task.Duplicates,
+		"invalid":    task.Invalid,
+		"hits":       task.Hits,
+	}).Error
+}
+
+// Update 更新导入任务
+func (r *importTaskRepository) Update(task *model.ImportTask) error {
+	return r.db.Save(task).Error
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// InboxRepository 站内通知数据访问接口
+type InboxRepository interface {
+	CreateBatch(notifications []*model.UserNotification) error
+	List(userID uint64, unreadOnly bool, page, pageSize int) ([]*model.UserNotification, int64, error)
+	CountUnread(userID uint64) (int64, error)
+	MarkRead(userID uint64, ids []uint64, readAt time.Time) (int64, error)
+	MarkAllRead(userID uint64, readAt time.Time) (int64, error)
+}
+
+type inboxRepository struct {
+	db *gorm.DB
+}
+
+// NewInboxRepository 创建站内通知数据访问实例
+func NewInboxRepository() InboxRepository {
+	return &inboxRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// CreateBatch 批量创建站内通知
+func (r *inboxRepository) CreateBatch(notifications []*model.UserNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+// List 分页获取用户的站内通知，按时间倒序
+func (r *inboxRepository) List(userID uint64, unreadOnly bool, page, pageSize int) ([]*model.UserNotification, int64, error) {
+	var notifications []*model.UserNotification
+	var total int64
+
+	query := r.db.Model(&model.UserNotification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *inboxRepository) CountUnread(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserNotification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的指定通知标记为已读，返回实际更新的条数
+func (r *inboxRepository) MarkRead(userID uint64, ids []uint64, readAt time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&model.UserNotification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead 将用户的所有未读通知标记为已读，返回实际更新的条数
+func (r *inboxRepository) MarkAllRead(userID uint64, readAt time.Time) (int64, error) {
+	result := r.db.Model(&model.UserNotification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 分钟桶时间在 SQL 中的格式
+const sqlMinuteFormat = "%Y-%m-%d %H:%i:00"
+
+// LiveCount 按时间桶和维度汇总的命中数
+type LiveCount struct {
+	Bucket    time.Time
+	Dimension string
+	DimKey    string
+	Count     int64
+}
+
+// LiveStatsRepository 监测组分钟计数数据访问接口
+type LiveStatsRepository interface {
+	Upsert(stats []*model.GroupMinuteStat) error
+	GetRange(groupID uint64, start, end time.Time, hourly bool) ([]*LiveCount, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+type liveStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewLiveStatsRepository 创建监测组分钟计数数据访问实例
+func NewLiveStatsRepository() LiveStatsRepository {
+	return &liveStatsRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Upsert 写入分钟计数，已存在的记录以新值覆盖（Redis 中保存的是该分钟的累计值）
+func (r *liveStatsRepository) Upsert(stats []*model.GroupMinuteStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"count", "updated_at"}),
+	}).CreateInBatches(stats, 500).Error
+}
+
+// liveRow 分钟计数按时间桶聚合的结果
+type liveRow struct {
+	BucketKey string
+	Dimension string
+	DimKey    string
+	Count     int64
+}
+
+// GetRange 获取监测组在 [start, end) 内各维度的命中数，按分钟或小时分桶
+func (r *liveStatsRepository) GetRange(groupID uint64, start, end time.Time, hourly bool) ([]*LiveCount, error) {
+	format := sqlMinuteFormat
+	if hourly {
+		format = sqlHourFormat
+	}
+
+	var rows []*liveRow
+	err := r.db.Model(&model.GroupMinuteStat{}).
+		Select("DATE_FORMAT(minute, ?) AS bucket_key, dimension, dim_key, SUM(count) AS count", format).
+		Where("group_id = ? AND minute >= ? AND minute < ?", groupID, start, end).
+		Group("bucket_key, dimension, dim_key").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]*LiveCount, 0, len(rows))
+	for _, row := range rows {
+		bucket, err := time.ParseInLocation(bucketLayout, row.BucketKey, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, &LiveCount{Bucket: bucket, Dimension: row.Dimension, DimKey: row.DimKey, Count: row.Count})
+	}
+	return counts, nil
+}
+
+// DeleteBefore 删除早于指定时间的分钟计数，返回删除的行数
+func (r *liveStatsRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("minute < ?", before).Delete(&model.GroupMinuteStat{})
+	return result.RowsAffected, result.Error
+}
@@ -1,17 +1,26 @@
 package repository
 
 import (
+	"time"
+
 	"sentinel-opinion-monitor/internal/model"
 	"sentinel-opinion-monitor/internal/pkg/mysql"
 
 	"gorm.io/gorm"
 )
 
+// FilterCounts 一次匹配中排除词和来源过滤规则（按 ID）匹配的舆情数
+type FilterCounts struct {
+	ExclusionWords map[uint64]int64 // 排除词排除的舆情数
+	SourceFilters  map[uint64]int64 // 来源过滤规则匹配的舆情数：屏蔽规则为排除数，允许规则为放行数
+}
+
 // MonitoringGroupRepository 监测组数据访问接口
 type MonitoringGroupRepository interface {
 	Create(group *model.MonitoringGroup) error
 	CreateWithKeywordsAndExclusionWords(group *model.MonitoringGroup, keywords []string, exclusionWords []string) error
 	GetByID(id uint64) (*model.MonitoringGroup, error)
+	GetByIDs(ids []uint64) ([]*model.MonitoringGroup, error)
 	GetByScenarioID(scenarioID uint64) ([]*model.MonitoringGroup, error)
 	Update(group *model.MonitoringGroup) error
 	Delete(id uint64) error
@@ -23,6 +32,13 @@ type MonitoringGroupRepository interface {
 	AddExclusionWord(groupID uint64, word string) error
 	RemoveExclusionWord(groupID uint64, wordID uint64) error
 	GetExclusionWords(groupID uint64) ([]*model.GroupExclusionWord, error)
+	AddSourceFilter(filter *model.GroupSourceFilter) error
+	RemoveSourceFilter(groupID uint64, filterID uint64) (int64, error)
+	GetSourceFilters(groupID uint64) ([]*model.GroupSourceFilter, error)
+	GetActiveWithDetails() ([]*model.MonitoringGroup, error)
+	UpdateLastScannedAt(id uint64, scannedAt time.Time) error
+	GetAllTerms() ([]string, error)
+	AddFilteredCounts(counts *FilterCounts, filteredAt time.Time) error
 }
 
 type monitoringGroupRepository struct {
@@ -99,6 +115,19 @@ func (r *monitoringGroupRepository) GetByID(id uint64) (*model.MonitoringGroup,
 	return &group, nil
 }
 
+// GetByIDs 根据 ID 列表批量获取监测组
+func (r *monitoringGroupRepository) GetByIDs(ids []uint64) ([]*model.MonitoringGroup, error) {
+	var groups []*model.MonitoringGroup
+	if len(ids) == 0 {
+		return groups, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
 // GetByScenarioID 根据场景ID获取监测组列表
 func (r *monitoringGroupRepository) GetByScenarioID(scenarioID uint64) ([]*model.MonitoringGroup, error) {
 	var groups []*model.MonitoringGroup
@@ -120,16 +149,22 @@ func (r *monitoringGroupRepository) Delete(id uint64) error {
 	r.db.Where("group_id = ?", id).Delete(&model.GroupKeyword{})
 	// 删除关联的排除词
 	r.db.Where("group_id = ?", id).Delete(&model.GroupExclusionWord{})
+	// 删除关联的来源过滤规则
+	r.db.Where("group_id = ?", id).Delete(&model.GroupSourceFilter{})
 	// 删除关联的渠道
 	r.db.Where("group_id = ?", id).Delete(&model.GroupChannel{})
+	// 删除噪音过滤器
+	r.db.Where("group_id = ?", id).Delete(&model.NoiseFilter{})
 	// 删除监测组
 	return r.db.Delete(&model.MonitoringGroup{}, id).Error
 }
 
-// GetWithDetails 获取监测组详细信息（包含渠道、关键词、排除词）
+// GetWithDetails 获取监测组详细信息（包含渠道、关键词、排除词、来源过滤规则）
 func (r *monitoringGroupRepository) GetWithDetails(id uint64) (*model.MonitoringGroup, error) {
 	var group model.MonitoringGroup
-	err := r.db.Preload("Scenario").Preload("Channels").Preload("Keywords").Preload("ExclusionWords").First(&group, id).Error
+	err := r.db.Preload("Scenario").Preload("Channels").Preload("Keywords").Preload("ExclusionWords").
+		Preload("SourceFilters").Preload("SourceFilters.Author").
+		First(&group, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -203,3 +238,90 @@ func (r *monitoringGroupRepository) GetExclusionWords(groupID uint64) ([]*model.
 	}
 	return words, nil
 }
+
+// AddSourceFilter 添加来源过滤规则
+func (r *monitoringGroupRepository) AddSourceFilter(filter *model.GroupSourceFilter) error {
+	return r.db.Create(filter).Error
+}
+
+// RemoveSourceFilter 删除来源过滤规则，返回删除的记录数
+func (r *monitoringGroupRepository) RemoveSourceFilter(groupID uint64, filterID uint64) (int64, error) {
+	result := r.db.Where("group_id = ? AND id = ?", groupID, filterID).Delete(&model.GroupSourceFilter{})
+	return result.RowsAffected, result.Error
+}
+
+// GetSourceFilters 获取来源过滤规则列表（包含作者信息）
+func (r *monitoringGroupRepository) GetSourceFilters(groupID uint64) ([]*model.GroupSourceFilter, error) {
+	var filters []*model.GroupSourceFilter
+	err := r.db.Preload("Author").Where("group_id = ?", groupID).Order("id ASC").Find(&filters).Error
+	if err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// GetActiveWithDetails 获取所有启用场景下启用的监测组（包含渠道、关键词、排除词、来源过滤规则）
+func (r *monitoringGroupRepository) GetActiveWithDetails() ([]*model.MonitoringGroup, error) {
+	var groups []*model.MonitoringGroup
+	err := r.db.Joins("JOIN scenarios ON scenarios.id = monitoring_groups.scenario_id AND scenarios.status = ?", 1).
+		Where("monitoring_groups.status = ?", 1).
+		Preload("Channels").Preload("Keywords").Preload("ExclusionWords").Preload("SourceFilters").
+		Order("monitoring_groups.scenario_id ASC, monitoring_groups.sort ASC, monitoring_groups.id ASC").
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UpdateLastScannedAt 更新最近一次扫描时间
+func (r *monitoringGroupRepository) UpdateLastScannedAt(id uint64, scannedAt time.Time) error {
+	return r.db.Model(&model.MonitoringGroup{}).Where("id = ?", id).Update("last_scanned_at", scannedAt).Error
+}
+
+// GetAllTerms 获取所有监测组的关键词和排除词（去重），用于构建分词用户词典
+func (r *monitoringGroupRepository) GetAllTerms() ([]string, error) {
+	var keywords []string
+	if err := r.db.Model(&model.GroupKeyword{}).Distinct().Pluck("keyword", &keywords).Error; err != nil {
+		return nil, err
+	}
+	var words []string
+	if err := r.db.Model(&model.GroupExclusionWord{}).Distinct().Pluck("word", &words).Error; err != nil {
+		return nil, err
+	}
+	return append(keywords, words...), nil
+}
+
+// AddFilteredCounts 累加排除词和来源过滤规则（按 ID）匹配的舆情数并更新最近匹配时间
+func (r *monitoringGroupRepository) AddFilteredCounts(counts *FilterCounts, filteredAt time.Time) error {
+	if counts == nil || len(counts.ExclusionWords)+len(counts.SourceFilters) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, count := range counts.ExclusionWords {
+			if count <= 0 {
+				continue
+			}
+			err := tx.Model(&model.GroupExclusionWord{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"filtered_count":   gorm.Expr("filtered_count + ?", count),
+				"last_filtered_at": filteredAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		for id, count := range counts.SourceFilters {
+			if count <= 0 {
+				continue
+			}
+			err := tx.Model(&model.GroupSourceFilter{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"matched_count":   gorm.Expr("matched_count + ?", count),
+				"last_matched_at": filteredAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// NoiseFilterRepository 监测组噪音过滤器数据访问接口
+type NoiseFilterRepository interface {
+	Create(filter *model.NoiseFilter) error
+	GetByGroupID(groupID uint64) (*model.NoiseFilter, error)
+	GetModel(id uint64) (string, error)
+	UpdateSettings(filter *model.NoiseFilter) error
+	UpdateModel(filter *model.NoiseFilter) error
+}
+
+type noiseFilterRepository struct {
+	db *gorm.DB
+}
+
+// NewNoiseFilterRepository 创建噪音过滤器数据访问实例
+func NewNoiseFilterRepository() NoiseFilterRepository {
+	return &noiseFilterRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建噪音过滤器
+func (r *noiseFilterRepository) Create(filter *model.NoiseFilter) error {
+	return r.db.Create(filter).Error
+}
+
+// GetByGroupID 获取监测组的噪音过滤器（不含模型数据），不存在时返回 nil
+func (r *noiseFilterRepository) GetByGroupID(groupID uint64) (*model.NoiseFilter, error) {
+	var filters []*model.NoiseFilter
+	err := r.db.Omit("model").Where("group_id = ?", groupID).Limit(1).Find(&filters).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return filters[0], nil
+}
+
+// GetModel 获取噪音过滤器的模型数据
+func (r *noiseFilterRepository) GetModel(id uint64) (string, error) {
+	var filter model.NoiseFilter
+	err := r.db.Select("id", "model").First(&filter, id).Error
+	if err != nil {
+		return "", err
+	}
+	return filter.Model, nil
+}
+
+// UpdateSettings 更新是否启用和屏蔽阈值
+func (r *noiseFilterRepository) UpdateSettings(filter *model.NoiseFilter) error {
+	return r.db.Model(&model.NoiseFilter{}).Where("id = ?", filter.ID).Updates(map[string]interface{}{
+		"enabled":   filter.Enabled,
+		"threshold": filter.Threshold,
+	}).Error
+}
+
+// UpdateModel 更新训练结果
+func (r *noiseFilterRepository) UpdateModel(filter *model.NoiseFilter) error {
+	return r.db.Model(&model.NoiseFilter{}).Where("id = ?", filter.ID).Updates(map[string]interface{}{
+		"relevant_docs": filter.RelevantDocs,
+		"noise_docs":    filter.NoiseDocs,
+		"terms":         filter.Terms,
+		"model":         filter.Model,
+		"trained_at":    filter.TrainedAt,
+	}).Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// DeliveryFilter 通知发送记录查询条件，零值字段表示不限
+type DeliveryFilter struct {
+	ChannelID uint64
+	Source    string
+	SourceID  uint64
+	Status    string
+}
+
+// NotificationChannelRepository 通知渠道数据访问接口
+type NotificationChannelRepository interface {
+	Create(channel *model.NotificationChannel) error
+	GetByID(id uint64) (*model.NotificationChannel, error)
+	GetByIDs(ids []uint64) ([]*model.NotificationChannel, error)
+	GetAll() ([]*model.NotificationChannel, error)
+	Update(channel *model.NotificationChannel) error
+	Delete(id uint64) error
+}
+
+// NotificationDeliveryRepository 通知发送记录数据访问接口
+type NotificationDeliveryRepository interface {
+	Create(delivery *model.NotificationDelivery) error
+	List(filter DeliveryFilter, page, pageSize int) ([]*model.NotificationDelivery, int64, error)
+}
+
+type notificationChannelRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationChannelRepository 创建通知渠道数据访问实例
+func NewNotificationChannelRepository() NotificationChannelRepository {
+	return &notificationChannelRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建通知渠道
+func (r *notificationChannelRepository) Create(channel *model.NotificationChannel) error {
+	return r.db.Create(channel).Error
+}
+
+// GetByID 根据 ID 获取通知渠道
+func (r *notificationChannelRepository) GetByID(id uint64) (*model.NotificationChannel, error) {
+	var channel model.NotificationChannel
+	err := r.db.First(&channel, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// GetByIDs 根据 ID 列表批量获取通知渠道
+func (r *notificationChannelRepository) GetByIDs(ids []uint64) ([]*model.NotificationChannel, error) {
+	var channels []*model.NotificationChannel
+	if len(ids) == 0 {
+		return channels, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&channels).Error
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// GetAll 获取所有通知渠道
+func (r *notificationChannelRepository) GetAll() ([]*model.NotificationChannel, error) {
+	var channels []*model.NotificationChannel
+	err := r.db.Order("id ASC").Find(&channels).Error
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// Update 更新通知渠道
+func (r *notificationChannelRepository) Update(channel *model.NotificationChannel) error {
+	return r.db.Save(channel).Error
+}
+
+// Delete 删除通知渠道
+func (r *notificationChannelRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.NotificationChannel{}, id).Error
+}
+
+type notificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationDeliveryRepository 创建通知发送记录数据访问实例
+func NewNotificationDeliveryRepository() NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 写入发送记录
+func (r *notificationDeliveryRepository) Create(delivery *model.NotificationDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// List 按条件分页获取发送记录，最新的在前
+func (r *notificationDeliveryRepository) List(filter DeliveryFilter, page, pageSize int) ([]*model.NotificationDelivery, int64, error) {
+	var deliveries []*model.NotificationDelivery
+	var total int64
+
+	query := r.db.Model(&model.NotificationDelivery{})
+	if filter.ChannelID > 0 {
+		query = query.Where("channel_id = ?", filter.ChannelID)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.SourceID > 0 {
+		query = query.Where("source_id = ?", filter.SourceID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// EscalationPolicyRepository 升级策略数据访问接口
+type EscalationPolicyRepository interface {
+	Create(policy *model.EscalationPolicy) error
+	GetByID(id uint64) (*model.EscalationPolicy, error)
+	GetAll() ([]*model.EscalationPolicy, error)
+	Update(policy *model.EscalationPolicy) error
+	Delete(id uint64) error
+	CountRules(id uint64) (int64, error)
+}
+
+// OnCallScheduleRepository 值班表数据访问接口
+type OnCallScheduleRepository interface {
+	Create(schedule *model.OnCallSchedule) error
+	GetByID(id uint64) (*model.OnCallSchedule, error)
+	GetAll() ([]*model.OnCallSchedule, error)
+	Update(schedule *model.OnCallSchedule) error
+	Delete(id uint64) error
+	CreateOverride(override *model.OnCallOverride) error
+	GetOverrideByID(id uint64) (*model.OnCallOverride, error)
+	GetOverrides(scheduleID uint64, since time.Time) ([]*model.OnCallOverride, error)
+	GetActiveOverride(scheduleID uint64, at time.Time) (*model.OnCallOverride, error)
+	DeleteOverride(id uint64) error
+}
+
+type escalationPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewEscalationPolicyRepository 创建升级策略数据访问实例
+func NewEscalationPolicyRepository() EscalationPolicyRepository {
+	return &escalationPolicyRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建升级策略
+func (r *escalationPolicyRepository) Create(policy *model.EscalationPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// GetByID 根据 ID 获取升级策略
+func (r *escalationPolicyRepository) GetByID(id uint64) (*model.EscalationPolicy, error) {
+	var policy model.EscalationPolicy
+	err := r.db.First(&policy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetAll 获取所有升级策略
+func (r *escalationPolicyRepository) GetAll() ([]*model.EscalationPolicy, error) {
+	var policies []*model.EscalationPolicy
+	err := r.db.Order("id ASC").Find(&policies).Error
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// Update 更新升级策略
+func (r *escalationPolicyRepository) Update(policy *model.EscalationPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// Delete 删除升级策略
+func (r *escalationPolicyRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.EscalationPolicy{}, id).Error
+}
+
+// CountRules 统计引用该策略的告警规则数量
+func (r *escalationPolicyRepository) CountRules(id uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.AlertRule{}).Where("escalation_policy_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+type onCallScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewOnCallScheduleRepository 创建值班表数据访问实例
+func NewOnCallScheduleRepository() OnCallScheduleRepository {
+	return &onCallScheduleRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建值班表
+func (r *onCallScheduleRepository) Create(schedule *model.OnCallSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// GetByID 根据 ID 获取值班表
+func (r *onCallScheduleRepository) GetByID(id uint64) (*model.OnCallSchedule, error) {
+	var schedule model.OnCallSchedule
+	err := r.db.First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetAll 获取所有值班表
+func (r *onCallScheduleRepository) GetAll() ([]*model.OnCallSchedule, error) {
+	var schedules []*model.OnCallSchedule
+	err := r.db.Order("id ASC").Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Update 更新值班表
+func (r *onCallScheduleRepository) Update(schedule *model.OnCallSchedule) error {
+	return r.db.Save(schedule).Error
+}
+
+// Delete 删除值班表及其替班记录
+func (r *onCallScheduleRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id = ?", id).Delete(&model.OnCallOverride{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.OnCallSchedule{}, id).Error
+	})
+}
+
+// CreateOverride 创建替班
+func (r *onCallScheduleRepository) CreateOverride(override *model.OnCallOverride) error {
+	return r.db.Create(override).Error
+}
+
+// GetOverrideByID 根据 ID 获取替班
+func (r *onCallScheduleRepository) GetOverrideByID(id uint64) (*model.OnCallOverride, error) {
+	var override model.OnCallOverride
+	err := r.db.First(&override, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// GetOverrides 获取值班表在 since 之后仍有效的替班，按开始时间排序
+func (r *onCallScheduleRepository) GetOverrides(scheduleID uint64, since time.Time) ([]*model.OnCallOverride, error) {
+	var overrides []*model.OnCallOverride
+	err := r.db.Where("schedule_id = ? AND end_at > ?", scheduleID, since).Order("start_at ASC").Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// GetActiveOverride 获取 at 时刻生效的替班，多个重叠时取最新创建的
+func (r *onCallScheduleRepository) GetActiveOverride(scheduleID uint64, at time.Time) (*model.OnCallOverride, error) {
+	var override model.OnCallOverride
+	err := r.db.Where("schedule_id = ? AND start_at <= ? AND end_at > ?", scheduleID, at, at).
+		Order("id DESC").
+		First(&override).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// DeleteOverride 删除替班
+func (r *onCallScheduleRepository) DeleteOverride(id uint64) error {
+	return r.db.Delete(&model.OnCallOverride{}, id).Error
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HitDocument 命中舆情的分析视图（同一舆情只出现一次）
+type HitDocument struct {
+	OpinionID      uint64
+	Content        string
+	Keywords       model.TermList
+	SentimentScore float64 // 按场景词典计算的情感得分
+	SentimentLabel string  // 按场景词典计算的情感标签
+	Engagement     int64   // 互动量（点赞+评论+转发）
+	Relevance      string  // 相关性标注（仅 GetLabeledDocuments 返回）
+	CreatedAt      time.Time
+}
+
+// KeywordHitStat 监测组按命中关键词汇总的命中数和相关性标注数
+type KeywordHitStat struct {
+	Keyword    string
+	Hits       int64
+	Relevant   int64
+	Irrelevant int64
+	Suppressed int64
+}
+
+// GroupLabelStat 监测组的相关性标注数
+type GroupLabelStat struct {
+	GroupID       uint64
+	Relevant      int64
+	Irrelevant    int64
+	LastLabeledAt *time.Time
+}
+
+// SuppressedHit 被噪音过滤器屏蔽的命中舆情
+type SuppressedHit struct {
+	ID          uint64     `json:"id"`
+	OpinionID   uint64     `json:"opinion_id"`
+	Keyword     string     `json:"keyword"`
+	NoiseScore  float64    `json:"noise_score"`
+	Relevance   string     `json:"relevance"`
+	RelevanceAt *time.Time `json:"relevance_at"`
+	Content     string     `json:"content"`
+	Source      string     `json:"source"`
+	Author      string     `json:"author"`
+	MatchedAt   time.Time  `json:"matched_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// FeedItem 实时推送的命中舆情，ID 为命中记录 ID，可作为断线续传的游标
+type FeedItem struct {
+	ID             uint64    `json:"id"`
+	OpinionID      uint64    `json:"opinion_id"`
+	ScenarioID     uint64    `json:"scenario_id"`
+	GroupID        uint64    `json:"group_id"`
+	Keyword        string    `json:"keyword"`
+	SentimentScore float64   `json:"sentiment_score"` // 按场景词典计算的情感得分
+	SentimentLabel string    `json:"sentiment_label"` // 按场景词典计算的情感标签
+	Content        string    `json:"content"`
+	Source         string    `json:"source"`
+	Engagement     int64     `json:"engagement"` // 互动量（点赞+评论+转发）
+	MatchedAt      time.Time `json:"matched_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// FeedFilter 命中舆情推送查询条件，ScenarioIDs 和 GroupIDs 满足其一即可
+type FeedFilter struct {
+	ScenarioIDs []uint64
+	GroupIDs    []uint64
+	OpinionIDs  []uint64 // 只查询这些舆情
+	AfterID     uint64   // 只查询 ID 大于该值的命中记录
+}
+
+// OpinionHitRepository 舆情命中记录数据访问接口
+type OpinionHitRepository interface {
+	CreateBatch(hits []*model.OpinionHit) error
+	GetDocuments(scenarioID, groupID uint64, start, end time.Time) ([]*HitDocument, error)
+	GetFeed(filter FeedFilter, limit int) ([]*FeedItem, error)
+	GetByOpinionIDs(opinionIDs []uint64) ([]*model.OpinionHit, error)
+	GetCreatedAfter(since time.Time, afterID uint64, limit int) ([]*model.OpinionHit, error)
+	GetByOpinionAndGroup(opinionID, groupID uint64) (*model.OpinionHit, error)
+	UpdateRelevance(id uint64, relevance string, userID uint64, markedAt *time.Time) error
+	GetKeywordStats(groupID uint64) ([]*KeywordHitStat, error)
+	GetLabeledDocuments(groupID uint64, since time.Time, limit int) ([]*HitDocument, error)
+	GetLabelStats() ([]*GroupLabelStat, error)
+	SetSuppressed(hit *model.OpinionHit, suppressed bool) error
+	GetSuppressed(groupID uint64, pending bool, page, pageSize int) ([]*SuppressedHit, int64, error)
+	CountSuppressed(groupID uint64) (int64, error)
+}
+
+type opinionHitRepository struct {
+	db *gorm.DB
+}
+
+// NewOpinionHitRepository 创建舆情命中记录数据访问实例
+func NewOpinionHitRepository() OpinionHitRepository {
+	return &opinionHitRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// CreateBatch 批量写入命中记录，同一舆情在同一监测组下只记录一次
+func (r *opinionHitRepository) CreateBatch(hits []*model.OpinionHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(hits, 200).Error
+}
+
+// GetDocuments 获取场景（指定 groupID 时为该监测组）在 [start, end) 内入库的命中舆情（不含被噪音过滤器屏蔽的命中）
+func (r *opinionHitRepository) GetDocuments(scenarioID, groupID uint64, start, end time.Time) ([]*HitDocument, error) {
+	var docs []*HitDocument
+	query := r.db.Table("opinion_hits").
+		Select("opinions.id AS opinion_id, opinions.content, opinions.keywords, "+
+			"MAX(opinion_hits.sentiment_score) AS sentiment_score, MAX(opinion_hits.sentiment_label) AS sentiment_label, "+
+			"opinions.like_count + opinions.comment_count + opinions.share_count AS engagement, opinions.created_at").
+		Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+		Where("opinion_hits.scenario_id = ? AND opinion_hits.suppressed = ?", scenarioID, false)
+	if groupID > 0 {
+		query = query.Where("opinion_hits.group_id = ?", groupID)
+	}
+	err := query.Where("opinions.created_at >= ? AND opinions.created_at < ?", start, end).
+		Group("opinions.id").
+		Order("opinions.id ASC").
+		Scan(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// GetFeed 按命中记录 ID 升序获取命中舆情（不含被噪音过滤器屏蔽的命中）
+func (r *opinionHitRepository) GetFeed(filter FeedFilter, limit int) ([]*FeedItem, error) {
+	var items []*FeedItem
+	if len(filter.ScenarioIDs) == 0 && len(filter.GroupIDs) == 0 {
+		return items, nil
+	}
+
+	query := r.db.Table("opinion_hits").
+		Select("opinion_hits.id, opinion_hits.opinion_id, opinion_hits.scenario_id, opinion_hits.group_id, opinion_hits.keyword, "+
+			"opinion_hits.sentiment_score, opinion_hits.sentiment_label, opinions.content, opinions.source, "+
+			"opinions.like_count + opinions.comment_count + opinions.share_count AS engagement, "+
+			"opinion_hits.created_at AS matched_at, opinions.created_at").
+		Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+		Where("opinion_hits.suppressed = ?", false)
+	switch {
+	case len(filter.ScenarioIDs) > 0 && len(filter.GroupIDs) > 0:
+		query = query.Where("(opinion_hits.scenario_id IN ? OR opinion_hits.group_id IN ?)", filter.ScenarioIDs, filter.GroupIDs)
+	case len(filter.ScenarioIDs) > 0:
+		query = query.Where("opinion_hits.scenario_id IN ?", filter.ScenarioIDs)
+	default:
+		query = query.Where("opinion_hits.group_id IN ?", filter.GroupIDs)
+	}
+	if len(filter.OpinionIDs) > 0 {
+		query = query.Where("opinion_hits.opinion_id IN ?", filter.OpinionIDs)
+	}
+	if filter.AfterID > 0 {
+		query = query.Where("opinion_hits.id > ?", filter.AfterID)
+	}
+
+	err := query.Order("opinion_hits.id ASC").Limit(limit).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetByOpinionIDs 获取指定舆情的命中记录（不含被噪音过滤器屏蔽的命中）
+func (r *opinionHitRepository) GetByOpinionIDs(opinionIDs []uint64) ([]*model.OpinionHit, error) {
+	var hits []*model.OpinionHit
+	if len(opinionIDs) == 0 {
+		return hits, nil
+	}
+	err := r.db.Where("opinion_id IN ? AND suppressed = ?", opinionIDs, false).Order("id ASC").Find(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// GetCreatedAfter 按创建时间和 ID 顺序分批获取 since 之后（含）创建的命中记录，afterID 为同一创建时间下已获取的最大 ID
+func (r *opinionHitRepository) GetCreatedAfter(since time.Time, afterID uint64, limit int) ([]*model.OpinionHit, error) {
+	var hits []*model.OpinionHit
+	err := r.db.Where("created_at >= ? AND (created_at > ? OR id > ?)", since, since, afterID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// GetByOpinionAndGroup 获取舆情在指定监测组下的命中记录
+func (r *opinionHitRepository) GetByOpinionAndGroup(opinionID, groupID uint64) (*model.OpinionHit, error) {
+	var hit model.OpinionHit
+	err := r.db.Where("opinion_id = ? AND group_id = ?", opinionID, groupID).First(&hit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hit, nil
+}
+
+// UpdateRelevance 更新命中记录的相关性标注，relevance 为空时清除标注
+func (r *opinionHitRepository) UpdateRelevance(id uint64, relevance string, userID uint64, markedAt *time.Time) error {
+	return r.db.Model(&model.OpinionHit{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"relevance":    relevance,
+		"relevance_by": userID,
+		"relevance_at": markedAt,
+	}).Error
+}
+
+// GetKeywordStats 按命中关键词汇总监测组的命中数和相关性标注数
+func (r *opinionHitRepository) GetKeywordStats(groupID uint64) ([]*KeywordHitStat, error) {
+	var stats []*KeywordHitStat
+	err := r.db.Model(&model.OpinionHit{}).
+		Select("keyword, COUNT(*) AS hits, "+
+			"SUM(CASE WHEN relevance = ? THEN 1 ELSE 0 END) AS relevant, "+
+			"SUM(CASE WHEN relevance = ? THEN 1 ELSE 0 END) AS irrelevant, "+
+			"SUM(CASE WHEN suppressed THEN 1 ELSE 0 END) AS suppressed",
+			model.HitRelevanceRelevant, model.HitRelevanceIrrelevant).
+		Where("group_id = ?", groupID).
+		Group("keyword").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetLabeledDocuments 获取监测组在 since 之后标注过相关性的命中舆情，按标注时间倒序
+func (r *opinionHitRepository) GetLabeledDocuments(groupID uint64, since time.Time, limit int) ([]*HitDocument, error) {
+	var docs []*HitDocument
+	err := r.db.Table("opinion_hits").
+		Select("opinions.id AS opinion_id, opinions.content, opinions.keywords, "+
+			"opinion_hits.sentiment_score, opinion_hits.sentiment_label, "+
+			"opinions.like_count + opinions.comment_count + opinions.share_count AS engagement, "+
+			"opinion_hits.relevance, opinions.created_at").
+		Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+		Where("opinion_hits.group_id = ? AND opinion_hits.relevance IN ? AND opinion_hits.relevance_at >= ?",
+			groupID, []string{model.HitRelevanceRelevant, model.HitRelevanceIrrelevant}, since).
+		Order("opinion_hits.relevance_at DESC").
+		Limit(limit).
+		Scan(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// GetLabelStats 按监测组汇总相关性标注数和最近标注时间
+func (r *opinionHitRepository) GetLabelStats() ([]*GroupLabelStat, error) {
+	var stats []*GroupLabelStat
+	err := r.db.Model(&model.OpinionHit{}).
+		Select("group_id, "+
+			"SUM(CASE WHEN relevance = ? THEN 1 ELSE 0 END) AS relevant, "+
+			"SUM(CASE WHEN relevance = ? THEN 1 ELSE 0 END) AS irrelevant, "+
+			"MAX(relevance_at) AS last_labeled_at",
+			model.HitRelevanceRelevant, model.HitRelevanceIrrelevant).
+		Where("relevance IN ?", []string{model.HitRelevanceRelevant, model.HitRelevanceIrrelevant}).
+		Group("group_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SetSuppressed 更新命中记录的屏蔽状态，并更新舆情的 updated_at 使检索索引增量同步
+func (r *opinionHitRepository) SetSuppressed(hit *model.OpinionHit, suppressed bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.OpinionHit{}).Where("id = ?", hit.ID).Update("suppressed", suppressed).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Opinion{}).Where("id = ?", hit.OpinionID).Update("updated_at", time.Now()).Error
+	})
+}
+
+// GetSuppressed 分页获取监测组被屏蔽的命中舆情，按命中记录 ID 倒序；pending 为 true 时只返回未标注的
+func (r *opinionHitRepository) GetSuppressed(groupID uint64, pending bool, page, pageSize int) ([]*SuppressedHit, int64, error) {
+	var hits []*SuppressedHit
+	var total int64
+
+	query := r.db.Table("opinion_hits").
+		Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+		Where("opinion_hits.group_id = ? AND opinion_hits.suppressed = ?", groupID, true)
+	if pending {
+		query = query.Where("opinion_hits.relevance = ?", "")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Select("opinion_hits.id, opinion_hits.opinion_id, opinion_hits.keyword, opinion_hits.noise_score, " +
+		"opinion_hits.relevance, opinion_hits.relevance_at, opinions.content, opinions.source, opinions.author, " +
+		"opinion_hits.created_at AS matched_at, opinions.created_at").
+		Order("opinion_hits.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// CountSuppressed 统计监测组被屏蔽且未标注的命中数
+func (r *opinionHitRepository) CountSuppressed(groupID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.OpinionHit{}).
+		Where("group_id = ? AND suppressed = ? AND relevance = ?", groupID, true, "").
+		Count(&count).Error
+	return count, err
+}
@@ -1,19 +1,104 @@
 package repository
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"sentinel-opinion-monitor/internal/model"
 	"sentinel-opinion-monitor/internal/pkg/mysql"
 
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
+// ErrFulltextIndexMissing opinions.content 上没有 FULLTEXT 索引
+var ErrFulltextIndexMissing = errors.New("opinions.content 上没有 FULLTEXT 索引")
+
+// mysqlErrFulltextIndexMissing MySQL 错误码 ER_FT_MATCHING_KEY_NOT_FOUND
+const mysqlErrFulltextIndexMissing = 1191
+
+// OpinionFilter 舆情查询条件，零值字段表示不限
+type OpinionFilter struct {
+	ScenarioID uint64     `json:"scenario_id,omitempty"` // 命中的场景
+	GroupID    uint64     `json:"group_id,omitempty"`    // 命中的监测组
+	Sentiment  string     `json:"sentiment,omitempty"`   // 情感标签；指定场景或监测组时按场景词典计算的结果筛选
+	Source     string     `json:"source,omitempty"`      // 来源
+	AuthorID   uint64     `json:"author_id,omitempty"`   // 作者
+	StartTime  *time.Time `json:"start_time,omitempty"`  // 入库时间起（含）
+	EndTime    *time.Time `json:"end_time,omitempty"`    // 入库时间止（不含）
+
+	HandlingStatuses []string `json:"handling_status,omitempty"` // 处置状态（任一）
+	AssigneeID       uint64   `json:"assignee_id,omitempty"`     // 处理人
+	Priority         string   `json:"priority,omitempty"`        // 优先级
+	Overdue          bool     `json:"overdue,omitempty"`         // 只返回已超过截止时间且仍未处理完的舆情
+}
+
+// FulltextQuery 全文检索条件
+type FulltextQuery struct {
+	Against     string // MATCH ... AGAINST 的检索串
+	BooleanMode bool   // true 为 IN BOOLEAN MODE，否则为 IN NATURAL LANGUAGE MODE
+	ByTime      bool   // true 按入库时间倒序，否则按相关度倒序
+}
+
+// LikeQuery 没有全文索引时的降级检索条件（LIKE 子串匹配），各词语不区分大小写
+type LikeQuery struct {
+	Must    []string // 必须全部包含
+	Should  []string // 至少包含一个（Must 非空时可以为空）
+	MustNot []string // 不能包含
+}
+
+// ScoredOpinion 带相关度得分的舆情
+type ScoredOpinion struct {
+	model.Opinion
+	Score float64
+}
+
+// FacetCount 分面统计中的一项
+type FacetCount struct {
+	Key   string `json:"key"`
+	Name  string `json:"name,omitempty"` // 显示名称（如监测组名称）
+	Count int64  `json:"count"`
+}
+
+// OpinionFacets 检索结果按来源、情感和监测组的分面统计，各项按数量倒序
+type OpinionFacets struct {
+	Source    []*FacetCount `json:"source"`
+	Sentiment []*FacetCount `json:"sentiment"` // 舆情整体的情感标签
+	Group     []*FacetCount `json:"group"`     // 命中的监测组，Key 为监测组ID
+}
+
+// SentimentCount 情感标签聚合结果
+type SentimentCount struct {
+	Label    string  `json:"label"`
+	Count    int64   `json:"count"`
+	AvgScore float64 `json:"avg_score"`
+}
+
 // OpinionRepository 舆情数据访问接口
 type OpinionRepository interface {
 	Create(opinion *model.Opinion) error
 	GetByID(id uint64) (*model.Opinion, error)
+	GetByIDs(ids []uint64) ([]*model.Opinion, error)
 	GetAll() ([]*model.Opinion, error)
+	GetByFilter(filter OpinionFilter) ([]*model.Opinion, error)
+	List(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error)
 	Update(opinion *model.Opinion) error
 	Delete(id uint64) error
+	GetCreatedBetween(start time.Time, afterID uint64, end time.Time, limit int) ([]*model.Opinion, error)
+	GetBatchAfterID(afterID uint64, limit int) ([]*model.Opinion, error)
+	UpdateAnalysis(opinion *model.Opinion) error
+	CountBySentiment(filter OpinionFilter) ([]*SentimentCount, error)
+	TopByEngagement(filter OpinionFilter, limit int) ([]*model.Opinion, error)
+	Count(filter OpinionFilter) (int64, error)
+	ListAfterID(filter OpinionFilter, afterID uint64, limit int) ([]*model.Opinion, error)
+	Search(query FulltextQuery, filter OpinionFilter, page, pageSize int) ([]*ScoredOpinion, int64, error)
+	SearchLike(query LikeQuery, filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error)
+	SearchFacets(query FulltextQuery, filter OpinionFilter, limit int) (*OpinionFacets, error)
+	SearchLikeFacets(query LikeQuery, filter OpinionFilter, limit int) (*OpinionFacets, error)
+	GetUpdatedAfter(since time.Time, afterID uint64, limit int) ([]*model.Opinion, error)
+	CreateBatch(opinions []*model.Opinion) error
+	GetIDsByContentHash(hashes []string) (map[string]uint64, error)
 }
 
 type opinionRepository struct {
@@ -32,6 +117,36 @@ func (r *opinionRepository) Create(opinion *model.Opinion) error {
 	return r.db.Create(opinion).Error
 }
 
+// CreateBatch 批量创建舆情
+func (r *opinionRepository) CreateBatch(opinions []*model.Opinion) error {
+	if len(opinions) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(opinions, 200).Error
+}
+
+// GetIDsByContentHash 查询已存在的内容哈希，返回哈希到舆情ID的映射
+func (r *opinionRepository) GetIDsByContentHash(hashes []string) (map[string]uint64, error) {
+	ids := make(map[string]uint64)
+	if len(hashes) == 0 {
+		return ids, nil
+	}
+	var rows []struct {
+		ID          uint64
+		ContentHash string
+	}
+	err := r.db.Model(&model.Opinion{}).Select("id, content_hash").Where("content_hash IN ?", hashes).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if _, ok := ids[row.ContentHash]; !ok {
+			ids[row.ContentHash] = row.ID
+		}
+	}
+	return ids, nil
+}
+
 // GetByID 根据 ID 获取舆情
 func (r *opinionRepository) GetByID(id uint64) (*model.Opinion, error) {
 	var opinion model.Opinion
@@ -42,6 +157,19 @@ func (r *opinionRepository) GetByID(id uint64) (*model.Opinion, error) {
 	return &opinion, nil
 }
 
+// GetByIDs 根据 ID 列表批量获取舆情
+func (r *opinionRepository) GetByIDs(ids []uint64) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	if len(ids) == 0 {
+		return opinions, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
 // GetAll 获取所有舆情
 func (r *opinionRepository) GetAll() ([]*model.Opinion, error) {
 	var opinions []*model.Opinion
@@ -62,3 +190,320 @@ func (r *opinionRepository) Delete(id uint64) error {
 	return r.db.Delete(&model.Opinion{}, id).Error
 }
 
+// GetByFilter 按条件获取全部舆情（不分页）
+func (r *opinionRepository) GetByFilter(filter OpinionFilter) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter).Order("opinions.id ASC").Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// GetCreatedBetween 按 (created_at, id) 顺序分页获取 [start, end) 内入库的舆情，
+// 从 created_at = start 且 id > afterID 处开始（afterID 为 0 时从 start 开始），不含批量导入的舆情（导入时已匹配监测组）
+func (r *opinionRepository) GetCreatedBetween(start time.Time, afterID uint64, end time.Time, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := r.db.Where("(created_at > ? OR (created_at = ? AND id > ?)) AND created_at < ? AND import_task_id = ?", start, start, afterID, end, 0).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// List 按条件分页获取舆情
+func (r *opinionRepository) List(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error) {
+	var opinions []*model.Opinion
+	var total int64
+
+	query := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("opinions.id DESC").Offset(offset).Limit(pageSize).Find(&opinions).Error; err != nil {
+		return nil, 0, err
+	}
+	return opinions, total, nil
+}
+
+// Search 使用 opinions.content 上的 FULLTEXT（ngram）索引检索舆情，返回相关度得分；
+// 没有全文索引时返回 ErrFulltextIndexMissing
+func (r *opinionRepository) Search(query FulltextQuery, filter OpinionFilter, page, pageSize int) ([]*ScoredOpinion, int64, error) {
+	var total int64
+	if err := r.fulltextScope(query, filter).Count(&total).Error; err != nil {
+		return nil, 0, fulltextError(err)
+	}
+
+	var opinions []*ScoredOpinion
+	order := "score DESC, opinions.id DESC"
+	if query.ByTime {
+		order = "opinions.id DESC"
+	}
+	offset := (page - 1) * pageSize
+	err := r.fulltextScope(query, filter).
+		Select("opinions.*, "+fulltextMatch(query)+" AS score", query.Against).
+		Order(order).
+		Offset(offset).
+		Limit(pageSize).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, 0, fulltextError(err)
+	}
+	return opinions, total, nil
+}
+
+// SearchLike 以 LIKE 子串匹配检索舆情，按入库时间倒序；用于没有全文索引或检索词短于 ngram 长度时
+func (r *opinionRepository) SearchLike(query LikeQuery, filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error) {
+	var total int64
+	if err := r.likeScope(query, filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var opinions []*model.Opinion
+	offset := (page - 1) * pageSize
+	if err := r.likeScope(query, filter).Order("opinions.id DESC").Offset(offset).Limit(pageSize).Find(&opinions).Error; err != nil {
+		return nil, 0, err
+	}
+	return opinions, total, nil
+}
+
+// SearchFacets 统计全文检索结果按来源、情感和监测组的分面，每个分面最多返回 limit 项
+func (r *opinionRepository) SearchFacets(query FulltextQuery, filter OpinionFilter, limit int) (*OpinionFacets, error) {
+	facets, err := r.facets(func() *gorm.DB { return r.fulltextScope(query, filter) }, limit)
+	if err != nil {
+		return nil, fulltextError(err)
+	}
+	return facets, nil
+}
+
+// SearchLikeFacets 统计 LIKE 匹配结果按来源、情感和监测组的分面，每个分面最多返回 limit 项
+func (r *opinionRepository) SearchLikeFacets(query LikeQuery, filter OpinionFilter, limit int) (*OpinionFacets, error) {
+	return r.facets(func() *gorm.DB { return r.likeScope(query, filter) }, limit)
+}
+
+// GetUpdatedAfter 按更新时间和 ID 顺序分批获取 since 之后（含）更新的舆情，afterID 为同一更新时间下已获取的最大 ID
+func (r *opinionRepository) GetUpdatedAfter(since time.Time, afterID uint64, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := r.db.Where("updated_at >= ? AND (updated_at > ? OR id > ?)", since, since, afterID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// fulltextScope 构造全文检索的查询（含筛选条件）
+func (r *opinionRepository) fulltextScope(query FulltextQuery, filter OpinionFilter) *gorm.DB {
+	return applyOpinionFilter(r.db.Model(&model.Opinion{}), filter).Where(fulltextMatch(query), query.Against)
+}
+
+// likeScope 构造 LIKE 匹配的查询（含筛选条件）
+func (r *opinionRepository) likeScope(query LikeQuery, filter OpinionFilter) *gorm.DB {
+	q := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter)
+	for _, term := range query.Must {
+		q = q.Where("opinions.content LIKE ?", likePattern(term))
+	}
+	if len(query.Should) > 0 {
+		conds := make([]string, len(query.Should))
+		args := make([]interface{}, len(query.Should))
+		for i, term := range query.Should {
+			conds[i] = "opinions.content LIKE ?"
+			args[i] = likePattern(term)
+		}
+		q = q.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+	for _, term := range query.MustNot {
+		q = q.Where("opinions.content NOT LIKE ?", likePattern(term))
+	}
+	return q
+}
+
+// facets 按来源、情感标签和命中的监测组统计 scope 返回的舆情
+func (r *opinionRepository) facets(scope func() *gorm.DB, limit int) (*OpinionFacets, error) {
+	facets := &OpinionFacets{}
+	err := scope().Select("opinions.source AS `key`, COUNT(*) AS count").
+		Group("opinions.source").Order("count DESC").Limit(limit).
+		Scan(&facets.Source).Error
+	if err != nil {
+		return nil, err
+	}
+	err = scope().Select("opinions.sentiment_label AS `key`, COUNT(*) AS count").
+		Group("opinions.sentiment_label").Order("count DESC").Limit(limit).
+		Scan(&facets.Sentiment).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Table("opinion_hits").
+		Select("opinion_hits.group_id AS `key`, COUNT(*) AS count").
+		Where("opinion_hits.opinion_id IN (?) AND opinion_hits.suppressed = ?", scope().Select("opinions.id"), false).
+		Group("opinion_hits.group_id").Order("count DESC").Limit(limit).
+		Scan(&facets.Group).Error
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// Count 按条件统计舆情数量
+func (r *opinionRepository) Count(filter OpinionFilter) (int64, error) {
+	var total int64
+	err := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter).Count(&total).Error
+	return total, err
+}
+
+// ListAfterID 按条件和 ID 顺序分批获取舆情，用于遍历大量结果
+func (r *opinionRepository) ListAfterID(filter OpinionFilter, afterID uint64, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter).
+		Where("opinions.id > ?", afterID).
+		Order("opinions.id ASC").
+		Limit(limit).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// GetBatchAfterID 按 ID 顺序分批获取舆情
+func (r *opinionRepository) GetBatchAfterID(afterID uint64, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// UpdateAnalysis 更新舆情富化结果（情感、实体、主题、关键词）
+func (r *opinionRepository) UpdateAnalysis(opinion *model.Opinion) error {
+	return r.db.Model(opinion).Select("sentiment_score", "sentiment_label", "entities", "topics", "keywords", "analyzer").Updates(opinion).Error
+}
+
+// TopByEngagement 获取互动量（点赞、评论、转发之和）最高的舆情
+func (r *opinionRepository) TopByEngagement(filter OpinionFilter, limit int) ([]*model.Opinion, error) {
+	var opinions []*model.Opinion
+	err := applyOpinionFilter(r.db.Model(&model.Opinion{}), filter).
+		Order("(opinions.like_count + opinions.comment_count + opinions.share_count) DESC, opinions.id DESC").
+		Limit(limit).
+		Find(&opinions).Error
+	if err != nil {
+		return nil, err
+	}
+	return opinions, nil
+}
+
+// CountBySentiment 按情感标签聚合舆情数量和平均得分
+// 指定场景或监测组时，使用命中记录中按场景词典计算的情感结果，同一舆情只计一次
+func (r *opinionRepository) CountBySentiment(filter OpinionFilter) ([]*SentimentCount, error) {
+	var counts []*SentimentCount
+
+	if filter.ScenarioID > 0 || filter.GroupID > 0 {
+		query := r.db.Table("opinion_hits").
+			Select("opinion_hits.sentiment_label AS label, COUNT(DISTINCT opinion_hits.opinion_id) AS count, AVG(opinion_hits.sentiment_score) AS avg_score").
+			Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+			Where("opinion_hits.suppressed = ?", false)
+		if filter.ScenarioID > 0 {
+			query = query.Where("opinion_hits.scenario_id = ?", filter.ScenarioID)
+		}
+		if filter.GroupID > 0 {
+			query = query.Where("opinion_hits.group_id = ?", filter.GroupID)
+		}
+		if filter.Sentiment != "" {
+			query = query.Where("opinion_hits.sentiment_label = ?", filter.Sentiment)
+		}
+		query = applyOpinionBaseFilter(query, filter)
+		err := query.Group("opinion_hits.sentiment_label").Scan(&counts).Error
+		return counts, err
+	}
+
+	query := r.db.Model(&model.Opinion{}).
+		Select("sentiment_label AS label, COUNT(*) AS count, AVG(sentiment_score) AS avg_score")
+	if filter.Sentiment != "" {
+		query = query.Where("sentiment_label = ?", filter.Sentiment)
+	}
+	query = applyOpinionBaseFilter(query, filter)
+	err := query.Group("sentiment_label").Scan(&counts).Error
+	return counts, err
+}
+
+// fulltextMatch 全文检索的 MATCH ... AGAINST 表达式
+// 舆情没有标题列，ft_content 只覆盖 content，检索范围只有内容；增加标题列时需把它加入同一个索引并在这里一起 MATCH
+func fulltextMatch(query FulltextQuery) string {
+	if query.BooleanMode {
+		return "MATCH(opinions.content) AGAINST (? IN BOOLEAN MODE)"
+	}
+	return "MATCH(opinions.content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+}
+
+// fulltextError 把缺少全文索引的 MySQL 错误转换为 ErrFulltextIndexMissing
+func fulltextError(err error) error {
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrFulltextIndexMissing {
+		return ErrFulltextIndexMissing
+	}
+	return err
+}
+
+// likePattern 构造包含指定词语的 LIKE 模式，转义通配符
+func likePattern(term string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(term) + "%"
+}
+
+// applyOpinionFilter 将查询条件应用到 opinions 表查询上
+func applyOpinionFilter(query *gorm.DB, filter OpinionFilter) *gorm.DB {
+	if filter.ScenarioID > 0 || filter.GroupID > 0 {
+		sub := query.Session(&gorm.Session{NewDB: true}).Table("opinion_hits").Select("opinion_id").Where("suppressed = ?", false)
+		if filter.ScenarioID > 0 {
+			sub = sub.Where("scenario_id = ?", filter.ScenarioID)
+		}
+		if filter.GroupID > 0 {
+			sub = sub.Where("group_id = ?", filter.GroupID)
+		}
+		if filter.Sentiment != "" {
+			sub = sub.Where("sentiment_label = ?", filter.Sentiment)
+		}
+		query = query.Where("opinions.id IN (?)", sub)
+	} else if filter.Sentiment != "" {
+		query = query.Where("opinions.sentiment_label = ?", filter.Sentiment)
+	}
+	return applyOpinionBaseFilter(query, filter)
+}
+
+// applyOpinionBaseFilter 应用来源、作者、时间和处置条件
+func applyOpinionBaseFilter(query *gorm.DB, filter OpinionFilter) *gorm.DB {
+	if filter.Source != "" {
+		query = query.Where("opinions.source = ?", filter.Source)
+	}
+	if filter.AuthorID > 0 {
+		query = query.Where("opinions.author_id = ?", filter.AuthorID)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("opinions.created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("opinions.created_at < ?", *filter.EndTime)
+	}
+	if len(filter.HandlingStatuses) > 0 {
+		query = query.Where("opinions.handling_status IN ?", filter.HandlingStatuses)
+	}
+	if filter.AssigneeID > 0 {
+		query = query.Where("opinions.assignee_id = ?", filter.AssigneeID)
+	}
+	if filter.Priority != "" {
+		query = query.Where("opinions.priority = ?", filter.Priority)
+	}
+	if filter.Overdue {
+		query = query.Where("opinions.due_at < ? AND opinions.handling_status IN ?", time.Now(),
+			[]string{model.HandlingStatusNew, model.HandlingStatusInProgress, model.HandlingStatusEscalated})
+	}
+	return query
+}
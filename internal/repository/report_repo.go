@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// ReportFilter 简报查询条件，零值字段表示不限
+type ReportFilter struct {
+	ScenarioID uint64
+	TemplateID uint64
+	Status     string
+}
+
+// ReportTemplateRepository 简报模板数据访问接口
+type ReportTemplateRepository interface {
+	Create(tmpl *model.ReportTemplate) error
+	GetByID(id uint64) (*model.ReportTemplate, error)
+	GetAll(scenarioID uint64) ([]*model.ReportTemplate, error)
+	GetByStatus(status int) ([]*model.ReportTemplate, error)
+	Update(tmpl *model.ReportTemplate) error
+	Delete(id uint64) error
+}
+
+// ReportRepository 简报数据访问接口
+type ReportRepository interface {
+	Create(report *model.Report) error
+	GetByID(id uint64) (*model.Report, error)
+	List(filter ReportFilter, page, pageSize int) ([]*model.Report, int64, error)
+	Update(report *model.Report) error
+}
+
+type reportTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewReportTemplateRepository 创建简报模板数据访问实例
+func NewReportTemplateRepository() ReportTemplateRepository {
+	return &reportTemplateRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建简报模板
+func (r *reportTemplateRepository) Create(tmpl *model.ReportTemplate) error {
+	return r.db.Create(tmpl).Error
+}
+
+// GetByID 根据 ID 获取简报模板
+func (r *reportTemplateRepository) GetByID(id uint64) (*model.ReportTemplate, error) {
+	var tmpl model.ReportTemplate
+	err := r.db.First(&tmpl, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// GetAll 获取简报模板，scenarioID 为 0 时返回全部
+func (r *reportTemplateRepository) GetAll(scenarioID uint64) ([]*model.ReportTemplate, error) {
+	var templates []*model.ReportTemplate
+	query := r.db.Order("id ASC")
+	if scenarioID > 0 {
+		query = query.Where("scenario_id = ?", scenarioID)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetByStatus 根据状态获取简报模板
+func (r *reportTemplateRepository) GetByStatus(status int) ([]*model.ReportTemplate, error) {
+	var templates []*model.ReportTemplate
+	err := r.db.Where("status = ?", status).Order("id ASC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Update 更新简报模板
+func (r *reportTemplateRepository) Update(tmpl *model.ReportTemplate) error {
+	return r.db.Save(tmpl).Error
+}
+
+// Delete 删除简报模板
+func (r *reportTemplateRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.ReportTemplate{}, id).Error
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository 创建简报数据访问实例
+func NewReportRepository() ReportRepository {
+	return &reportRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建简报记录
+func (r *reportRepository) Create(report *model.Report) error {
+	return r.db.Create(report).Error
+}
+
+// GetByID 根据 ID 获取简报
+func (r *reportRepository) GetByID(id uint64) (*model.Report, error) {
+	var report model.Report
+	err := r.db.First(&report, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// List 按条件分页获取简报，按生成时间倒序
+func (r *reportRepository) List(filter ReportFilter, page, pageSize int) ([]*model.Report, int64, error) {
+	var reports []*model.Report
+	var total int64
+
+	query := r.db.Model(&model.Report{})
+	if filter.ScenarioID > 0 {
+		query = query.Where("scenario_id = ?", filter.ScenarioID)
+	}
+	if filter.TemplateID > 0 {
+		query = query.Where("template_id = ?", filter.TemplateID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// Update 更新简报记录
+func (r *reportRepository) Update(report *model.Report) error {
+	return r.db.Save(report).Error
+}
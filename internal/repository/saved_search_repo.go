@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SavedSearchRepository 保存的检索及其订阅的数据访问接口
+type SavedSearchRepository interface {
+	Create(search *model.SavedSearch) error
+	GetByID(id uint64) (*model.SavedSearch, error)
+	GetByIDs(ids []uint64) ([]*model.SavedSearch, error)
+	ListVisible(userID uint64, mineOnly bool) ([]*model.SavedSearch, error)
+	Update(search *model.SavedSearch) error
+	Delete(id uint64) error
+
+	GetSubscription(searchID, userID uint64) (*model.SavedSearchSubscription, error)
+	GetSubscriptionsByUser(userID uint64) ([]*model.SavedSearchSubscription, error)
+	GetAllSubscriptions() ([]*model.SavedSearchSubscription, error)
+	SaveSubscription(sub *model.SavedSearchSubscription) error
+	UpdateSubscriptionProgress(id uint64, checkedAt time.Time, notifiedAt *time.Time) error
+	DeleteSubscription(id uint64) error
+	DeleteOtherSubscriptions(searchID, userID uint64) error
+
+	GetMatchedOpinionIDs(subscriptionID uint64, opinionIDs []uint64) ([]uint64, error)
+	CreateMatches(matches []*model.SavedSearchMatch) error
+	DeleteMatchesBefore(before time.Time) (int64, error)
+}
+
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchRepository 创建保存的检索数据访问实例
+func NewSavedSearchRepository() SavedSearchRepository {
+	return &savedSearchRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建保存的检索
+func (r *savedSearchRepository) Create(search *model.SavedSearch) error {
+	return r.db.Create(search).Error
+}
+
+// GetByID 根据 ID 获取保存的检索
+func (r *savedSearchRepository) GetByID(id uint64) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	err := r.db.First(&search, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// GetByIDs 根据 ID 批量获取保存的检索
+func (r *savedSearchRepository) GetByIDs(ids []uint64) ([]*model.SavedSearch, error) {
+	var searches []*model.SavedSearch
+	if len(ids) == 0 {
+		return searches, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// ListVisible 获取用户可见的检索（自己创建的和团队可见的），mineOnly 为 true 时只返回自己创建的
+func (r *savedSearchRepository) ListVisible(userID uint64, mineOnly bool) ([]*model.SavedSearch, error) {
+	var searches []*model.SavedSearch
+	query := r.db.Model(&model.SavedSearch{})
+	if mineOnly {
+		query = query.Where("created_by = ?", userID)
+	} else {
+		query = query.Where("created_by = ? OR visibility = ?", userID, model.SavedSearchTeam)
+	}
+	err := query.Order("id DESC").Find(&searches).Error
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// Update 更新保存的检索
+func (r *savedSearchRepository) Update(search *model.SavedSearch) error {
+	return r.db.Save(search).Error
+}
+
+// Delete 删除保存的检索及其订阅和推送记录
+func (r *savedSearchRepository) Delete(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		subQuery := tx.Model(&model.SavedSearchSubscription{}).Select("id").Where("saved_search_id = ?", id)
+		if err := tx.Where("subscription_id IN (?)", subQuery).Delete(&model.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("saved_search_id = ?", id).Delete(&model.SavedSearchSubscription{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.SavedSearch{}, id).Error
+	})
+}
+
+// GetSubscription 获取用户对检索的订阅
+func (r *savedSearchRepository) GetSubscription(searchID, userID uint64) (*model.SavedSearchSubscription, error) {
+	var sub model.SavedSearchSubscription
+	err := r.db.Where("saved_search_id = ? AND user_id = ?", searchID, userID).First(&sub).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetSubscriptionsByUser 获取用户的所有订阅
+func (r *savedSearchRepository) GetSubscriptionsByUser(userID uint64) ([]*model.SavedSearchSubscription, error) {
+	var subs []*model.SavedSearchSubscription
+	err := r.db.Where("user_id = ?", userID).Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetAllSubscriptions 获取所有订阅
+func (r *savedSearchRepository) GetAllSubscriptions() ([]*model.SavedSearchSubscription, error) {
+	var subs []*model.SavedSearchSubscription
+	err := r.db.Order("id ASC").Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// SaveSubscription 创建或更新订阅
+func (r *savedSearchRepository) SaveSubscription(sub *model.SavedSearchSubscription) error {
+	return r.db.Save(sub).Error
+}
+
+// UpdateSubscriptionProgress 更新订阅的检查时间，notifiedAt 不为空时同时更新推送时间
+func (r *savedSearchRepository) UpdateSubscriptionProgress(id uint64, checkedAt time.Time, notifiedAt *time.Time) error {
+	updates := map[string]interface{}{"last_checked_at": checkedAt}
+	if notifiedAt != nil {
+		updates["last_notified_at"] = *notifiedAt
+	}
+	return r.db.Model(&model.SavedSearchSubscription{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteSubscription 删除订阅及其推送记录
+func (r *savedSearchRepository) DeleteSubscription(id uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&model.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.SavedSearchSubscription{}, id).Error
+	})
+}
+
+// DeleteOtherSubscriptions 删除检索上除指定用户以外的订阅及其推送记录（检索改为仅创建人可见时）
+func (r *savedSearchRepository) DeleteOtherSubscriptions(searchID, userID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		subQuery := tx.Model(&model.SavedSearchSubscription{}).Select("id").Where("saved_search_id = ? AND user_id <> ?", searchID, userID)
+		if err := tx.Where("subscription_id IN (?)", subQuery).Delete(&model.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("saved_search_id = ? AND user_id <> ?", searchID, userID).Delete(&model.SavedSearchSubscription{}).Error
+	})
+}
+
+// GetMatchedOpinionIDs 返回 opinionIDs 中已推送给订阅的舆情 ID
+func (r *savedSearchRepository) GetMatchedOpinionIDs(subscriptionID uint64, opinionIDs []uint64) ([]uint64, error) {
+	var ids []uint64
+	if len(opinionIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&model.SavedSearchMatch{}).
+		Where("subscription_id = ? AND opinion_id IN ?", subscriptionID, opinionIDs).
+		Pluck("opinion_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CreateMatches 批量记录已推送的舆情，已存在的记录忽略
+func (r *savedSearchRepository) CreateMatches(matches []*model.SavedSearchMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(matches, 200).Error
+}
+
+// DeleteMatchesBefore 删除指定时间之前的推送记录，返回删除的条数
+func (r *savedSearchRepository) DeleteMatchesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&model.SavedSearchMatch{})
+	return result.RowsAffected, result.Error
+}
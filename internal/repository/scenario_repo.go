@@ -73,13 +73,15 @@ func (r *scenarioRepository) Update(scenario *model.Scenario) error {
 func (r *scenarioRepository) Delete(id uint64) error {
 	// 先删除关联的监测组
 	r.db.Where("scenario_id = ?", id).Delete(&model.MonitoringGroup{})
+	// 删除关注的作者
+	r.db.Where("scenario_id = ?", id).Delete(&model.ScenarioAuthorWatch{})
 	return r.db.Delete(&model.Scenario{}, id).Error
 }
 
 // GetWithGroups 获取场景及其监测组
 func (r *scenarioRepository) GetWithGroups(id uint64) (*model.Scenario, error) {
 	var scenario model.Scenario
-	err := r.db.Preload("Tag").Preload("Groups").Preload("Groups.Channels").Preload("Groups.Keywords").Preload("Groups.ExclusionWords").First(&scenario, id).Error
+	err := r.db.Preload("Tag").Preload("Groups").Preload("Groups.Channels").Preload("Groups.Keywords").Preload("Groups.ExclusionWords").Preload("Groups.SourceFilters").First(&scenario, id).Error
 	if err != nil {
 		return nil, err
 	}
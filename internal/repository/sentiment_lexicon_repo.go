@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// SentimentLexiconRepository 自定义情感词典数据访问接口
+type SentimentLexiconRepository interface {
+	Create(entry *model.SentimentLexicon) error
+	GetByID(id uint64) (*model.SentimentLexicon, error)
+	GetByScenarioAndWord(scenarioID uint64, word string) (*model.SentimentLexicon, error)
+	GetByScenarioID(scenarioID uint64) ([]*model.SentimentLexicon, error)
+	GetEffective(scenarioID uint64) ([]*model.SentimentLexicon, error)
+	Update(entry *model.SentimentLexicon) error
+	Delete(id uint64) error
+}
+
+type sentimentLexiconRepository struct {
+	db *gorm.DB
+}
+
+// NewSentimentLexiconRepository 创建自定义情感词典数据访问实例
+func NewSentimentLexiconRepository() SentimentLexiconRepository {
+	return &sentimentLexiconRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// Create 创建词条
+func (r *sentimentLexiconRepository) Create(entry *model.SentimentLexicon) error {
+	return r.db.Create(entry).Error
+}
+
+// GetByID 根据 ID 获取词条
+func (r *sentimentLexiconRepository) GetByID(id uint64) (*model.SentimentLexicon, error) {
+	var entry model.SentimentLexicon
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetByScenarioAndWord 根据场景和词语获取词条
+func (r *sentimentLexiconRepository) GetByScenarioAndWord(scenarioID uint64, word string) (*model.SentimentLexicon, error) {
+	var entry model.SentimentLexicon
+	err := r.db.Where("scenario_id = ? AND word = ?", scenarioID, word).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetByScenarioID 获取指定范围的词条（scenarioID 为 0 表示全局词条）
+func (r *sentimentLexiconRepository) GetByScenarioID(scenarioID uint64) ([]*model.SentimentLexicon, error) {
+	var entries []*model.SentimentLexicon
+	err := r.db.Where("scenario_id = ?", scenarioID).Order("id ASC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetEffective 获取场景生效的词条：全局词条在前，场景词条在后，后者覆盖前者
+func (r *sentimentLexiconRepository) GetEffective(scenarioID uint64) ([]*model.SentimentLexicon, error) {
+	var entries []*model.SentimentLexicon
+	query := r.db.Where("scenario_id = 0")
+	if scenarioID > 0 {
+		query = r.db.Where("scenario_id IN ?", []uint64{0, scenarioID})
+	}
+	err := query.Order("scenario_id ASC, id ASC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Update 更新词条
+func (r *sentimentLexiconRepository) Update(entry *model.SentimentLexicon) error {
+	return r.db.Save(entry).Error
+}
+
+// Delete 删除词条
+func (r *sentimentLexiconRepository) Delete(id uint64) error {
+	return r.db.Delete(&model.SentimentLexicon{}, id).Error
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// bucketLayout 汇总桶时间的字符串格式
+const bucketLayout = "2006-01-02 15:04:05"
+
+// 桶时间在 SQL 中的格式
+const (
+	sqlHourFormat = "%Y-%m-%d %H:00:00"
+	sqlDayFormat  = "%Y-%m-%d 00:00:00"
+)
+
+// BucketCount 时间桶计数
+type BucketCount struct {
+	Bucket time.Time
+	Count  int64
+}
+
+// KeyCount 维度取值计数
+type KeyCount struct {
+	DimKey string
+	Count  int64
+}
+
+// StatsRepository 场景统计汇总数据访问接口
+type StatsRepository interface {
+	Aggregate(scenarioID uint64, start, end time.Time) ([]*model.StatRollup, error)
+	ReplaceRange(scenarioID uint64, start, end time.Time, rollups []*model.StatRollup) error
+	LatestBucket(scenarioID uint64) (*time.Time, error)
+	Series(scenarioID uint64, dimension, dimKey string, start, end time.Time, daily bool) ([]*BucketCount, error)
+	Breakdown(scenarioID uint64, dimension string, start, end time.Time, limit int) ([]*KeyCount, error)
+	Sum(scenarioID uint64, dimension, dimKey string, start, end time.Time) (int64, error)
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository 创建场景统计汇总数据访问实例
+func NewStatsRepository() StatsRepository {
+	return &statsRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// aggregateRow 原始数据按小时聚合的结果
+type aggregateRow struct {
+	BucketKey string
+	DimKey    string
+	Count     int64
+}
+
+// Aggregate 从命中记录按小时计算场景在 [start, end) 内各维度的舆情数（按舆情入库时间分桶）
+func (r *statsRepository) Aggregate(scenarioID uint64, start, end time.Time) ([]*model.StatRollup, error) {
+	dimensions := []struct {
+		name string
+		expr string
+		cond string
+	}{
+		{model.StatDimTotal, "''", ""},
+		{model.StatDimChannel, "opinions.source", ""},
+		{model.StatDimGroup, "CAST(opinion_hits.group_id AS CHAR)", ""},
+		{model.StatDimSentiment, "opinion_hits.sentiment_label", ""},
+		{model.StatDimAuthor, "opinions.author", "opinions.author <> ''"},
+		{model.StatDimKeyword, "opinion_hits.keyword", "opinion_hits.keyword IS NOT NULL AND opinion_hits.keyword <> ''"},
+	}
+
+	var rollups []*model.StatRollup
+	for _, dim := range dimensions {
+		var rows []*aggregateRow
+		query := r.db.Table("opinion_hits").
+			Select("DATE_FORMAT(opinions.created_at, ?) AS bucket_key, "+dim.expr+" AS dim_key, COUNT(DISTINCT opinion_hits.opinion_id) AS count", sqlHourFormat).
+			Joins("JOIN opinions ON opinions.id = opinion_hits.opinion_id").
+			Where("opinion_hits.scenario_id = ? AND opinion_hits.suppressed = ?", scenarioID, false).
+			Where("opinions.created_at >= ? AND opinions.created_at < ?", start, end)
+		if dim.cond != "" {
+			query = query.Where(dim.cond)
+		}
+		if err := query.Group("bucket_key, dim_key").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			bucket, err := time.ParseInLocation(bucketLayout, row.BucketKey, time.Local)
+			if err != nil {
+				return nil, err
+			}
+			rollups = append(rollups, &model.StatRollup{
+				ScenarioID: scenarioID,
+				Dimension:  dim.name,
+				DimKey:     row.DimKey,
+				Bucket:     bucket,
+				Count:      row.Count,
+			})
+		}
+	}
+	return rollups, nil
+}
+
+// ReplaceRange 用新的汇总结果替换场景在 [start, end) 内的汇总数据
+func (r *statsRepository) ReplaceRange(scenarioID uint64, start, end time.Time, rollups []*model.StatRollup) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("scenario_id = ? AND bucket >= ? AND bucket < ?", scenarioID, start, end).
+			Delete(&model.StatRollup{}).Error
+		if err != nil {
+			return err
+		}
+		if len(rollups) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rollups, 500).Error
+	})
+}
+
+// LatestBucket 获取场景最新的汇总桶时间，没有汇总数据时返回 nil
+func (r *statsRepository) LatestBucket(scenarioID uint64) (*time.Time, error) {
+	var rollup model.StatRollup
+	err := r.db.Where("scenario_id = ?", scenarioID).Order("bucket DESC").Limit(1).Find(&rollup).Error
+	if err != nil {
+		return nil, err
+	}
+	if rollup.ID == 0 {
+		return nil, nil
+	}
+	return &rollup.Bucket, nil
+}
+
+// Series 按小时或按天获取维度取值在 [start, end) 内的时间序列（只包含有数据的桶）
+func (r *statsRepository) Series(scenarioID uint64, dimension, dimKey string, start, end time.Time, daily bool) ([]*BucketCount, error) {
+	format := sqlHourFormat
+	if daily {
+		format = sqlDayFormat
+	}
+
+	var rows []*aggregateRow
+	err := r.db.Model(&model.StatRollup{}).
+		Select("DATE_FORMAT(bucket, ?) AS bucket_key, SUM(count) AS count", format).
+		Where("scenario_id = ? AND dimension = ? AND dim_key = ?", scenarioID, dimension, dimKey).
+		Where("bucket >= ? AND bucket < ?", start, end).
+		Group("bucket_key").
+		Order("bucket_key ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]*BucketCount, 0, len(rows))
+	for _, row := range rows {
+		bucket, err := time.ParseInLocation(bucketLayout, row.BucketKey, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, &BucketCount{Bucket: bucket, Count: row.Count})
+	}
+	return series, nil
+}
+
+// Breakdown 获取维度在 [start, end) 内各取值的舆情数，按数量降序；limit 为 0 表示不限
+func (r *statsRepository) Breakdown(scenarioID uint64, dimension string, start, end time.Time, limit int) ([]*KeyCount, error) {
+	var rows []*KeyCount
+	query := r.db.Model(&model.StatRollup{}).
+		Select("dim_key, SUM(count) AS count").
+		Where("scenario_id = ? AND dimension = ?", scenarioID, dimension).
+		Where("bucket >= ? AND bucket < ?", start, end).
+		Group("dim_key").
+		Order("count DESC, dim_key ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Sum 获取维度取值在 [start, end) 内的舆情总数
+func (r *statsRepository) Sum(scenarioID uint64, dimension, dimKey string, start, end time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.StatRollup{}).
+		Select("COALESCE(SUM(count), 0)").
+		Where("scenario_id = ? AND dimension = ? AND dim_key = ?", scenarioID, dimension, dimKey).
+		Where("bucket >= ? AND bucket < ?", start, end).
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/pkg/mysql"
+
+	"gorm.io/gorm"
+)
+
+// TrendingRepository 热词和话题数据访问接口
+type TrendingRepository interface {
+	ReplaceTerms(scenarioID uint64, terms []*model.TrendingTerm) error
+	GetTerms(scenarioID uint64, limit int) ([]*model.TrendingTerm, error)
+	ReplaceTopics(scenarioID uint64, topics []*model.OpinionTopic) error
+	GetTopics(scenarioID uint64, limit int) ([]*model.OpinionTopic, error)
+}
+
+type trendingRepository struct {
+	db *gorm.DB
+}
+
+// NewTrendingRepository 创建热词和话题数据访问实例
+func NewTrendingRepository() TrendingRepository {
+	return &trendingRepository{
+		db: mysql.GetDB(),
+	}
+}
+
+// ReplaceTerms 用新的计算结果替换场景热词快照
+func (r *trendingRepository) ReplaceTerms(scenarioID uint64, terms []*model.TrendingTerm) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scenario_id = ?", scenarioID).Delete(&model.TrendingTerm{}).Error; err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(terms, 200).Error
+	})
+}
+
+// GetTerms 获取场景热词，按突发得分降序
+func (r *trendingRepository) GetTerms(scenarioID uint64, limit int) ([]*model.TrendingTerm, error) {
+	var terms []*model.TrendingTerm
+	err := r.db.Where("scenario_id = ?", scenarioID).Order("score DESC, count DESC").Limit(limit).Find(&terms).Error
+	if err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// ReplaceTopics 用新的聚类结果替换场景话题快照
+func (r *trendingRepository) ReplaceTopics(scenarioID uint64, topics []*model.OpinionTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scenario_id = ?", scenarioID).Delete(&model.OpinionTopic{}).Error; err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(topics, 100).Error
+	})
+}
+
+// GetTopics 获取场景话题，按热度得分降序
+func (r *trendingRepository) GetTopics(scenarioID uint64, limit int) ([]*model.OpinionTopic, error) {
+	var topics []*model.OpinionTopic
+	err := r.db.Where("scenario_id = ?", scenarioID).Order("score DESC, size DESC").Limit(limit).Find(&topics).Error
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
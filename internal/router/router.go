@@ -1,6 +1,7 @@
 package router
 
 import (
+	"sentinel-opinion-monitor/internal/config"
 	"sentinel-opinion-monitor/internal/handler"
 	"sentinel-opinion-monitor/internal/middleware"
 	"sentinel-opinion-monitor/internal/repository"
@@ -33,12 +34,80 @@ func SetupRouter() *gin.Engine {
 	permissionService := service.NewPermissionService(permissionRepo)
 	permissionHandler := handler.NewPermissionHandler(permissionService)
 
+	// 场景、监测组（情感词典、分词词典和舆情依赖）
+	scenarioRepo := repository.NewScenarioRepository()
+	groupRepo := repository.NewMonitoringGroupRepository()
+
+	// 情感分析
+	sentimentLexiconRepo := repository.NewSentimentLexiconRepository()
+	sentimentService := service.NewSentimentService(sentimentLexiconRepo, scenarioRepo)
+	sentimentHandler := handler.NewSentimentHandler(sentimentService)
+
+	// 分词（监测组关键词和排除词自动加入用户词典）
+	var segmentCfg *config.SegmentConfig
+	if cfg := config.Get(); cfg != nil {
+		segmentCfg = &cfg.Segment
+	}
+	segmentService := service.NewSegmentService(groupRepo, segmentCfg)
+	segmentHandler := handler.NewSegmentHandler(segmentService)
+
+	// 舆情富化（情感、实体、主题、关键词）
+	var analysisCfg *config.AnalysisConfig
+	if cfg := config.Get(); cfg != nil {
+		analysisCfg = &cfg.Analysis
+	}
+	enrichmentService := service.NewEnrichmentService(analysisCfg, sentimentService, segmentService)
+
+	// 作者和场景关注的作者
+	authorRepo := repository.NewAuthorRepository()
+	var authorCfg *config.AuthorConfig
+	if cfg := config.Get(); cfg != nil {
+		authorCfg = &cfg.Author
+	}
+	authorService := service.NewAuthorService(authorCfg, authorRepo, scenarioRepo)
+	authorHandler := handler.NewAuthorHandler(authorService)
+
 	// 舆情相关
 	opinionRepo := repository.NewOpinionRepository()
-	opinionService := service.NewOpinionService(opinionRepo)
+	opinionService := service.NewOpinionService(opinionRepo, enrichmentService, authorService)
 	opinionHandler := handler.NewOpinionHandler(opinionService)
 	pingHandler := handler.NewPingHandler()
 
+	// 舆情全文检索（启用内嵌索引时在后台同步新增和变更的舆情）
+	var searchCfg *config.SearchConfig
+	if cfg := config.Get(); cfg != nil {
+		searchCfg = &cfg.Search
+	}
+	searchService := service.NewSearchService(searchCfg, opinionRepo, repository.NewOpinionHitRepository(), groupRepo)
+	searchHandler := handler.NewSearchHandler(searchService)
+
+	// 舆情导出
+	var exportCfg *config.ExportConfig
+	if cfg := config.Get(); cfg != nil {
+		exportCfg = &cfg.Export
+	}
+	exportService := service.NewExportService(exportCfg, repository.NewExportTaskRepository(), opinionRepo)
+	exportHandler := handler.NewExportHandler(exportService)
+
+	// 噪音过滤器（按相关性标注训练，对新命中打分并屏蔽疑似噪音）
+	var noiseFilterCfg *config.NoiseFilterConfig
+	if cfg := config.Get(); cfg != nil {
+		noiseFilterCfg = &cfg.NoiseFilter
+	}
+	noiseFilterService := service.NewNoiseFilterService(noiseFilterCfg, repository.NewNoiseFilterRepository(), groupRepo, repository.NewOpinionHitRepository(), segmentService)
+
+	// 站内通知
+	inboxService := service.NewInboxService(repository.NewInboxRepository())
+	inboxHandler := handler.NewInboxHandler(inboxService)
+
+	// 舆情处置
+	var workflowCfg *config.WorkflowConfig
+	if cfg := config.Get(); cfg != nil {
+		workflowCfg = &cfg.Workflow
+	}
+	handlingService := service.NewHandlingService(workflowCfg, opinionRepo, repository.NewOpinionHandlingRepository(), userRepo, inboxService)
+	handlingHandler := handler.NewHandlingHandler(handlingService)
+
 	// 标签管理
 	tagRepo := repository.NewTagRepository()
 	tagService := service.NewTagService(tagRepo)
@@ -50,14 +119,104 @@ func SetupRouter() *gin.Engine {
 	channelHandler := handler.NewChannelHandler(channelService)
 
 	// 场景管理
-	scenarioRepo := repository.NewScenarioRepository()
 	scenarioService := service.NewScenarioService(scenarioRepo, tagRepo)
 	scenarioHandler := handler.NewScenarioHandler(scenarioService)
 
 	// 监测组管理
-	groupRepo := repository.NewMonitoringGroupRepository()
-	groupService := service.NewMonitoringGroupService(groupRepo, scenarioRepo)
-	groupHandler := handler.NewMonitoringGroupHandler(groupService)
+	groupService := service.NewMonitoringGroupService(groupRepo, scenarioRepo, authorRepo)
+	var previewCfg *config.PreviewConfig
+	if cfg := config.Get(); cfg != nil {
+		previewCfg = &cfg.Preview
+	}
+	groupPreviewService := service.NewGroupPreviewService(previewCfg, groupRepo, channelRepo, opinionRepo, authorRepo, segmentService)
+	var keywordStatCfg *config.KeywordStatConfig
+	if cfg := config.Get(); cfg != nil {
+		keywordStatCfg = &cfg.KeywordStat
+	}
+	keywordStatService := service.NewKeywordStatService(keywordStatCfg, groupRepo, repository.NewOpinionHitRepository(), segmentService)
+	groupHandler := handler.NewMonitoringGroupHandler(groupService, groupPreviewService, keywordStatService)
+	noiseFilterHandler := handler.NewNoiseFilterHandler(noiseFilterService)
+
+	// 热词和话题
+	var trendingCfg *config.TrendingConfig
+	if cfg := config.Get(); cfg != nil {
+		trendingCfg = &cfg.Trending
+	}
+	trendingService := service.NewTrendingService(trendingCfg, repository.NewTrendingRepository(), repository.NewOpinionHitRepository(), opinionRepo, scenarioRepo, segmentService)
+	trendingHandler := handler.NewTrendingHandler(trendingService)
+
+	// 场景统计看板
+	var statsCfg *config.StatsConfig
+	if cfg := config.Get(); cfg != nil {
+		statsCfg = &cfg.Stats
+	}
+	statsService := service.NewStatsService(statsCfg, repository.NewStatsRepository(), scenarioRepo, groupRepo, channelRepo)
+	statsHandler := handler.NewStatsHandler(statsService)
+	comparisonService := service.NewComparisonService(repository.NewComparisonSetRepository(), scenarioRepo, statsService)
+	comparisonHandler := handler.NewComparisonHandler(comparisonService)
+
+	// 舆情批量导入（导入的舆情按所有启用的监测组匹配，完成后补算统计汇总）
+	var importCfg *config.ImportConfig
+	if cfg := config.Get(); cfg != nil {
+		importCfg = &cfg.Import
+	}
+	importService := service.NewImportService(importCfg, repository.NewImportTaskRepository(), opinionRepo, groupRepo, repository.NewOpinionHitRepository(), enrichmentService, service.NewMatchService(segmentService, authorRepo), noiseFilterService, authorService, statsService)
+	importHandler := handler.NewImportHandler(importService)
+
+	// 监测组实时计数
+	var liveCfg *config.LiveConfig
+	if cfg := config.Get(); cfg != nil {
+		liveCfg = &cfg.Live
+	}
+	liveStatsService := service.NewLiveStatsService(liveCfg, repository.NewLiveStatsRepository(), groupRepo)
+	liveStatsHandler := handler.NewLiveStatsHandler(liveStatsService)
+
+	// 告警
+	policyRepo := repository.NewEscalationPolicyRepository()
+	alertService := service.NewAlertService(repository.NewAlertRuleRepository(), repository.NewAlertEventRepository(), repository.NewAlertEventLogRepository(), policyRepo, repository.NewOpinionHitRepository(), scenarioRepo, groupRepo, segmentService)
+	alertHandler := handler.NewAlertHandler(alertService)
+
+	// 实时推送连接（一次性连接票据、WebSocket 来源校验）
+	var streamCfg *config.StreamConfig
+	var allowedOrigins []string
+	if cfg := config.Get(); cfg != nil {
+		streamCfg = &cfg.Stream
+		allowedOrigins = cfg.Stream.AllowedOrigins
+	}
+	streamTicketService := service.NewStreamTicketService(streamCfg)
+	streamTicketHandler := handler.NewStreamTicketHandler(streamTicketService)
+
+	// 命中舆情实时推送
+	feedService := service.NewFeedService(repository.NewOpinionHitRepository(), scenarioRepo, groupRepo)
+	feedHandler := handler.NewFeedHandler(feedService, allowedOrigins)
+
+	// 通知渠道
+	var notifyCfg *config.NotifyConfig
+	if cfg := config.Get(); cfg != nil {
+		notifyCfg = &cfg.Notify
+	}
+	notificationService := service.NewNotificationService(notifyCfg, repository.NewNotificationChannelRepository(), repository.NewNotificationDeliveryRepository())
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// 场景简报
+	var reportCfg *config.ReportConfig
+	if cfg := config.Get(); cfg != nil {
+		reportCfg = &cfg.Report
+	}
+	reportService := service.NewReportService(reportCfg, repository.NewReportTemplateRepository(), repository.NewReportRepository(), scenarioRepo, opinionRepo, statsService, trendingService, notificationService)
+	reportHandler := handler.NewReportHandler(reportService)
+
+	// 保存的检索和订阅推送
+	var savedSearchCfg *config.SavedSearchConfig
+	if cfg := config.Get(); cfg != nil {
+		savedSearchCfg = &cfg.SavedSearch
+	}
+	savedSearchService := service.NewSavedSearchService(savedSearchCfg, repository.NewSavedSearchRepository(), opinionRepo, userRepo, searchService, notificationService, inboxService)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService)
+
+	// 值班和升级策略
+	onCallService := service.NewOnCallService(policyRepo, repository.NewOnCallScheduleRepository(), userRepo, repository.NewNotificationChannelRepository())
+	onCallHandler := handler.NewOnCallHandler(onCallService)
 
 	// 公开路由（无需认证）
 	public := r.Group("/api/v1")
@@ -71,6 +230,18 @@ func SetupRouter() *gin.Engine {
 			auth.POST("/register", authHandler.Register) // 用户注册
 			auth.POST("/login", authHandler.Login)       // 用户登录
 		}
+
+		// 导出文件下载（通过地址中的签名和有效期校验）
+		public.GET("/exports/:id/download", exportHandler.DownloadExport)
+	}
+
+	// 实时推送路由（需要认证，支持通过 ticket 查询参数传递一次性连接票据）
+	streams := r.Group("/api/v1")
+	streams.Use(middleware.StreamAuthMiddleware(streamTicketHandler))
+	{
+		streams.GET("/inbox/stream", inboxHandler.Stream)   // 站内通知实时推送（Server-Sent Events）
+		streams.GET("/opinions/stream", feedHandler.Stream) // 命中舆情实时推送（Server-Sent Events）
+		streams.GET("/opinions/ws", feedHandler.WebSocket)  // 命中舆情实时推送（WebSocket）
 	}
 
 	// 需要认证的路由
@@ -78,8 +249,9 @@ func SetupRouter() *gin.Engine {
 	protected.Use(middleware.AuthMiddleware())
 	{
 		// 当前用户信息
-		protected.GET("/auth/me", authHandler.GetUserInfo)          // 获取当前用户信息
-		protected.PUT("/auth/password", userHandler.ChangePassword) // 修改密码
+		protected.GET("/auth/me", authHandler.GetUserInfo)               // 获取当前用户信息
+		protected.PUT("/auth/password", userHandler.ChangePassword)      // 修改密码
+		protected.POST("/auth/stream-ticket", streamTicketHandler.Issue) // 获取实时推送连接票据
 
 		// 用户管理（需要管理员权限）
 		users := protected.Group("/users")
@@ -116,14 +288,70 @@ func SetupRouter() *gin.Engine {
 			permissions.DELETE("/:id", permissionHandler.DeletePermission) // 删除权限
 		}
 
+		// 站内通知（需要认证，只能访问自己的通知）
+		inbox := protected.Group("/inbox")
+		{
+			inbox.GET("", inboxHandler.List)                     // 获取通知列表（支持 unread=true）
+			inbox.GET("/unread-count", inboxHandler.UnreadCount) // 获取未读数
+			inbox.POST("/read", inboxHandler.MarkRead)           // 标记指定通知为已读
+			inbox.POST("/read-all", inboxHandler.MarkAllRead)    // 全部标记为已读
+		}
+
+		// 舆情导出任务（需要认证，只能查看自己创建的任务）
+		exports := protected.Group("/exports")
+		{
+			exports.POST("", exportHandler.CreateExport) // 创建异步导出任务（CSV/XLSX/NDJSON）
+			exports.GET("", exportHandler.GetExports)    // 获取导出任务列表
+			exports.GET("/:id", exportHandler.GetExport) // 获取导出任务进度和下载地址
+		}
+
+		// 舆情批量导入（需要管理员权限）
+		imports := protected.Group("/imports")
+		imports.Use(middleware.RequireRole("admin"))
+		{
+			imports.POST("", importHandler.CreateImport)                   // 上传文件并创建导入任务（CSV/XLSX/NDJSON）
+			imports.GET("", importHandler.GetImports)                      // 获取导入任务列表
+			imports.GET("/:id", importHandler.GetImport)                   // 获取导入任务进度和结果
+			imports.GET("/:id/result", importHandler.DownloadImportResult) // 下载未导入的行及原因
+		}
+
 		// 舆情相关接口（需要认证）
 		opinions := protected.Group("/opinions")
 		{
-			opinions.GET("", opinionHandler.GetAllOpinions) // 获取舆情列表
-			opinions.POST("", opinionHandler.CreateOpinion) // 创建舆情
-			opinions.GET("/:id", opinionHandler.GetOpinion) // 获取舆情详情
+			opinions.GET("", opinionHandler.GetAllOpinions)                    // 获取舆情列表（支持筛选，可选分页）
+			opinions.POST("", opinionHandler.CreateOpinion)                    // 创建舆情
+			opinions.GET("/sentiment-stats", opinionHandler.GetSentimentStats) // 按情感标签聚合
+			opinions.GET("/search", searchHandler.SearchOpinions)              // 全文检索舆情内容（支持列表的筛选参数）
+			opinions.GET("/my-queue", handlingHandler.MyQueue)                 // 获取分配给我的待办舆情
+			opinions.GET("/export", exportHandler.ExportOpinions)              // 直接下载舆情（CSV/NDJSON，支持列表的筛选参数）
+			opinions.GET("/:id", opinionHandler.GetOpinion)                    // 获取舆情详情
+			opinions.POST("/:id/assign", handlingHandler.Assign)               // 分配处理人
+			opinions.POST("/:id/transition", handlingHandler.Transition)       // 变更处置状态
+			opinions.POST("/:id/triage", handlingHandler.Triage)               // 调整优先级和截止时间
+			opinions.GET("/:id/comments", handlingHandler.GetComments)         // 获取内部评论
+			opinions.POST("/:id/comments", handlingHandler.AddComment)         // 添加内部评论
+			opinions.GET("/:id/history", handlingHandler.GetHistory)           // 获取处置记录
 		}
 
+		// 情感分析
+		sentimentGroup := protected.Group("/sentiment")
+		{
+			sentimentGroup.GET("/lexicons", sentimentHandler.GetLexiconEntries) // 获取自定义词条列表
+			sentimentGroup.POST("/analyze", sentimentHandler.Analyze)           // 试算文本情感
+		}
+
+		// 情感词典管理（需要管理员权限）
+		sentimentAdmin := protected.Group("/sentiment")
+		sentimentAdmin.Use(middleware.RequireRole("admin"))
+		{
+			sentimentAdmin.POST("/lexicons", sentimentHandler.CreateLexiconEntry)       // 创建自定义词条
+			sentimentAdmin.PUT("/lexicons/:id", sentimentHandler.UpdateLexiconEntry)    // 更新自定义词条
+			sentimentAdmin.DELETE("/lexicons/:id", sentimentHandler.DeleteLexiconEntry) // 删除自定义词条
+		}
+
+		// 分词（需要认证）
+		protected.POST("/segment", segmentHandler.Segment) // 试算分词和关键词提取
+
 		// 标签管理（查看需要认证，增删改需要admin权限）
 		tags := protected.Group("/tags")
 		{
@@ -156,37 +384,189 @@ func SetupRouter() *gin.Engine {
 			channelsAdmin.DELETE("/:id", channelHandler.DeleteChannel) // 删除渠道
 		}
 
+		// 作者（需要认证）
+		authors := protected.Group("/authors")
+		{
+			authors.GET("", authorHandler.GetAuthors)    // 获取作者列表（支持按渠道、认证、影响力筛选和排序）
+			authors.GET("/:id", authorHandler.GetAuthor) // 获取作者详情
+		}
+
 		// 场景管理（查看需要认证，增删改需要admin权限）
 		scenarios := protected.Group("/scenarios")
 		{
 			scenarios.GET("", scenarioHandler.GetScenarios)                     // 获取场景列表
 			scenarios.GET("/:id", scenarioHandler.GetScenario)                  // 获取场景详情
 			scenarios.GET("/:id/groups", scenarioHandler.GetScenarioWithGroups) // 获取场景及其监测组
+			scenarios.GET("/:id/trending", trendingHandler.GetTrending)         // 获取场景热词
+			scenarios.GET("/:id/topics", trendingHandler.GetTopics)             // 获取场景话题
+			scenarios.GET("/:id/stats", statsHandler.GetScenarioStats)          // 获取场景统计看板数据
+			scenarios.GET("/compare", statsHandler.CompareScenarios)            // 多场景对比（声量份额、情感、渠道、走势）
+			scenarios.GET("/:id/author-watches", authorHandler.GetWatches)      // 获取场景关注的作者
 		}
 
 		// 场景管理（需要管理员权限）
 		scenariosAdmin := protected.Group("/scenarios")
 		scenariosAdmin.Use(middleware.RequireRole("admin"))
 		{
-			scenariosAdmin.POST("", scenarioHandler.CreateScenario)       // 创建场景
-			scenariosAdmin.PUT("/:id", scenarioHandler.UpdateScenario)    // 更新场景
-			scenariosAdmin.DELETE("/:id", scenarioHandler.DeleteScenario) // 删除场景
+			scenariosAdmin.POST("", scenarioHandler.CreateScenario)                            // 创建场景
+			scenariosAdmin.PUT("/:id", scenarioHandler.UpdateScenario)                         // 更新场景
+			scenariosAdmin.DELETE("/:id", scenarioHandler.DeleteScenario)                      // 删除场景
+			scenariosAdmin.POST("/:id/author-watches", authorHandler.AddWatch)                 // 关注作者
+			scenariosAdmin.DELETE("/:id/author-watches/:author_id", authorHandler.RemoveWatch) // 取消关注作者
+		}
+
+		// 告警规则（需要认证）
+		alertRules := protected.Group("/alert-rules")
+		{
+			alertRules.GET("", alertHandler.GetRules)    // 获取告警规则列表
+			alertRules.GET("/:id", alertHandler.GetRule) // 获取告警规则详情
+		}
+
+		// 告警规则管理（需要管理员权限）
+		alertRulesAdmin := protected.Group("/alert-rules")
+		alertRulesAdmin.Use(middleware.RequireRole("admin"))
+		{
+			alertRulesAdmin.POST("", alertHandler.CreateRule)              // 创建告警规则
+			alertRulesAdmin.PUT("/:id", alertHandler.UpdateRule)           // 更新告警规则
+			alertRulesAdmin.DELETE("/:id", alertHandler.DeleteRule)        // 删除告警规则
+			alertRulesAdmin.POST("/:id/silence", alertHandler.SilenceRule) // 静默告警规则
+			alertRulesAdmin.POST("/:id/dry-run", alertHandler.DryRunRule)  // 试运行已保存的规则
+			alertRulesAdmin.POST("/dry-run", alertHandler.DryRun)          // 试运行未保存的规则
+		}
+
+		// 告警事件（需要认证）
+		alertEvents := protected.Group("/alert-events")
+		{
+			alertEvents.GET("", alertHandler.GetEvents)                 // 获取告警事件列表
+			alertEvents.GET("/:id", alertHandler.GetEvent)              // 获取告警事件详情
+			alertEvents.GET("/:id/timeline", alertHandler.GetTimeline)  // 获取告警事件时间线
+			alertEvents.POST("/:id/ack", alertHandler.AcknowledgeEvent) // 认领告警
+			alertEvents.POST("/:id/resolve", alertHandler.ResolveEvent) // 手动恢复告警
+		}
+
+		// 竞品对比组（需要认证，修改和删除限创建人或管理员）
+		comparisons := protected.Group("/comparisons")
+		{
+			comparisons.GET("", comparisonHandler.GetComparisons)                 // 获取对比组列表
+			comparisons.GET("/:id", comparisonHandler.GetComparison)              // 获取对比组详情
+			comparisons.GET("/:id/report", comparisonHandler.GetComparisonReport) // 获取对比组的对比数据
+			comparisons.POST("", comparisonHandler.CreateComparison)              // 创建对比组
+			comparisons.PUT("/:id", comparisonHandler.UpdateComparison)           // 更新对比组
+			comparisons.DELETE("/:id", comparisonHandler.DeleteComparison)        // 删除对比组
+		}
+
+		// 保存的检索（需要认证，仅创建人可见的检索对其他用户不可见，修改和删除限创建人或管理员）
+		savedSearches := protected.Group("/saved-searches")
+		{
+			savedSearches.GET("", savedSearchHandler.GetSavedSearches)                // 获取可见的检索列表（支持mine=true）
+			savedSearches.GET("/:id", savedSearchHandler.GetSavedSearch)              // 获取保存的检索详情
+			savedSearches.GET("/:id/run", savedSearchHandler.RunSavedSearch)          // 执行保存的检索
+			savedSearches.POST("", savedSearchHandler.CreateSavedSearch)              // 保存检索
+			savedSearches.PUT("/:id", savedSearchHandler.UpdateSavedSearch)           // 更新保存的检索
+			savedSearches.DELETE("/:id", savedSearchHandler.DeleteSavedSearch)        // 删除保存的检索
+			savedSearches.PUT("/:id/subscription", savedSearchHandler.Subscribe)      // 订阅或修改订阅（推送到通知渠道需要管理员权限）
+			savedSearches.DELETE("/:id/subscription", savedSearchHandler.Unsubscribe) // 取消订阅
+		}
+
+		// 简报模板（查看需要认证，增删改和立即生成需要admin权限）
+		reportTemplates := protected.Group("/report-templates")
+		{
+			reportTemplates.GET("", reportHandler.GetTemplates)    // 获取简报模板列表（支持scenario_id查询参数）
+			reportTemplates.GET("/:id", reportHandler.GetTemplate) // 获取简报模板详情
 		}
 
-		// 监测组管理（查看需要认证，增删改需要admin权限）
+		// 简报模板（需要管理员权限）
+		reportTemplatesAdmin := protected.Group("/report-templates")
+		reportTemplatesAdmin.Use(middleware.RequireRole("admin"))
+		{
+			reportTemplatesAdmin.POST("", reportHandler.CreateTemplate)       // 创建简报模板
+			reportTemplatesAdmin.PUT("/:id", reportHandler.UpdateTemplate)    // 更新简报模板
+			reportTemplatesAdmin.DELETE("/:id", reportHandler.DeleteTemplate) // 删除简报模板
+			reportTemplatesAdmin.POST("/:id/run", reportHandler.RunTemplate)  // 立即生成简报
+		}
+
+		// 已生成的简报（需要认证）
+		reports := protected.Group("/reports")
+		{
+			reports.GET("", reportHandler.GetReports)                  // 获取简报列表
+			reports.GET("/:id", reportHandler.GetReport)               // 获取简报详情
+			reports.GET("/:id/download", reportHandler.DownloadReport) // 下载简报文件
+		}
+
+		// 升级策略（需要认证）
+		policies := protected.Group("/escalation-policies")
+		{
+			policies.GET("", onCallHandler.GetPolicies)   // 获取升级策略列表
+			policies.GET("/:id", onCallHandler.GetPolicy) // 获取升级策略详情
+		}
+
+		// 升级策略管理（需要管理员权限）
+		policiesAdmin := protected.Group("/escalation-policies")
+		policiesAdmin.Use(middleware.RequireRole("admin"))
+		{
+			policiesAdmin.POST("", onCallHandler.CreatePolicy)       // 创建升级策略
+			policiesAdmin.PUT("/:id", onCallHandler.UpdatePolicy)    // 更新升级策略
+			policiesAdmin.DELETE("/:id", onCallHandler.DeletePolicy) // 删除升级策略
+		}
+
+		// 值班表（需要认证）
+		schedules := protected.Group("/oncall-schedules")
+		{
+			schedules.GET("", onCallHandler.GetSchedules)                 // 获取值班表列表
+			schedules.GET("/:id", onCallHandler.GetSchedule)              // 获取值班表详情
+			schedules.GET("/:id/current", onCallHandler.GetCurrentOnCall) // 获取当前值班用户
+			schedules.GET("/:id/overrides", onCallHandler.GetOverrides)   // 获取替班列表
+		}
+
+		// 值班表管理（需要管理员权限）
+		schedulesAdmin := protected.Group("/oncall-schedules")
+		schedulesAdmin.Use(middleware.RequireRole("admin"))
+		{
+			schedulesAdmin.POST("", onCallHandler.CreateSchedule)                              // 创建值班表
+			schedulesAdmin.PUT("/:id", onCallHandler.UpdateSchedule)                           // 更新值班表
+			schedulesAdmin.DELETE("/:id", onCallHandler.DeleteSchedule)                        // 删除值班表
+			schedulesAdmin.POST("/:id/overrides", onCallHandler.CreateOverride)                // 创建替班
+			schedulesAdmin.DELETE("/:id/overrides/:override_id", onCallHandler.DeleteOverride) // 删除替班
+		}
+
+		// 通知渠道管理（需要管理员权限）
+		notifyChannels := protected.Group("/notification-channels")
+		notifyChannels.Use(middleware.RequireRole("admin"))
+		{
+			notifyChannels.POST("", notificationHandler.CreateChannel)        // 创建通知渠道
+			notifyChannels.GET("", notificationHandler.GetChannels)           // 获取通知渠道列表
+			notifyChannels.GET("/:id", notificationHandler.GetChannel)        // 获取通知渠道详情
+			notifyChannels.PUT("/:id", notificationHandler.UpdateChannel)     // 更新通知渠道
+			notifyChannels.DELETE("/:id", notificationHandler.DeleteChannel)  // 删除通知渠道
+			notifyChannels.POST("/:id/test", notificationHandler.TestChannel) // 发送测试消息
+		}
+
+		// 通知发送记录（需要管理员权限）
+		deliveries := protected.Group("/notification-deliveries")
+		deliveries.Use(middleware.RequireRole("admin"))
+		{
+			deliveries.GET("", notificationHandler.GetDeliveries) // 获取发送记录列表
+		}
+
+		// 监测组管理（查看需要认证，增删改需要user角色）
 		groups := protected.Group("/monitoring-groups")
 		{
-			groups.GET("/scenario/:scenario_id", groupHandler.GetGroupsByScenario) // 根据场景ID获取监测组列表
-			groups.GET("/:id", groupHandler.GetGroup)                              // 获取监测组详情
-			groups.GET("/:id/keywords", groupHandler.GetKeywords)                  // 获取关键词列表
-			groups.GET("/:id/exclusion-words", groupHandler.GetExclusionWords)     // 获取排除词列表
+			groups.GET("/scenario/:scenario_id", groupHandler.GetGroupsByScenario)   // 根据场景ID获取监测组列表
+			groups.GET("/:id", groupHandler.GetGroup)                                // 获取监测组详情
+			groups.GET("/:id/keywords", groupHandler.GetKeywords)                    // 获取关键词列表（含命中统计和排除词统计）
+			groups.GET("/:id/exclusion-words", groupHandler.GetExclusionWords)       // 获取排除词列表
+			groups.GET("/:id/source-filters", groupHandler.GetSourceFilters)         // 获取来源过滤规则（作者、域名、链接模式的允许/屏蔽名单）
+			groups.GET("/:id/live", liveStatsHandler.GetGroupLiveStats)              // 获取监测组实时计数
+			groups.GET("/:id/noise-filter", noiseFilterHandler.GetNoiseFilter)       // 获取噪音过滤器设置和训练状态
+			groups.GET("/:id/suppressed-hits", noiseFilterHandler.GetSuppressedHits) // 获取被噪音过滤器屏蔽的命中（供复核）
 		}
 
-		// 监测组管理（需要管理员权限）
+		// 监测组管理（需要 user 角色）
 		groupsAdmin := protected.Group("/monitoring-groups")
 		groupsAdmin.Use(middleware.RequireRole("user"))
 		{
 			groupsAdmin.POST("", groupHandler.CreateGroup)                                        // 创建监测组
+			groupsAdmin.POST("/preview", groupHandler.PreviewGroup)                               // 按历史舆情预览关键词和排除词的命中效果（不保存）
 			groupsAdmin.PUT("/:id", groupHandler.UpdateGroup)                                     // 更新监测组
 			groupsAdmin.DELETE("/:id", groupHandler.DeleteGroup)                                  // 删除监测组
 			groupsAdmin.POST("/:id/channels", groupHandler.AssignChannels)                        // 分配渠道
@@ -194,6 +574,18 @@ func SetupRouter() *gin.Engine {
 			groupsAdmin.DELETE("/:id/keywords/:keyword_id", groupHandler.RemoveKeyword)           // 删除关键词
 			groupsAdmin.POST("/:id/exclusion-words", groupHandler.AddExclusionWord)               // 添加排除词
 			groupsAdmin.DELETE("/:id/exclusion-words/:word_id", groupHandler.RemoveExclusionWord) // 删除排除词
+			groupsAdmin.POST("/:id/source-filters", groupHandler.AddSourceFilter)                 // 添加来源过滤规则
+			groupsAdmin.DELETE("/:id/source-filters/:filter_id", groupHandler.RemoveSourceFilter) // 删除来源过滤规则
+			groupsAdmin.PUT("/:id/noise-filter", noiseFilterHandler.UpdateNoiseFilter)            // 更新噪音过滤器设置
+			groupsAdmin.POST("/:id/noise-filter/train", noiseFilterHandler.TrainNoiseFilter)      // 立即训练噪音过滤器
+		}
+
+		// 命中相关性标注和关键词建议（需要 admin 或 user 角色）
+		groupHits := protected.Group("/monitoring-groups")
+		groupHits.Use(middleware.RequireRole("admin", "user"))
+		{
+			groupHits.PUT("/:id/hits/:opinion_id/relevance", groupHandler.MarkHitRelevance) // 标注命中舆情是否相关（也用于恢复被噪音过滤器屏蔽的命中）
+			groupHits.GET("/:id/keyword-suggestions", groupHandler.GetKeywordSuggestions)   // 根据相关性标注建议关键词和排除词
 		}
 	}
 
@@ -0,0 +1,503 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/mapping"
+	blevesearch "github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+const (
+	// currentFile 记录当前使用的索引版本
+	currentFile = "CURRENT"
+
+	// generationLayout 索引版本目录名（按创建时间）
+	generationLayout = "20060102150405.000000"
+
+	// checkpointKey 同步进度在索引中的保存位置
+	checkpointKey = "checkpoint"
+
+	// minTermLength 内嵌索引按二元分词建立，短于该长度的检索词无法匹配
+	minTermLength = 2
+)
+
+// openConfig 打开索引的参数：索引文件被其他进程占用时等待的时长
+var openConfig = map[string]interface{}{"bolt_timeout": "5s"}
+
+// Index 内嵌检索索引（bleve）
+// 同一索引版本只能由一个进程打开，多个 Web 实例需要使用各自的索引目录。
+type Index struct {
+	dir string
+
+	mu         sync.RWMutex
+	generation string
+	index      bleve.Index
+	closed     bool
+	done       chan struct{}
+}
+
+// IndexDir 内嵌索引的存储目录
+func IndexDir(cfg *config.SearchConfig) string {
+	if cfg == nil || cfg.IndexDir == "" {
+		return "data/search"
+	}
+	return cfg.IndexDir
+}
+
+// Open 打开 dir 下的当前索引版本，没有时创建一个空索引并设为当前版本；同时删除比当前版本更早的版本
+func Open(dir string) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建索引目录失败: %w", err)
+	}
+	generation, err := readCurrent(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var index bleve.Index
+	if generation == "" {
+		generation = time.Now().Format(generationLayout)
+		if index, err = newBleveIndex(filepath.Join(dir, generation)); err != nil {
+			return nil, err
+		}
+		if err := writeCurrent(dir, generation); err != nil {
+			index.Close()
+			return nil, err
+		}
+	} else if index, err = bleve.OpenUsing(filepath.Join(dir, generation), openConfig); err != nil {
+		return nil, fmt.Errorf("打开索引 %s 失败: %w", generation, err)
+	}
+
+	removeOlderGenerations(dir, generation)
+	return &Index{dir: dir, generation: generation, index: index, done: make(chan struct{})}, nil
+}
+
+// Create 在 dir 下创建一个新的空索引版本，用于重建索引；写入完成后调用 Publish 设为当前版本。
+// 同时删除其他未发布的版本（之前中断的重建）
+func Create(dir string) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建索引目录失败: %w", err)
+	}
+	current, err := readCurrent(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, generation := range listGenerations(dir) {
+		if generation != current {
+			os.RemoveAll(filepath.Join(dir, generation))
+		}
+	}
+
+	generation := time.Now().Format(generationLayout)
+	index, err := newBleveIndex(filepath.Join(dir, generation))
+	if err != nil {
+		return nil, err
+	}
+	return &Index{dir: dir, generation: generation, index: index, done: make(chan struct{})}, nil
+}
+
+// Generation 索引版本
+func (i *Index) Generation() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.generation
+}
+
+// Done 索引关闭时关闭的通道
+func (i *Index) Done() <-chan struct{} {
+	return i.done
+}
+
+// Close 关闭索引
+func (i *Index) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	close(i.done)
+	return i.index.Close()
+}
+
+// Publish 关闭索引并把它设为当前版本，使用该目录的 Web 服务在下次同步时切换到该版本
+func (i *Index) Publish() error {
+	if err := i.Close(); err != nil {
+		return err
+	}
+	return writeCurrent(i.dir, i.generation)
+}
+
+// Reload 当前版本已被重建替换时切换到新版本，并删除旧版本；返回是否发生了切换
+func (i *Index) Reload() (bool, error) {
+	generation, err := readCurrent(i.dir)
+	if err != nil || generation == "" || generation == i.Generation() {
+		return false, err
+	}
+
+	index, err := bleve.OpenUsing(filepath.Join(i.dir, generation), openConfig)
+	if err != nil {
+		return false, fmt.Errorf("打开索引 %s 失败: %w", generation, err)
+	}
+
+	i.mu.Lock()
+	if i.closed {
+		i.mu.Unlock()
+		index.Close()
+		return false, ErrClosed
+	}
+	old := i.index
+	i.index = index
+	i.generation = generation
+	i.mu.Unlock()
+
+	old.Close()
+	removeOlderGenerations(i.dir, generation)
+	return true, nil
+}
+
+// Checkpoint 读取同步进度，新索引返回零值
+func (i *Index) Checkpoint() (Checkpoint, error) {
+	var cp Checkpoint
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return cp, ErrClosed
+	}
+	data, err := i.index.GetInternal([]byte(checkpointKey))
+	if err != nil || len(data) == 0 {
+		return cp, err
+	}
+	err = json.Unmarshal(data, &cp)
+	return cp, err
+}
+
+// Write 写入（覆盖）文档、删除 deleted 中的舆情并保存同步进度，在同一批次中完成
+func (i *Index) Write(docs []*Document, deleted []uint64, cp Checkpoint) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return ErrClosed
+	}
+
+	batch := i.index.NewBatch()
+	for _, doc := range docs {
+		if err := batch.Index(strconv.FormatUint(doc.ID, 10), doc.fields()); err != nil {
+			return err
+		}
+	}
+	for _, id := range deleted {
+		batch.Delete(strconv.FormatUint(id, 10))
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	batch.SetInternal([]byte(checkpointKey), data)
+	return i.index.Batch(batch)
+}
+
+// Delete 从索引中删除舆情
+func (i *Index) Delete(ids []uint64) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return ErrClosed
+	}
+	batch := i.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(strconv.FormatUint(id, 10))
+	}
+	return i.index.Batch(batch)
+}
+
+// Count 索引中的舆情数
+func (i *Index) Count() (uint64, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return 0, ErrClosed
+	}
+	return i.index.DocCount()
+}
+
+// Search 检索舆情，返回按相关度（或入库时间）排序的舆情 ID 和分面统计；
+// 筛选条件包含处置字段或有短于 2 个字符的检索词时返回 ErrUnsupported
+func (i *Index) Search(q Query) (*Result, error) {
+	conjuncts, err := filterQueries(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	textQuery, err := buildTextQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	conjuncts = append([]query.Query{textQuery}, conjuncts...)
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), q.PageSize, (q.Page-1)*q.PageSize, false)
+	if q.ByTime {
+		req.SortBy([]string{"-created_at", "-_score"})
+	} else {
+		req.SortBy([]string{"-_score", "-created_at"})
+	}
+	if q.Facets {
+		req.AddFacet("source", bleve.NewFacetRequest("source", q.FacetSize))
+		req.AddFacet("sentiment", bleve.NewFacetRequest("sentiment", q.FacetSize))
+		req.AddFacet("group", bleve.NewFacetRequest("group_ids", q.FacetSize))
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return nil, ErrClosed
+	}
+	res, err := i.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: int64(res.Total), Engine: Engine, Hits: make([]*Hit, 0, len(res.Hits))}
+	for _, doc := range res.Hits {
+		id, err := strconv.ParseUint(doc.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		result.Hits = append(result.Hits, &Hit{ID: id, Score: doc.Score})
+	}
+	if q.Facets {
+		result.Facets = &repository.OpinionFacets{
+			Source:    facetCounts(res.Facets["source"]),
+			Sentiment: facetCounts(res.Facets["sentiment"]),
+			Group:     facetCounts(res.Facets["group"]),
+		}
+	}
+	return result, nil
+}
+
+// fields 写入索引的字段
+func (d *Document) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"content":        d.Content,
+		"source":         d.Source,
+		"author":         d.Author,
+		"sentiment":      d.Sentiment,
+		"created_at":     d.CreatedAt,
+		"scenario_ids":   d.ScenarioIDs,
+		"group_ids":      d.GroupIDs,
+		"hit_sentiments": d.HitSentiments,
+	}
+}
+
+// newBleveIndex 在 path 创建空索引：内容使用 CJK 二元分词，其他字段按原值索引，均不保存原文
+func newBleveIndex(path string) (bleve.Index, error) {
+	content := bleve.NewTextFieldMapping()
+	content.Analyzer = cjk.AnalyzerName
+	content.Store = false
+	content.IncludeInAll = false
+	content.DocValues = false
+
+	keyword := func() *mapping.FieldMapping {
+		field := bleve.NewKeywordFieldMapping()
+		field.Store = false
+		field.IncludeInAll = false
+		field.IncludeTermVectors = false
+		return field
+	}
+	createdAt := bleve.NewDateTimeFieldMapping()
+	createdAt.Store = false
+	createdAt.IncludeInAll = false
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("content", content)
+	doc.AddFieldMappingsAt("created_at", createdAt)
+	for _, name := range []string{"source", "author", "sentiment", "scenario_ids", "group_ids", "hit_sentiments"} {
+		doc.AddFieldMappingsAt(name, keyword())
+	}
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = doc
+	indexMapping.DefaultField = "content"
+	indexMapping.StoreDynamic = false
+	indexMapping.IndexDynamic = false
+	indexMapping.DocValuesDynamic = false
+
+	index, err := bleve.NewUsing(path, indexMapping, bleve.Config.DefaultIndexType, bleve.Config.DefaultKVStore, openConfig)
+	if err != nil {
+		return nil, fmt.Errorf("创建索引失败: %w", err)
+	}
+	return index, nil
+}
+
+// buildTextQuery 把检索词转换为内容字段上的查询：每个词语按短语匹配，单个英文或数字词语可以前缀匹配
+func buildTextQuery(q Query) (query.Query, error) {
+	boolean := bleve.NewBooleanQuery()
+	hasMust := false
+	for _, term := range q.Terms {
+		if utf8.RuneCountInString(term.Text) < minTermLength {
+			return nil, ErrUnsupported
+		}
+
+		var termQuery query.Query
+		if term.Wildcard && !term.Phrase && isWord(term.Text) {
+			prefix := bleve.NewPrefixQuery(strings.ToLower(term.Text))
+			prefix.SetField("content")
+			termQuery = prefix
+		} else {
+			phrase := bleve.NewMatchPhraseQuery(term.Text)
+			phrase.SetField("content")
+			termQuery = phrase
+		}
+
+		switch {
+		case q.Boolean && term.Op == '+':
+			boolean.AddMust(termQuery)
+			hasMust = true
+		case q.Boolean && term.Op == '-':
+			boolean.AddMustNot(termQuery)
+		default:
+			boolean.AddShould(termQuery)
+		}
+	}
+	if !hasMust {
+		boolean.SetMinShould(1)
+	}
+	return boolean, nil
+}
+
+// filterQueries 把舆情列表的筛选条件转换为索引字段上的查询
+func filterQueries(filter repository.OpinionFilter) ([]query.Query, error) {
+	if filter.AuthorID > 0 || len(filter.HandlingStatuses) > 0 || filter.AssigneeID > 0 || filter.Priority != "" || filter.Overdue {
+		return nil, ErrUnsupported
+	}
+
+	var queries []query.Query
+	addTerm := func(field, value string) {
+		term := bleve.NewTermQuery(value)
+		term.SetField(field)
+		queries = append(queries, term)
+	}
+	if filter.ScenarioID > 0 {
+		addTerm("scenario_ids", strconv.FormatUint(filter.ScenarioID, 10))
+	}
+	if filter.GroupID > 0 {
+		addTerm("group_ids", strconv.FormatUint(filter.GroupID, 10))
+	}
+	if filter.Sentiment != "" {
+		switch {
+		case filter.GroupID > 0:
+			addTerm("hit_sentiments", groupSentiment(filter.GroupID, filter.Sentiment))
+		case filter.ScenarioID > 0:
+			addTerm("hit_sentiments", scenarioSentiment(filter.ScenarioID, filter.Sentiment))
+		default:
+			addTerm("sentiment", filter.Sentiment)
+		}
+	}
+	if filter.Source != "" {
+		addTerm("source", filter.Source)
+	}
+	if filter.StartTime != nil || filter.EndTime != nil {
+		var start, end time.Time
+		if filter.StartTime != nil {
+			start = *filter.StartTime
+		}
+		if filter.EndTime != nil {
+			end = *filter.EndTime
+		}
+		inclusiveStart, inclusiveEnd := true, false
+		dateRange := bleve.NewDateRangeInclusiveQuery(start, end, &inclusiveStart, &inclusiveEnd)
+		dateRange.SetField("created_at")
+		queries = append(queries, dateRange)
+	}
+	return queries, nil
+}
+
+// isWord 是否为单个不含 CJK 字符的词语（索引中按整词保存，可以前缀匹配）
+func isWord(text string) bool {
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return false
+		}
+	}
+	return true
+}
+
+// facetCounts 转换 bleve 的分面结果
+func facetCounts(facet *blevesearch.FacetResult) []*repository.FacetCount {
+	counts := make([]*repository.FacetCount, 0)
+	if facet == nil || facet.Terms == nil {
+		return counts
+	}
+	for _, term := range facet.Terms.Terms() {
+		counts = append(counts, &repository.FacetCount{Key: term.Term, Count: int64(term.Count)})
+	}
+	return counts
+}
+
+// readCurrent 读取当前索引版本，没有时返回空字符串
+func readCurrent(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, currentFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("读取当前索引版本失败: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeCurrent 原子地更新当前索引版本
+func writeCurrent(dir, generation string) error {
+	tmp := filepath.Join(dir, currentFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(generation+"\n"), 0644); err != nil {
+		return fmt.Errorf("写入当前索引版本失败: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, currentFile)); err != nil {
+		return fmt.Errorf("写入当前索引版本失败: %w", err)
+	}
+	return nil
+}
+
+// listGenerations 列出 dir 下的索引版本，按创建时间升序
+func listGenerations(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var generations []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(generationLayout, entry.Name()); err == nil {
+			generations = append(generations, entry.Name())
+		}
+	}
+	return generations
+}
+
+// removeOlderGenerations 删除比 current 更早的索引版本；更新的版本可能是正在进行的重建，保留
+func removeOlderGenerations(dir, current string) {
+	for _, generation := range listGenerations(dir) {
+		if generation < current {
+			os.RemoveAll(filepath.Join(dir, generation))
+		}
+	}
+}
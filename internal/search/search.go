@@ -0,0 +1,145 @@
+// Package search 实现舆情检索的内嵌索引，作为 MySQL FULLTEXT 索引之外的检索后端。
+//
+// 内嵌索引基于 bleve，舆情内容使用 CJK 二元分词，保存在本地磁盘，由 Web 服务在后台
+// 按舆情的更新时间和命中记录的创建时间增量同步。索引目录下每次重建产生一个新的版本子目录，
+// CURRENT 文件记录当前使用的版本，重建完成后 Web 服务自动切换到新版本。
+package search
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// 检索后端
+const (
+	BackendMySQL    = "mysql"    // MySQL FULLTEXT（ngram）索引
+	BackendEmbedded = "embedded" // 内嵌索引
+)
+
+// Engine 内嵌索引在检索结果中的名称
+const Engine = "embedded"
+
+// ErrUnsupported 内嵌索引无法处理的检索（如按处置状态筛选、检索词过短），调用方应改用 MySQL 检索
+var ErrUnsupported = errors.New("内嵌索引不支持该检索条件")
+
+// ErrClosed 索引已关闭
+var ErrClosed = errors.New("检索索引已关闭")
+
+// Term 检索串中的一个词语或短语
+type Term struct {
+	Text     string
+	Op       rune // '+' 必须包含，'-' 不能包含，0 可选
+	Phrase   bool
+	Wildcard bool // 前缀匹配
+}
+
+// Query 检索条件
+type Query struct {
+	Terms     []Term
+	Boolean   bool // false 为自然语言模式（各词语均为可选）
+	Filter    repository.OpinionFilter
+	ByTime    bool // true 按入库时间倒序，否则按相关度倒序
+	Page      int
+	PageSize  int
+	Facets    bool // 是否统计分面
+	FacetSize int  // 每个分面返回的条目数
+}
+
+// Hit 检索命中的一条舆情
+type Hit struct {
+	ID      uint64
+	Score   float64
+	Opinion *model.Opinion // 已加载的舆情；为空时由调用方按 ID 加载
+}
+
+// Result 检索结果
+type Result struct {
+	Hits   []*Hit
+	Total  int64
+	Engine string
+	Facets *repository.OpinionFacets
+}
+
+// Document 索引中的一条舆情
+type Document struct {
+	ID            uint64
+	Content       string
+	Source        string
+	Author        string
+	Sentiment     string
+	CreatedAt     time.Time
+	ScenarioIDs   []string
+	GroupIDs      []string
+	HitSentiments []string // 按场景词典计算的情感，如 s:1:negative（场景）、g:5:negative（监测组）
+}
+
+// NewDocument 根据舆情及其命中记录构造索引文档
+func NewDocument(opinion *model.Opinion, hits []*model.OpinionHit) *Document {
+	doc := &Document{
+		ID:        opinion.ID,
+		Content:   opinion.Content,
+		Source:    opinion.Source,
+		Author:    opinion.Author,
+		Sentiment: opinion.SentimentLabel,
+		CreatedAt: opinion.CreatedAt,
+	}
+	scenarios := make(map[uint64]bool)
+	for _, hit := range hits {
+		doc.GroupIDs = append(doc.GroupIDs, strconv.FormatUint(hit.GroupID, 10))
+		doc.HitSentiments = append(doc.HitSentiments, groupSentiment(hit.GroupID, hit.SentimentLabel))
+		if !scenarios[hit.ScenarioID] {
+			scenarios[hit.ScenarioID] = true
+			doc.ScenarioIDs = append(doc.ScenarioIDs, strconv.FormatUint(hit.ScenarioID, 10))
+			doc.HitSentiments = append(doc.HitSentiments, scenarioSentiment(hit.ScenarioID, hit.SentimentLabel))
+		}
+	}
+	return doc
+}
+
+// Checkpoint 增量同步的进度，同步时从该时间之前一小段开始，以覆盖提交较晚的事务
+type Checkpoint struct {
+	OpinionsUntil time.Time `json:"opinions_until"` // 已同步的舆情更新时间
+	HitsUntil     time.Time `json:"hits_until"`     // 已同步的命中记录创建时间
+}
+
+var defaultIndex *Index
+
+// Init 在 search.backend 为 embedded 时打开内嵌索引（不存在时创建），否则不做任何事
+func Init(cfg *config.SearchConfig) error {
+	if cfg == nil || cfg.Backend != BackendEmbedded {
+		return nil
+	}
+	index, err := Open(IndexDir(cfg))
+	if err != nil {
+		return err
+	}
+	defaultIndex = index
+	return nil
+}
+
+// Get 获取全局内嵌索引，未启用或未初始化时返回 nil
+func Get() *Index {
+	return defaultIndex
+}
+
+// Close 关闭全局内嵌索引
+func Close() {
+	if defaultIndex != nil {
+		_ = defaultIndex.Close()
+	}
+}
+
+// scenarioSentiment 场景维度的情感词项
+func scenarioSentiment(scenarioID uint64, label string) string {
+	return "s:" + strconv.FormatUint(scenarioID, 10) + ":" + label
+}
+
+// groupSentiment 监测组维度的情感词项
+func groupSentiment(groupID uint64, label string) string {
+	return "g:" + strconv.FormatUint(groupID, 10) + ":" + label
+}
@@ -45,6 +45,11 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// RegisterOnShutdown 注册关闭时执行的函数，用于通知长连接（如实时推送）尽快退出
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
 // Stop 停止服务器
 func (s *Server) Stop(ctx context.Context) error {
 	appLogger.Get().Info("服务器关闭中...")
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// maxAlertOpinions 告警事件最多记录的相关舆情数量
+const maxAlertOpinions = 20
+
+// alertFinding 单次评估中满足条件的告警
+type alertFinding struct {
+	DedupKey   string
+	Title      string
+	Value      float64
+	OpinionIDs []uint64
+}
+
+// evaluateAlertRule 用窗口内的命中舆情评估规则，previous 为上一窗口的命中舆情（仅增长率规则使用）
+// 关键词规则使用 segmenter 分词匹配
+func evaluateAlertRule(rule *model.AlertRule, current, previous []*repository.HitDocument, segmenter *segment.Segmenter) []alertFinding {
+	switch rule.Type {
+	case model.AlertRuleVolume:
+		count := len(current)
+		if count == 0 || float64(count) < rule.Threshold {
+			return nil
+		}
+		return []alertFinding{{
+			DedupKey:   ruleDedupKey(rule),
+			Title:      fmt.Sprintf("【%s】%d 分钟内命中舆情 %d 条，达到阈值 %s", rule.Name, rule.WindowMinutes, count, formatAlertNumber(rule.Threshold)),
+			Value:      float64(count),
+			OpinionIDs: documentIDs(current),
+		}}
+
+	case model.AlertRuleNegativeRatio:
+		total := len(current)
+		if total == 0 || total < rule.MinCount {
+			return nil
+		}
+		negatives := make([]*repository.HitDocument, 0)
+		for _, doc := range current {
+			if doc.SentimentLabel == "negative" {
+				negatives = append(negatives, doc)
+			}
+		}
+		ratio := float64(len(negatives)) / float64(total)
+		if ratio < rule.Threshold {
+			return nil
+		}
+		return []alertFinding{{
+			DedupKey:   ruleDedupKey(rule),
+			Title:      fmt.Sprintf("【%s】%d 分钟内负面舆情占比 %.1f%%（%d/%d），达到阈值 %.1f%%", rule.Name, rule.WindowMinutes, ratio*100, len(negatives), total, rule.Threshold*100),
+			Value:      roundAlertValue(ratio),
+			OpinionIDs: documentIDs(negatives),
+		}}
+
+	case model.AlertRuleEngagement:
+		findings := make([]alertFinding, 0)
+		for _, doc := range current {
+			if float64(doc.Engagement) < rule.Threshold {
+				continue
+			}
+			findings = append(findings, alertFinding{
+				DedupKey:   fmt.Sprintf("%s:opinion:%d", ruleDedupKey(rule), doc.OpinionID),
+				Title:      fmt.Sprintf("【%s】舆情互动量 %d，达到阈值 %s：%s", rule.Name, doc.Engagement, formatAlertNumber(rule.Threshold), summarize(doc.Content, 50)),
+				Value:      float64(doc.Engagement),
+				OpinionIDs: []uint64{doc.OpinionID},
+			})
+		}
+		return findings
+
+	case model.AlertRuleKeyword:
+		threshold := math.Max(rule.Threshold, 1)
+		tokens := make([]map[string]bool, len(current))
+		for i, doc := range current {
+			tokens[i] = tokenSet(segmenter.Cut(doc.Content))
+		}
+		findings := make([]alertFinding, 0)
+		for _, keyword := range rule.Keywords {
+			matched := make([]*repository.HitDocument, 0)
+			for i, doc := range current {
+				if termInTokens(keyword, tokens[i]) {
+					matched = append(matched, doc)
+				}
+			}
+			if float64(len(matched)) < threshold {
+				continue
+			}
+			findings = append(findings, alertFinding{
+				DedupKey:   fmt.Sprintf("%s:keyword:%s", ruleDedupKey(rule), strings.ToLower(keyword)),
+				Title:      fmt.Sprintf("【%s】%d 分钟内 %d 条舆情命中关键词「%s」", rule.Name, rule.WindowMinutes, len(matched), keyword),
+				Value:      float64(len(matched)),
+				OpinionIDs: documentIDs(matched),
+			})
+		}
+		return findings
+
+	case model.AlertRuleGrowth:
+		cur, prev := len(current), len(previous)
+		if cur == 0 || cur < rule.MinCount {
+			return nil
+		}
+		growth := float64(cur-prev) / math.Max(float64(prev), 1)
+		if growth < rule.Threshold {
+			return nil
+		}
+		return []alertFinding{{
+			DedupKey:   ruleDedupKey(rule),
+			Title:      fmt.Sprintf("【%s】%d 分钟内舆情 %d 条，较上一窗口 %d 条增长 %.1f%%，达到阈值 %.1f%%", rule.Name, rule.WindowMinutes, cur, prev, growth*100, rule.Threshold*100),
+			Value:      roundAlertValue(growth),
+			OpinionIDs: documentIDs(current),
+		}}
+	}
+	return nil
+}
+
+// applyFindings 根据评估结果推进事件状态：
+// 已有未恢复事件的去重键更新持续触发信息；新的去重键在非静默、非冷却期内触发新事件；
+// 不再满足条件的未恢复事件标记为已恢复。lastResolvedAt 返回去重键最近一次恢复的时间
+func applyFindings(rule *model.AlertRule, findings []alertFinding, open []*model.AlertEvent, lastResolvedAt func(dedupKey string) *time.Time, now time.Time) (fired, updated, resolved []*model.AlertEvent) {
+	openByKey := make(map[string]*model.AlertEvent, len(open))
+	for _, event := range open {
+		openByKey[event.DedupKey] = event
+	}
+
+	silenced := rule.SilenceUntil != nil && now.Before(*rule.SilenceUntil)
+	cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+	seen := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		seen[finding.DedupKey] = true
+
+		if event, ok := openByKey[finding.DedupKey]; ok {
+			event.Title = finding.Title
+			event.Value = finding.Value
+			event.OpinionIDs = model.IDList(finding.OpinionIDs)
+			event.FireCount++
+			event.LastSeenAt = now
+			updated = append(updated, event)
+			continue
+		}
+
+		if silenced {
+			continue
+		}
+		if t := lastResolvedAt(finding.DedupKey); t != nil && now.Sub(*t) < cooldown {
+			continue
+		}
+
+		fired = append(fired, &model.AlertEvent{
+			RuleID:     rule.ID,
+			ScenarioID: rule.ScenarioID,
+			GroupID:    rule.GroupID,
+			DedupKey:   finding.DedupKey,
+			Severity:   rule.Severity,
+			Status:     model.AlertStatusFiring,
+			Title:      finding.Title,
+			Value:      finding.Value,
+			Threshold:  rule.Threshold,
+			OpinionIDs: model.IDList(finding.OpinionIDs),
+			FireCount:  1,
+			FiredAt:    now,
+			LastSeenAt: now,
+		})
+	}
+
+	for _, event := range open {
+		if seen[event.DedupKey] {
+			continue
+		}
+		resolvedAt := now
+		event.Status = model.AlertStatusResolved
+		event.ResolvedAt = &resolvedAt
+		resolved = append(resolved, event)
+	}
+	return fired, updated, resolved
+}
+
+// ruleDedupKey 规则级别的去重键
+func ruleDedupKey(rule *model.AlertRule) string {
+	return fmt.Sprintf("rule:%d", rule.ID)
+}
+
+// documentIDs 返回舆情ID，最多 maxAlertOpinions 条
+func documentIDs(docs []*repository.HitDocument) []uint64 {
+	ids := make([]uint64, 0, len(docs))
+	for _, doc := range docs {
+		if len(ids) >= maxAlertOpinions {
+			break
+		}
+		ids = append(ids, doc.OpinionID)
+	}
+	return ids
+}
+
+// summarize 截取内容摘要
+func summarize(content string, limit int) string {
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit]) + "..."
+}
+
+// formatAlertNumber 格式化阈值，整数不带小数位
+func formatAlertNumber(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// roundAlertValue 保留四位小数
+func roundAlertValue(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultAlertWindow 默认告警统计窗口（分钟）
+	DefaultAlertWindow = 60
+	// MaxAlertWindow 最大告警统计窗口（分钟），即一天
+	MaxAlertWindow = 1440
+	// DefaultAlertCooldown 默认恢复后再次触发的冷却时间（分钟）
+	DefaultAlertCooldown = 30
+	// maxDryRunSteps 试运行最多评估的次数
+	maxDryRunSteps = 1000
+)
+
+// 告警级别
+var alertSeverities = map[string]bool{"info": true, "warning": true, "critical": true}
+
+// AlertEventFilter 告警事件查询条件
+type AlertEventFilter = repository.AlertEventFilter
+
+// AlertRuleParams 告警规则参数，字段为 nil 表示不设置/不修改
+type AlertRuleParams struct {
+	Name               *string
+	ScenarioID         *uint64
+	GroupID            *uint64 // 0 表示整个场景
+	Type               *string
+	Threshold          *float64
+	WindowMinutes      *int
+	MinCount           *int
+	Keywords           []string
+	Severity           *string
+	CooldownMinutes    *int
+	ChannelIDs         []uint64 // 通知渠道
+	EscalationPolicyID *uint64  // 升级策略，0 表示不升级
+	Status             *int
+}
+
+// AlertDryRunResult 告警规则试运行结果
+type AlertDryRunResult struct {
+	Evaluations int                 `json:"evaluations"` // 评估次数
+	Events      []*model.AlertEvent `json:"events"`      // 按规则会产生的告警事件
+}
+
+// AlertService 告警服务接口
+type AlertService interface {
+	CreateRule(params *AlertRuleParams) (*model.AlertRule, error)
+	GetRule(id uint64) (*model.AlertRule, error)
+	ListRules(scenarioID uint64) ([]*model.AlertRule, error)
+	UpdateRule(id uint64, params *AlertRuleParams) (*model.AlertRule, error)
+	DeleteRule(id uint64) error
+	SilenceRule(id uint64, minutes int) (*model.AlertRule, error)
+	BuildRule(params *AlertRuleParams) (*model.AlertRule, error)
+	DryRun(rule *model.AlertRule, start, end time.Time, step time.Duration) (*AlertDryRunResult, error)
+	EvaluateRule(rule *model.AlertRule, now time.Time) ([]*model.AlertEvent, error)
+	GetEvent(id uint64) (*model.AlertEvent, error)
+	ListEvents(filter AlertEventFilter, page, pageSize int) ([]*model.AlertEvent, int64, error)
+	AcknowledgeEvent(id, userID uint64, note string) (*model.AlertEvent, error)
+	ResolveEvent(id, userID uint64, note string) (*model.AlertEvent, error)
+	GetTimeline(id uint64) ([]*model.AlertEventLog, error)
+}
+
+type alertService struct {
+	ruleRepo       repository.AlertRuleRepository
+	eventRepo      repository.AlertEventRepository
+	logRepo        repository.AlertEventLogRepository
+	policyRepo     repository.EscalationPolicyRepository
+	hitRepo        repository.OpinionHitRepository
+	scenarioRepo   repository.ScenarioRepository
+	groupRepo      repository.MonitoringGroupRepository
+	segmentService SegmentService
+}
+
+// NewAlertService 创建告警服务实例
+func NewAlertService(
+	ruleRepo repository.AlertRuleRepository,
+	eventRepo repository.AlertEventRepository,
+	logRepo repository.AlertEventLogRepository,
+	policyRepo repository.EscalationPolicyRepository,
+	hitRepo repository.OpinionHitRepository,
+	scenarioRepo repository.ScenarioRepository,
+	groupRepo repository.MonitoringGroupRepository,
+	segmentService SegmentService,
+) AlertService {
+	return &alertService{
+		ruleRepo:       ruleRepo,
+		eventRepo:      eventRepo,
+		logRepo:        logRepo,
+		policyRepo:     policyRepo,
+		hitRepo:        hitRepo,
+		scenarioRepo:   scenarioRepo,
+		groupRepo:      groupRepo,
+		segmentService: segmentService,
+	}
+}
+
+// CreateRule 创建告警规则
+func (s *alertService) CreateRule(params *AlertRuleParams) (*model.AlertRule, error) {
+	rule, err := s.BuildRule(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, errors.New("创建告警规则失败")
+	}
+	return rule, nil
+}
+
+// BuildRule 根据参数构建并校验告警规则（不落库），用于创建和试运行
+func (s *alertService) BuildRule(params *AlertRuleParams) (*model.AlertRule, error) {
+	if params == nil || params.Name == nil || params.ScenarioID == nil || params.Type == nil || params.Threshold == nil {
+		return nil, errors.New("规则名称、场景、类型和阈值不能为空")
+	}
+
+	rule := &model.AlertRule{
+		WindowMinutes:   DefaultAlertWindow,
+		Severity:        "warning",
+		CooldownMinutes: DefaultAlertCooldown,
+		Status:          1, // 正常状态
+	}
+	if err := applyAlertRuleParams(rule, params); err != nil {
+		return nil, err
+	}
+	if err := s.validateRuleScope(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRule 根据 ID 获取告警规则
+func (s *alertService) GetRule(id uint64) (*model.AlertRule, error) {
+	return s.ruleRepo.GetByID(id)
+}
+
+// ListRules 获取告警规则列表
+func (s *alertService) ListRules(scenarioID uint64) ([]*model.AlertRule, error) {
+	return s.ruleRepo.List(scenarioID)
+}
+
+// UpdateRule 更新告警规则
+func (s *alertService) UpdateRule(id uint64, params *AlertRuleParams) (*model.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("告警规则不存在")
+	}
+
+	if err := applyAlertRuleParams(rule, params); err != nil {
+		return nil, err
+	}
+	if err := s.validateRuleScope(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, errors.New("更新告警规则失败")
+	}
+	return rule, nil
+}
+
+// DeleteRule 删除告警规则
+func (s *alertService) DeleteRule(id uint64) error {
+	if _, err := s.ruleRepo.GetByID(id); err != nil {
+		return errors.New("告警规则不存在")
+	}
+	return s.ruleRepo.Delete(id)
+}
+
+// SilenceRule 静默告警规则指定分钟数，静默期间不产生新事件；minutes 为 0 表示取消静默
+func (s *alertService) SilenceRule(id uint64, minutes int) (*model.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("告警规则不存在")
+	}
+	if minutes < 0 || minutes > 7*MaxAlertWindow {
+		return nil, errors.New("静默时长必须在0到10080分钟之间")
+	}
+
+	if minutes == 0 {
+		rule.SilenceUntil = nil
+	} else {
+		until := time.Now().Add(time.Duration(minutes) * time.Minute)
+		rule.SilenceUntil = &until
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, errors.New("更新告警规则失败")
+	}
+	return rule, nil
+}
+
+// EvaluateRule 评估规则并落库事件状态，返回本次新触发的事件
+func (s *alertService) EvaluateRule(rule *model.AlertRule, now time.Time) ([]*model.AlertEvent, error) {
+	window := time.Duration(rule.WindowMinutes) * time.Minute
+	start := now.Add(-window)
+	if rule.Type == model.AlertRuleGrowth {
+		start = now.Add(-2 * window)
+	}
+
+	docs, err := s.hitRepo.GetDocuments(rule.ScenarioID, rule.GroupID, start, now)
+	if err != nil {
+		return nil, err
+	}
+	segmenter, err := s.ruleSegmenter(rule)
+	if err != nil {
+		return nil, err
+	}
+	current, previous := splitWindow(docs, now, window)
+	findings := evaluateAlertRule(rule, current, previous, segmenter)
+
+	open, err := s.eventRepo.GetOpenByRule(rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	lastResolvedAt := func(dedupKey string) *time.Time {
+		event, err := s.eventRepo.GetLatestByDedupKey(rule.ID, dedupKey)
+		if err != nil {
+			return nil
+		}
+		return event.ResolvedAt
+	}
+
+	fired, updated, resolved := applyFindings(rule, findings, open, lastResolvedAt, now)
+	for _, event := range fired {
+		if err := s.eventRepo.Create(event); err != nil {
+			return nil, err
+		}
+		s.writeLog(event.ID, model.AlertActionFired, 0, event.Title)
+	}
+	// 只写评估字段和条件恢复，避免覆盖评估期间用户的认领/恢复操作
+	for _, event := range updated {
+		if err := s.eventRepo.UpdateObservation(event); err != nil {
+			return nil, err
+		}
+	}
+	for _, event := range resolved {
+		ok, err := s.eventRepo.Resolve(event.ID, 0, *event.ResolvedAt)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.writeLog(event.ID, model.AlertActionResolved, 0, "不再满足告警条件，自动恢复")
+		}
+	}
+
+	if err := s.ruleRepo.UpdateLastEvaluatedAt(rule.ID, now); err != nil {
+		return nil, err
+	}
+	return fired, nil
+}
+
+// DryRun 在历史数据上按 step 间隔回放规则评估（包括去重、冷却和恢复），不落库
+func (s *alertService) DryRun(rule *model.AlertRule, start, end time.Time, step time.Duration) (*AlertDryRunResult, error) {
+	if !end.After(start) {
+		return nil, errors.New("结束时间必须晚于开始时间")
+	}
+	window := time.Duration(rule.WindowMinutes) * time.Minute
+	if step <= 0 {
+		step = window
+	}
+	if int(end.Sub(start)/step) > maxDryRunSteps {
+		return nil, fmt.Errorf("评估次数超过%d次，请缩小时间范围或增大评估间隔", maxDryRunSteps)
+	}
+
+	docs, err := s.hitRepo.GetDocuments(rule.ScenarioID, rule.GroupID, start.Add(-2*window), end)
+	if err != nil {
+		return nil, err
+	}
+	segmenter, err := s.ruleSegmenter(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AlertDryRunResult{Events: make([]*model.AlertEvent, 0)}
+	open := make([]*model.AlertEvent, 0)
+	resolvedAt := make(map[string]*time.Time)
+	lastResolvedAt := func(dedupKey string) *time.Time {
+		return resolvedAt[dedupKey]
+	}
+
+	for now := start.Add(step); !now.After(end); now = now.Add(step) {
+		current, previous := splitWindow(docs, now, window)
+		findings := evaluateAlertRule(rule, current, previous, segmenter)
+		fired, updated, resolved := applyFindings(rule, findings, open, lastResolvedAt, now)
+		result.Evaluations++
+
+		for _, event := range resolved {
+			resolvedAt[event.DedupKey] = event.ResolvedAt
+		}
+		result.Events = append(result.Events, fired...)
+		open = append(append(make([]*model.AlertEvent, 0, len(updated)+len(fired)), updated...), fired...)
+	}
+	return result, nil
+}
+
+// GetEvent 根据 ID 获取告警事件
+func (s *alertService) GetEvent(id uint64) (*model.AlertEvent, error) {
+	return s.eventRepo.GetByID(id)
+}
+
+// ListEvents 按条件分页获取告警事件
+func (s *alertService) ListEvents(filter AlertEventFilter, page, pageSize int) ([]*model.AlertEvent, int64, error) {
+	return s.eventRepo.List(filter, page, pageSize)
+}
+
+// AcknowledgeEvent 认领告警，认领后停止升级，条件不再满足时仍会自动恢复
+func (s *alertService) AcknowledgeEvent(id, userID uint64, note string) (*model.AlertEvent, error) {
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("告警事件不存在")
+	}
+	if event.Status != model.AlertStatusFiring {
+		return nil, errors.New("只能认领未处理的告警")
+	}
+
+	ok, err := s.eventRepo.Acknowledge(id, userID, time.Now())
+	if err != nil {
+		return nil, errors.New("认领告警失败")
+	}
+	if !ok {
+		return nil, errors.New("告警状态已变化，请刷新后重试")
+	}
+
+	s.writeLog(id, model.AlertActionAcknowledged, userID, note)
+	return s.eventRepo.GetByID(id)
+}
+
+// ResolveEvent 手动恢复告警，恢复后按规则冷却时间决定何时可再次触发
+func (s *alertService) ResolveEvent(id, userID uint64, note string) (*model.AlertEvent, error) {
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("告警事件不存在")
+	}
+	if event.Status == model.AlertStatusResolved {
+		return nil, errors.New("告警已恢复")
+	}
+
+	ok, err := s.eventRepo.Resolve(id, userID, time.Now())
+	if err != nil {
+		return nil, errors.New("恢复告警失败")
+	}
+	if !ok {
+		return nil, errors.New("告警状态已变化，请刷新后重试")
+	}
+
+	s.writeLog(id, model.AlertActionResolved, userID, note)
+	return s.eventRepo.GetByID(id)
+}
+
+// GetTimeline 获取告警事件的时间线
+func (s *alertService) GetTimeline(id uint64) ([]*model.AlertEventLog, error) {
+	if _, err := s.eventRepo.GetByID(id); err != nil {
+		return nil, errors.New("告警事件不存在")
+	}
+	return s.logRepo.GetByEventID(id)
+}
+
+// writeLog 写入告警时间线，失败只记录日志
+func (s *alertService) writeLog(eventID uint64, action string, userID uint64, detail string) {
+	log := &model.AlertEventLog{
+		EventID: eventID,
+		Action:  action,
+		UserID:  userID,
+		Detail:  truncateRunes(detail, 1000),
+	}
+	if err := s.logRepo.Create(log); err != nil {
+		appLogger.Get().Error("写入告警时间线失败", zap.Uint64("event_id", eventID), zap.Error(err))
+	}
+}
+
+// ruleSegmenter 关键词规则使用的分词器，规则关键词加入用户词典保证整词切出
+func (s *alertService) ruleSegmenter(rule *model.AlertRule) (*segment.Segmenter, error) {
+	if rule.Type != model.AlertRuleKeyword {
+		return nil, nil
+	}
+	base, err := s.segmentService.GetSegmenter()
+	if err != nil {
+		return nil, err
+	}
+	return segment.NewSegmenter(base.Dictionary(), rule.Keywords), nil
+}
+
+// validateRuleScope 校验规则的场景和监测组
+func (s *alertService) validateRuleScope(rule *model.AlertRule) error {
+	if _, err := s.scenarioRepo.GetByID(rule.ScenarioID); err != nil {
+		return errors.New("场景不存在")
+	}
+	if rule.GroupID > 0 {
+		group, err := s.groupRepo.GetByID(rule.GroupID)
+		if err != nil {
+			return errors.New("监测组不存在")
+		}
+		if group.ScenarioID != rule.ScenarioID {
+			return errors.New("监测组不属于该场景")
+		}
+	}
+	if rule.EscalationPolicyID > 0 {
+		if _, err := s.policyRepo.GetByID(rule.EscalationPolicyID); err != nil {
+			return errors.New("升级策略不存在")
+		}
+	}
+	return nil
+}
+
+// splitWindow 将舆情按入库时间拆分为当前窗口 [now-window, now) 和上一窗口 [now-2*window, now-window)
+func splitWindow(docs []*repository.HitDocument, now time.Time, window time.Duration) (current, previous []*repository.HitDocument) {
+	currentStart := now.Add(-window)
+	previousStart := now.Add(-2 * window)
+	for _, doc := range docs {
+		switch {
+		case doc.CreatedAt.Before(previousStart) || !doc.CreatedAt.Before(now):
+			// 不在评估范围内
+		case !doc.CreatedAt.Before(currentStart):
+			current = append(current, doc)
+		default:
+			previous = append(previous, doc)
+		}
+	}
+	return current, previous
+}
+
+// applyAlertRuleParams 将参数写入规则并校验
+func applyAlertRuleParams(rule *model.AlertRule, params *AlertRuleParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("规则名称不能为空")
+		}
+		rule.Name = name
+	}
+	if params.ScenarioID != nil {
+		rule.ScenarioID = *params.ScenarioID
+	}
+	if params.GroupID != nil {
+		rule.GroupID = *params.GroupID
+	}
+	if params.Type != nil {
+		rule.Type = *params.Type
+	}
+	if params.Threshold != nil {
+		rule.Threshold = *params.Threshold
+	}
+	if params.WindowMinutes != nil {
+		if *params.WindowMinutes < 1 || *params.WindowMinutes > MaxAlertWindow {
+			return fmt.Errorf("统计窗口必须在1到%d分钟之间", MaxAlertWindow)
+		}
+		rule.WindowMinutes = *params.WindowMinutes
+	}
+	if params.MinCount != nil {
+		if *params.MinCount < 0 {
+			return errors.New("最小样本数不能为负数")
+		}
+		rule.MinCount = *params.MinCount
+	}
+	if params.Keywords != nil {
+		keywords := make(model.StringList, 0, len(params.Keywords))
+		for _, keyword := range params.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+		rule.Keywords = keywords
+	}
+	if params.Severity != nil {
+		if !alertSeverities[*params.Severity] {
+			return errors.New("告警级别必须为 info、warning 或 critical")
+		}
+		rule.Severity = *params.Severity
+	}
+	if params.CooldownMinutes != nil {
+		if *params.CooldownMinutes < 0 || *params.CooldownMinutes > MaxAlertWindow {
+			return fmt.Errorf("冷却时间必须在0到%d分钟之间", MaxAlertWindow)
+		}
+		rule.CooldownMinutes = *params.CooldownMinutes
+	}
+	if params.ChannelIDs != nil {
+		rule.ChannelIDs = model.IDList(uniqueIDs(params.ChannelIDs))
+	}
+	if params.EscalationPolicyID != nil {
+		rule.EscalationPolicyID = *params.EscalationPolicyID
+	}
+	if params.Status != nil {
+		if *params.Status != 1 && *params.Status != 2 {
+			return errors.New("状态必须为1或2")
+		}
+		rule.Status = *params.Status
+	}
+
+	switch rule.Type {
+	case model.AlertRuleVolume, model.AlertRuleEngagement, model.AlertRuleGrowth:
+		if rule.Threshold <= 0 {
+			return errors.New("阈值必须大于0")
+		}
+	case model.AlertRuleNegativeRatio:
+		if rule.Threshold <= 0 || rule.Threshold > 1 {
+			return errors.New("负面占比阈值必须在0到1之间")
+		}
+	case model.AlertRuleKeyword:
+		if len(rule.Keywords) == 0 {
+			return errors.New("关键词规则至少需要一个关键词")
+		}
+		if rule.Threshold < 0 {
+			return errors.New("阈值不能为负数")
+		}
+	default:
+		return errors.New("不支持的规则类型")
+	}
+	return nil
+}
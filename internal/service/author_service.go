@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// authorBatchSize 计算影响力时每批处理的作者数
+const authorBatchSize = 1000
+
+// AuthorFilter 作者查询条件
+type AuthorFilter = repository.AuthorFilter
+
+// AuthorService 作者和场景关注作者服务接口
+type AuthorService interface {
+	Resolve(opinions []*model.Opinion) error
+	GetAuthor(id uint64) (*model.Author, error)
+	ListAuthors(filter AuthorFilter, page, pageSize int) ([]*model.Author, int64, error)
+	KOLScore() float64
+	LinkExisting() (int, error)
+	RefreshInfluence() (int, error)
+
+	GetWatches(scenarioID uint64) ([]*model.ScenarioAuthorWatch, error)
+	AddWatch(scenarioID, authorID, userID uint64, note string) (*model.ScenarioAuthorWatch, error)
+	RemoveWatch(scenarioID, authorID uint64) error
+}
+
+type authorService struct {
+	cfg          config.AuthorConfig
+	authorRepo   repository.AuthorRepository
+	scenarioRepo repository.ScenarioRepository
+}
+
+// NewAuthorService 创建作者服务实例
+func NewAuthorService(cfg *config.AuthorConfig, authorRepo repository.AuthorRepository, scenarioRepo repository.ScenarioRepository) AuthorService {
+	s := &authorService{
+		authorRepo:   authorRepo,
+		scenarioRepo: scenarioRepo,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.InfluenceDays <= 0 {
+		s.cfg.InfluenceDays = 30
+	}
+	if s.cfg.KOLScore <= 0 || s.cfg.KOLScore > 100 {
+		s.cfg.KOLScore = 60
+	}
+	return s
+}
+
+// Resolve 按舆情的来源和作者账号信息创建或更新作者，并回填舆情的 AuthorID（用于入库前）。
+// 账号ID为空时以作者名称作为账号ID；作者名称和账号ID都为空的舆情不关联作者
+func (s *authorService) Resolve(opinions []*model.Opinion) error {
+	// 唯一索引不区分大小写，作者按小写的标识合并
+	now := time.Now()
+	authors := make(map[repository.AuthorKey]*model.Author)
+	var keys []repository.AuthorKey
+	for _, opinion := range opinions {
+		key, ok := authorKey(opinion)
+		if !ok {
+			continue
+		}
+		seenAt := opinion.CreatedAt
+		if seenAt.IsZero() {
+			seenAt = now
+		}
+
+		author, ok := authors[lowerAuthorKey(key)]
+		if !ok {
+			author = &model.Author{
+				Channel:     key.Channel,
+				ExternalID:  key.ExternalID,
+				FirstSeenAt: seenAt,
+				LastSeenAt:  seenAt,
+			}
+			authors[lowerAuthorKey(key)] = author
+			keys = append(keys, key)
+		}
+		if name := truncateRunes(strings.TrimSpace(opinion.Author), 100); name != "" {
+			author.Name = name
+		}
+		if opinion.AuthorFollowers > 0 {
+			author.FollowerCount = opinion.AuthorFollowers
+		}
+		author.Verified = author.Verified || opinion.AuthorVerified
+		author.OpinionCount++
+		if seenAt.Before(author.FirstSeenAt) {
+			author.FirstSeenAt = seenAt
+		}
+		if seenAt.After(author.LastSeenAt) {
+			author.LastSeenAt = seenAt
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	batch := make([]*model.Author, len(keys))
+	for i, key := range keys {
+		batch[i] = authors[lowerAuthorKey(key)]
+	}
+	if err := s.authorRepo.Touch(batch); err != nil {
+		return err
+	}
+	saved, err := s.authorRepo.GetByKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[repository.AuthorKey]uint64, len(saved))
+	for _, author := range saved {
+		ids[lowerAuthorKey(repository.AuthorKey{Channel: author.Channel, ExternalID: author.ExternalID})] = author.ID
+	}
+	for _, opinion := range opinions {
+		if key, ok := authorKey(opinion); ok {
+			opinion.AuthorID = ids[lowerAuthorKey(key)]
+		}
+	}
+	return nil
+}
+
+// GetAuthor 获取作者详情
+func (s *authorService) GetAuthor(id uint64) (*model.Author, error) {
+	author, err := s.authorRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("作者不存在")
+	}
+	return author, nil
+}
+
+// ListAuthors 按条件分页获取作者
+func (s *authorService) ListAuthors(filter AuthorFilter, page, pageSize int) ([]*model.Author, int64, error) {
+	return s.authorRepo.List(filter, page, pageSize)
+}
+
+// KOLScore 返回视为 KOL 的影响力得分下限
+func (s *authorService) KOLScore() float64 {
+	return s.cfg.KOLScore
+}
+
+// LinkExisting 为有作者名称但未关联作者的已入库舆情创建作者并关联（以作者名称作为账号ID），返回关联的舆情数
+func (s *authorService) LinkExisting() (int, error) {
+	linked := 0
+	var afterID uint64
+	for {
+		opinions, err := s.authorRepo.GetUnlinkedOpinions(afterID, authorBatchSize)
+		if err != nil {
+			return linked, err
+		}
+		if len(opinions) == 0 {
+			break
+		}
+		if err := s.Resolve(opinions); err != nil {
+			return linked, err
+		}
+
+		byAuthor := make(map[uint64][]uint64)
+		for _, opinion := range opinions {
+			if opinion.AuthorID > 0 {
+				byAuthor[opinion.AuthorID] = append(byAuthor[opinion.AuthorID], opinion.ID)
+			}
+		}
+		for authorID, opinionIDs := range byAuthor {
+			if err := s.authorRepo.LinkOpinions(authorID, opinionIDs); err != nil {
+				return linked, err
+			}
+			linked += len(opinionIDs)
+		}
+		afterID = opinions[len(opinions)-1].ID
+	}
+	return linked, nil
+}
+
+// RefreshInfluence 按最近 influence_days 天的舆情重新计算所有作者的平均互动量和影响力得分，返回有变化的作者数
+func (s *authorService) RefreshInfluence() (int, error) {
+	since := time.Now().AddDate(0, 0, -s.cfg.InfluenceDays)
+	stats, err := s.authorRepo.GetEngagement(since)
+	if err != nil {
+		return 0, err
+	}
+	engagement := make(map[uint64]float64, len(stats))
+	for _, stat := range stats {
+		engagement[stat.AuthorID] = math.Round(stat.AvgEngagement*100) / 100
+	}
+
+	updated := 0
+	var afterID uint64
+	for {
+		authors, err := s.authorRepo.ListAfterID(afterID, authorBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		for _, author := range authors {
+			avg := engagement[author.ID]
+			score := influenceScore(author.FollowerCount, author.Verified, avg)
+			if avg == author.AvgEngagement && score == author.InfluenceScore {
+				continue
+			}
+			if err := s.authorRepo.UpdateInfluence(author.ID, avg, score); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		if len(authors) < authorBatchSize {
+			break
+		}
+		afterID = authors[len(authors)-1].ID
+	}
+	return updated, nil
+}
+
+// GetWatches 获取场景关注的作者
+func (s *authorService) GetWatches(scenarioID uint64) ([]*model.ScenarioAuthorWatch, error) {
+	if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, errors.New("场景不存在")
+	}
+	return s.authorRepo.GetWatches(scenarioID)
+}
+
+// AddWatch 场景关注作者，之后该作者发布的舆情直接命中场景下的监测组
+func (s *authorService) AddWatch(scenarioID, authorID, userID uint64, note string) (*model.ScenarioAuthorWatch, error) {
+	if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, errors.New("场景不存在")
+	}
+	author, err := s.authorRepo.GetByID(authorID)
+	if err != nil {
+		return nil, errors.New("作者不存在")
+	}
+	ids, err := s.authorRepo.GetWatchedAuthorIDs(scenarioID)
+	if err != nil {
+		return nil, errors.New("获取关注的作者失败")
+	}
+	for _, id := range ids {
+		if id == authorID {
+			return nil, errors.New("已关注该作者")
+		}
+	}
+
+	watch := &model.ScenarioAuthorWatch{
+		ScenarioID: scenarioID,
+		AuthorID:   authorID,
+		Note:       strings.TrimSpace(note),
+		CreatedBy:  userID,
+	}
+	if err := s.authorRepo.AddWatch(watch); err != nil {
+		return nil, errors.New("关注作者失败")
+	}
+	watch.Author = author
+	return watch, nil
+}
+
+// RemoveWatch 取消场景对作者的关注
+func (s *authorService) RemoveWatch(scenarioID, authorID uint64) error {
+	removed, err := s.authorRepo.RemoveWatch(scenarioID, authorID)
+	if err != nil {
+		return errors.New("取消关注失败")
+	}
+	if removed == 0 {
+		return errors.New("未关注该作者")
+	}
+	return nil
+}
+
+// authorKey 返回舆情作者的唯一标识，没有作者信息时返回 false
+func authorKey(opinion *model.Opinion) (repository.AuthorKey, bool) {
+	externalID := strings.TrimSpace(opinion.AuthorExternalID)
+	if externalID == "" {
+		externalID = strings.TrimSpace(opinion.Author)
+	}
+	channel := strings.TrimSpace(opinion.Source)
+	if externalID == "" || channel == "" {
+		return repository.AuthorKey{}, false
+	}
+	return repository.AuthorKey{
+		Channel:    truncateRunes(channel, 255),
+		ExternalID: truncateRunes(externalID, 100),
+	}, true
+}
+
+// lowerAuthorKey 返回小写的作者标识
+func lowerAuthorKey(key repository.AuthorKey) repository.AuthorKey {
+	return repository.AuthorKey{Channel: strings.ToLower(key.Channel), ExternalID: strings.ToLower(key.ExternalID)}
+}
+
+// influenceScore 计算影响力得分（0~100）：
+// 粉丝数占 50 分（按对数计，1000 万粉丝满分），最近舆情的平均互动量占 35 分（按对数计，平均 1 万满分），认证账号加 15 分
+func influenceScore(followers int64, verified bool, avgEngagement float64) float64 {
+	score := math.Min(math.Log10(float64(followers)+1)/7, 1) * 50
+	score += math.Min(math.Log10(avgEngagement+1)/4, 1) * 35
+	if verified {
+		score += 15
+	}
+	return math.Round(score*100) / 100
+}
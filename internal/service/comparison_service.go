@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// ComparisonSetParams 对比组参数，字段为 nil 表示不设置/不修改
+type ComparisonSetParams struct {
+	Name        *string
+	Description *string
+	ScenarioIDs []uint64
+	Interval    *string
+}
+
+// ComparisonService 竞品对比组服务接口
+type ComparisonService interface {
+	Create(userID uint64, params *ComparisonSetParams) (*model.ComparisonSet, error)
+	Get(id uint64) (*model.ComparisonSet, error)
+	List() ([]*model.ComparisonSet, error)
+	Update(id, userID uint64, isAdmin bool, params *ComparisonSetParams) (*model.ComparisonSet, error)
+	Delete(id, userID uint64, isAdmin bool) error
+	Report(id uint64, query StatsQuery) (*ScenarioComparison, error)
+}
+
+type comparisonService struct {
+	comparisonRepo repository.ComparisonSetRepository
+	scenarioRepo   repository.ScenarioRepository
+	statsService   StatsService
+}
+
+// NewComparisonService 创建竞品对比组服务实例
+func NewComparisonService(
+	comparisonRepo repository.ComparisonSetRepository,
+	scenarioRepo repository.ScenarioRepository,
+	statsService StatsService,
+) ComparisonService {
+	return &comparisonService{
+		comparisonRepo: comparisonRepo,
+		scenarioRepo:   scenarioRepo,
+		statsService:   statsService,
+	}
+}
+
+// Create 创建对比组
+func (s *comparisonService) Create(userID uint64, params *ComparisonSetParams) (*model.ComparisonSet, error) {
+	if params == nil || params.Name == nil || params.ScenarioIDs == nil {
+		return nil, errors.New("对比组名称和场景不能为空")
+	}
+
+	set := &model.ComparisonSet{Interval: StatsIntervalDay, CreatedBy: userID}
+	if err := s.applyParams(set, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.comparisonRepo.Create(set); err != nil {
+		return nil, errors.New("创建对比组失败")
+	}
+	return set, nil
+}
+
+// Get 根据 ID 获取对比组
+func (s *comparisonService) Get(id uint64) (*model.ComparisonSet, error) {
+	return s.comparisonRepo.GetByID(id)
+}
+
+// List 获取所有对比组
+func (s *comparisonService) List() ([]*model.ComparisonSet, error) {
+	return s.comparisonRepo.GetAll()
+}
+
+// Update 更新对比组，只有创建人和管理员可以修改
+func (s *comparisonService) Update(id, userID uint64, isAdmin bool, params *ComparisonSetParams) (*model.ComparisonSet, error) {
+	set, err := s.comparisonRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("对比组不存在")
+	}
+	if set.CreatedBy != userID && !isAdmin {
+		return nil, errors.New("只有创建人或管理员可以修改对比组")
+	}
+
+	if err := s.applyParams(set, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.comparisonRepo.Update(set); err != nil {
+		return nil, errors.New("更新对比组失败")
+	}
+	return set, nil
+}
+
+// Delete 删除对比组，只有创建人和管理员可以删除
+func (s *comparisonService) Delete(id, userID uint64, isAdmin bool) error {
+	set, err := s.comparisonRepo.GetByID(id)
+	if err != nil {
+		return errors.New("对比组不存在")
+	}
+	if set.CreatedBy != userID && !isAdmin {
+		return errors.New("只有创建人或管理员可以删除对比组")
+	}
+	return s.comparisonRepo.Delete(id)
+}
+
+// Report 按对比组中的场景生成对比数据，未指定统计粒度时使用对比组的默认粒度
+func (s *comparisonService) Report(id uint64, query StatsQuery) (*ScenarioComparison, error) {
+	set, err := s.comparisonRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("对比组不存在")
+	}
+	if query.Interval == "" {
+		query.Interval = set.Interval
+	}
+	return s.statsService.CompareScenarios(set.ScenarioIDs, query)
+}
+
+// applyParams 校验参数并写入对比组
+func (s *comparisonService) applyParams(set *model.ComparisonSet, params *ComparisonSetParams) error {
+	if params == nil {
+		return nil
+	}
+	if params.Name != nil {
+		if *params.Name == "" {
+			return errors.New("对比组名称不能为空")
+		}
+		set.Name = *params.Name
+	}
+	if params.Description != nil {
+		set.Description = *params.Description
+	}
+	if params.Interval != nil {
+		if *params.Interval != StatsIntervalHour && *params.Interval != StatsIntervalDay {
+			return errors.New("无效的统计粒度，可选值: hour, day")
+		}
+		set.Interval = *params.Interval
+	}
+	if params.ScenarioIDs != nil {
+		ids := uniqueIDs(params.ScenarioIDs)
+		if len(ids) < 2 || len(ids) > MaxComparisonScenarios {
+			return fmt.Errorf("对比的场景数必须在 2~%d 之间", MaxComparisonScenarios)
+		}
+		for _, id := range ids {
+			if _, err := s.scenarioRepo.GetByID(id); err != nil {
+				return fmt.Errorf("场景%d不存在", id)
+			}
+		}
+		set.ScenarioIDs = ids
+	}
+	return nil
+}
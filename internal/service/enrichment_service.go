@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// enrichTimeout 单次富化调用的整体超时
+const enrichTimeout = 30 * time.Second
+
+// EnrichmentService 舆情富化服务接口（情感、实体、主题、关键词）
+type EnrichmentService interface {
+	AnalyzerName() string
+	Enrich(docs []analysis.Document) ([]analysis.Result, error)
+	EnrichOpinions(opinions []*model.Opinion) error
+	EnrichHits(hits []*model.OpinionHit, opinions []*model.Opinion) error
+}
+
+type enrichmentService struct {
+	registry       *analysis.Registry
+	analyzer       analysis.Analyzer
+	segmentService SegmentService
+}
+
+// NewEnrichmentService 创建舆情富化服务实例
+// 内置分析器始终注册；配置了外部服务地址时注册 HTTP 分析器，选用后失败自动回退到内置分析器
+func NewEnrichmentService(cfg *config.AnalysisConfig, sentimentService SentimentService, segmentService SegmentService) EnrichmentService {
+	registry := analysis.NewRegistry()
+	builtin := analysis.NewLexiconAnalyzer(sentimentService.GetAnalyzer)
+	registry.Register(builtin)
+
+	selected := analysis.BuiltinName
+	if cfg != nil {
+		if cfg.HTTP.Endpoint != "" {
+			registry.Register(analysis.NewHTTPAnalyzer(analysis.HTTPOptions{
+				Endpoint:         cfg.HTTP.Endpoint,
+				Token:            cfg.HTTP.Token,
+				Timeout:          time.Duration(cfg.HTTP.TimeoutMs) * time.Millisecond,
+				BatchSize:        cfg.HTTP.BatchSize,
+				FailureThreshold: cfg.HTTP.FailureThreshold,
+				OpenDuration:     time.Duration(cfg.HTTP.OpenSeconds) * time.Second,
+			}))
+		}
+		if cfg.Analyzer != "" {
+			selected = cfg.Analyzer
+		}
+	}
+
+	var analyzer analysis.Analyzer = builtin
+	if selected != analysis.BuiltinName {
+		primary, err := registry.Get(selected)
+		if err != nil {
+			appLogger.Get().Warn("分析器不可用，使用内置分析器", zap.String("analyzer", selected), zap.Error(err))
+		} else {
+			analyzer = analysis.NewFallbackAnalyzer(primary, builtin, func(err error) {
+				appLogger.Get().Warn("外部分析器调用失败，回退到内置分析器", zap.String("analyzer", selected), zap.Error(err))
+			})
+		}
+	}
+
+	return &enrichmentService{
+		registry:       registry,
+		analyzer:       analyzer,
+		segmentService: segmentService,
+	}
+}
+
+// AnalyzerName 当前使用的分析器名称
+func (s *enrichmentService) AnalyzerName() string {
+	return s.analyzer.Name()
+}
+
+// Enrich 分析文档
+func (s *enrichmentService) Enrich(docs []analysis.Document) ([]analysis.Result, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), enrichTimeout)
+	defer cancel()
+	return s.analyzer.Analyze(ctx, docs)
+}
+
+// EnrichOpinions 使用全局词典富化舆情并提取关键词，结果写入舆情字段（不落库）
+func (s *enrichmentService) EnrichOpinions(opinions []*model.Opinion) error {
+	docs := make([]analysis.Document, len(opinions))
+	for i, opinion := range opinions {
+		docs[i] = analysis.Document{ID: opinion.ID, Text: opinion.Content}
+	}
+
+	results, err := s.Enrich(docs)
+	if err != nil {
+		return err
+	}
+
+	for i, opinion := range opinions {
+		applyAnalysisResult(opinion, results[i])
+
+		keywords, err := s.segmentService.ExtractKeywords(opinion.Content)
+		if err != nil {
+			appLogger.Get().Warn("提取舆情关键词失败", zap.Uint64("opinion_id", opinion.ID), zap.Error(err))
+			continue
+		}
+		opinion.Keywords = keywords
+	}
+	return nil
+}
+
+// EnrichHits 按命中场景的自定义词典计算命中舆情的情感，结果写入命中记录（不落库）
+func (s *enrichmentService) EnrichHits(hits []*model.OpinionHit, opinions []*model.Opinion) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	contents := make(map[uint64]string, len(opinions))
+	for _, opinion := range opinions {
+		contents[opinion.ID] = opinion.Content
+	}
+	docs := make([]analysis.Document, len(hits))
+	for i, hit := range hits {
+		docs[i] = analysis.Document{ID: hit.OpinionID, ScenarioID: hit.ScenarioID, Text: contents[hit.OpinionID]}
+	}
+
+	results, err := s.Enrich(docs)
+	if err != nil {
+		return err
+	}
+	for i, hit := range hits {
+		if results[i].Sentiment != nil {
+			hit.SentimentScore = results[i].Sentiment.Score
+			hit.SentimentLabel = results[i].Sentiment.Label
+		}
+	}
+	return nil
+}
+
+// applyAnalysisResult 将分析结果写入舆情
+func applyAnalysisResult(opinion *model.Opinion, result analysis.Result) {
+	if result.Sentiment != nil {
+		opinion.SentimentScore = result.Sentiment.Score
+		opinion.SentimentLabel = result.Sentiment.Label
+	}
+	entities := make(model.EntityList, 0, len(result.Entities))
+	for _, entity := range result.Entities {
+		entities = append(entities, model.NamedEntity{Text: entity.Text, Type: entity.Type})
+	}
+	opinion.Entities = entities
+	opinion.Topics = model.StringList(result.Topics)
+	opinion.Analyzer = result.Analyzer
+}
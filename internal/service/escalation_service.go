@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// EscalationService 告警通知和升级服务接口
+type EscalationService interface {
+	Dispatch(rule *model.AlertRule, event *model.AlertEvent, now time.Time)
+	Escalate(now time.Time) (int, error)
+}
+
+type escalationService struct {
+	ruleRepo            repository.AlertRuleRepository
+	eventRepo           repository.AlertEventRepository
+	logRepo             repository.AlertEventLogRepository
+	policyRepo          repository.EscalationPolicyRepository
+	onCallService       OnCallService
+	notificationService NotificationService
+	inboxService        InboxService
+}
+
+// NewEscalationService 创建告警通知和升级服务实例
+func NewEscalationService(
+	ruleRepo repository.AlertRuleRepository,
+	eventRepo repository.AlertEventRepository,
+	logRepo repository.AlertEventLogRepository,
+	policyRepo repository.EscalationPolicyRepository,
+	onCallService OnCallService,
+	notificationService NotificationService,
+	inboxService InboxService,
+) EscalationService {
+	return &escalationService{
+		ruleRepo:            ruleRepo,
+		eventRepo:           eventRepo,
+		logRepo:             logRepo,
+		policyRepo:          policyRepo,
+		onCallService:       onCallService,
+		notificationService: notificationService,
+		inboxService:        inboxService,
+	}
+}
+
+// Dispatch 发送新触发事件的首次通知：规则配置的通知渠道，以及升级策略中延迟为 0 的层级
+func (s *escalationService) Dispatch(rule *model.AlertRule, event *model.AlertEvent, now time.Time) {
+	for _, delivery := range s.notificationService.NotifyAlert(rule, event) {
+		s.logDelivery(event.ID, 0, delivery)
+	}
+
+	policy := s.activePolicy(rule)
+	if policy == nil {
+		return
+	}
+	if _, err := s.escalateEvent(rule, policy, event, now); err != nil {
+		appLogger.Get().Error("告警升级失败", zap.Uint64("event_id", event.ID), zap.Error(err))
+	}
+}
+
+// Escalate 检查所有未认领的告警，按升级策略通知到期的层级，返回本次通知的层级数
+func (s *escalationService) Escalate(now time.Time) (int, error) {
+	events, err := s.eventRepo.GetByStatus(model.AlertStatusFiring)
+	if err != nil {
+		return 0, err
+	}
+
+	rules := make(map[uint64]*model.AlertRule)
+	policies := make(map[uint64]*model.EscalationPolicy)
+	total := 0
+	for _, event := range events {
+		rule, ok := rules[event.RuleID]
+		if !ok {
+			rule, _ = s.ruleRepo.GetByID(event.RuleID)
+			rules[event.RuleID] = rule
+		}
+		if rule == nil || rule.Status != 1 || rule.EscalationPolicyID == 0 {
+			continue
+		}
+
+		policy, ok := policies[rule.EscalationPolicyID]
+		if !ok {
+			policy = s.activePolicy(rule)
+			policies[rule.EscalationPolicyID] = policy
+		}
+		if policy == nil {
+			continue
+		}
+
+		count, err := s.escalateEvent(rule, policy, event, now)
+		if err != nil {
+			appLogger.Get().Error("告警升级失败", zap.Uint64("event_id", event.ID), zap.Error(err))
+			continue
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// escalateEvent 通知事件所有已到期但尚未通知的层级；先以条件更新抢占层级，避免并发重复通知
+func (s *escalationService) escalateEvent(rule *model.AlertRule, policy *model.EscalationPolicy, event *model.AlertEvent, now time.Time) (int, error) {
+	from := event.EscalationLevel
+	to := from
+	for to < len(policy.Tiers) && !now.Before(event.FiredAt.Add(time.Duration(policy.Tiers[to].DelayMinutes)*time.Minute)) {
+		to++
+	}
+	if to == from {
+		return 0, nil
+	}
+
+	ok, err := s.eventRepo.AdvanceEscalation(event.ID, from, to, now)
+	if err != nil || !ok {
+		return 0, err
+	}
+	event.EscalationLevel = to
+	event.EscalatedAt = &now
+
+	for level := from + 1; level <= to; level++ {
+		s.notifyTier(rule, event, level, policy.Tiers[level-1], now)
+	}
+	return to - from, nil
+}
+
+// notifyTier 通知单个升级层级：层级渠道，以及指定用户和值班用户的邮箱和站内通知
+func (s *escalationService) notifyTier(rule *model.AlertRule, event *model.AlertEvent, level int, tier model.EscalationTier, now time.Time) {
+	msg := alertMessage(rule, event)
+	if tier.DelayMinutes > 0 {
+		msg.Title = fmt.Sprintf("【升级 L%d】%s", level, msg.Title)
+		msg.Content = fmt.Sprintf("告警已触发 %d 分钟仍未认领。\n%s", int(now.Sub(event.FiredAt).Minutes()), msg.Content)
+	}
+	msg.Data["escalation_level"] = level
+
+	var detail []string
+	if len(tier.ChannelIDs) > 0 {
+		detail = append(detail, fmt.Sprintf("渠道 %s", joinIDs(tier.ChannelIDs)))
+	}
+
+	users, err := s.onCallService.TierUsers(tier, now)
+	if err != nil {
+		detail = append(detail, "值班用户获取失败: "+err.Error())
+	}
+	emails := make([]string, 0, len(users))
+	names := make([]string, 0, len(users))
+	userIDs := make([]uint64, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+		userIDs = append(userIDs, user.ID)
+		if user.Email != "" {
+			emails = append(emails, user.Email)
+		}
+	}
+	if len(names) > 0 {
+		detail = append(detail, "用户 "+strings.Join(names, "、"))
+	}
+
+	s.writeLog(&model.AlertEventLog{
+		EventID: event.ID,
+		Action:  model.AlertActionEscalated,
+		Level:   level,
+		Detail:  fmt.Sprintf("通知第%d级：%s", level, strings.Join(detail, "；")),
+	})
+
+	for _, delivery := range s.notificationService.Send(tier.ChannelIDs, msg, NotifySourceAlert, event.ID) {
+		s.logDelivery(event.ID, level, delivery)
+	}
+	if len(emails) > 0 {
+		s.logDelivery(event.ID, level, s.notificationService.SendEmail(emails, msg, NotifySourceAlert, event.ID))
+	}
+	s.inboxService.Notify(userIDs, model.UserNotification{
+		Type:       model.InboxTypeAlert,
+		Title:      msg.Title,
+		Content:    msg.Content,
+		SourceType: model.InboxSourceAlertEvent,
+		SourceID:   event.ID,
+	})
+}
+
+// activePolicy 获取规则启用的升级策略，未配置或已禁用时返回 nil
+func (s *escalationService) activePolicy(rule *model.AlertRule) *model.EscalationPolicy {
+	if rule.EscalationPolicyID == 0 {
+		return nil
+	}
+	policy, err := s.policyRepo.GetByID(rule.EscalationPolicyID)
+	if err != nil || policy.Status != 1 || len(policy.Tiers) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// logDelivery 将一次通知发送写入时间线
+func (s *escalationService) logDelivery(eventID uint64, level int, delivery *model.NotificationDelivery) {
+	target := fmt.Sprintf("渠道#%d(%s)", delivery.ChannelID, delivery.ChannelType)
+	if delivery.ChannelID == 0 {
+		target = "用户邮件"
+	}
+	detail := target + " 发送成功"
+	if delivery.Status != model.DeliveryStatusSuccess {
+		detail = fmt.Sprintf("%s 发送失败（尝试%d次）: %s", target, delivery.Attempts, delivery.Error)
+	}
+
+	s.writeLog(&model.AlertEventLog{
+		EventID:    eventID,
+		Action:     model.AlertActionNotified,
+		Level:      level,
+		DeliveryID: delivery.ID,
+		Detail:     truncateRunes(detail, 1000),
+	})
+}
+
+// writeLog 写入时间线，失败只记录日志
+func (s *escalationService) writeLog(log *model.AlertEventLog) {
+	if err := s.logRepo.Create(log); err != nil {
+		appLogger.Get().Error("写入告警时间线失败", zap.Uint64("event_id", log.EventID), zap.Error(err))
+	}
+}
+
+// joinIDs 将 ID 列表格式化为 #1、#2
+func joinIDs(ids []uint64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("#%d", id))
+	}
+	return strings.Join(parts, "、")
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/export"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+const (
+	exportBatchSize = 1000 // 每批读取的舆情数，每批写完后更新一次进度
+
+	// exportStaleAfter 执行中的任务超过该时长没有进度更新时视为中断（如进程重启）
+	exportStaleAfter = 30 * time.Minute
+)
+
+// ExportService 舆情导出服务接口
+type ExportService interface {
+	CheckSync(filter OpinionFilter) (int64, error)
+	Stream(w io.Writer, format string, filter OpinionFilter) error
+	CreateTask(userID uint64, format string, filter OpinionFilter) (*model.ExportTask, error)
+	GetTask(id, userID uint64, isAdmin bool) (*model.ExportTask, error)
+	ListTasks(userID uint64, page, pageSize int) ([]*model.ExportTask, int64, error)
+	Process(id uint64) error
+	RunPending(now time.Time) (int, error)
+	Download(id uint64, expires, signature string) (string, string, error)
+}
+
+type exportService struct {
+	cfg         config.ExportConfig
+	taskRepo    repository.ExportTaskRepository
+	opinionRepo repository.OpinionRepository
+	slots       chan struct{} // Web 服务中执行导出任务的并发槽位
+}
+
+// NewExportService 创建舆情导出服务实例
+func NewExportService(cfg *config.ExportConfig, taskRepo repository.ExportTaskRepository, opinionRepo repository.OpinionRepository) ExportService {
+	s := &exportService{
+		taskRepo:    taskRepo,
+		opinionRepo: opinionRepo,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.StorageDir == "" {
+		s.cfg.StorageDir = "data/exports"
+	}
+	if s.cfg.SyncLimit <= 0 {
+		s.cfg.SyncLimit = 10000
+	}
+	if s.cfg.MaxRows <= 0 {
+		s.cfg.MaxRows = 1000000
+	}
+	if s.cfg.MaxConcurrent <= 0 {
+		s.cfg.MaxConcurrent = 2
+	}
+	if s.cfg.RetentionHours <= 0 {
+		s.cfg.RetentionHours = 24
+	}
+	if s.cfg.URLTTLMinutes <= 0 {
+		s.cfg.URLTTLMinutes = 60
+	}
+	// 下载接口无需登录，只凭签名校验，未配置密钥时不签发也不接受下载地址
+	if s.cfg.SigningKey == "" {
+		appLogger.Get().Warn("未配置 export.signing_key，导出文件不提供下载地址")
+	}
+	s.slots = make(chan struct{}, s.cfg.MaxConcurrent)
+	return s
+}
+
+// CheckSync 统计符合条件的舆情数，超过同步导出上限时返回错误
+func (s *exportService) CheckSync(filter OpinionFilter) (int64, error) {
+	total, err := s.opinionRepo.Count(filter)
+	if err != nil {
+		return 0, errors.New("统计舆情数量失败")
+	}
+	if total > int64(s.cfg.SyncLimit) {
+		return total, fmt.Errorf("符合条件的舆情有 %d 条，超过直接下载的上限 %d 条，请创建导出任务", total, s.cfg.SyncLimit)
+	}
+	return total, nil
+}
+
+// Stream 把符合条件的舆情按 ID 顺序以 CSV 或 NDJSON 写入 w，每批写完后刷新输出
+func (s *exportService) Stream(w io.Writer, format string, filter OpinionFilter) error {
+	if format != export.FormatCSV && format != export.FormatNDJSON {
+		return errors.New("直接下载只支持 csv 和 ndjson 格式")
+	}
+	writer, err := export.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+	if _, err := s.writeOpinions(writer, filter, s.cfg.SyncLimit, func(int64) {
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+	}); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// CreateTask 创建异步导出任务并尝试立即在后台执行；并发槽位已满时任务保持等待，由导出任务脚本执行
+// 未指定结束时间时以创建时刻为止，使导出结果与创建时统计的数量一致
+func (s *exportService) CreateTask(userID uint64, format string, filter OpinionFilter) (*model.ExportTask, error) {
+	if !export.IsValidFormat(format) {
+		return nil, errors.New("无效的导出格式，可选值: csv, xlsx, ndjson")
+	}
+	if filter.EndTime == nil {
+		now := time.Now()
+		filter.EndTime = &now
+	}
+
+	total, err := s.opinionRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("统计舆情数量失败")
+	}
+	limit := int64(s.cfg.MaxRows)
+	if format == export.FormatXLSX && limit > export.MaxXLSXRows {
+		limit = export.MaxXLSXRows
+	}
+	if total > limit {
+		return nil, fmt.Errorf("符合条件的舆情有 %d 条，超过单次导出的上限 %d 条，请缩小筛选范围", total, limit)
+	}
+
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	task := &model.ExportTask{
+		UserID: userID,
+		Format: format,
+		Filter: string(filterJSON),
+		Status: model.ExportStatusPending,
+		Total:  total,
+	}
+	if err := s.taskRepo.Create(task); err != nil {
+		return nil, errors.New("创建导出任务失败")
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		go func() {
+			defer func() { <-s.slots }()
+			_ = s.Process(task.ID)
+		}()
+	default:
+	}
+
+	s.decorate(task)
+	return task, nil
+}
+
+// GetTask 获取导出任务，只有创建人和管理员可以查看
+func (s *exportService) GetTask(id, userID uint64, isAdmin bool) (*model.ExportTask, error) {
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil || (task.UserID != userID && !isAdmin) {
+		return nil, errors.New("导出任务不存在")
+	}
+	s.decorate(task)
+	return task, nil
+}
+
+// ListTasks 分页获取用户创建的导出任务
+func (s *exportService) ListTasks(userID uint64, page, pageSize int) ([]*model.ExportTask, int64, error) {
+	tasks, total, err := s.taskRepo.List(userID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, task := range tasks {
+		s.decorate(task)
+	}
+	return tasks, total, nil
+}
+
+// Process 执行等待中的导出任务；任务已被其他进程执行时直接返回
+func (s *exportService) Process(id uint64) error {
+	claimed, err := s.taskRepo.Claim(id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	runErr := s.run(task)
+	now := time.Now()
+	task.FinishedAt = &now
+	if runErr != nil {
+		task.Status = model.ExportStatusFailed
+		task.Error = truncateRunes(runErr.Error(), 1000)
+	} else {
+		expiresAt := now.Add(time.Duration(s.cfg.RetentionHours) * time.Hour)
+		task.Status = model.ExportStatusSuccess
+		task.ExpiresAt = &expiresAt
+	}
+	if err := s.taskRepo.Update(task); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// RunPending 供导出任务脚本调用：把长时间没有进度的执行中任务标记为失败，清理过期文件，
+// 并依次执行等待中的任务，返回执行的任务数
+func (s *exportService) RunPending(now time.Time) (int, error) {
+	running, err := s.taskRepo.GetByStatus(model.ExportStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	for _, task := range running {
+		if task.UpdatedAt.After(now.Add(-exportStaleAfter)) {
+			continue
+		}
+		task.Status = model.ExportStatusFailed
+		task.Error = "导出中断，请重新创建导出任务"
+		task.FinishedAt = &now
+		if err := s.taskRepo.Update(task); err != nil {
+			return 0, err
+		}
+	}
+
+	expired, err := s.taskRepo.GetExpired(now)
+	if err != nil {
+		return 0, err
+	}
+	for _, task := range expired {
+		if task.File != "" {
+			if err := os.Remove(filepath.Join(s.cfg.StorageDir, filepath.FromSlash(task.File))); err != nil && !os.IsNotExist(err) {
+				return 0, err
+			}
+		}
+		task.Status = model.ExportStatusExpired
+		task.File = ""
+		if err := s.taskRepo.Update(task); err != nil {
+			return 0, err
+		}
+	}
+
+	pending, err := s.taskRepo.GetByStatus(model.ExportStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for _, task := range pending {
+		if err := s.Process(task.ID); err != nil {
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// Download 校验下载地址的签名和有效期，返回文件的本地路径和下载文件名
+func (s *exportService) Download(id uint64, expires, signature string) (string, string, error) {
+	if s.cfg.SigningKey == "" {
+		return "", "", errors.New("未配置下载地址签名密钥")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(id, expiresAt))) {
+		return "", "", errors.New("下载地址无效")
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", "", errors.New("下载地址已过期")
+	}
+
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil || task.Status != model.ExportStatusSuccess || task.File == "" {
+		return "", "", errors.New("导出文件不存在或已过期")
+	}
+	path := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(task.File))
+	if _, err := os.Stat(path); err != nil {
+		return "", "", errors.New("导出文件不存在或已过期")
+	}
+	return path, exportFilename(task.CreatedAt, task.Format), nil
+}
+
+// run 按任务的筛选条件写出文件，先写入临时文件，完成后再改名
+func (s *exportService) run(task *model.ExportTask) error {
+	var filter OpinionFilter
+	if err := json.Unmarshal([]byte(task.Filter), &filter); err != nil {
+		return fmt.Errorf("解析筛选条件失败: %w", err)
+	}
+
+	dir := filepath.Join(s.cfg.StorageDir, task.CreatedAt.Format("20060102"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建导出目录失败: %w", err)
+	}
+	name := fmt.Sprintf("%d.%s", task.ID, task.Format)
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	writer, err := export.NewWriter(task.Format, f)
+	if err != nil {
+		return err
+	}
+	processed, err := s.writeOpinions(writer, filter, s.cfg.MaxRows, func(processed int64) {
+		_ = s.taskRepo.UpdateProgress(task.ID, processed)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("保存导出文件失败: %w", err)
+	}
+
+	task.Processed = processed
+	task.File = task.CreatedAt.Format("20060102") + "/" + name
+	task.FileSize = info.Size()
+	return nil
+}
+
+// writeOpinions 按 ID 顺序分批读取符合条件的舆情并写出，最多 limit 条；每批写完后调用 progress
+func (s *exportService) writeOpinions(writer export.Writer, filter OpinionFilter, limit int, progress func(int64)) (int64, error) {
+	var (
+		afterID   uint64
+		processed int64
+	)
+	for processed < int64(limit) {
+		size := exportBatchSize
+		if remaining := int64(limit) - processed; remaining < int64(size) {
+			size = int(remaining)
+		}
+		opinions, err := s.opinionRepo.ListAfterID(filter, afterID, size)
+		if err != nil {
+			return processed, errors.New("读取舆情失败")
+		}
+		for _, opinion := range opinions {
+			if err := writer.Write(opinion); err != nil {
+				return processed, fmt.Errorf("写入导出文件失败: %w", err)
+			}
+			processed++
+		}
+		if err := writer.Flush(); err != nil {
+			return processed, fmt.Errorf("写入导出文件失败: %w", err)
+		}
+		if progress != nil {
+			progress(processed)
+		}
+		if len(opinions) < size {
+			break
+		}
+		afterID = opinions[len(opinions)-1].ID
+	}
+	return processed, nil
+}
+
+// decorate 填充进度、筛选条件和下载地址
+func (s *exportService) decorate(task *model.ExportTask) {
+	switch {
+	case task.Status == model.ExportStatusSuccess || task.Status == model.ExportStatusExpired:
+		task.Progress = 1
+	case task.Total > 0:
+		task.Progress = float64(task.Processed) / float64(task.Total)
+		if task.Progress > 1 {
+			task.Progress = 1
+		}
+	}
+	_ = json.Unmarshal([]byte(task.Filter), &task.Query)
+
+	if task.Status != model.ExportStatusSuccess || task.ExpiresAt == nil || s.cfg.SigningKey == "" {
+		return
+	}
+	expiresAt := time.Now().Add(time.Duration(s.cfg.URLTTLMinutes) * time.Minute)
+	if task.ExpiresAt.Before(expiresAt) {
+		expiresAt = *task.ExpiresAt
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", s.sign(task.ID, expiresAt.Unix()))
+	task.DownloadURL = fmt.Sprintf("/api/v1/exports/%d/download?%s", task.ID, query.Encode())
+}
+
+// sign 计算下载地址的签名
+func (s *exportService) sign(id uint64, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.SigningKey))
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// exportFilename 导出文件的下载文件名，如 opinions-20240115150405.csv
+func exportFilename(t time.Time, format string) string {
+	return fmt.Sprintf("opinions-%s.%s", t.Format("20060102150405"), format)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+)
+
+func successfulExportTask() *model.ExportTask {
+	expiresAt := time.Now().Add(time.Hour)
+	return &model.ExportTask{ID: 5, Status: model.ExportStatusSuccess, ExpiresAt: &expiresAt}
+}
+
+func TestExportWithoutSigningKeyIssuesNoDownloadURL(t *testing.T) {
+	s := NewExportService(&config.ExportConfig{}, nil, nil).(*exportService)
+
+	task := successfulExportTask()
+	s.decorate(task)
+	if task.DownloadURL != "" {
+		t.Errorf("DownloadURL = %q, want none without signing_key", task.DownloadURL)
+	}
+
+	// 任何签名都不被接受，包括用空密钥计算出的签名
+	expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	expiresAt, _ := strconv.ParseInt(expires, 10, 64)
+	if _, _, err := s.Download(5, expires, s.sign(5, expiresAt)); err == nil {
+		t.Error("Download() accepted a signature without signing_key")
+	}
+}
+
+func TestExportDownloadURLSignature(t *testing.T) {
+	s := NewExportService(&config.ExportConfig{SigningKey: "test-key", URLTTLMinutes: 10}, nil, nil).(*exportService)
+
+	task := successfulExportTask()
+	s.decorate(task)
+	if !strings.HasPrefix(task.DownloadURL, "/api/v1/exports/5/download?") {
+		t.Fatalf("DownloadURL = %q", task.DownloadURL)
+	}
+	u, err := url.Parse(task.DownloadURL)
+	if err != nil {
+		t.Fatalf("parse DownloadURL: %v", err)
+	}
+	expiresAt, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if got := u.Query().Get("signature"); got != s.sign(5, expiresAt) {
+		t.Errorf("signature = %q, want %q", got, s.sign(5, expiresAt))
+	}
+
+	other := NewExportService(&config.ExportConfig{SigningKey: "other-key"}, nil, nil).(*exportService)
+	if _, _, err := other.Download(5, u.Query().Get("expires"), u.Query().Get("signature")); err == nil || err.Error() != "下载地址无效" {
+		t.Errorf("Download() with another key error = %v, want 下载地址无效", err)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"sentinel-opinion-monitor/internal/realtime"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+const (
+	// FeedMaxSubscriptions 单个推送连接最多订阅的场景和监测组数量
+	FeedMaxSubscriptions = 50
+	// FeedBacklogLimit 断线续传时最多补发的命中舆情数，超过时客户端需重新拉取列表
+	FeedBacklogLimit = 500
+)
+
+// FeedSubscription 推送连接订阅的场景和监测组
+type FeedSubscription struct {
+	ScenarioIDs []uint64
+	GroupIDs    []uint64
+	scenarios   map[uint64]bool
+	groups      map[uint64]bool
+	topics      []string
+}
+
+// Topics 需要监听的实时主题（监测组按所属场景的主题监听）
+func (s *FeedSubscription) Topics() []string {
+	return s.topics
+}
+
+// Match 检查命中舆情是否属于订阅范围
+func (s *FeedSubscription) Match(item *repository.FeedItem) bool {
+	return s.scenarios[item.ScenarioID] || s.groups[item.GroupID]
+}
+
+// FeedService 命中舆情实时推送服务接口
+type FeedService interface {
+	Subscribe(scenarioIDs, groupIDs []uint64) (*FeedSubscription, error)
+	Backlog(subscription *FeedSubscription, afterID uint64) ([]*repository.FeedItem, bool, error)
+	Publish(groupID uint64, opinionIDs []uint64) (int, error)
+}
+
+type feedService struct {
+	hitRepo      repository.OpinionHitRepository
+	scenarioRepo repository.ScenarioRepository
+	groupRepo    repository.MonitoringGroupRepository
+}
+
+// NewFeedService 创建命中舆情实时推送服务实例
+func NewFeedService(hitRepo repository.OpinionHitRepository, scenarioRepo repository.ScenarioRepository, groupRepo repository.MonitoringGroupRepository) FeedService {
+	return &feedService{
+		hitRepo:      hitRepo,
+		scenarioRepo: scenarioRepo,
+		groupRepo:    groupRepo,
+	}
+}
+
+// FeedTopic 场景命中舆情的实时推送主题
+func FeedTopic(scenarioID uint64) string {
+	return fmt.Sprintf("feed:scenario:%d", scenarioID)
+}
+
+// Subscribe 校验订阅的场景和监测组并生成订阅
+func (s *feedService) Subscribe(scenarioIDs, groupIDs []uint64) (*FeedSubscription, error) {
+	scenarioIDs = uniqueIDs(scenarioIDs)
+	groupIDs = uniqueIDs(groupIDs)
+	if len(scenarioIDs) == 0 && len(groupIDs) == 0 {
+		return nil, errors.New("请指定要订阅的场景或监测组")
+	}
+	if len(scenarioIDs)+len(groupIDs) > FeedMaxSubscriptions {
+		return nil, fmt.Errorf("最多订阅 %d 个场景和监测组", FeedMaxSubscriptions)
+	}
+
+	subscription := &FeedSubscription{
+		ScenarioIDs: scenarioIDs,
+		GroupIDs:    groupIDs,
+		scenarios:   make(map[uint64]bool, len(scenarioIDs)),
+		groups:      make(map[uint64]bool, len(groupIDs)),
+	}
+	topics := make(map[uint64]bool)
+	for _, id := range scenarioIDs {
+		if _, err := s.scenarioRepo.GetByID(id); err != nil {
+			return nil, fmt.Errorf("场景 %d 不存在", id)
+		}
+		subscription.scenarios[id] = true
+		topics[id] = true
+	}
+	for _, id := range groupIDs {
+		group, err := s.groupRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("监测组 %d 不存在", id)
+		}
+		subscription.groups[id] = true
+		topics[group.ScenarioID] = true
+	}
+	for scenarioID := range topics {
+		subscription.topics = append(subscription.topics, FeedTopic(scenarioID))
+	}
+	return subscription, nil
+}
+
+// Backlog 获取 afterID 之后的命中舆情用于断线续传；超过 FeedBacklogLimit 条时不返回数据，truncated 为 true
+func (s *feedService) Backlog(subscription *FeedSubscription, afterID uint64) ([]*repository.FeedItem, bool, error) {
+	items, err := s.hitRepo.GetFeed(repository.FeedFilter{
+		ScenarioIDs: subscription.ScenarioIDs,
+		GroupIDs:    subscription.GroupIDs,
+		AfterID:     afterID,
+	}, FeedBacklogLimit+1)
+	if err != nil {
+		return nil, false, errors.New("获取待补发舆情失败")
+	}
+	if len(items) > FeedBacklogLimit {
+		return nil, true, nil
+	}
+	return items, false, nil
+}
+
+// Publish 推送监测组新命中的舆情，命中记录从数据库读取以获得准确的 ID；返回推送条数
+func (s *feedService) Publish(groupID uint64, opinionIDs []uint64) (int, error) {
+	published := 0
+	for start := 0; start < len(opinionIDs); start += FeedBacklogLimit {
+		end := start + FeedBacklogLimit
+		if end > len(opinionIDs) {
+			end = len(opinionIDs)
+		}
+		items, err := s.hitRepo.GetFeed(repository.FeedFilter{
+			GroupIDs:   []uint64{groupID},
+			OpinionIDs: opinionIDs[start:end],
+		}, end-start)
+		if err != nil {
+			return published, err
+		}
+		for _, item := range items {
+			if err := realtime.Publish(FeedTopic(item.ScenarioID), item); err != nil {
+				return published, err
+			}
+			published++
+		}
+	}
+	return published, nil
+}
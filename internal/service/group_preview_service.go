@@ -0,0 +1,345 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+const (
+	// maxPreviewTerms 预览时关键词和排除词各自的数量上限
+	maxPreviewTerms = 200
+	// maxPreviewSampleSize 预览返回的样例数上限
+	maxPreviewSampleSize = 100
+	// previewBatchSize 预览时每批读取的舆情数
+	previewBatchSize = 1000
+	// previewSnippetLength 样例摘要的长度（字符数）
+	previewSnippetLength = 120
+)
+
+// GroupPreviewParams 监测组规则预览参数
+// 指定 GroupID 时，未传的关键词、排除词和渠道沿用该监测组当前的配置，并按该监测组的来源过滤规则和所属场景关注的作者匹配；
+// 未指定 GroupID 时按 ScenarioID 对应场景关注的作者匹配
+type GroupPreviewParams struct {
+	GroupID        uint64
+	ScenarioID     uint64
+	Keywords       []string
+	ExclusionWords []string
+	ChannelIDs     []uint64
+	StartTime      *time.Time
+	EndTime        *time.Time
+	SampleSize     int
+}
+
+// PreviewTermCount 关键词或排除词的预览统计
+type PreviewTermCount struct {
+	Word    string `json:"word"`
+	Matched int64  `json:"matched"`        // 关键词：包含该词的舆情数（排除前）；排除词：被该词排除的舆情数
+	Hits    int64  `json:"hits,omitempty"` // 关键词：最终命中记录中记为该关键词的舆情数（按关键词顺序取第一个命中的）
+	Only    int64  `json:"only,omitempty"` // 排除词：只被该词排除的舆情数，删除该词后这些舆情会被命中
+}
+
+// PreviewSample 预览样例
+type PreviewSample struct {
+	OpinionID  uint64    `json:"opinion_id"`
+	Source     string    `json:"source"`
+	Author     string    `json:"author"`
+	Snippet    string    `json:"snippet"` // 已做 HTML 转义，命中的关键词以 <em> 标记
+	Sentiment  string    `json:"sentiment"`
+	Keyword    string    `json:"keyword"`
+	Watched    bool      `json:"watched,omitempty"`     // 作者被场景关注
+	ExcludedBy []string  `json:"excluded_by,omitempty"` // 排除该舆情的排除词
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GroupPreviewResult 监测组规则预览结果
+type GroupPreviewResult struct {
+	StartTime       time.Time           `json:"start_time"`
+	EndTime         time.Time           `json:"end_time"`
+	Total           int64               `json:"total"`           // 时间范围内的舆情数
+	Scanned         int64               `json:"scanned"`         // 符合渠道限制的舆情数
+	Matched         int64               `json:"matched"`         // 命中关键词或作者被场景关注的舆情数（排除前）
+	Watched         int64               `json:"watched"`         // 其中作者被场景关注的舆情数
+	SourceFiltered  int64               `json:"source_filtered"` // 被来源过滤规则排除的舆情数
+	Excluded        int64               `json:"excluded"`        // 被排除词排除的舆情数
+	Hits            int64               `json:"hits"`            // 最终命中的舆情数
+	HitRate         float64             `json:"hit_rate"`        // 最终命中数 / 符合渠道限制的舆情数
+	Keywords        []*PreviewTermCount `json:"keywords"`
+	ExclusionWords  []*PreviewTermCount `json:"exclusion_words"`
+	Samples         []*PreviewSample    `json:"samples"`          // 最新的命中样例
+	ExcludedSamples []*PreviewSample    `json:"excluded_samples"` // 最新的被排除样例
+}
+
+// GroupPreviewService 监测组规则预览服务接口
+type GroupPreviewService interface {
+	Preview(params *GroupPreviewParams) (*GroupPreviewResult, error)
+}
+
+type groupPreviewService struct {
+	cfg            config.PreviewConfig
+	groupRepo      repository.MonitoringGroupRepository
+	channelRepo    repository.ChannelRepository
+	opinionRepo    repository.OpinionRepository
+	authorRepo     repository.AuthorRepository
+	segmentService SegmentService
+}
+
+// NewGroupPreviewService 创建监测组规则预览服务实例
+func NewGroupPreviewService(
+	cfg *config.PreviewConfig,
+	groupRepo repository.MonitoringGroupRepository,
+	channelRepo repository.ChannelRepository,
+	opinionRepo repository.OpinionRepository,
+	authorRepo repository.AuthorRepository,
+	segmentService SegmentService,
+) GroupPreviewService {
+	s := &groupPreviewService{
+		groupRepo:      groupRepo,
+		channelRepo:    channelRepo,
+		opinionRepo:    opinionRepo,
+		authorRepo:     authorRepo,
+		segmentService: segmentService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.MaxOpinions <= 0 {
+		s.cfg.MaxOpinions = 50000
+	}
+	if s.cfg.DefaultDays <= 0 {
+		s.cfg.DefaultDays = 7
+	}
+	if s.cfg.SampleSize <= 0 {
+		s.cfg.SampleSize = 20
+	}
+	return s
+}
+
+// Preview 用草稿的关键词、排除词和渠道匹配时间范围内已入库的舆情，逐条匹配与舆情扫描共用 groupMatcher（不考虑采集计划），
+// 返回命中数、各关键词和排除词的影响以及样例，不保存任何数据
+func (s *groupPreviewService) Preview(params *GroupPreviewParams) (*GroupPreviewResult, error) {
+	if params == nil {
+		return nil, errors.New("请求参数错误")
+	}
+	draft, err := s.draftGroup(params)
+	if err != nil {
+		return nil, err
+	}
+	watched, err := watchedAuthors(s.authorRepo, draft.ScenarioID)
+	if err != nil {
+		return nil, errors.New("获取关注的作者失败")
+	}
+	if len(draft.Keywords) == 0 && len(watched) == 0 {
+		return nil, errors.New("关键词不能为空")
+	}
+
+	end := time.Now()
+	if params.EndTime != nil {
+		end = *params.EndTime
+	}
+	start := end.AddDate(0, 0, -s.cfg.DefaultDays)
+	if params.StartTime != nil {
+		start = *params.StartTime
+	}
+	if !start.Before(end) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	sampleSize := params.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = s.cfg.SampleSize
+	}
+	if sampleSize > maxPreviewSampleSize {
+		sampleSize = maxPreviewSampleSize
+	}
+
+	filter := OpinionFilter{StartTime: &start, EndTime: &end}
+	total, err := s.opinionRepo.Count(filter)
+	if err != nil {
+		return nil, errors.New("统计舆情失败")
+	}
+	if total > int64(s.cfg.MaxOpinions) {
+		return nil, fmt.Errorf("时间范围内共有 %d 条舆情，超过预览上限 %d 条，请缩小时间范围", total, s.cfg.MaxOpinions)
+	}
+
+	// 草稿中的词语加入用户词典，保证与保存后扫描时的分词一致
+	var words []string
+	for _, keyword := range draft.Keywords {
+		words = append(words, strings.Fields(keyword.Keyword)...)
+	}
+	for _, word := range draft.ExclusionWords {
+		words = append(words, strings.Fields(word.Word)...)
+	}
+	segmenter, err := s.segmentService.GetSegmenterWith(words)
+	if err != nil {
+		return nil, errors.New("加载分词词典失败")
+	}
+	matcher := newGroupMatcher(draft, watched, segmenter)
+
+	result := &GroupPreviewResult{
+		StartTime:       start,
+		EndTime:         end,
+		Total:           total,
+		Keywords:        make([]*PreviewTermCount, len(draft.Keywords)),
+		ExclusionWords:  make([]*PreviewTermCount, len(draft.ExclusionWords)),
+		Samples:         []*PreviewSample{},
+		ExcludedSamples: []*PreviewSample{},
+	}
+	for i, keyword := range draft.Keywords {
+		result.Keywords[i] = &PreviewTermCount{Word: keyword.Keyword}
+	}
+	for i, word := range draft.ExclusionWords {
+		result.ExclusionWords[i] = &PreviewTermCount{Word: word.Word}
+	}
+
+	var afterID uint64
+	for {
+		opinions, err := s.opinionRepo.ListAfterID(filter, afterID, previewBatchSize)
+		if err != nil {
+			return nil, errors.New("查询舆情失败")
+		}
+		for _, opinion := range opinions {
+			match := matcher.match(opinion)
+			if match.stage == matchOffChannel {
+				continue
+			}
+			result.Scanned++
+			for _, i := range match.keywords {
+				result.Keywords[i].Matched++
+			}
+			if match.stage == matchNoKeyword {
+				continue
+			}
+			result.Matched++
+			if match.watched {
+				result.Watched++
+			}
+
+			switch match.stage {
+			case matchSourceFiltered:
+				result.SourceFiltered++
+			case matchExcluded:
+				result.Excluded++
+				names := make([]string, len(match.excludedBy))
+				for j, i := range match.excludedBy {
+					result.ExclusionWords[i].Matched++
+					names[j] = draft.ExclusionWords[i].Word
+				}
+				if len(match.excludedBy) == 1 {
+					result.ExclusionWords[match.excludedBy[0]].Only++
+				}
+				sample := previewSample(opinion, match)
+				sample.ExcludedBy = names
+				result.ExcludedSamples = appendLatest(result.ExcludedSamples, sample, sampleSize)
+			case matchHit:
+				result.Hits++
+				if len(match.keywords) > 0 {
+					result.Keywords[match.keywords[0]].Hits++
+				}
+				result.Samples = appendLatest(result.Samples, previewSample(opinion, match), sampleSize)
+			}
+		}
+		if len(opinions) < previewBatchSize {
+			break
+		}
+		afterID = opinions[len(opinions)-1].ID
+	}
+
+	if result.Scanned > 0 {
+		result.HitRate = float64(result.Hits) / float64(result.Scanned)
+	}
+	reverseSamples(result.Samples)
+	reverseSamples(result.ExcludedSamples)
+	return result, nil
+}
+
+// draftGroup 根据参数构造用于匹配的监测组草稿
+func (s *groupPreviewService) draftGroup(params *GroupPreviewParams) (*model.MonitoringGroup, error) {
+	draft := &model.MonitoringGroup{ScenarioID: params.ScenarioID}
+	if params.GroupID > 0 {
+		group, err := s.groupRepo.GetWithDetails(params.GroupID)
+		if err != nil {
+			return nil, errors.New("监测组不存在")
+		}
+		draft.ScenarioID = group.ScenarioID
+		draft.Keywords = group.Keywords
+		draft.ExclusionWords = group.ExclusionWords
+		draft.Channels = group.Channels
+		draft.SourceFilters = group.SourceFilters
+	}
+
+	if params.Keywords != nil {
+		draft.Keywords = nil
+		for _, keyword := range uniqueStrings(trimStrings(params.Keywords)) {
+			draft.Keywords = append(draft.Keywords, model.GroupKeyword{Keyword: keyword})
+		}
+	}
+	if params.ExclusionWords != nil {
+		draft.ExclusionWords = nil
+		for _, word := range uniqueStrings(trimStrings(params.ExclusionWords)) {
+			draft.ExclusionWords = append(draft.ExclusionWords, model.GroupExclusionWord{Word: word})
+		}
+	}
+	if params.ChannelIDs != nil {
+		draft.Channels = nil
+		for _, id := range uniqueIDs(params.ChannelIDs) {
+			channel, err := s.channelRepo.GetByID(id)
+			if err != nil {
+				return nil, fmt.Errorf("渠道%d不存在", id)
+			}
+			draft.Channels = append(draft.Channels, *channel)
+		}
+	}
+
+	if len(draft.Keywords) > maxPreviewTerms || len(draft.ExclusionWords) > maxPreviewTerms {
+		return nil, fmt.Errorf("关键词和排除词各自不能超过 %d 个", maxPreviewTerms)
+	}
+	return draft, nil
+}
+
+// previewSample 构造预览样例，摘要中标记命中的关键词
+func previewSample(opinion *model.Opinion, match *opinionMatch) *PreviewSample {
+	return &PreviewSample{
+		OpinionID: opinion.ID,
+		Source:    opinion.Source,
+		Author:    opinion.Author,
+		Snippet:   highlightSnippet(opinion.Content, strings.Fields(match.keyword), previewSnippetLength),
+		Sentiment: opinion.SentimentLabel,
+		Keyword:   match.keyword,
+		Watched:   match.watched,
+		CreatedAt: opinion.CreatedAt,
+	}
+}
+
+// appendLatest 按 ID 顺序追加样例，只保留最后 limit 条
+func appendLatest(samples []*PreviewSample, sample *PreviewSample, limit int) []*PreviewSample {
+	samples = append(samples, sample)
+	if len(samples) > limit {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+// reverseSamples 将样例反转为从新到旧
+func reverseSamples(samples []*PreviewSample) {
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+}
+
+// uniqueStrings 去重并保持原有顺序
+func uniqueStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
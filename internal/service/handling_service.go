@@ -0,0 +1,369 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// handlingTransitions 允许的处置状态流转
+var handlingTransitions = map[string][]string{
+	model.HandlingStatusNew:        {model.HandlingStatusInProgress, model.HandlingStatusEscalated, model.HandlingStatusIgnored},
+	model.HandlingStatusInProgress: {model.HandlingStatusResponded, model.HandlingStatusEscalated, model.HandlingStatusIgnored},
+	model.HandlingStatusEscalated:  {model.HandlingStatusInProgress, model.HandlingStatusResponded, model.HandlingStatusIgnored},
+	model.HandlingStatusResponded:  {model.HandlingStatusInProgress},
+	model.HandlingStatusIgnored:    {model.HandlingStatusNew, model.HandlingStatusInProgress},
+}
+
+// OpenHandlingStatuses 仍需处理的状态，"我的待办"默认只返回这些状态
+var OpenHandlingStatuses = []string{model.HandlingStatusNew, model.HandlingStatusInProgress, model.HandlingStatusEscalated}
+
+// mentionPattern 评论中 @用户名 的写法，用户名以空白或常见标点结束
+var mentionPattern = regexp.MustCompile(`@([^\s@，。、；：！？,;:!?()（）]+)`)
+
+// maxMentions 单条评论最多通知的用户数
+const maxMentions = 20
+
+// defaultSLAHours 未配置时各优先级的处置时限（小时）
+var defaultSLAHours = map[string]int{
+	model.PriorityUrgent: 2,
+	model.PriorityHigh:   8,
+	model.PriorityMedium: 24,
+	model.PriorityLow:    72,
+}
+
+// HandlingService 舆情处置服务接口
+type HandlingService interface {
+	Assign(opinionID, actorID uint64, isAdmin bool, assigneeID uint64, note string) (*model.Opinion, error)
+	Transition(opinionID, actorID uint64, isAdmin bool, toStatus, note string) (*model.Opinion, error)
+	Triage(opinionID, actorID uint64, priority string, dueAt *time.Time, note string) (*model.Opinion, error)
+	AddComment(opinionID, userID uint64, content string) (*model.OpinionComment, error)
+	GetComments(opinionID uint64) ([]*model.OpinionComment, error)
+	GetHistory(opinionID uint64) ([]*model.OpinionHandlingLog, error)
+	MyQueue(userID uint64, filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error)
+}
+
+type handlingService struct {
+	opinionRepo  repository.OpinionRepository
+	handlingRepo repository.OpinionHandlingRepository
+	userRepo     repository.UserRepository
+	inboxService InboxService
+	slaHours     map[string]int
+}
+
+// NewHandlingService 创建舆情处置服务实例
+func NewHandlingService(cfg *config.WorkflowConfig, opinionRepo repository.OpinionRepository, handlingRepo repository.OpinionHandlingRepository, userRepo repository.UserRepository, inboxService InboxService) HandlingService {
+	slaHours := make(map[string]int, len(defaultSLAHours))
+	for priority, hours := range defaultSLAHours {
+		slaHours[priority] = hours
+	}
+	if cfg != nil {
+		for priority, hours := range cfg.SLAHours {
+			if _, ok := slaHours[priority]; ok && hours > 0 {
+				slaHours[priority] = hours
+			}
+		}
+	}
+
+	return &handlingService{
+		opinionRepo:  opinionRepo,
+		handlingRepo: handlingRepo,
+		userRepo:     userRepo,
+		inboxService: inboxService,
+		slaHours:     slaHours,
+	}
+}
+
+// IsValidHandlingStatus 检查处置状态是否有效
+func IsValidHandlingStatus(status string) bool {
+	_, ok := handlingTransitions[status]
+	return ok
+}
+
+// IsValidPriority 检查优先级是否有效
+func IsValidPriority(priority string) bool {
+	_, ok := defaultSLAHours[priority]
+	return ok
+}
+
+// Assign 分配处理人（assigneeID 为 0 表示取消分配），未设置截止时间时按优先级 SLA 计算；
+// 已分配的舆情只有处理人或管理员可以重新分配；分配给他人时给处理人发送站内通知
+func (s *handlingService) Assign(opinionID, actorID uint64, isAdmin bool, assigneeID uint64, note string) (*model.Opinion, error) {
+	opinion, err := s.opinionRepo.GetByID(opinionID)
+	if err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	if opinion.AssigneeID > 0 && opinion.AssigneeID != actorID && !isAdmin {
+		return nil, errors.New("只有处理人或管理员可以重新分配")
+	}
+	if assigneeID > 0 {
+		user, err := s.userRepo.GetByID(assigneeID)
+		if err != nil {
+			return nil, errors.New("处理人不存在")
+		}
+		if user.Status != 1 {
+			return nil, errors.New("处理人已被禁用")
+		}
+	}
+
+	fields := map[string]interface{}{"assignee_id": assigneeID}
+	if assigneeID > 0 && opinion.DueAt == nil {
+		fields["due_at"] = *s.slaDueAt(opinion.Priority, time.Now())
+	}
+	if err := s.update(opinion, fields); err != nil {
+		return nil, err
+	}
+
+	s.writeLog(&model.OpinionHandlingLog{
+		OpinionID:  opinionID,
+		UserID:     actorID,
+		Action:     model.HandlingActionAssign,
+		FromStatus: opinion.HandlingStatus,
+		ToStatus:   opinion.HandlingStatus,
+		AssigneeID: assigneeID,
+		Note:       note,
+	})
+
+	if assigneeID > 0 && assigneeID != actorID {
+		content := opinionSummary(opinion)
+		if note = strings.TrimSpace(note); note != "" {
+			content = "备注：" + note + "\n" + content
+		}
+		s.inboxService.Notify([]uint64{assigneeID}, model.UserNotification{
+			Type:       model.InboxTypeAssignment,
+			Title:      fmt.Sprintf("舆情 #%d 已分配给你（优先级 %s）", opinionID, opinion.Priority),
+			Content:    content,
+			SourceType: model.InboxSourceOpinion,
+			SourceID:   opinionID,
+			ActorID:    actorID,
+		})
+	}
+	return s.opinionRepo.GetByID(opinionID)
+}
+
+// Transition 变更处置状态：须符合流转规则；已分配的舆情只有处理人或管理员可以变更，
+// 未分配的舆情开始处理时自动分配给操作人；忽略和上报必须填写原因
+func (s *handlingService) Transition(opinionID, actorID uint64, isAdmin bool, toStatus, note string) (*model.Opinion, error) {
+	opinion, err := s.opinionRepo.GetByID(opinionID)
+	if err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	if !IsValidHandlingStatus(toStatus) {
+		return nil, errors.New("无效的处置状态，可选值: new, in_progress, responded, ignored, escalated")
+	}
+
+	fromStatus := opinion.HandlingStatus
+	if !canTransition(fromStatus, toStatus) {
+		return nil, errors.New("不允许从 " + fromStatus + " 变更为 " + toStatus)
+	}
+	note = strings.TrimSpace(note)
+	if note == "" && (toStatus == model.HandlingStatusIgnored || toStatus == model.HandlingStatusEscalated) {
+		return nil, errors.New("忽略或上报舆情时必须填写原因")
+	}
+	if opinion.AssigneeID > 0 && opinion.AssigneeID != actorID && !isAdmin {
+		return nil, errors.New("只有处理人或管理员可以变更处置状态")
+	}
+
+	now := time.Now()
+	assigneeID := opinion.AssigneeID
+	fields := map[string]interface{}{"handling_status": toStatus}
+	if assigneeID == 0 && toStatus == model.HandlingStatusInProgress {
+		assigneeID = actorID
+		fields["assignee_id"] = actorID
+		if opinion.DueAt == nil {
+			fields["due_at"] = *s.slaDueAt(opinion.Priority, now)
+		}
+	}
+	if toStatus == model.HandlingStatusResponded || toStatus == model.HandlingStatusIgnored {
+		fields["handled_at"] = now
+	}
+	if err := s.update(opinion, fields); err != nil {
+		return nil, err
+	}
+
+	s.writeLog(&model.OpinionHandlingLog{
+		OpinionID:  opinionID,
+		UserID:     actorID,
+		Action:     model.HandlingActionTransition,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		AssigneeID: assigneeID,
+		Note:       note,
+	})
+	return s.opinionRepo.GetByID(opinionID)
+}
+
+// Triage 调整优先级和截止时间，未指定截止时间时按新优先级的 SLA 从当前时间重新计算
+func (s *handlingService) Triage(opinionID, actorID uint64, priority string, dueAt *time.Time, note string) (*model.Opinion, error) {
+	opinion, err := s.opinionRepo.GetByID(opinionID)
+	if err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	if priority == "" {
+		priority = opinion.Priority
+	}
+	if !IsValidPriority(priority) {
+		return nil, errors.New("无效的优先级，可选值: low, medium, high, urgent")
+	}
+
+	due := dueAt
+	if due == nil {
+		due = s.slaDueAt(priority, time.Now())
+	}
+	fields := map[string]interface{}{
+		"priority": priority,
+		"due_at":   *due,
+	}
+	if err := s.update(opinion, fields); err != nil {
+		return nil, err
+	}
+
+	if note = strings.TrimSpace(note); note == "" {
+		note = "优先级 " + priority + "，截止时间 " + due.Format("2006-01-02 15:04:05")
+	}
+	s.writeLog(&model.OpinionHandlingLog{
+		OpinionID:  opinionID,
+		UserID:     actorID,
+		Action:     model.HandlingActionTriage,
+		FromStatus: opinion.HandlingStatus,
+		ToStatus:   opinion.HandlingStatus,
+		AssigneeID: opinion.AssigneeID,
+		Note:       note,
+	})
+	return s.opinionRepo.GetByID(opinionID)
+}
+
+// AddComment 添加内部评论，评论中 @用户名 提到的用户会收到站内通知
+func (s *handlingService) AddComment(opinionID, userID uint64, content string) (*model.OpinionComment, error) {
+	opinion, err := s.opinionRepo.GetByID(opinionID)
+	if err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("评论内容不能为空")
+	}
+
+	comment := &model.OpinionComment{
+		OpinionID: opinionID,
+		UserID:    userID,
+		Content:   content,
+	}
+	if err := s.handlingRepo.CreateComment(comment); err != nil {
+		return nil, errors.New("添加评论失败")
+	}
+
+	if mentioned := s.mentionedUsers(content, userID); len(mentioned) > 0 {
+		s.inboxService.Notify(mentioned, model.UserNotification{
+			Type:       model.InboxTypeMention,
+			Title:      fmt.Sprintf("有人在舆情 #%d 的评论中提到了你", opinionID),
+			Content:    truncateRunes(content, 500) + "\n" + opinionSummary(opinion),
+			SourceType: model.InboxSourceOpinion,
+			SourceID:   opinionID,
+			ActorID:    userID,
+		})
+	}
+	return comment, nil
+}
+
+// GetComments 获取舆情的内部评论
+func (s *handlingService) GetComments(opinionID uint64) ([]*model.OpinionComment, error) {
+	if _, err := s.opinionRepo.GetByID(opinionID); err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	return s.handlingRepo.GetComments(opinionID)
+}
+
+// GetHistory 获取舆情的处置记录
+func (s *handlingService) GetHistory(opinionID uint64) ([]*model.OpinionHandlingLog, error) {
+	if _, err := s.opinionRepo.GetByID(opinionID); err != nil {
+		return nil, errors.New("舆情不存在")
+	}
+	return s.handlingRepo.GetLogs(opinionID)
+}
+
+// MyQueue 获取分配给用户的舆情，未指定状态时只返回待处理、处理中和已上报的舆情
+func (s *handlingService) MyQueue(userID uint64, filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error) {
+	filter.AssigneeID = userID
+	if len(filter.HandlingStatuses) == 0 {
+		filter.HandlingStatuses = OpenHandlingStatuses
+	}
+	return s.handlingRepo.ListQueue(filter, page, pageSize)
+}
+
+// update 以当前处置状态为条件更新，防止并发操作互相覆盖
+func (s *handlingService) update(opinion *model.Opinion, fields map[string]interface{}) error {
+	ok, err := s.handlingRepo.UpdateHandling(opinion.ID, opinion.HandlingStatus, fields)
+	if err != nil {
+		return errors.New("更新处置信息失败")
+	}
+	if !ok {
+		return errors.New("舆情处置状态已变化，请刷新后重试")
+	}
+	return nil
+}
+
+// slaDueAt 按优先级 SLA 计算截止时间
+func (s *handlingService) slaDueAt(priority string, from time.Time) *time.Time {
+	hours, ok := s.slaHours[priority]
+	if !ok {
+		hours = s.slaHours[model.PriorityMedium]
+	}
+	due := from.Add(time.Duration(hours) * time.Hour)
+	return &due
+}
+
+// writeLog 写入处置记录，失败只记录日志
+func (s *handlingService) writeLog(log *model.OpinionHandlingLog) {
+	log.Note = truncateRunes(log.Note, 1000)
+	if err := s.handlingRepo.CreateLog(log); err != nil {
+		appLogger.Get().Error("写入舆情处置记录失败", zap.Uint64("opinion_id", log.OpinionID), zap.Error(err))
+	}
+}
+
+// mentionedUsers 解析评论中提到的正常状态用户，不包括评论者本人
+func (s *handlingService) mentionedUsers(content string, authorID uint64) []uint64 {
+	var ids []uint64
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		if len(seen) > maxMentions {
+			break
+		}
+
+		user, err := s.userRepo.GetByUsername(username)
+		if err != nil || user.Status != 1 || user.ID == authorID {
+			continue
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// opinionSummary 站内通知中展示的舆情摘要
+func opinionSummary(opinion *model.Opinion) string {
+	return fmt.Sprintf("[%s] %s", opinion.Source, truncateRunes(opinion.Content, 100))
+}
+
+// canTransition 检查状态流转是否允许
+func canTransition(from, to string) bool {
+	for _, next := range handlingTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// fakeHandlingOpinionRepo 只实现处置流程用到的方法
+type fakeHandlingOpinionRepo struct {
+	repository.OpinionRepository
+	opinions map[uint64]*model.Opinion
+}
+
+func (r *fakeHandlingOpinionRepo) GetByID(id uint64) (*model.Opinion, error) {
+	if opinion, ok := r.opinions[id]; ok {
+		copied := *opinion
+		return &copied, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// fakeHandlingRepo 按条件更新内存中的舆情并记录处置日志
+type fakeHandlingRepo struct {
+	repository.OpinionHandlingRepository
+	opinions map[uint64]*model.Opinion
+	logs     []*model.OpinionHandlingLog
+}
+
+func (r *fakeHandlingRepo) UpdateHandling(opinionID uint64, fromStatus string, fields map[string]interface{}) (bool, error) {
+	opinion := r.opinions[opinionID]
+	if opinion == nil || opinion.HandlingStatus != fromStatus {
+		return false, nil
+	}
+	if v, ok := fields["assignee_id"]; ok {
+		opinion.AssigneeID = v.(uint64)
+	}
+	if v, ok := fields["handling_status"]; ok {
+		opinion.HandlingStatus = v.(string)
+	}
+	return true, nil
+}
+
+func (r *fakeHandlingRepo) CreateLog(log *model.OpinionHandlingLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+}
+
+func (fakeUserRepo) GetByID(id uint64) (*model.User, error) {
+	return &model.User{ID: id, Status: 1}, nil
+}
+
+type fakeInboxService struct {
+	InboxService
+	notified []uint64
+}
+
+func (s *fakeInboxService) Notify(userIDs []uint64, tmpl model.UserNotification) {
+	s.notified = append(s.notified, userIDs...)
+}
+
+func TestHandlingAssignPermissions(t *testing.T) {
+	const (
+		assignee = 2
+		other    = 3
+		admin    = 9
+	)
+	cases := []struct {
+		name       string
+		assigneeID uint64
+		actorID    uint64
+		isAdmin    bool
+		to         uint64
+		wantErr    bool
+	}{
+		{name: "unassigned claimed by anyone", assigneeID: 0, actorID: other, to: other},
+		{name: "assignee hands over", assigneeID: assignee, actorID: assignee, to: other},
+		{name: "admin reassigns", assigneeID: assignee, actorID: admin, isAdmin: true, to: other},
+		{name: "other user cannot take over", assigneeID: assignee, actorID: other, to: other, wantErr: true},
+		{name: "other user cannot unassign", assigneeID: assignee, actorID: other, to: 0, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opinions := map[uint64]*model.Opinion{
+				1: {ID: 1, HandlingStatus: model.HandlingStatusNew, AssigneeID: c.assigneeID, Priority: model.PriorityMedium},
+			}
+			handlingRepo := &fakeHandlingRepo{opinions: opinions}
+			svc := NewHandlingService(nil, &fakeHandlingOpinionRepo{opinions: opinions}, handlingRepo, fakeUserRepo{}, &fakeInboxService{})
+
+			_, err := svc.Assign(1, c.actorID, c.isAdmin, c.to, "")
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("Assign() error = nil, want permission error")
+				}
+				if opinions[1].AssigneeID != c.assigneeID || len(handlingRepo.logs) != 0 {
+					t.Errorf("denied Assign changed the opinion: assignee = %d, logs = %d", opinions[1].AssigneeID, len(handlingRepo.logs))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Assign() error = %v", err)
+			}
+			if opinions[1].AssigneeID != c.to {
+				t.Errorf("assignee = %d, want %d", opinions[1].AssigneeID, c.to)
+			}
+		})
+	}
+}
+
+func TestHandlingAssignThenTransitionRequiresAssignee(t *testing.T) {
+	opinions := map[uint64]*model.Opinion{
+		1: {ID: 1, HandlingStatus: model.HandlingStatusNew, AssigneeID: 2, Priority: model.PriorityMedium},
+	}
+	svc := NewHandlingService(nil, &fakeHandlingOpinionRepo{opinions: opinions}, &fakeHandlingRepo{opinions: opinions}, fakeUserRepo{}, &fakeInboxService{})
+
+	// 非处理人既不能把舆情改派给自己，也不能直接变更状态
+	if _, err := svc.Assign(1, 3, false, 3, ""); err == nil {
+		t.Fatal("Assign() to self by non-assignee succeeded")
+	}
+	if _, err := svc.Transition(1, 3, false, model.HandlingStatusInProgress, ""); err == nil {
+		t.Fatal("Transition() by non-assignee succeeded")
+	}
+	if _, err := svc.Transition(1, 2, false, model.HandlingStatusInProgress, ""); err != nil {
+		t.Fatalf("Transition() by assignee error = %v", err)
+	}
+}
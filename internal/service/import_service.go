@@ -0,0 +1,634 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/importer"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// importStaleAfter 执行中的任务超过该时长没有进度更新时视为中断（如进程重启）
+const importStaleAfter = 30 * time.Minute
+
+// ImportService 舆情批量导入服务接口
+type ImportService interface {
+	CreateTask(userID uint64, filename, format, source string, mapping map[string]string, r io.Reader) (*model.ImportTask, error)
+	ImportFile(path, format, source string, mapping map[string]string) (*model.ImportTask, error)
+	GetTask(id uint64) (*model.ImportTask, error)
+	ListTasks(page, pageSize int) ([]*model.ImportTask, int64, error)
+	Process(id uint64) error
+	RunPending(now time.Time) (int, error)
+	ResultFile(id uint64) (string, string, error)
+}
+
+type importService struct {
+	cfg               config.ImportConfig
+	taskRepo          repository.ImportTaskRepository
+	opinionRepo       repository.OpinionRepository
+	groupRepo         repository.MonitoringGroupRepository
+	hitRepo           repository.OpinionHitRepository
+	enrichmentService EnrichmentService
+	matchService      MatchService
+	noiseService      NoiseFilterService
+	authorService     AuthorService
+	statsService      StatsService
+	slots             chan struct{} // Web 服务中执行导入任务的并发槽位
+}
+
+// NewImportService 创建舆情批量导入服务实例
+func NewImportService(
+	cfg *config.ImportConfig,
+	taskRepo repository.ImportTaskRepository,
+	opinionRepo repository.OpinionRepository,
+	groupRepo repository.MonitoringGroupRepository,
+	hitRepo repository.OpinionHitRepository,
+	enrichmentService EnrichmentService,
+	matchService MatchService,
+	noiseService NoiseFilterService,
+	authorService AuthorService,
+	statsService StatsService,
+) ImportService {
+	s := &importService{
+		taskRepo:          taskRepo,
+		opinionRepo:       opinionRepo,
+		groupRepo:         groupRepo,
+		hitRepo:           hitRepo,
+		enrichmentService: enrichmentService,
+		matchService:      matchService,
+		noiseService:      noiseService,
+		authorService:     authorService,
+		statsService:      statsService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.StorageDir == "" {
+		s.cfg.StorageDir = "data/imports"
+	}
+	if s.cfg.MaxFileMB <= 0 {
+		s.cfg.MaxFileMB = 100
+	}
+	if s.cfg.MaxRows <= 0 {
+		s.cfg.MaxRows = 1000000
+	}
+	if s.cfg.BatchSize <= 0 {
+		s.cfg.BatchSize = 500
+	}
+	if s.cfg.MaxConcurrent <= 0 {
+		s.cfg.MaxConcurrent = 1
+	}
+	if s.cfg.RetentionHours <= 0 {
+		s.cfg.RetentionHours = 168
+	}
+	s.slots = make(chan struct{}, s.cfg.MaxConcurrent)
+	return s
+}
+
+// CreateTask 保存上传的文件并创建导入任务，尝试立即在后台执行；并发槽位已满时任务保持等待，由导入任务脚本执行
+// format 为空时按文件扩展名判断；CSV 和 XLSX 的表头在创建时校验
+func (s *importService) CreateTask(userID uint64, filename, format, source string, mapping map[string]string, r io.Reader) (*model.ImportTask, error) {
+	task, err := s.create(userID, filename, format, source, mapping, r)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		go func() {
+			defer func() { <-s.slots }()
+			_ = s.Process(task.ID)
+		}()
+	default:
+	}
+
+	s.decorate(task)
+	return task, nil
+}
+
+// ImportFile 导入本地文件并等待完成，供命令行使用；文件会先复制到存储目录
+func (s *importService) ImportFile(path, format, source string, mapping map[string]string) (*model.ImportTask, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer f.Close()
+
+	task, err := s.create(0, filepath.Base(path), format, source, mapping, f)
+	if err != nil {
+		return nil, err
+	}
+	processErr := s.Process(task.ID)
+	task, err = s.taskRepo.GetByID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.decorate(task)
+	return task, processErr
+}
+
+// GetTask 获取导入任务
+func (s *importService) GetTask(id uint64) (*model.ImportTask, error) {
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("导入任务不存在")
+	}
+	s.decorate(task)
+	return task, nil
+}
+
+// ListTasks 分页获取导入任务
+func (s *importService) ListTasks(page, pageSize int) ([]*model.ImportTask, int64, error) {
+	tasks, total, err := s.taskRepo.List(0, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, task := range tasks {
+		s.decorate(task)
+	}
+	return tasks, total, nil
+}
+
+// Process 执行等待中的导入任务；任务已被其他进程执行时直接返回
+func (s *importService) Process(id uint64) error {
+	claimed, err := s.taskRepo.Claim(id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	runErr := s.run(task)
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(s.cfg.RetentionHours) * time.Hour)
+	task.FinishedAt = &now
+	task.ExpiresAt = &expiresAt
+	if runErr != nil {
+		task.Status = model.ImportStatusFailed
+		task.Error = truncateRunes(runErr.Error(), 1000)
+	} else {
+		task.Status = model.ImportStatusSuccess
+	}
+	if err := s.taskRepo.Update(task); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// RunPending 供导入任务脚本调用：把长时间没有进度的执行中任务标记为失败，清理过期的上传文件和结果文件，
+// 并依次执行等待中的任务，返回执行的任务数
+func (s *importService) RunPending(now time.Time) (int, error) {
+	running, err := s.taskRepo.GetByStatus(model.ImportStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	for _, task := range running {
+		if task.UpdatedAt.After(now.Add(-importStaleAfter)) {
+			continue
+		}
+		// 已入库的行不会回滚，重新导入同一文件时会作为重复跳过
+		expiresAt := now.Add(time.Duration(s.cfg.RetentionHours) * time.Hour)
+		task.Status = model.ImportStatusFailed
+		task.Error = "导入中断，已导入的行会保留，重新导入同一文件时将跳过"
+		task.FinishedAt = &now
+		task.ExpiresAt = &expiresAt
+		if err := s.taskRepo.Update(task); err != nil {
+			return 0, err
+		}
+	}
+
+	expired, err := s.taskRepo.GetExpired(now)
+	if err != nil {
+		return 0, err
+	}
+	for _, task := range expired {
+		for _, file := range []string{task.File, task.ResultFile} {
+			if file == "" {
+				continue
+			}
+			if err := os.Remove(filepath.Join(s.cfg.StorageDir, filepath.FromSlash(file))); err != nil && !os.IsNotExist(err) {
+				return 0, err
+			}
+		}
+		task.Status = model.ImportStatusExpired
+		task.File = ""
+		task.ResultFile = ""
+		if err := s.taskRepo.Update(task); err != nil {
+			return 0, err
+		}
+	}
+
+	pending, err := s.taskRepo.GetByStatus(model.ImportStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for _, task := range pending {
+		if err := s.Process(task.ID); err != nil {
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// ResultFile 返回导入结果文件的本地路径和下载文件名
+func (s *importService) ResultFile(id uint64) (string, string, error) {
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return "", "", errors.New("导入任务不存在")
+	}
+	if task.ResultFile == "" {
+		return "", "", errors.New("没有结果文件（全部导入成功、任务未完成或文件已过期）")
+	}
+	path := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(task.ResultFile))
+	if _, err := os.Stat(path); err != nil {
+		return "", "", errors.New("结果文件不存在或已过期")
+	}
+	return path, fmt.Sprintf("import-%d-result.csv", task.ID), nil
+}
+
+// create 校验参数，把文件保存到存储目录并创建导入任务
+func (s *importService) create(userID uint64, filename, format, source string, mapping map[string]string, r io.Reader) (*model.ImportTask, error) {
+	if format == "" {
+		format = importer.FormatFromFilename(filename)
+	}
+	if !importer.IsValidFormat(format) {
+		return nil, errors.New("无效的文件格式，可选值: csv, xlsx, ndjson")
+	}
+	mapper, err := importer.NewMapper(s.cfg.Mapping, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if mapping == nil {
+		mapping = map[string]string{}
+	}
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dir := now.Format("20060102")
+	if err := os.MkdirAll(filepath.Join(s.cfg.StorageDir, dir), 0o755); err != nil {
+		return nil, fmt.Errorf("创建导入目录失败: %w", err)
+	}
+	file := fmt.Sprintf("%s/%d.%s", dir, now.UnixNano(), format)
+	path := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(file))
+	if err := s.save(path, r); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	if err := checkImportHeader(path, format, mapper); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	task := &model.ImportTask{
+		UserID:   userID,
+		Filename: truncateRunes(filename, 255),
+		Format:   format,
+		Source:   truncateRunes(source, 255),
+		Mapping:  string(mappingJSON),
+		File:     file,
+		Status:   model.ImportStatusPending,
+	}
+	if err := s.taskRepo.Create(task); err != nil {
+		os.Remove(path)
+		return nil, errors.New("创建导入任务失败")
+	}
+	return task, nil
+}
+
+// save 把上传的文件写入 path，超过大小上限时返回错误
+func (s *importService) save(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	defer f.Close()
+
+	limit := int64(s.cfg.MaxFileMB) << 20
+	n, err := io.Copy(f, io.LimitReader(r, limit+1))
+	if err != nil {
+		return fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	if n > limit {
+		return fmt.Errorf("文件超过 %d MB", s.cfg.MaxFileMB)
+	}
+	if n == 0 {
+		return errors.New("文件为空")
+	}
+	return f.Close()
+}
+
+// run 逐批读取文件：校验、去重、富化、入库并匹配监测组，未导入的行写入结果文件
+func (s *importService) run(task *model.ImportTask) error {
+	var mapping map[string]string
+	if task.Mapping != "" {
+		if err := json.Unmarshal([]byte(task.Mapping), &mapping); err != nil {
+			return fmt.Errorf("解析列映射失败: %w", err)
+		}
+	}
+	mapper, err := importer.NewMapper(s.cfg.Mapping, mapping)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(task.File))
+
+	// 先统计行数，用于进度和行数上限
+	total, err := countImportRows(path, task.Format)
+	if err != nil {
+		return err
+	}
+	if total > int64(s.cfg.MaxRows) {
+		return fmt.Errorf("文件有 %d 行，超过单次导入的上限 %d 行", total, s.cfg.MaxRows)
+	}
+	task.Total = total
+	if err := s.taskRepo.UpdateProgress(task); err != nil {
+		return err
+	}
+
+	groups, err := s.groupRepo.GetActiveWithDetails()
+	if err != nil {
+		return errors.New("获取监测组失败")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开上传文件失败: %w", err)
+	}
+	defer f.Close()
+	reader, err := importer.NewReader(task.Format, f)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	resultFile := filepath.Dir(task.File) + fmt.Sprintf("/%d-result.csv", task.ID)
+	resultPath := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(resultFile))
+	rf, err := os.Create(resultPath)
+	if err != nil {
+		return fmt.Errorf("创建结果文件失败: %w", err)
+	}
+	defer rf.Close()
+	// 中途失败时保留已写出的结果，便于排查
+	task.ResultFile = resultFile
+	results, err := importer.NewResultWriter(rf)
+	if err != nil {
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	defer results.Flush()
+
+	b := &importRun{
+		task:      task,
+		mapper:    mapper,
+		groups:    groups,
+		results:   results,
+		seen:      make(map[string]int),
+		scenarios: make(map[uint64]bool),
+	}
+	// 中途失败时已入库的舆情同样需要补算汇总
+	defer s.rollup(b)
+	records := make([]*importer.Record, 0, s.cfg.BatchSize)
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		records = append(records, rec)
+		if len(records) < s.cfg.BatchSize {
+			continue
+		}
+		if err := s.importBatch(b, records); err != nil {
+			return err
+		}
+		records = records[:0]
+	}
+	if err := s.importBatch(b, records); err != nil {
+		return err
+	}
+
+	if err := results.Flush(); err != nil {
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	if err := rf.Close(); err != nil {
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	if task.Duplicates+task.Invalid == 0 {
+		os.Remove(resultPath)
+		task.ResultFile = ""
+	}
+	return nil
+}
+
+// importRun 一次导入过程中跨批次共享的状态
+type importRun struct {
+	task    *model.ImportTask
+	mapper  *importer.Mapper
+	groups  []*model.MonitoringGroup
+	results *importer.ResultWriter
+	seen    map[string]int // 内容哈希 -> 文件中首次出现的行号
+
+	// 有命中的场景和导入舆情的入库时间范围，导入完成后重算这段时间的统计汇总
+	scenarios map[uint64]bool
+	earliest  time.Time
+	latest    time.Time
+}
+
+// importBatch 导入一批数据行并更新进度
+func (s *importService) importBatch(b *importRun, records []*importer.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	task := b.task
+	now := time.Now()
+
+	// 校验并去掉文件内重复的行
+	opinions := make([]*model.Opinion, 0, len(records))
+	rows := make(map[*model.Opinion]int, len(records))
+	hashes := make([]string, 0, len(records))
+	for _, rec := range records {
+		opinion, err := b.mapper.Parse(rec, task.Source, now)
+		if err != nil {
+			task.Invalid++
+			if err := b.results.Write(rec.Row, importer.ResultInvalid, 0, err.Error()); err != nil {
+				return fmt.Errorf("写入结果文件失败: %w", err)
+			}
+			continue
+		}
+		opinion.ContentHash = opinionContentHash(opinion.Source, opinion.Content)
+		if row, ok := b.seen[opinion.ContentHash]; ok {
+			task.Duplicates++
+			if err := b.results.Write(rec.Row, importer.ResultDuplicate, 0, fmt.Sprintf("与第 %d 行重复", row)); err != nil {
+				return fmt.Errorf("写入结果文件失败: %w", err)
+			}
+			continue
+		}
+		b.seen[opinion.ContentHash] = rec.Row
+		opinions = append(opinions, opinion)
+		rows[opinion] = rec.Row
+		hashes = append(hashes, opinion.ContentHash)
+	}
+
+	// 跳过已入库的舆情
+	existing, err := s.opinionRepo.GetIDsByContentHash(hashes)
+	if err != nil {
+		return errors.New("查询已有舆情失败")
+	}
+	fresh := opinions[:0]
+	for _, opinion := range opinions {
+		if id, ok := existing[opinion.ContentHash]; ok {
+			task.Duplicates++
+			if err := b.results.Write(rows[opinion], importer.ResultDuplicate, id, "与已有舆情重复"); err != nil {
+				return fmt.Errorf("写入结果文件失败: %w", err)
+			}
+			continue
+		}
+		opinion.ImportTaskID = task.ID
+		opinion.HandlingStatus = model.HandlingStatusNew
+		opinion.Priority = model.PriorityMedium
+		fresh = append(fresh, opinion)
+	}
+
+	if len(fresh) > 0 {
+		if err := s.enrichmentService.EnrichOpinions(fresh); err != nil {
+			return fmt.Errorf("舆情富化失败: %w", err)
+		}
+		if err := s.authorService.Resolve(fresh); err != nil {
+			return fmt.Errorf("关联作者失败: %w", err)
+		}
+		if err := s.opinionRepo.CreateBatch(fresh); err != nil {
+			return errors.New("保存舆情失败")
+		}
+		task.Imported += int64(len(fresh))
+		for _, opinion := range fresh {
+			if b.earliest.IsZero() || opinion.CreatedAt.Before(b.earliest) {
+				b.earliest = opinion.CreatedAt
+			}
+			if opinion.CreatedAt.After(b.latest) {
+				b.latest = opinion.CreatedAt
+			}
+		}
+
+		// 导入的舆情按所有启用的监测组匹配，不受采集计划限制；
+		// 舆情带有导入任务ID，扫描任务不会再次匹配，命中也不推送到实时流和实时计数
+		for _, group := range b.groups {
+			hits, counts, err := s.matchService.MatchGroup(group, fresh)
+			if err != nil {
+				return fmt.Errorf("匹配监测组 %d 失败: %w", group.ID, err)
+			}
+			if err := s.groupRepo.AddFilteredCounts(counts, time.Now()); err != nil {
+				appLogger.Get().Warn("更新排除词和来源过滤统计失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			}
+			if err := s.enrichmentService.EnrichHits(hits, fresh); err != nil {
+				return fmt.Errorf("命中舆情情感分析失败: %w", err)
+			}
+			if _, err := s.noiseService.Apply(group.ID, hits, fresh); err != nil {
+				appLogger.Get().Warn("噪音过滤失败", zap.Uint64("group_id", group.ID), zap.Error(err))
+			}
+			if err := s.hitRepo.CreateBatch(hits); err != nil {
+				return errors.New("保存命中记录失败")
+			}
+			task.Hits += int64(len(hits))
+			if len(hits) > 0 {
+				b.scenarios[group.ScenarioID] = true
+			}
+		}
+	}
+
+	task.Processed += int64(len(records))
+	return s.taskRepo.UpdateProgress(task)
+}
+
+// rollup 重算有命中的场景在导入舆情入库时间范围内的统计汇总
+// 汇总任务只重算最近一段时间，导入的历史舆情需要单独补算；失败时只记录日志，不影响导入结果
+func (s *importService) rollup(b *importRun) {
+	for scenarioID := range b.scenarios {
+		if _, err := s.statsService.RollupRange(scenarioID, b.earliest, b.latest); err != nil {
+			appLogger.Get().Warn("重算导入舆情的统计汇总失败",
+				zap.Uint64("task_id", b.task.ID), zap.Uint64("scenario_id", scenarioID), zap.Error(err))
+		}
+	}
+}
+
+// decorate 填充进度、列映射和结果文件下载地址
+func (s *importService) decorate(task *model.ImportTask) {
+	switch {
+	case task.Status == model.ImportStatusSuccess:
+		task.Progress = 1
+	case task.Total > 0:
+		task.Progress = float64(task.Processed) / float64(task.Total)
+		if task.Progress > 1 {
+			task.Progress = 1
+		}
+	}
+	if task.Mapping != "" {
+		_ = json.Unmarshal([]byte(task.Mapping), &task.ColumnMapping)
+	}
+	if task.ResultFile != "" {
+		task.ResultURL = fmt.Sprintf("/api/v1/imports/%d/result", task.ID)
+	}
+}
+
+// checkImportHeader 校验 CSV 和 XLSX 的表头，NDJSON 在导入时逐行校验
+func checkImportHeader(path, format string, mapper *importer.Mapper) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader, err := importer.NewReader(format, f)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if headers := reader.Headers(); headers != nil {
+		return mapper.CheckHeader(headers)
+	}
+	return nil
+}
+
+// countImportRows 统计文件中的数据行数（不含表头和空行）
+func countImportRows(path, format string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("打开上传文件失败: %w", err)
+	}
+	defer f.Close()
+	reader, err := importer.NewReader(format, f)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	var total int64
+	for {
+		_, err := reader.Next()
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		total++
+	}
+}
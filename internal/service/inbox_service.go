@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/pkg/redis"
+	"sentinel-opinion-monitor/internal/realtime"
+	"sentinel-opinion-monitor/internal/repository"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// unreadCacheTTL 未读数缓存有效期，缓存与数据库短暂不一致时最多持续这么久
+const unreadCacheTTL = 10 * time.Minute
+
+// 实时推送的事件类型
+const (
+	InboxEventNotification = "notification" // 新通知
+	InboxEventUnread       = "unread"       // 未读数变化（如在其他页面标记已读）
+)
+
+// incrIfExists 只在缓存存在时增加未读数，缓存不存在时留待下次读取从数据库重建
+var incrIfExists = goredis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("INCRBY", KEYS[1], ARGV[1])
+end
+return false
+`)
+
+// InboxEvent 推送给在线用户的站内通知事件
+type InboxEvent struct {
+	Type         string                  `json:"type"`
+	Notification *model.UserNotification `json:"notification,omitempty"`
+	UnreadCount  int64                   `json:"unread_count"`
+}
+
+// InboxService 站内通知服务接口
+type InboxService interface {
+	Notify(userIDs []uint64, tmpl model.UserNotification)
+	List(userID uint64, unreadOnly bool, page, pageSize int) ([]*model.UserNotification, int64, error)
+	UnreadCount(userID uint64) (int64, error)
+	MarkRead(userID uint64, ids []uint64) (int64, error)
+	MarkAllRead(userID uint64) (int64, error)
+}
+
+type inboxService struct {
+	inboxRepo repository.InboxRepository
+}
+
+// NewInboxService 创建站内通知服务实例
+func NewInboxService(inboxRepo repository.InboxRepository) InboxService {
+	return &inboxService{
+		inboxRepo: inboxRepo,
+	}
+}
+
+// InboxTopic 用户站内通知的实时推送主题
+func InboxTopic(userID uint64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// Notify 按模板给多个用户发送站内通知（重复的用户只发一次），并推送给在线用户；失败只记录日志
+func (s *inboxService) Notify(userIDs []uint64, tmpl model.UserNotification) {
+	userIDs = uniqueIDs(userIDs)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	tmpl.Title = truncateRunes(tmpl.Title, 255)
+	tmpl.Content = truncateRunes(tmpl.Content, 1000)
+	notifications := make([]*model.UserNotification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		n := tmpl
+		n.ID = 0
+		n.UserID = userID
+		n.ReadAt = nil
+		notifications = append(notifications, &n)
+	}
+	if err := s.inboxRepo.CreateBatch(notifications); err != nil {
+		appLogger.Get().Error("创建站内通知失败", zap.String("type", tmpl.Type), zap.Error(err))
+		return
+	}
+
+	for _, n := range notifications {
+		s.incrUnread(n.UserID)
+		count, _ := s.UnreadCount(n.UserID)
+		s.publish(n.UserID, &InboxEvent{Type: InboxEventNotification, Notification: n, UnreadCount: count})
+	}
+}
+
+// List 分页获取用户的站内通知
+func (s *inboxService) List(userID uint64, unreadOnly bool, page, pageSize int) ([]*model.UserNotification, int64, error) {
+	return s.inboxRepo.List(userID, unreadOnly, page, pageSize)
+}
+
+// UnreadCount 获取未读数，优先读取 Redis 缓存，未命中时从数据库统计并回填
+func (s *inboxService) UnreadCount(userID uint64) (int64, error) {
+	key := unreadCacheKey(userID)
+	if redis.GetClient() != nil {
+		if val, err := redis.Get(key); err == nil {
+			if count, err := strconv.ParseInt(val, 10, 64); err == nil && count >= 0 {
+				return count, nil
+			}
+		}
+	}
+
+	count, err := s.inboxRepo.CountUnread(userID)
+	if err != nil {
+		return 0, errors.New("获取未读数失败")
+	}
+	if redis.GetClient() != nil {
+		if err := redis.Set(key, count, unreadCacheTTL); err != nil {
+			appLogger.Get().Warn("缓存未读数失败", zap.Uint64("user_id", userID), zap.Error(err))
+		}
+	}
+	return count, nil
+}
+
+// MarkRead 标记指定通知为已读，返回实际标记的条数
+func (s *inboxService) MarkRead(userID uint64, ids []uint64) (int64, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return 0, errors.New("请指定要标记的通知")
+	}
+	if len(ids) > 200 {
+		return 0, errors.New("一次最多标记 200 条通知")
+	}
+
+	count, err := s.inboxRepo.MarkRead(userID, ids, time.Now())
+	if err != nil {
+		return 0, errors.New("标记已读失败")
+	}
+	if count > 0 {
+		s.resetUnread(userID)
+	}
+	return count, nil
+}
+
+// MarkAllRead 标记所有通知为已读，返回实际标记的条数
+func (s *inboxService) MarkAllRead(userID uint64) (int64, error) {
+	count, err := s.inboxRepo.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return 0, errors.New("标记已读失败")
+	}
+	if count > 0 {
+		s.resetUnread(userID)
+	}
+	return count, nil
+}
+
+// incrUnread 新通知写入后增加缓存的未读数
+func (s *inboxService) incrUnread(userID uint64) {
+	client := redis.GetClient()
+	if client == nil {
+		return
+	}
+	err := incrIfExists.Run(redis.GetContext(), client, []string{unreadCacheKey(userID)}, 1).Err()
+	if err != nil && err != goredis.Nil {
+		appLogger.Get().Warn("更新未读数缓存失败", zap.Uint64("user_id", userID), zap.Error(err))
+		_ = redis.Delete(unreadCacheKey(userID))
+	}
+}
+
+// resetUnread 标记已读后清除未读数缓存，并把最新未读数推送给用户的其他在线页面
+func (s *inboxService) resetUnread(userID uint64) {
+	if redis.GetClient() != nil {
+		if err := redis.Delete(unreadCacheKey(userID)); err != nil {
+			appLogger.Get().Warn("清除未读数缓存失败", zap.Uint64("user_id", userID), zap.Error(err))
+		}
+	}
+	count, err := s.UnreadCount(userID)
+	if err != nil {
+		return
+	}
+	s.publish(userID, &InboxEvent{Type: InboxEventUnread, UnreadCount: count})
+}
+
+// publish 推送站内通知事件，失败只记录日志
+func (s *inboxService) publish(userID uint64, event *InboxEvent) {
+	if err := realtime.Publish(InboxTopic(userID), event); err != nil {
+		appLogger.Get().Warn("推送站内通知失败", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+}
+
+// unreadCacheKey 未读数缓存键
+func unreadCacheKey(userID uint64) string {
+	return fmt.Sprintf("inbox:unread:%d", userID)
+}
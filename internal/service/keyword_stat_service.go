@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// maxSuggestLimit 建议关键词和排除词各自的最大返回数量
+const maxSuggestLimit = 100
+
+// KeywordEffect 关键词效果统计
+// 同一舆情命中多个关键词时只记为排在最前的关键词，因此各关键词的命中数之和等于监测组的命中数（不含只因关注作者而命中、没有关键词的命中）
+type KeywordEffect struct {
+	*model.GroupKeyword
+	HitCount        int64   `json:"hit_count"`        // 记为该关键词的命中数
+	RelevantCount   int64   `json:"relevant_count"`   // 其中标注为相关的数量
+	IrrelevantCount int64   `json:"irrelevant_count"` // 其中标注为不相关的数量
+	IrrelevantRate  float64 `json:"irrelevant_rate"`  // 不相关数 / 已标注数，没有标注时为 0
+	SuppressedCount int64   `json:"suppressed_count"` // 其中被屏蔽的数量（噪音过滤器自动屏蔽或标注为不相关）
+}
+
+// GroupKeywordStats 监测组关键词和排除词效果统计
+type GroupKeywordStats struct {
+	Keywords       []*KeywordEffect            `json:"keywords"`
+	ExclusionWords []*model.GroupExclusionWord `json:"exclusion_words"`
+}
+
+// TermSuggestion 建议的关键词或排除词
+type TermSuggestion struct {
+	Word       string  `json:"word"`
+	Relevant   int     `json:"relevant"`   // 包含该词的相关舆情数
+	Irrelevant int     `json:"irrelevant"` // 包含该词的不相关舆情数
+	Score      float64 `json:"score"`      // 该词在两类舆情中出现比例的对数比（平滑后），越大越倾向于该类
+}
+
+// KeywordSuggestions 根据相关性标注计算的关键词和排除词建议
+type KeywordSuggestions struct {
+	Since          time.Time         `json:"since"`
+	Relevant       int               `json:"relevant"`   // 参与计算的相关舆情数
+	Irrelevant     int               `json:"irrelevant"` // 参与计算的不相关舆情数
+	Keywords       []*TermSuggestion `json:"keywords"`
+	ExclusionWords []*TermSuggestion `json:"exclusion_words"`
+}
+
+// KeywordStatService 关键词效果统计服务接口
+type KeywordStatService interface {
+	GetStats(groupID uint64) (*GroupKeywordStats, error)
+	Suggest(groupID uint64, days, limit int) (*KeywordSuggestions, error)
+	MarkRelevance(groupID, opinionID, userID uint64, relevance string) (*model.OpinionHit, error)
+}
+
+type keywordStatService struct {
+	cfg            config.KeywordStatConfig
+	groupRepo      repository.MonitoringGroupRepository
+	hitRepo        repository.OpinionHitRepository
+	segmentService SegmentService
+}
+
+// NewKeywordStatService 创建关键词效果统计服务实例
+func NewKeywordStatService(
+	cfg *config.KeywordStatConfig,
+	groupRepo repository.MonitoringGroupRepository,
+	hitRepo repository.OpinionHitRepository,
+	segmentService SegmentService,
+) KeywordStatService {
+	s := &keywordStatService{
+		groupRepo:      groupRepo,
+		hitRepo:        hitRepo,
+		segmentService: segmentService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.SuggestDays <= 0 {
+		s.cfg.SuggestDays = 30
+	}
+	if s.cfg.MaxDocuments <= 0 {
+		s.cfg.MaxDocuments = 5000
+	}
+	if s.cfg.MinDocuments <= 0 {
+		s.cfg.MinDocuments = 3
+	}
+	if s.cfg.SuggestLimit <= 0 {
+		s.cfg.SuggestLimit = 20
+	}
+	return s
+}
+
+// GetStats 获取监测组各关键词的命中数和不相关占比，以及各排除词累计排除的舆情数
+func (s *keywordStatService) GetStats(groupID uint64) (*GroupKeywordStats, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+
+	keywords, err := s.groupRepo.GetKeywords(groupID)
+	if err != nil {
+		return nil, errors.New("获取关键词列表失败")
+	}
+	words, err := s.groupRepo.GetExclusionWords(groupID)
+	if err != nil {
+		return nil, errors.New("获取排除词列表失败")
+	}
+	hitStats, err := s.hitRepo.GetKeywordStats(groupID)
+	if err != nil {
+		return nil, errors.New("统计关键词命中失败")
+	}
+
+	byKeyword := make(map[string]*repository.KeywordHitStat, len(hitStats))
+	for _, stat := range hitStats {
+		byKeyword[stat.Keyword] = stat
+	}
+
+	stats := &GroupKeywordStats{
+		Keywords:       make([]*KeywordEffect, len(keywords)),
+		ExclusionWords: words,
+	}
+	for i, keyword := range keywords {
+		effect := &KeywordEffect{GroupKeyword: keyword}
+		if stat, ok := byKeyword[keyword.Keyword]; ok {
+			effect.HitCount = stat.Hits
+			effect.RelevantCount = stat.Relevant
+			effect.IrrelevantCount = stat.Irrelevant
+			effect.SuppressedCount = stat.Suppressed
+			if labeled := stat.Relevant + stat.Irrelevant; labeled > 0 {
+				effect.IrrelevantRate = float64(stat.Irrelevant) / float64(labeled)
+			}
+		}
+		stats.Keywords[i] = effect
+	}
+	return stats, nil
+}
+
+// Suggest 根据最近 days 天内标注过相关性的命中舆情，建议新的关键词（多出现在相关舆情中）和排除词（多出现在不相关舆情中）
+// 词语取自舆情提取的关键词，已是该监测组关键词或排除词的词语不再建议
+func (s *keywordStatService) Suggest(groupID uint64, days, limit int) (*KeywordSuggestions, error) {
+	group, err := s.groupRepo.GetWithDetails(groupID)
+	if err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+	if days <= 0 {
+		days = s.cfg.SuggestDays
+	}
+	if limit <= 0 {
+		limit = s.cfg.SuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	docs, err := s.hitRepo.GetLabeledDocuments(groupID, since, s.cfg.MaxDocuments)
+	if err != nil {
+		return nil, errors.New("获取已标注舆情失败")
+	}
+
+	known := make(map[string]bool)
+	for _, keyword := range group.Keywords {
+		for _, part := range strings.Fields(strings.ToLower(keyword.Keyword)) {
+			known[part] = true
+		}
+	}
+	for _, word := range group.ExclusionWords {
+		for _, part := range strings.Fields(strings.ToLower(word.Word)) {
+			known[part] = true
+		}
+	}
+
+	result := &KeywordSuggestions{
+		Since:          since,
+		Keywords:       []*TermSuggestion{},
+		ExclusionWords: []*TermSuggestion{},
+	}
+	counts := make(map[string]*TermSuggestion)
+	for _, doc := range docs {
+		terms := doc.Keywords
+		if len(terms) == 0 {
+			extracted, err := s.segmentService.ExtractKeywords(doc.Content)
+			if err != nil {
+				return nil, errors.New("提取舆情关键词失败")
+			}
+			terms = extracted
+		}
+
+		relevant := doc.Relevance == model.HitRelevanceRelevant
+		if relevant {
+			result.Relevant++
+		} else {
+			result.Irrelevant++
+		}
+
+		seen := make(map[string]bool, len(terms))
+		for _, term := range terms {
+			word := strings.ToLower(term.Word)
+			if seen[word] || known[word] || utf8.RuneCountInString(word) < 2 {
+				continue
+			}
+			seen[word] = true
+
+			count, ok := counts[word]
+			if !ok {
+				count = &TermSuggestion{Word: word}
+				counts[word] = count
+			}
+			if relevant {
+				count.Relevant++
+			} else {
+				count.Irrelevant++
+			}
+		}
+	}
+
+	for _, count := range counts {
+		// 加 0.5 平滑，避免只在一类舆情中出现的词语得分无穷大
+		relevantRate := (float64(count.Relevant) + 0.5) / (float64(result.Relevant) + 1)
+		irrelevantRate := (float64(count.Irrelevant) + 0.5) / (float64(result.Irrelevant) + 1)
+		score := math.Log(relevantRate / irrelevantRate)
+
+		switch {
+		case result.Relevant > 0 && count.Relevant >= s.cfg.MinDocuments && score > 0:
+			count.Score = math.Round(score*1000) / 1000
+			result.Keywords = append(result.Keywords, count)
+		case result.Irrelevant > 0 && count.Irrelevant >= s.cfg.MinDocuments && score < 0:
+			count.Score = math.Round(-score*1000) / 1000
+			result.ExclusionWords = append(result.ExclusionWords, count)
+		}
+	}
+
+	result.Keywords = topSuggestions(result.Keywords, limit)
+	result.ExclusionWords = topSuggestions(result.ExclusionWords, limit)
+	return result, nil
+}
+
+// MarkRelevance 标注舆情在监测组下的命中是否相关，relevance 为空时清除标注。
+// 标注为不相关的命中会被屏蔽，标注为相关的命中会取消屏蔽（用于恢复被噪音过滤器误屏蔽的命中）
+func (s *keywordStatService) MarkRelevance(groupID, opinionID, userID uint64, relevance string) (*model.OpinionHit, error) {
+	if relevance != "" && relevance != model.HitRelevanceRelevant && relevance != model.HitRelevanceIrrelevant {
+		return nil, errors.New("无效的相关性标注")
+	}
+
+	hit, err := s.hitRepo.GetByOpinionAndGroup(opinionID, groupID)
+	if err != nil {
+		return nil, errors.New("该舆情未命中此监测组")
+	}
+
+	var markedAt *time.Time
+	if relevance != "" {
+		now := time.Now()
+		markedAt = &now
+	} else {
+		userID = 0
+	}
+	if err := s.hitRepo.UpdateRelevance(hit.ID, relevance, userID, markedAt); err != nil {
+		return nil, errors.New("保存相关性标注失败")
+	}
+	hit.Relevance = relevance
+	hit.RelevanceBy = userID
+	hit.RelevanceAt = markedAt
+
+	suppressed := hit.Suppressed
+	switch relevance {
+	case model.HitRelevanceRelevant:
+		suppressed = false
+	case model.HitRelevanceIrrelevant:
+		suppressed = true
+	}
+	if suppressed != hit.Suppressed {
+		if err := s.hitRepo.SetSuppressed(hit, suppressed); err != nil {
+			return nil, errors.New("更新屏蔽状态失败")
+		}
+		hit.Suppressed = suppressed
+	}
+	return hit, nil
+}
+
+// topSuggestions 按得分和出现次数排序，取前 limit 个
+func topSuggestions(suggestions []*TermSuggestion, limit int) []*TermSuggestion {
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Relevant+a.Irrelevant != b.Relevant+b.Irrelevant {
+			return a.Relevant+a.Irrelevant > b.Relevant+b.Irrelevant
+		}
+		return a.Word < b.Word
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/pkg/redis"
+	"sentinel-opinion-monitor/internal/repository"
+
+	goredis "github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// 实时计数的统计粒度
+const (
+	LiveIntervalMinute = "minute"
+	LiveIntervalHour   = "hour"
+)
+
+const (
+	liveDefaultMinutes = 60
+	liveMaxMinutes     = 1440 // 按分钟最多查询 24 小时
+	liveMaxHours       = 168  // 按小时最多查询 7 天
+
+	// liveRecentMinutes 最近多少分钟的计数直接读取 Redis，更早的读取已落库的数据；
+	// 落库任务每分钟执行，这段时间内的分钟都已落库或仍保留在 Redis 中
+	liveRecentMinutes = 120
+
+	// liveDirtyKey 有待落库分钟计数的有序集合，成员为 "监测组ID:分钟时间戳"，分值为分钟时间戳
+	liveDirtyKey = "live:dirty"
+)
+
+// 分钟计数哈希中的字段
+const (
+	liveFieldTotal           = "total"
+	liveFieldChannelPrefix   = "channel:"
+	liveFieldSentimentPrefix = "sentiment:"
+)
+
+// takeMinute 原子地读取分钟计数并移出待落库集合；落库期间新增的计数会重新加入待落库集合
+var takeMinute = goredis.NewScript(`
+local counts = redis.call("HGETALL", KEYS[2])
+redis.call("ZREM", KEYS[1], ARGV[1])
+return counts
+`)
+
+// LiveQuery 实时计数查询条件，Minutes 和 Hours 只能指定一个
+type LiveQuery struct {
+	Minutes int // 最近 N 分钟，按分钟统计
+	Hours   int // 最近 N 小时，按小时统计
+}
+
+// LivePoint 实时计数时间序列中的一个点
+type LivePoint struct {
+	Time        time.Time        `json:"time"`
+	Total       int64            `json:"total"`
+	ByChannel   map[string]int64 `json:"by_channel"`
+	BySentiment map[string]int64 `json:"by_sentiment"`
+}
+
+// GroupLiveStats 监测组实时计数
+type GroupLiveStats struct {
+	GroupID     uint64           `json:"group_id"`
+	Interval    string           `json:"interval"`
+	StartTime   time.Time        `json:"start_time"`
+	EndTime     time.Time        `json:"end_time"`
+	Total       int64            `json:"total"`
+	ByChannel   map[string]int64 `json:"by_channel"`
+	BySentiment map[string]int64 `json:"by_sentiment"`
+	Series      []*LivePoint     `json:"series"`
+}
+
+// LiveStatsService 监测组实时计数服务接口
+type LiveStatsService interface {
+	Record(groupID uint64, hits []*model.OpinionHit, sources map[uint64]string, at time.Time) error
+	Flush(now time.Time) (int, error)
+	GetGroupStats(groupID uint64, query LiveQuery) (*GroupLiveStats, error)
+}
+
+type liveStatsService struct {
+	cfg       config.LiveConfig
+	liveRepo  repository.LiveStatsRepository
+	groupRepo repository.MonitoringGroupRepository
+}
+
+// NewLiveStatsService 创建监测组实时计数服务实例
+func NewLiveStatsService(
+	cfg *config.LiveConfig,
+	liveRepo repository.LiveStatsRepository,
+	groupRepo repository.MonitoringGroupRepository,
+) LiveStatsService {
+	s := &liveStatsService{
+		liveRepo:  liveRepo,
+		groupRepo: groupRepo,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.RedisTTLHours*60 <= liveRecentMinutes {
+		s.cfg.RedisTTLHours = 26
+	}
+	if s.cfg.RetentionDays <= 0 {
+		s.cfg.RetentionDays = 7
+	}
+	return s
+}
+
+// Record 把监测组在某一时刻的新命中累加到 Redis 的分钟计数（总数、按渠道、按情感），
+// sources 为舆情ID到来源渠道的映射；未配置 Redis 时不计数
+func (s *liveStatsService) Record(groupID uint64, hits []*model.OpinionHit, sources map[uint64]string, at time.Time) error {
+	client := redis.GetClient()
+	if client == nil || len(hits) == 0 {
+		return nil
+	}
+
+	minute := at.Truncate(time.Minute).Unix()
+	key := liveMinuteKey(groupID, minute)
+	counts := map[string]int64{liveFieldTotal: int64(len(hits))}
+	for _, hit := range hits {
+		counts[liveFieldChannelPrefix+sources[hit.OpinionID]]++
+		label := hit.SentimentLabel
+		if label == "" {
+			label = string(sentiment.LabelNeutral)
+		}
+		counts[liveFieldSentimentPrefix+label]++
+	}
+
+	ctx := redis.GetContext()
+	pipe := client.TxPipeline()
+	for field, count := range counts {
+		pipe.HIncrBy(ctx, key, field, count)
+	}
+	pipe.Expire(ctx, key, time.Duration(s.cfg.RedisTTLHours)*time.Hour)
+	pipe.ZAdd(ctx, liveDirtyKey, &goredis.Z{Score: float64(minute), Member: liveDirtyMember(groupID, minute)})
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Flush 把已结束的分钟计数从 Redis 写入 MySQL，并清理超过保留天数的数据，返回落库的分钟数
+func (s *liveStatsService) Flush(now time.Time) (int, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return 0, errors.New("未配置 Redis")
+	}
+	ctx := redis.GetContext()
+
+	current := now.Truncate(time.Minute).Unix()
+	members, err := client.ZRangeByScore(ctx, liveDirtyKey, &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(current, 10),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		stats []*model.GroupMinuteStat
+		taken []*goredis.Z
+	)
+	for _, member := range members {
+		groupID, minute, ok := parseLiveDirtyMember(member)
+		if !ok {
+			client.ZRem(ctx, liveDirtyKey, member)
+			continue
+		}
+		fields, err := takeMinute.Run(ctx, client, []string{liveDirtyKey, liveMinuteKey(groupID, minute)}, member).StringSlice()
+		if err != nil {
+			appLogger.Get().Warn("读取分钟计数失败", zap.String("member", member), zap.Error(err))
+			continue
+		}
+		taken = append(taken, &goredis.Z{Score: float64(minute), Member: member})
+		for i := 0; i+1 < len(fields); i += 2 {
+			count, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				continue
+			}
+			dimension, dimKey := parseLiveField(fields[i])
+			if dimension == "" {
+				continue
+			}
+			stats = append(stats, &model.GroupMinuteStat{
+				GroupID:   groupID,
+				Minute:    time.Unix(minute, 0),
+				Dimension: dimension,
+				DimKey:    dimKey,
+				Count:     count,
+			})
+		}
+	}
+
+	if err := s.liveRepo.Upsert(stats); err != nil {
+		// 放回待落库集合，下次重试
+		if len(taken) > 0 {
+			if err := client.ZAdd(ctx, liveDirtyKey, taken...).Err(); err != nil {
+				appLogger.Get().Error("恢复待落库分钟失败", zap.Int("minutes", len(taken)), zap.Error(err))
+			}
+		}
+		return 0, err
+	}
+
+	before := now.AddDate(0, 0, -s.cfg.RetentionDays)
+	if deleted, err := s.liveRepo.DeleteBefore(before); err != nil {
+		appLogger.Get().Warn("清理过期分钟计数失败", zap.Error(err))
+	} else if deleted > 0 {
+		appLogger.Get().Info("清理过期分钟计数", zap.Int64("rows", deleted))
+	}
+	return len(taken), nil
+}
+
+// GetGroupStats 获取监测组最近 N 分钟（按分钟）或 N 小时（按小时）的命中数，没有命中的时间点补零；
+// 最近的分钟直接读取 Redis，更早的读取 MySQL
+func (s *liveStatsService) GetGroupStats(groupID uint64, query LiveQuery) (*GroupLiveStats, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+
+	now := time.Now()
+	stats := &GroupLiveStats{
+		GroupID:     groupID,
+		ByChannel:   map[string]int64{},
+		BySentiment: map[string]int64{},
+	}
+	var step time.Duration
+	switch {
+	case query.Minutes != 0 && query.Hours != 0:
+		return nil, errors.New("minutes 和 hours 只能指定一个")
+	case query.Hours != 0:
+		if query.Hours < 0 || query.Hours > liveMaxHours {
+			return nil, fmt.Errorf("hours 取值范围为 1~%d", liveMaxHours)
+		}
+		step = time.Hour
+		stats.Interval = LiveIntervalHour
+		stats.EndTime = truncateHour(now).Add(time.Hour)
+		stats.StartTime = stats.EndTime.Add(-time.Duration(query.Hours) * time.Hour)
+	default:
+		if query.Minutes == 0 {
+			query.Minutes = liveDefaultMinutes
+		}
+		if query.Minutes < 0 || query.Minutes > liveMaxMinutes {
+			return nil, fmt.Errorf("minutes 取值范围为 1~%d", liveMaxMinutes)
+		}
+		step = time.Minute
+		stats.Interval = LiveIntervalMinute
+		stats.EndTime = now.Truncate(time.Minute).Add(time.Minute)
+		stats.StartTime = stats.EndTime.Add(-time.Duration(query.Minutes) * time.Minute)
+	}
+	hourly := step == time.Hour
+
+	points := make(map[int64]*LivePoint)
+	for t := stats.StartTime; t.Before(stats.EndTime); t = t.Add(step) {
+		point := &LivePoint{Time: t, ByChannel: map[string]int64{}, BySentiment: map[string]int64{}}
+		points[t.Unix()] = point
+		stats.Series = append(stats.Series, point)
+	}
+	add := func(bucket time.Time, dimension, dimKey string, count int64) {
+		point, ok := points[bucket.Unix()]
+		if !ok {
+			return
+		}
+		switch dimension {
+		case model.StatDimTotal:
+			point.Total += count
+			stats.Total += count
+		case model.StatDimChannel:
+			point.ByChannel[dimKey] += count
+			stats.ByChannel[dimKey] += count
+		case model.StatDimSentiment:
+			point.BySentiment[dimKey] += count
+			stats.BySentiment[dimKey] += count
+		}
+	}
+
+	// 最近的分钟读取 Redis，Redis 不可用时全部读取 MySQL（最近未落库的分钟会缺失）
+	split := stats.EndTime
+	recentStart := now.Truncate(time.Minute).Add(-liveRecentMinutes * time.Minute)
+	if recentStart.Before(stats.StartTime) {
+		recentStart = stats.StartTime
+	}
+	recent, err := s.recentCounts(groupID, recentStart, stats.EndTime)
+	if err != nil {
+		appLogger.Get().Warn("读取实时计数失败，改为读取数据库", zap.Uint64("group_id", groupID), zap.Error(err))
+	} else if recent != nil {
+		split = recentStart
+		for _, c := range recent {
+			bucket := c.Bucket
+			if hourly {
+				bucket = truncateHour(bucket)
+			}
+			add(bucket, c.Dimension, c.DimKey, c.Count)
+		}
+	}
+
+	if split.After(stats.StartTime) {
+		rows, err := s.liveRepo.GetRange(groupID, stats.StartTime, split, hourly)
+		if err != nil {
+			return nil, errors.New("获取实时计数失败")
+		}
+		for _, row := range rows {
+			add(row.Bucket, row.Dimension, row.DimKey, row.Count)
+		}
+	}
+	return stats, nil
+}
+
+// recentCounts 从 Redis 读取监测组 [start, end) 内每分钟的计数；未配置 Redis 时返回 nil
+func (s *liveStatsService) recentCounts(groupID uint64, start, end time.Time) ([]*repository.LiveCount, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return nil, nil
+	}
+	ctx := redis.GetContext()
+
+	pipe := client.Pipeline()
+	var minutes []int64
+	var cmds []*goredis.StringStringMapCmd
+	for t := start; t.Before(end); t = t.Add(time.Minute) {
+		minutes = append(minutes, t.Unix())
+		cmds = append(cmds, pipe.HGetAll(ctx, liveMinuteKey(groupID, t.Unix())))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, err
+	}
+
+	counts := make([]*repository.LiveCount, 0)
+	for i, cmd := range cmds {
+		for field, value := range cmd.Val() {
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			dimension, dimKey := parseLiveField(field)
+			if dimension == "" {
+				continue
+			}
+			counts = append(counts, &repository.LiveCount{
+				Bucket:    time.Unix(minutes[i], 0),
+				Dimension: dimension,
+				DimKey:    dimKey,
+				Count:     count,
+			})
+		}
+	}
+	return counts, nil
+}
+
+// liveMinuteKey 监测组分钟计数的 Redis 哈希键
+func liveMinuteKey(groupID uint64, minute int64) string {
+	return fmt.Sprintf("live:group:%d:%d", groupID, minute)
+}
+
+// liveDirtyMember 待落库集合中的成员
+func liveDirtyMember(groupID uint64, minute int64) string {
+	return fmt.Sprintf("%d:%d", groupID, minute)
+}
+
+// parseLiveDirtyMember 解析待落库集合中的成员
+func parseLiveDirtyMember(member string) (uint64, int64, bool) {
+	parts := strings.SplitN(member, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	groupID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	minute, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return groupID, minute, true
+}
+
+// parseLiveField 把分钟计数哈希中的字段转换为统计维度和取值，无法识别时维度为空
+func parseLiveField(field string) (string, string) {
+	switch {
+	case field == liveFieldTotal:
+		return model.StatDimTotal, ""
+	case strings.HasPrefix(field, liveFieldChannelPrefix):
+		return model.StatDimChannel, strings.TrimPrefix(field, liveFieldChannelPrefix)
+	case strings.HasPrefix(field, liveFieldSentimentPrefix):
+		return model.StatDimSentiment, strings.TrimPrefix(field, liveFieldSentimentPrefix)
+	}
+	return "", ""
+}
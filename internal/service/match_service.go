@@ -0,0 +1,326 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// FilterCounts 一次匹配中排除词和来源过滤规则（按 ID）匹配的舆情数
+type FilterCounts = repository.FilterCounts
+
+// MatchService 舆情匹配服务接口
+type MatchService interface {
+	MatchGroup(group *model.MonitoringGroup, opinions []*model.Opinion) ([]*model.OpinionHit, *FilterCounts, error)
+}
+
+type matchService struct {
+	segmentService SegmentService
+	authorRepo     repository.AuthorRepository
+}
+
+// NewMatchService 创建舆情匹配服务实例
+func NewMatchService(segmentService SegmentService, authorRepo repository.AuthorRepository) MatchService {
+	return &matchService{
+		segmentService: segmentService,
+		authorRepo:     authorRepo,
+	}
+}
+
+// MatchGroup 使用监测组的渠道、关键词、来源过滤规则和排除词匹配舆情，返回命中记录和各排除词、来源过滤规则匹配的舆情数，
+// 单条舆情的匹配规则见 groupMatcher.match；同时包含多个排除词时每个排除词各计一次
+func (s *matchService) MatchGroup(group *model.MonitoringGroup, opinions []*model.Opinion) ([]*model.OpinionHit, *FilterCounts, error) {
+	watched, err := watchedAuthors(s.authorRepo, group.ScenarioID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(group.Keywords) == 0 && len(watched) == 0 {
+		return nil, nil, nil
+	}
+
+	segmenter, err := s.segmentService.GetSegmenter()
+	if err != nil {
+		return nil, nil, err
+	}
+	matcher := newGroupMatcher(group, watched, segmenter)
+
+	hits := make([]*model.OpinionHit, 0)
+	counts := &FilterCounts{
+		ExclusionWords: make(map[uint64]int64),
+		SourceFilters:  make(map[uint64]int64),
+	}
+	for _, opinion := range opinions {
+		result := matcher.match(opinion)
+		for _, id := range result.sourceRules {
+			counts.SourceFilters[id]++
+		}
+		switch result.stage {
+		case matchExcluded:
+			for _, i := range result.excludedBy {
+				counts.ExclusionWords[group.ExclusionWords[i].ID]++
+			}
+		case matchHit:
+			hits = append(hits, &model.OpinionHit{
+				OpinionID:  opinion.ID,
+				GroupID:    group.ID,
+				ScenarioID: group.ScenarioID,
+				Keyword:    result.keyword,
+				Watched:    result.watched,
+			})
+		}
+	}
+	return hits, counts, nil
+}
+
+// watchedAuthors 返回场景关注的作者ID集合，未指定场景时为空
+func watchedAuthors(authorRepo repository.AuthorRepository, scenarioID uint64) (map[uint64]bool, error) {
+	if scenarioID == 0 {
+		return nil, nil
+	}
+	ids, err := authorRepo.GetWatchedAuthorIDs(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		watched[id] = true
+	}
+	return watched, nil
+}
+
+// 单条舆情的匹配结果，按匹配步骤依次推进
+const (
+	matchOffChannel     = iota // 不符合渠道限制
+	matchNoKeyword             // 未命中关键词，且作者未被场景关注
+	matchSourceFiltered        // 被来源过滤规则排除
+	matchExcluded              // 被排除词排除
+	matchHit                   // 命中
+)
+
+// opinionMatch 单条舆情的匹配结果
+type opinionMatch struct {
+	stage       int
+	keywords    []int    // 舆情包含的关键词（下标），一条舆情可以包含多个关键词
+	keyword     string   // 命中记录中的关键词，取排在最前的命中关键词；关注作者的舆情未命中关键词时为空
+	watched     bool     // 作者被场景关注
+	sourceRules []uint64 // 匹配的来源过滤规则：被排除时为屏蔽规则，通过允许名单时为允许规则
+	excludedBy  []int    // 排除该舆情的排除词（下标）
+}
+
+// groupMatcher 监测组的匹配规则，扫描任务、批量导入和规则预览共用
+type groupMatcher struct {
+	group     *model.MonitoringGroup
+	watched   map[uint64]bool
+	segmenter *segment.Segmenter
+	rules     *sourceRules
+}
+
+// newGroupMatcher 创建监测组的匹配规则，watched 为场景关注的作者
+func newGroupMatcher(group *model.MonitoringGroup, watched map[uint64]bool, segmenter *segment.Segmenter) *groupMatcher {
+	return &groupMatcher{
+		group:     group,
+		watched:   watched,
+		segmenter: segmenter,
+		rules:     compileSourceRules(group.SourceFilters),
+	}
+}
+
+// match 匹配单条舆情
+// 关键词和排除词按分词结果匹配（二者均已加入用户词典，不会被切开），避免 "米" 命中 "小米" 这类子串误判。
+// 场景关注的作者发布的舆情只需符合渠道限制，不要求命中关键词，也不受排除词和允许名单影响，但仍受屏蔽名单限制
+func (m *groupMatcher) match(opinion *model.Opinion) *opinionMatch {
+	result := &opinionMatch{stage: matchOffChannel}
+	if !matchChannel(m.group.Channels, opinion) {
+		return result
+	}
+
+	result.watched = opinion.AuthorID > 0 && m.watched[opinion.AuthorID]
+	tokens := tokenSet(m.segmenter.Cut(opinion.Content))
+	for i, keyword := range m.group.Keywords {
+		if termInTokens(keyword.Keyword, tokens) {
+			if len(result.keywords) == 0 {
+				result.keyword = keyword.Keyword
+			}
+			result.keywords = append(result.keywords, i)
+		}
+	}
+	if len(result.keywords) == 0 && !result.watched {
+		result.stage = matchNoKeyword
+		return result
+	}
+
+	passed, matched := m.rules.check(opinion, result.watched)
+	result.sourceRules = matched
+	if !passed {
+		result.stage = matchSourceFiltered
+		return result
+	}
+
+	if !result.watched {
+		for i, word := range m.group.ExclusionWords {
+			if termInTokens(word.Word, tokens) {
+				result.excludedBy = append(result.excludedBy, i)
+			}
+		}
+		if len(result.excludedBy) > 0 {
+			result.stage = matchExcluded
+			return result
+		}
+	}
+
+	result.stage = matchHit
+	return result
+}
+
+// matchChannel 监测组未绑定渠道时不限制来源，否则舆情来源需为绑定渠道的代码或名称
+func matchChannel(channels []model.Channel, opinion *model.Opinion) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, channel := range channels {
+		if strings.EqualFold(opinion.Source, channel.Code) || opinion.Source == channel.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// termInTokens 判断词语是否出现在分词结果中，含空格的词语要求每一部分都出现
+func termInTokens(term string, tokens map[string]bool) bool {
+	parts := strings.Fields(strings.ToLower(term))
+	if len(parts) == 0 {
+		return false
+	}
+	for _, part := range parts {
+		if !tokens[part] {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenSet 将分词结果转换为集合
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		set[token] = true
+	}
+	return set
+}
+
+// sourceRule 编译后的来源过滤规则
+type sourceRule struct {
+	id       uint64
+	kind     string
+	authorID uint64
+	domain   string
+	pattern  *regexp.Regexp
+}
+
+// sourceRules 监测组的来源过滤规则，按模式分为屏蔽名单和允许名单
+type sourceRules struct {
+	block []sourceRule
+	allow []sourceRule
+}
+
+// compileSourceRules 编译来源过滤规则，无法编译的链接模式忽略
+func compileSourceRules(filters []model.GroupSourceFilter) *sourceRules {
+	rules := &sourceRules{}
+	for _, filter := range filters {
+		rule := sourceRule{id: filter.ID, kind: filter.Type, authorID: filter.AuthorID}
+		switch filter.Type {
+		case model.SourceFilterDomain:
+			rule.domain = strings.ToLower(filter.Value)
+		case model.SourceFilterURL:
+			pattern, err := compileURLPattern(filter.Value)
+			if err != nil {
+				continue
+			}
+			rule.pattern = pattern
+		}
+		if filter.Mode == model.SourceFilterAllow {
+			rules.allow = append(rules.allow, rule)
+		} else {
+			rules.block = append(rules.block, rule)
+		}
+	}
+	return rules
+}
+
+// check 判断舆情是否通过来源过滤，并返回匹配的规则 ID：
+// 匹配任一屏蔽规则时不通过，返回匹配的屏蔽规则；有允许规则时需匹配其中之一，通过时返回匹配的允许规则。
+// skipAllow 为 true 时不检查允许名单（场景关注的作者）
+func (r *sourceRules) check(opinion *model.Opinion, skipAllow bool) (bool, []uint64) {
+	if len(r.block) == 0 && (skipAllow || len(r.allow) == 0) {
+		return true, nil
+	}
+	host := urlHost(opinion.URL)
+
+	var blocked []uint64
+	for _, rule := range r.block {
+		if rule.match(opinion, host) {
+			blocked = append(blocked, rule.id)
+		}
+	}
+	if len(blocked) > 0 {
+		return false, blocked
+	}
+	if skipAllow || len(r.allow) == 0 {
+		return true, nil
+	}
+
+	var allowed []uint64
+	for _, rule := range r.allow {
+		if rule.match(opinion, host) {
+			allowed = append(allowed, rule.id)
+		}
+	}
+	return len(allowed) > 0, allowed
+}
+
+// match 判断舆情是否匹配规则，host 为原文链接的域名（小写）
+func (r sourceRule) match(opinion *model.Opinion, host string) bool {
+	switch r.kind {
+	case model.SourceFilterAuthor:
+		return opinion.AuthorID > 0 && opinion.AuthorID == r.authorID
+	case model.SourceFilterDomain:
+		return host != "" && (host == r.domain || strings.HasSuffix(host, "."+r.domain))
+	case model.SourceFilterURL:
+		return opinion.URL != "" && r.pattern != nil && r.pattern.MatchString(strings.TrimSpace(opinion.URL))
+	}
+	return false
+}
+
+// urlHost 返回链接的域名（小写，不含端口），缺少协议时按 http 解析，无法解析时返回空字符串
+func urlHost(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// compileURLPattern 将链接模式编译为不区分大小写的正则表达式：* 匹配任意字符，需匹配完整链接；
+// 模式不含协议时匹配任意协议
+func compileURLPattern(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.Contains(pattern, "://") {
+		pattern = "*://" + pattern
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"testing"
+
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/model"
+)
+
+func TestGroupMatcherStages(t *testing.T) {
+	group := &model.MonitoringGroup{
+		ID:             1,
+		ScenarioID:     1,
+		Channels:       []model.Channel{{Code: "weibo", Name: "微博"}},
+		Keywords:       []model.GroupKeyword{{Keyword: "小米"}, {Keyword: "雷军"}},
+		ExclusionWords: []model.GroupExclusionWord{{ID: 11, Word: "抽奖"}, {ID: 12, Word: "广告"}},
+		SourceFilters: []model.GroupSourceFilter{
+			{ID: 21, Type: model.SourceFilterDomain, Mode: model.SourceFilterBlock, Value: "spam.example.com"},
+			{ID: 22, Type: model.SourceFilterDomain, Mode: model.SourceFilterAllow, Value: "weibo.com"},
+		},
+	}
+	segmenter := segment.NewSegmenter(segment.NewDictionary(), []string{"小米", "雷军", "抽奖", "广告"})
+	matcher := newGroupMatcher(group, map[uint64]bool{7: true}, segmenter)
+
+	cases := []struct {
+		name        string
+		opinion     *model.Opinion
+		stage       int
+		keyword     string
+		keywords    []int
+		sourceRules []uint64
+		excludedBy  []int
+	}{
+		{
+			name:    "off channel",
+			opinion: &model.Opinion{Source: "douyin", Content: "小米发布会", URL: "https://weibo.com/1"},
+			stage:   matchOffChannel,
+		},
+		{
+			name:    "no keyword",
+			opinion: &model.Opinion{Source: "weibo", Content: "今天天气不错", URL: "https://weibo.com/1"},
+			stage:   matchNoKeyword,
+		},
+		{
+			name:        "hit records first keyword",
+			opinion:     &model.Opinion{Source: "微博", Content: "雷军说小米要造车", URL: "https://weibo.com/1"},
+			stage:       matchHit,
+			keyword:     "小米",
+			keywords:    []int{0, 1},
+			sourceRules: []uint64{22},
+		},
+		{
+			name:        "not in allow list",
+			opinion:     &model.Opinion{Source: "weibo", Content: "小米发布会", URL: "https://news.example.com/1"},
+			stage:       matchSourceFiltered,
+			keyword:     "小米",
+			keywords:    []int{0},
+			sourceRules: nil,
+		},
+		{
+			name:        "excluded by both words",
+			opinion:     &model.Opinion{Source: "weibo", Content: "小米抽奖广告", URL: "https://weibo.com/1"},
+			stage:       matchExcluded,
+			keyword:     "小米",
+			keywords:    []int{0},
+			sourceRules: []uint64{22},
+			excludedBy:  []int{0, 1},
+		},
+		{
+			name:    "watched author without keyword skips allow list and exclusions",
+			opinion: &model.Opinion{Source: "weibo", AuthorID: 7, Content: "转发抽奖", URL: "https://news.example.com/1"},
+			stage:   matchHit,
+		},
+		{
+			name:        "watched author still blocked",
+			opinion:     &model.Opinion{Source: "weibo", AuthorID: 7, Content: "小米", URL: "https://spam.example.com/1"},
+			stage:       matchSourceFiltered,
+			keyword:     "小米",
+			keywords:    []int{0},
+			sourceRules: []uint64{21},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := matcher.match(c.opinion)
+			if got.stage != c.stage {
+				t.Fatalf("stage = %d, want %d", got.stage, c.stage)
+			}
+			if got.keyword != c.keyword {
+				t.Errorf("keyword = %q, want %q", got.keyword, c.keyword)
+			}
+			if !equalInts(got.keywords, c.keywords) {
+				t.Errorf("keywords = %v, want %v", got.keywords, c.keywords)
+			}
+			if !equalIDs(got.sourceRules, c.sourceRules) {
+				t.Errorf("sourceRules = %v, want %v", got.sourceRules, c.sourceRules)
+			}
+			if !equalInts(got.excludedBy, c.excludedBy) {
+				t.Errorf("excludedBy = %v, want %v", got.excludedBy, c.excludedBy)
+			}
+		})
+	}
+}
+
+func TestTermInTokens(t *testing.T) {
+	tokens := tokenSet([]string{"小米", "su7", "发布会"})
+
+	cases := []struct {
+		term string
+		want bool
+	}{
+		{term: "小米", want: true},
+		{term: "SU7", want: true},
+		{term: "米", want: false}, // 按分词结果匹配，不做子串匹配
+		{term: "小米 发布会", want: true},
+		{term: "小米  SU7", want: true},
+		{term: "小米 雷军", want: false}, // 含空格的词语要求每一部分都出现
+		{term: " ", want: false},
+	}
+	for _, c := range cases {
+		if got := termInTokens(c.term, tokens); got != c.want {
+			t.Errorf("termInTokens(%q) = %v, want %v", c.term, got, c.want)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalIDs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
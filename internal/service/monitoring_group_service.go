@@ -2,17 +2,51 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"sentinel-opinion-monitor/internal/model"
 	"sentinel-opinion-monitor/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+const (
+	// DefaultScanInterval 默认采集间隔（分钟）
+	DefaultScanInterval = 60
+	// MaxScanInterval 最大采集间隔（分钟），即一天
+	MaxScanInterval = 1440
+)
+
+// GroupScheduleParams 监测组采集计划参数，字段为 nil 表示不设置/不修改
+type GroupScheduleParams struct {
+	ScanInterval   *int    // 采集间隔（分钟）
+	ActiveHours    *string // 生效时段，如 09:00-18:00，支持跨天如 22:00-06:00，空字符串表示全天
+	ActiveWeekdays *string // 生效星期，如 1,2,3,4,5（0为周日），空字符串表示每天
+	StartDate      *string // 开始日期 YYYY-MM-DD，空字符串表示不限
+	EndDate        *string // 结束日期 YYYY-MM-DD（含当天），空字符串表示不限
+}
+
+// maxSourceFiltersPerGroup 每个监测组的来源过滤规则数量上限
+const maxSourceFiltersPerGroup = 500
+
+// SourceFilterParams 添加来源过滤规则参数
+type SourceFilterParams struct {
+	Type     string // 类型：author、domain、url
+	Mode     string // 模式：block、allow
+	AuthorID uint64 // 作者ID，类型为 author 时必填
+	Value    string // 域名或链接模式，类型为 domain、url 时必填
+	Note     string // 备注
+	UserID   uint64 // 添加人
+}
+
 // MonitoringGroupService 监测组服务接口
 type MonitoringGroupService interface {
-	CreateGroup(scenarioID uint64, name string, sort int) (*model.MonitoringGroup, error)
-	CreateGroupWithKeywordsAndExclusionWords(scenarioID uint64, name string, sort int, keywords []string, exclusionWords []string) (*model.MonitoringGroup, error)
+	CreateGroup(scenarioID uint64, name string, sort int, schedule *GroupScheduleParams) (*model.MonitoringGroup, error)
+	CreateGroupWithKeywordsAndExclusionWords(scenarioID uint64, name string, sort int, schedule *GroupScheduleParams, keywords []string, exclusionWords []string) (*model.MonitoringGroup, error)
 	GetGroupByID(id uint64) (*model.MonitoringGroup, error)
 	GetGroupsByScenarioID(scenarioID uint64) ([]*model.MonitoringGroup, error)
-	UpdateGroup(id uint64, name string, sort, status int) error
+	UpdateGroup(id uint64, name string, sort, status int, schedule *GroupScheduleParams) error
 	DeleteGroup(id uint64) error
 	GetGroupWithDetails(id uint64) (*model.MonitoringGroup, error)
 	AssignChannels(groupID uint64, channelIDs []uint64) error
@@ -22,23 +56,29 @@ type MonitoringGroupService interface {
 	AddExclusionWord(groupID uint64, word string) error
 	RemoveExclusionWord(groupID uint64, wordID uint64) error
 	GetExclusionWords(groupID uint64) ([]*model.GroupExclusionWord, error)
+	AddSourceFilter(groupID uint64, params *SourceFilterParams) (*model.GroupSourceFilter, error)
+	RemoveSourceFilter(groupID uint64, filterID uint64) error
+	GetSourceFilters(groupID uint64) ([]*model.GroupSourceFilter, error)
+	IsGroupDue(group *model.MonitoringGroup, now time.Time) bool
 }
 
 type monitoringGroupService struct {
 	groupRepo    repository.MonitoringGroupRepository
 	scenarioRepo repository.ScenarioRepository
+	authorRepo   repository.AuthorRepository
 }
 
 // NewMonitoringGroupService 创建监测组服务实例
-func NewMonitoringGroupService(groupRepo repository.MonitoringGroupRepository, scenarioRepo repository.ScenarioRepository) MonitoringGroupService {
+func NewMonitoringGroupService(groupRepo repository.MonitoringGroupRepository, scenarioRepo repository.ScenarioRepository, authorRepo repository.AuthorRepository) MonitoringGroupService {
 	return &monitoringGroupService{
 		groupRepo:    groupRepo,
 		scenarioRepo: scenarioRepo,
+		authorRepo:   authorRepo,
 	}
 }
 
 // CreateGroup 创建监测组
-func (s *monitoringGroupService) CreateGroup(scenarioID uint64, name string, sort int) (*model.MonitoringGroup, error) {
+func (s *monitoringGroupService) CreateGroup(scenarioID uint64, name string, sort int, schedule *GroupScheduleParams) (*model.MonitoringGroup, error) {
 	// 验证场景是否存在
 	_, err := s.scenarioRepo.GetByID(scenarioID)
 	if err != nil {
@@ -46,10 +86,15 @@ func (s *monitoringGroupService) CreateGroup(scenarioID uint64, name string, sor
 	}
 
 	group := &model.MonitoringGroup{
-		ScenarioID: scenarioID,
-		Name:       name,
-		Sort:       sort,
-		Status:     1, // 正常状态
+		ScenarioID:   scenarioID,
+		Name:         name,
+		Sort:         sort,
+		Status:       1, // 正常状态
+		ScanInterval: DefaultScanInterval,
+	}
+
+	if err := applyGroupSchedule(group, schedule); err != nil {
+		return nil, err
 	}
 
 	if err := s.groupRepo.Create(group); err != nil {
@@ -60,7 +105,7 @@ func (s *monitoringGroupService) CreateGroup(scenarioID uint64, name string, sor
 }
 
 // CreateGroupWithKeywordsAndExclusionWords 在事务中创建监测组及其关键词和排除词
-func (s *monitoringGroupService) CreateGroupWithKeywordsAndExclusionWords(scenarioID uint64, name string, sort int, keywords []string, exclusionWords []string) (*model.MonitoringGroup, error) {
+func (s *monitoringGroupService) CreateGroupWithKeywordsAndExclusionWords(scenarioID uint64, name string, sort int, schedule *GroupScheduleParams, keywords []string, exclusionWords []string) (*model.MonitoringGroup, error) {
 	// 验证场景是否存在
 	_, err := s.scenarioRepo.GetByID(scenarioID)
 	if err != nil {
@@ -68,10 +113,15 @@ func (s *monitoringGroupService) CreateGroupWithKeywordsAndExclusionWords(scenar
 	}
 
 	group := &model.MonitoringGroup{
-		ScenarioID: scenarioID,
-		Name:       name,
-		Sort:       sort,
-		Status:     1, // 正常状态
+		ScenarioID:   scenarioID,
+		Name:         name,
+		Sort:         sort,
+		Status:       1, // 正常状态
+		ScanInterval: DefaultScanInterval,
+	}
+
+	if err := applyGroupSchedule(group, schedule); err != nil {
+		return nil, err
 	}
 
 	// 在事务中创建监测组、关键词和排除词
@@ -93,7 +143,7 @@ func (s *monitoringGroupService) GetGroupsByScenarioID(scenarioID uint64) ([]*mo
 }
 
 // UpdateGroup 更新监测组
-func (s *monitoringGroupService) UpdateGroup(id uint64, name string, sort, status int) error {
+func (s *monitoringGroupService) UpdateGroup(id uint64, name string, sort, status int, schedule *GroupScheduleParams) error {
 	group, err := s.groupRepo.GetByID(id)
 	if err != nil {
 		return errors.New("监测组不存在")
@@ -109,6 +159,10 @@ func (s *monitoringGroupService) UpdateGroup(id uint64, name string, sort, statu
 		group.Status = status
 	}
 
+	if err := applyGroupSchedule(group, schedule); err != nil {
+		return err
+	}
+
 	return s.groupRepo.Update(group)
 }
 
@@ -162,3 +216,285 @@ func (s *monitoringGroupService) RemoveExclusionWord(groupID uint64, wordID uint
 func (s *monitoringGroupService) GetExclusionWords(groupID uint64) ([]*model.GroupExclusionWord, error) {
 	return s.groupRepo.GetExclusionWords(groupID)
 }
+
+// AddSourceFilter 添加来源过滤规则；同一来源在一个监测组中只能出现一次（不区分允许和屏蔽）
+func (s *monitoringGroupService) AddSourceFilter(groupID uint64, params *SourceFilterParams) (*model.GroupSourceFilter, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+	if params.Mode != model.SourceFilterBlock && params.Mode != model.SourceFilterAllow {
+		return nil, errors.New("无效的模式，可选值: block, allow")
+	}
+
+	filter := &model.GroupSourceFilter{
+		GroupID:   groupID,
+		Type:      params.Type,
+		Mode:      params.Mode,
+		Note:      strings.TrimSpace(params.Note),
+		CreatedBy: params.UserID,
+	}
+	switch params.Type {
+	case model.SourceFilterAuthor:
+		author, err := s.authorRepo.GetByID(params.AuthorID)
+		if err != nil {
+			return nil, errors.New("作者不存在")
+		}
+		filter.AuthorID = author.ID
+		filter.Author = author
+	case model.SourceFilterDomain:
+		domain, err := normalizeDomain(params.Value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Value = domain
+	case model.SourceFilterURL:
+		pattern := strings.TrimSpace(params.Value)
+		if strings.Trim(pattern, "*") == "" {
+			return nil, errors.New("链接模式不能为空")
+		}
+		if utf8.RuneCountInString(pattern) > 512 {
+			return nil, errors.New("链接模式不能超过 512 个字符")
+		}
+		if _, err := compileURLPattern(pattern); err != nil {
+			return nil, errors.New("无效的链接模式")
+		}
+		filter.Value = pattern
+	default:
+		return nil, errors.New("无效的类型，可选值: author, domain, url")
+	}
+
+	existing, err := s.groupRepo.GetSourceFilters(groupID)
+	if err != nil {
+		return nil, errors.New("获取来源过滤规则失败")
+	}
+	if len(existing) >= maxSourceFiltersPerGroup {
+		return nil, fmt.Errorf("每个监测组最多 %d 条来源过滤规则", maxSourceFiltersPerGroup)
+	}
+	for _, other := range existing {
+		if other.Type == filter.Type && other.AuthorID == filter.AuthorID && strings.EqualFold(other.Value, filter.Value) {
+			if other.Mode == model.SourceFilterAllow {
+				return nil, errors.New("该来源已在允许名单中")
+			}
+			return nil, errors.New("该来源已在屏蔽名单中")
+		}
+	}
+
+	if err := s.groupRepo.AddSourceFilter(filter); err != nil {
+		return nil, errors.New("添加来源过滤规则失败")
+	}
+	return filter, nil
+}
+
+// RemoveSourceFilter 删除来源过滤规则
+func (s *monitoringGroupService) RemoveSourceFilter(groupID uint64, filterID uint64) error {
+	removed, err := s.groupRepo.RemoveSourceFilter(groupID, filterID)
+	if err != nil {
+		return errors.New("删除来源过滤规则失败")
+	}
+	if removed == 0 {
+		return errors.New("来源过滤规则不存在")
+	}
+	return nil
+}
+
+// GetSourceFilters 获取来源过滤规则列表
+func (s *monitoringGroupService) GetSourceFilters(groupID uint64) ([]*model.GroupSourceFilter, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+	return s.groupRepo.GetSourceFilters(groupID)
+}
+
+// normalizeDomain 规范化域名规则：支持直接填写链接，转为小写并去掉开头的 www. 和 *.
+func normalizeDomain(value string) (string, error) {
+	domain := strings.TrimPrefix(urlHost(strings.TrimPrefix(strings.TrimSpace(value), "*.")), "www.")
+	if domain == "" || !strings.Contains(domain, ".") || strings.ContainsAny(domain, "* ") {
+		return "", errors.New("无效的域名")
+	}
+	if len(domain) > 255 {
+		return "", errors.New("域名不能超过 255 个字符")
+	}
+	return domain, nil
+}
+
+// IsGroupDue 判断监测组在 now 时刻是否需要执行采集
+func (s *monitoringGroupService) IsGroupDue(group *model.MonitoringGroup, now time.Time) bool {
+	if group.Status != 1 {
+		return false
+	}
+	if !inGroupSchedule(group, now) {
+		return false
+	}
+	if group.LastScannedAt == nil {
+		return true
+	}
+
+	interval := group.ScanInterval
+	if interval <= 0 {
+		interval = DefaultScanInterval
+	}
+	return !now.Before(group.LastScannedAt.Add(time.Duration(interval) * time.Minute))
+}
+
+// inGroupSchedule 判断 now 是否落在监测组的生效日期、星期和时段内
+func inGroupSchedule(group *model.MonitoringGroup, now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if group.StartDate != nil && today.Before(truncateDate(*group.StartDate, now.Location())) {
+		return false
+	}
+	if group.EndDate != nil && today.After(truncateDate(*group.EndDate, now.Location())) {
+		return false
+	}
+
+	if group.ActiveWeekdays != "" {
+		weekdays, err := parseActiveWeekdays(group.ActiveWeekdays)
+		if err == nil && !weekdays[now.Weekday()] {
+			return false
+		}
+	}
+
+	if group.ActiveHours != "" {
+		start, end, err := parseActiveHours(group.ActiveHours)
+		if err == nil {
+			minute := now.Hour()*60 + now.Minute()
+			if start <= end {
+				// 当天时段，如 09:00-18:00
+				return minute >= start && minute < end
+			}
+			// 跨天时段，如 22:00-06:00
+			return minute >= start || minute < end
+		}
+	}
+
+	return true
+}
+
+// applyGroupSchedule 校验采集计划参数并写入监测组
+func applyGroupSchedule(group *model.MonitoringGroup, schedule *GroupScheduleParams) error {
+	if schedule == nil {
+		return nil
+	}
+
+	if schedule.ScanInterval != nil {
+		if *schedule.ScanInterval < 1 || *schedule.ScanInterval > MaxScanInterval {
+			return fmt.Errorf("采集间隔必须在1到%d分钟之间", MaxScanInterval)
+		}
+		group.ScanInterval = *schedule.ScanInterval
+	}
+
+	if schedule.ActiveHours != nil {
+		hours := strings.TrimSpace(*schedule.ActiveHours)
+		if hours != "" {
+			if _, _, err := parseActiveHours(hours); err != nil {
+				return err
+			}
+		}
+		group.ActiveHours = hours
+	}
+
+	if schedule.ActiveWeekdays != nil {
+		weekdays := strings.TrimSpace(*schedule.ActiveWeekdays)
+		if weekdays != "" {
+			if _, err := parseActiveWeekdays(weekdays); err != nil {
+				return err
+			}
+		}
+		group.ActiveWeekdays = weekdays
+	}
+
+	if schedule.StartDate != nil {
+		date, err := parseScheduleDate(*schedule.StartDate)
+		if err != nil {
+			return errors.New("开始日期格式错误，应为YYYY-MM-DD")
+		}
+		group.StartDate = date
+	}
+
+	if schedule.EndDate != nil {
+		date, err := parseScheduleDate(*schedule.EndDate)
+		if err != nil {
+			return errors.New("结束日期格式错误，应为YYYY-MM-DD")
+		}
+		group.EndDate = date
+	}
+
+	if group.StartDate != nil && group.EndDate != nil && group.EndDate.Before(*group.StartDate) {
+		return errors.New("结束日期不能早于开始日期")
+	}
+
+	return nil
+}
+
+// parseActiveHours 解析生效时段，返回起止时刻（当天分钟数）
+func parseActiveHours(value string) (int, int, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("生效时段格式错误，应为HH:MM-HH:MM")
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return 0, 0, errors.New("生效时段格式错误，应为HH:MM-HH:MM")
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return 0, 0, errors.New("生效时段格式错误，应为HH:MM-HH:MM")
+	}
+	if start == end {
+		return 0, 0, errors.New("生效时段起止时间不能相同")
+	}
+	return start, end, nil
+}
+
+// parseClock 解析 HH:MM，返回当天分钟数（允许 24:00 表示当天结束）
+func parseClock(value string) (int, error) {
+	t := strings.TrimSpace(value)
+	parts := strings.Split(t, ":")
+	if len(parts) != 2 {
+		return 0, errors.New("invalid clock")
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, errors.New("invalid clock")
+	}
+	return hour*60 + minute, nil
+}
+
+// parseActiveWeekdays 解析生效星期列表
+func parseActiveWeekdays(value string) (map[time.Weekday]bool, error) {
+	weekdays := make(map[time.Weekday]bool)
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || day < 0 || day > 6 {
+			return nil, errors.New("生效星期格式错误，应为0-6的逗号分隔列表（0为周日）")
+		}
+		weekdays[time.Weekday(day)] = true
+	}
+	return weekdays, nil
+}
+
+// parseScheduleDate 解析 YYYY-MM-DD 日期，空字符串返回 nil
+func parseScheduleDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// truncateDate 将日期截断到 loc 时区下的零点
+func truncateDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
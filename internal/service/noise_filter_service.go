@@ -0,0 +1,321 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/noise"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// NoiseFilterParams 噪音过滤器设置参数（未传的字段保持不变）
+type NoiseFilterParams struct {
+	Enabled   *bool
+	Threshold *float64 // 0 表示使用全局配置
+}
+
+// NoiseFilterService 噪音过滤器服务接口
+type NoiseFilterService interface {
+	Get(groupID uint64) (*model.NoiseFilter, error)
+	Update(groupID uint64, params *NoiseFilterParams) (*model.NoiseFilter, error)
+	Train(groupID uint64) (*model.NoiseFilter, error)
+	TrainDue() (int, error)
+	Apply(groupID uint64, hits []*model.OpinionHit, opinions []*model.Opinion) (int, error)
+	GetSuppressed(groupID uint64, pending bool, page, pageSize int) ([]*repository.SuppressedHit, int64, error)
+}
+
+// cachedNoiseModel 已加载的模型，训练时间变化时重新加载
+type cachedNoiseModel struct {
+	trainedAt time.Time
+	model     *noise.Model
+}
+
+type noiseFilterService struct {
+	cfg            config.NoiseFilterConfig
+	filterRepo     repository.NoiseFilterRepository
+	groupRepo      repository.MonitoringGroupRepository
+	hitRepo        repository.OpinionHitRepository
+	segmentService SegmentService
+
+	mu     sync.Mutex
+	models map[uint64]*cachedNoiseModel
+}
+
+// NewNoiseFilterService 创建噪音过滤器服务实例
+func NewNoiseFilterService(
+	cfg *config.NoiseFilterConfig,
+	filterRepo repository.NoiseFilterRepository,
+	groupRepo repository.MonitoringGroupRepository,
+	hitRepo repository.OpinionHitRepository,
+	segmentService SegmentService,
+) NoiseFilterService {
+	s := &noiseFilterService{
+		filterRepo:     filterRepo,
+		groupRepo:      groupRepo,
+		hitRepo:        hitRepo,
+		segmentService: segmentService,
+		models:         make(map[uint64]*cachedNoiseModel),
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.Threshold <= 0 || s.cfg.Threshold > 1 {
+		s.cfg.Threshold = 0.9
+	}
+	if s.cfg.MinSamples <= 0 {
+		s.cfg.MinSamples = 20
+	}
+	if s.cfg.MaxSamples <= 0 {
+		s.cfg.MaxSamples = 5000
+	}
+	if s.cfg.MinTermDocs <= 0 {
+		s.cfg.MinTermDocs = 2
+	}
+	if s.cfg.MaxTerms <= 0 {
+		s.cfg.MaxTerms = 20000
+	}
+	return s
+}
+
+// Get 获取监测组的噪音过滤器设置和训练状态，未设置过时返回默认设置
+func (s *noiseFilterService) Get(groupID uint64) (*model.NoiseFilter, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+
+	filter, err := s.filterRepo.GetByGroupID(groupID)
+	if err != nil {
+		return nil, errors.New("获取噪音过滤器失败")
+	}
+	if filter == nil {
+		filter = &model.NoiseFilter{GroupID: groupID, Enabled: true}
+	}
+	count, err := s.hitRepo.CountSuppressed(groupID)
+	if err != nil {
+		return nil, errors.New("统计屏蔽的命中失败")
+	}
+	filter.SuppressedCount = count
+	s.decorate(filter)
+	return filter, nil
+}
+
+// Update 更新监测组噪音过滤器的启用状态和屏蔽阈值
+func (s *noiseFilterService) Update(groupID uint64, params *NoiseFilterParams) (*model.NoiseFilter, error) {
+	if params.Threshold != nil && (*params.Threshold < 0 || *params.Threshold > 1) {
+		return nil, errors.New("屏蔽阈值必须在 0~1 之间")
+	}
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+
+	filter, err := s.ensure(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if params.Enabled != nil {
+		filter.Enabled = *params.Enabled
+	}
+	if params.Threshold != nil {
+		filter.Threshold = *params.Threshold
+	}
+	if err := s.filterRepo.UpdateSettings(filter); err != nil {
+		return nil, errors.New("保存噪音过滤器设置失败")
+	}
+	return s.Get(groupID)
+}
+
+// Train 用监测组最近标注的命中舆情训练噪音过滤器，相关和不相关样本均需达到 min_samples 条
+func (s *noiseFilterService) Train(groupID uint64) (*model.NoiseFilter, error) {
+	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+		return nil, errors.New("监测组不存在")
+	}
+
+	docs, err := s.hitRepo.GetLabeledDocuments(groupID, time.Unix(0, 0), s.cfg.MaxSamples)
+	if err != nil {
+		return nil, errors.New("获取已标注舆情失败")
+	}
+	segmenter, err := s.segmentService.GetSegmenter()
+	if err != nil {
+		return nil, errors.New("加载分词词典失败")
+	}
+
+	samples := make([]noise.Sample, len(docs))
+	relevant, irrelevant := 0, 0
+	for i, doc := range docs {
+		isNoise := doc.Relevance == model.HitRelevanceIrrelevant
+		if isNoise {
+			irrelevant++
+		} else {
+			relevant++
+		}
+		samples[i] = noise.Sample{Tokens: segmenter.Cut(doc.Content), Noise: isNoise}
+	}
+	if relevant < s.cfg.MinSamples || irrelevant < s.cfg.MinSamples {
+		return nil, fmt.Errorf("标注样本不足：相关 %d 条、不相关 %d 条，各需至少 %d 条", relevant, irrelevant, s.cfg.MinSamples)
+	}
+
+	trained := noise.Train(samples, s.cfg.MinTermDocs, s.cfg.MaxTerms)
+	data, err := json.Marshal(trained)
+	if err != nil {
+		return nil, errors.New("序列化模型失败")
+	}
+
+	filter, err := s.ensure(groupID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	filter.RelevantDocs = trained.RelevantDocs
+	filter.NoiseDocs = trained.NoiseDocs
+	filter.Terms = len(trained.Terms)
+	filter.Model = string(data)
+	filter.TrainedAt = &now
+	if err := s.filterRepo.UpdateModel(filter); err != nil {
+		return nil, errors.New("保存模型失败")
+	}
+
+	s.mu.Lock()
+	s.models[groupID] = &cachedNoiseModel{trainedAt: now, model: trained}
+	s.mu.Unlock()
+
+	return s.Get(groupID)
+}
+
+// TrainDue 重新训练标注有变化（有新的标注或样本数变化）且样本充足的监测组，返回训练的监测组数
+func (s *noiseFilterService) TrainDue() (int, error) {
+	stats, err := s.hitRepo.GetLabelStats()
+	if err != nil {
+		return 0, err
+	}
+
+	trained := 0
+	for _, stat := range stats {
+		if stat.Relevant < int64(s.cfg.MinSamples) || stat.Irrelevant < int64(s.cfg.MinSamples) {
+			continue
+		}
+		filter, err := s.filterRepo.GetByGroupID(stat.GroupID)
+		if err != nil {
+			return trained, err
+		}
+		if filter != nil && filter.TrainedAt != nil {
+			changed := stat.LastLabeledAt != nil && stat.LastLabeledAt.After(*filter.TrainedAt)
+			samples := stat.Relevant + stat.Irrelevant
+			if samples > int64(s.cfg.MaxSamples) {
+				samples = int64(s.cfg.MaxSamples)
+			}
+			if !changed && samples == int64(filter.RelevantDocs+filter.NoiseDocs) {
+				continue
+			}
+		}
+
+		if _, err := s.Train(stat.GroupID); err != nil {
+			// 最近的 max_samples 条样本中某一类可能不足，跳过该监测组
+			appLogger.Get().Warn("训练噪音过滤器失败", zap.Uint64("group_id", stat.GroupID), zap.Error(err))
+			continue
+		}
+		trained++
+	}
+	return trained, nil
+}
+
+// Apply 对新命中打分（NoiseScore），噪音过滤器启用且得分不低于阈值时屏蔽命中（Suppressed），返回屏蔽数。
+// 监测组没有训练过模型时不做处理；场景关注的作者的命中只打分不屏蔽
+func (s *noiseFilterService) Apply(groupID uint64, hits []*model.OpinionHit, opinions []*model.Opinion) (int, error) {
+	if len(hits) == 0 {
+		return 0, nil
+	}
+	filter, classifier, err := s.load(groupID)
+	if err != nil || classifier == nil {
+		return 0, err
+	}
+	segmenter, err := s.segmentService.GetSegmenter()
+	if err != nil {
+		return 0, err
+	}
+
+	contents := make(map[uint64]string, len(opinions))
+	for _, opinion := range opinions {
+		contents[opinion.ID] = opinion.Content
+	}
+
+	s.decorate(filter)
+	suppress := s.cfg.Enabled && filter.Enabled
+	suppressed := 0
+	for _, hit := range hits {
+		score := classifier.Score(segmenter.Cut(contents[hit.OpinionID]))
+		hit.NoiseScore = math.Round(score*10000) / 10000
+		if suppress && !hit.Watched && score >= filter.EffectiveThreshold {
+			hit.Suppressed = true
+			suppressed++
+		}
+	}
+	return suppressed, nil
+}
+
+// GetSuppressed 分页获取被屏蔽的命中舆情，pending 为 true 时只返回未标注的
+func (s *noiseFilterService) GetSuppressed(groupID uint64, pending bool, page, pageSize int) ([]*repository.SuppressedHit, int64, error) {
+	return s.hitRepo.GetSuppressed(groupID, pending, page, pageSize)
+}
+
+// ensure 获取监测组的噪音过滤器，不存在时按默认设置创建
+func (s *noiseFilterService) ensure(groupID uint64) (*model.NoiseFilter, error) {
+	filter, err := s.filterRepo.GetByGroupID(groupID)
+	if err != nil {
+		return nil, errors.New("获取噪音过滤器失败")
+	}
+	if filter != nil {
+		return filter, nil
+	}
+	filter = &model.NoiseFilter{GroupID: groupID, Enabled: true}
+	if err := s.filterRepo.Create(filter); err != nil {
+		return nil, errors.New("创建噪音过滤器失败")
+	}
+	return filter, nil
+}
+
+// load 获取监测组的噪音过滤器和模型，模型按训练时间缓存；没有训练过时模型为 nil
+func (s *noiseFilterService) load(groupID uint64) (*model.NoiseFilter, *noise.Model, error) {
+	filter, err := s.filterRepo.GetByGroupID(groupID)
+	if err != nil || filter == nil || filter.TrainedAt == nil {
+		return filter, nil, err
+	}
+
+	s.mu.Lock()
+	cached, ok := s.models[groupID]
+	s.mu.Unlock()
+	if ok && cached.trainedAt.Equal(*filter.TrainedAt) {
+		return filter, cached.model, nil
+	}
+
+	data, err := s.filterRepo.GetModel(filter.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var classifier noise.Model
+	if err := json.Unmarshal([]byte(data), &classifier); err != nil {
+		return nil, nil, fmt.Errorf("解析噪音过滤器模型失败: %w", err)
+	}
+
+	s.mu.Lock()
+	s.models[groupID] = &cachedNoiseModel{trainedAt: *filter.TrainedAt, model: &classifier}
+	s.mu.Unlock()
+	return filter, &classifier, nil
+}
+
+// decorate 填充实际使用的阈值和训练状态
+func (s *noiseFilterService) decorate(filter *model.NoiseFilter) {
+	filter.EffectiveThreshold = filter.Threshold
+	if filter.EffectiveThreshold <= 0 {
+		filter.EffectiveThreshold = s.cfg.Threshold
+	}
+	filter.Trained = filter.TrainedAt != nil && filter.RelevantDocs > 0 && filter.NoiseDocs > 0
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/noise"
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// fakeNoiseFilterRepo 返回固定的过滤器设置和模型
+type fakeNoiseFilterRepo struct {
+	repository.NoiseFilterRepository
+	filter *model.NoiseFilter
+	model  string
+}
+
+func (r *fakeNoiseFilterRepo) GetByGroupID(groupID uint64) (*model.NoiseFilter, error) {
+	if r.filter == nil {
+		return nil, nil
+	}
+	copied := *r.filter
+	return &copied, nil
+}
+
+func (r *fakeNoiseFilterRepo) GetModel(id uint64) (string, error) {
+	return r.model, nil
+}
+
+// fakeSegmentService 使用空的基础词典，只按用户词切分
+type fakeSegmentService struct {
+	SegmentService
+	words []string
+}
+
+func (s fakeSegmentService) GetSegmenter() (*segment.Segmenter, error) {
+	return segment.NewSegmenter(segment.NewDictionary(), s.words), nil
+}
+
+func TestNoiseFilterApply(t *testing.T) {
+	var samples []noise.Sample
+	for i := 0; i < 5; i++ {
+		samples = append(samples,
+			noise.Sample{Tokens: []string{"小米", "汽车", "发布会"}},
+			noise.Sample{Tokens: []string{"转发", "抽奖", "关注"}, Noise: true},
+		)
+	}
+	data, err := json.Marshal(noise.Train(samples, 1, 0))
+	if err != nil {
+		t.Fatalf("marshal model: %v", err)
+	}
+	trainedAt := time.Now()
+	segments := fakeSegmentService{words: []string{"小米", "汽车", "发布会", "转发", "抽奖", "关注"}}
+
+	const (
+		noiseScore    = 0.9954
+		relevantScore = 0.0046
+	)
+	cases := []struct {
+		name           string
+		globalDisabled bool
+		filter         *model.NoiseFilter
+		content        string
+		watched        bool
+		score          float64
+		suppressed     bool
+	}{
+		{
+			name:       "noise suppressed at default threshold",
+			filter:     &model.NoiseFilter{ID: 1, Enabled: true, TrainedAt: &trainedAt},
+			content:    "转发抽奖关注",
+			score:      noiseScore,
+			suppressed: true,
+		},
+		{
+			name:    "relevant kept",
+			filter:  &model.NoiseFilter{ID: 1, Enabled: true, TrainedAt: &trainedAt},
+			content: "小米汽车发布会",
+			score:   relevantScore,
+		},
+		{
+			name:    "group threshold above score",
+			filter:  &model.NoiseFilter{ID: 1, Enabled: true, Threshold: 0.999, TrainedAt: &trainedAt},
+			content: "转发抽奖关注",
+			score:   noiseScore,
+		},
+		{
+			name:    "group filter disabled only scores",
+			filter:  &model.NoiseFilter{ID: 1, Enabled: false, TrainedAt: &trainedAt},
+			content: "转发抽奖关注",
+			score:   noiseScore,
+		},
+		{
+			name:           "global switch off only scores",
+			globalDisabled: true,
+			filter:         &model.NoiseFilter{ID: 1, Enabled: true, TrainedAt: &trainedAt},
+			content:        "转发抽奖关注",
+			score:          noiseScore,
+		},
+		{
+			name:    "watched author never suppressed",
+			filter:  &model.NoiseFilter{ID: 1, Enabled: true, TrainedAt: &trainedAt},
+			content: "转发抽奖关注",
+			watched: true,
+			score:   noiseScore,
+		},
+		{
+			name:    "not trained",
+			filter:  &model.NoiseFilter{ID: 1, Enabled: true},
+			content: "转发抽奖关注",
+		},
+		{
+			name:    "no filter",
+			content: "转发抽奖关注",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &config.NoiseFilterConfig{Enabled: !c.globalDisabled}
+			repo := &fakeNoiseFilterRepo{filter: c.filter, model: string(data)}
+			svc := NewNoiseFilterService(cfg, repo, nil, nil, segments)
+
+			hits := []*model.OpinionHit{{OpinionID: 1, GroupID: 3, Watched: c.watched}}
+			opinions := []*model.Opinion{{ID: 1, Content: c.content}}
+			suppressed, err := svc.Apply(3, hits, opinions)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if hits[0].NoiseScore != c.score {
+				t.Errorf("NoiseScore = %v, want %v", hits[0].NoiseScore, c.score)
+			}
+			if hits[0].Suppressed != c.suppressed {
+				t.Errorf("Suppressed = %v, want %v", hits[0].Suppressed, c.suppressed)
+			}
+			want := 0
+			if c.suppressed {
+				want = 1
+			}
+			if suppressed != want {
+				t.Errorf("Apply() = %d, want %d", suppressed, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/notify"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// 通知来源
+const (
+	NotifySourceAlert       = "alert"
+	NotifySourceReport      = "report"
+	NotifySourceTest        = "test"
+	NotifySourceSavedSearch = "saved_search"
+)
+
+// DeliveryFilter 通知发送记录查询条件
+type DeliveryFilter = repository.DeliveryFilter
+
+// NotificationChannelParams 通知渠道参数，字段为 nil 表示不设置/不修改
+type NotificationChannelParams struct {
+	Name      *string
+	Type      *string
+	URL       *string
+	Secret    *string
+	Emails    []string
+	AtMobiles []string
+	AtAll     *bool
+	Template  *string
+	Status    *int
+}
+
+// NotificationService 通知服务接口
+type NotificationService interface {
+	CreateChannel(params *NotificationChannelParams) (*model.NotificationChannel, error)
+	GetChannel(id uint64) (*model.NotificationChannel, error)
+	ListChannels() ([]*model.NotificationChannel, error)
+	UpdateChannel(id uint64, params *NotificationChannelParams) (*model.NotificationChannel, error)
+	DeleteChannel(id uint64) error
+	TestChannel(id uint64) (*model.NotificationDelivery, error)
+	Send(channelIDs []uint64, msg notify.Message, source string, sourceID uint64) []*model.NotificationDelivery
+	SendEmail(emails []string, msg notify.Message, source string, sourceID uint64) *model.NotificationDelivery
+	NotifyAlert(rule *model.AlertRule, event *model.AlertEvent) []*model.NotificationDelivery
+	ListDeliveries(filter DeliveryFilter, page, pageSize int) ([]*model.NotificationDelivery, int64, error)
+}
+
+type notificationService struct {
+	channelRepo  repository.NotificationChannelRepository
+	deliveryRepo repository.NotificationDeliveryRepository
+	notifiers    map[string]notify.Notifier
+	retry        notify.RetryPolicy
+	timeout      time.Duration
+}
+
+// NewNotificationService 创建通知服务实例
+func NewNotificationService(cfg *config.NotifyConfig, channelRepo repository.NotificationChannelRepository, deliveryRepo repository.NotificationDeliveryRepository) NotificationService {
+	var notifyCfg config.NotifyConfig
+	if cfg != nil {
+		notifyCfg = *cfg
+	}
+
+	timeout := time.Duration(notifyCfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	retry := notify.DefaultRetryPolicy
+	if notifyCfg.RetryAttempts > 0 {
+		retry.MaxAttempts = notifyCfg.RetryAttempts
+	}
+	if notifyCfg.RetryBackoffMs > 0 {
+		retry.InitialBackoff = time.Duration(notifyCfg.RetryBackoffMs) * time.Millisecond
+	}
+
+	client := &http.Client{Timeout: timeout}
+	notifiers := map[string]notify.Notifier{
+		notify.TypeWebhook:  notify.NewWebhookNotifier(client),
+		notify.TypeDingTalk: notify.NewDingTalkNotifier(client),
+		notify.TypeWeCom:    notify.NewWeComNotifier(client),
+		notify.TypeFeishu:   notify.NewFeishuNotifier(client),
+		notify.TypeEmail: notify.NewEmailNotifier(notify.SMTPOptions{
+			Host:     notifyCfg.SMTP.Host,
+			Port:     notifyCfg.SMTP.Port,
+			Username: notifyCfg.SMTP.Username,
+			Password: notifyCfg.SMTP.Password,
+			From:     notifyCfg.SMTP.From,
+			TLS:      notifyCfg.SMTP.TLS,
+			Timeout:  timeout,
+		}),
+	}
+
+	return &notificationService{
+		channelRepo:  channelRepo,
+		deliveryRepo: deliveryRepo,
+		notifiers:    notifiers,
+		retry:        retry,
+		timeout:      timeout,
+	}
+}
+
+// CreateChannel 创建通知渠道
+func (s *notificationService) CreateChannel(params *NotificationChannelParams) (*model.NotificationChannel, error) {
+	if params == nil || params.Name == nil || params.Type == nil {
+		return nil, errors.New("渠道名称和类型不能为空")
+	}
+
+	channel := &model.NotificationChannel{Status: 1} // 正常状态
+	if err := s.applyChannelParams(channel, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.Create(channel); err != nil {
+		return nil, errors.New("创建通知渠道失败")
+	}
+	return channel, nil
+}
+
+// GetChannel 根据 ID 获取通知渠道
+func (s *notificationService) GetChannel(id uint64) (*model.NotificationChannel, error) {
+	return s.channelRepo.GetByID(id)
+}
+
+// ListChannels 获取所有通知渠道
+func (s *notificationService) ListChannels() ([]*model.NotificationChannel, error) {
+	return s.channelRepo.GetAll()
+}
+
+// UpdateChannel 更新通知渠道
+func (s *notificationService) UpdateChannel(id uint64, params *NotificationChannelParams) (*model.NotificationChannel, error) {
+	channel, err := s.channelRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("通知渠道不存在")
+	}
+
+	if err := s.applyChannelParams(channel, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.Update(channel); err != nil {
+		return nil, errors.New("更新通知渠道失败")
+	}
+	return channel, nil
+}
+
+// DeleteChannel 删除通知渠道
+func (s *notificationService) DeleteChannel(id uint64) error {
+	if _, err := s.channelRepo.GetByID(id); err != nil {
+		return errors.New("通知渠道不存在")
+	}
+	return s.channelRepo.Delete(id)
+}
+
+// TestChannel 向渠道发送一条测试消息
+func (s *notificationService) TestChannel(id uint64) (*model.NotificationDelivery, error) {
+	channel, err := s.channelRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("通知渠道不存在")
+	}
+
+	msg := notify.Message{
+		Title:    "【测试】舆情监控通知",
+		Content:  fmt.Sprintf("这是一条来自通知渠道「%s」的测试消息。", channel.Name),
+		Severity: "info",
+		Time:     time.Now(),
+	}
+	return s.deliver(channel, msg, NotifySourceTest, channel.ID), nil
+}
+
+// Send 向多个渠道发送消息并记录发送结果，禁用或不存在的渠道会被跳过
+func (s *notificationService) Send(channelIDs []uint64, msg notify.Message, source string, sourceID uint64) []*model.NotificationDelivery {
+	channels, err := s.channelRepo.GetByIDs(channelIDs)
+	if err != nil {
+		appLogger.Get().Error("获取通知渠道失败", zap.Error(err))
+		return nil
+	}
+
+	deliveries := make([]*model.NotificationDelivery, 0, len(channels))
+	for _, channel := range channels {
+		if channel.Status != 1 {
+			continue
+		}
+		deliveries = append(deliveries, s.deliver(channel, msg, source, sourceID))
+	}
+	return deliveries
+}
+
+// SendEmail 通过系统 SMTP 直接向邮箱发送消息（如通知值班用户），发送记录的渠道ID为 0
+func (s *notificationService) SendEmail(emails []string, msg notify.Message, source string, sourceID uint64) *model.NotificationDelivery {
+	channel := &model.NotificationChannel{
+		Name:   "用户邮件",
+		Type:   notify.TypeEmail,
+		Emails: model.StringList(emails),
+		Status: 1,
+	}
+	return s.deliver(channel, msg, source, sourceID)
+}
+
+// NotifyAlert 按规则配置的通知渠道发送告警事件
+func (s *notificationService) NotifyAlert(rule *model.AlertRule, event *model.AlertEvent) []*model.NotificationDelivery {
+	if len(rule.ChannelIDs) == 0 {
+		return nil
+	}
+	return s.Send(rule.ChannelIDs, alertMessage(rule, event), NotifySourceAlert, event.ID)
+}
+
+// ListDeliveries 按条件分页获取发送记录
+func (s *notificationService) ListDeliveries(filter DeliveryFilter, page, pageSize int) ([]*model.NotificationDelivery, int64, error) {
+	return s.deliveryRepo.List(filter, page, pageSize)
+}
+
+// deliver 渲染模板并按重试策略发送到单个渠道，写入发送记录
+func (s *notificationService) deliver(channel *model.NotificationChannel, msg notify.Message, source string, sourceID uint64) *model.NotificationDelivery {
+	delivery := &model.NotificationDelivery{
+		ChannelID:   channel.ID,
+		ChannelType: channel.Type,
+		Source:      source,
+		SourceID:    sourceID,
+		Title:       truncateRunes(msg.Title, 255),
+	}
+
+	started := time.Now()
+	err := s.send(channel, msg, delivery)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.Error = truncateRunes(err.Error(), 1000)
+		appLogger.Get().Warn("发送通知失败",
+			zap.Uint64("channel_id", channel.ID),
+			zap.String("source", source),
+			zap.Uint64("source_id", sourceID),
+			zap.Error(err),
+		)
+	} else {
+		delivery.Status = model.DeliveryStatusSuccess
+	}
+
+	if err := s.deliveryRepo.Create(delivery); err != nil {
+		appLogger.Get().Error("写入通知发送记录失败", zap.Uint64("channel_id", channel.ID), zap.Error(err))
+	}
+	return delivery
+}
+
+// send 渲染并发送消息，记录尝试次数
+func (s *notificationService) send(channel *model.NotificationChannel, msg notify.Message, delivery *model.NotificationDelivery) error {
+	notifier, ok := s.notifiers[channel.Type]
+	if !ok {
+		return errors.New("不支持的渠道类型")
+	}
+
+	body, err := notify.Render(channel.Type, channel.Template, msg)
+	if err != nil {
+		return fmt.Errorf("渲染消息模板失败: %w", err)
+	}
+	msg.Body = body
+
+	target := notify.Target{
+		URL:       channel.URL,
+		Secret:    channel.Secret,
+		Emails:    channel.Emails,
+		AtMobiles: channel.AtMobiles,
+		AtAll:     channel.AtAll,
+	}
+
+	// 整体超时覆盖所有重试
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.retry.MaxAttempts)*s.timeout+s.retry.MaxBackoff)
+	defer cancel()
+
+	attempts, err := notify.SendWithRetry(ctx, notifier, target, msg, s.retry)
+	delivery.Attempts = attempts
+	return err
+}
+
+// applyChannelParams 将参数写入渠道并校验
+func (s *notificationService) applyChannelParams(channel *model.NotificationChannel, params *NotificationChannelParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("渠道名称不能为空")
+		}
+		channel.Name = name
+	}
+	if params.Type != nil {
+		if _, ok := s.notifiers[*params.Type]; !ok {
+			return errors.New("渠道类型必须为 webhook、email、dingtalk、wecom 或 feishu")
+		}
+		channel.Type = *params.Type
+	}
+	if params.URL != nil {
+		channel.URL = strings.TrimSpace(*params.URL)
+	}
+	if params.Secret != nil {
+		channel.Secret = strings.TrimSpace(*params.Secret)
+	}
+	if params.Emails != nil {
+		channel.Emails = trimStrings(params.Emails)
+	}
+	if params.AtMobiles != nil {
+		channel.AtMobiles = trimStrings(params.AtMobiles)
+	}
+	if params.AtAll != nil {
+		channel.AtAll = *params.AtAll
+	}
+	if params.Template != nil {
+		if err := notify.ParseTemplate(*params.Template); err != nil {
+			return fmt.Errorf("消息模板格式错误: %v", err)
+		}
+		channel.Template = *params.Template
+	}
+	if params.Status != nil {
+		if *params.Status != 1 && *params.Status != 2 {
+			return errors.New("状态必须为1或2")
+		}
+		channel.Status = *params.Status
+	}
+
+	if channel.Type == notify.TypeEmail {
+		if len(channel.Emails) == 0 {
+			return errors.New("邮件渠道至少需要一个收件人")
+		}
+	} else if !strings.HasPrefix(channel.URL, "http://") && !strings.HasPrefix(channel.URL, "https://") {
+		return errors.New("请填写有效的 Webhook 地址")
+	}
+	return nil
+}
+
+// alertMessage 构造告警通知消息
+func alertMessage(rule *model.AlertRule, event *model.AlertEvent) notify.Message {
+	content := fmt.Sprintf("规则：%s\n指标值：%s（阈值 %s）\n相关舆情：%d 条",
+		rule.Name, formatAlertNumber(event.Value), formatAlertNumber(event.Threshold), len(event.OpinionIDs))
+
+	return notify.Message{
+		Title:    event.Title,
+		Content:  content,
+		Severity: event.Severity,
+		Status:   event.Status,
+		Time:     event.FiredAt,
+		Data: map[string]interface{}{
+			"event_id":    event.ID,
+			"rule_id":     rule.ID,
+			"rule_name":   rule.Name,
+			"rule_type":   rule.Type,
+			"scenario_id": event.ScenarioID,
+			"group_id":    event.GroupID,
+			"dedup_key":   event.DedupKey,
+			"value":       event.Value,
+			"threshold":   event.Threshold,
+			"opinion_ids": event.OpinionIDs,
+		},
+	}
+}
+
+// trimStrings 去掉空白项
+func trimStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
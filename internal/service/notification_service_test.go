@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/notify"
+	"sentinel-opinion-monitor/internal/notify/smtpstub"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// fakeChannelRepo 内存中的通知渠道仓库
+type fakeChannelRepo struct {
+	channels map[uint64]*model.NotificationChannel
+}
+
+func (r *fakeChannelRepo) Create(channel *model.NotificationChannel) error {
+	channel.ID = uint64(len(r.channels) + 1)
+	r.channels[channel.ID] = channel
+	return nil
+}
+
+func (r *fakeChannelRepo) GetByID(id uint64) (*model.NotificationChannel, error) {
+	if channel, ok := r.channels[id]; ok {
+		return channel, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *fakeChannelRepo) GetByIDs(ids []uint64) ([]*model.NotificationChannel, error) {
+	var channels []*model.NotificationChannel
+	for _, id := range ids {
+		if channel, ok := r.channels[id]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+func (r *fakeChannelRepo) GetAll() ([]*model.NotificationChannel, error) {
+	channels := make([]*model.NotificationChannel, 0, len(r.channels))
+	for _, channel := range r.channels {
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+func (r *fakeChannelRepo) Update(channel *model.NotificationChannel) error {
+	r.channels[channel.ID] = channel
+	return nil
+}
+
+func (r *fakeChannelRepo) Delete(id uint64) error {
+	delete(r.channels, id)
+	return nil
+}
+
+// fakeDeliveryRepo 记录写入的发送记录
+type fakeDeliveryRepo struct {
+	deliveries []*model.NotificationDelivery
+}
+
+func (r *fakeDeliveryRepo) Create(delivery *model.NotificationDelivery) error {
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) List(filter repository.DeliveryFilter, page, pageSize int) ([]*model.NotificationDelivery, int64, error) {
+	return r.deliveries, int64(len(r.deliveries)), nil
+}
+
+func newTestNotificationService(cfg *config.NotifyConfig, channels ...*model.NotificationChannel) (NotificationService, *fakeDeliveryRepo) {
+	channelRepo := &fakeChannelRepo{channels: map[uint64]*model.NotificationChannel{}}
+	for _, channel := range channels {
+		channelRepo.channels[channel.ID] = channel
+	}
+	deliveryRepo := &fakeDeliveryRepo{}
+	return NewNotificationService(cfg, channelRepo, deliveryRepo), deliveryRepo
+}
+
+func TestNotificationSendRecordsDeliveries(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ok := &model.NotificationChannel{ID: 1, Type: notify.TypeWebhook, URL: server.URL + "/ok", Status: 1}
+	broken := &model.NotificationChannel{ID: 2, Type: notify.TypeWebhook, URL: server.URL + "/broken", Status: 1}
+	disabled := &model.NotificationChannel{ID: 3, Type: notify.TypeWebhook, URL: server.URL + "/ok", Status: 0}
+	svc, repo := newTestNotificationService(&config.NotifyConfig{TimeoutMs: 1000, RetryAttempts: 3, RetryBackoffMs: 1}, ok, broken, disabled)
+
+	deliveries := svc.Send([]uint64{1, 2, 3}, notify.Message{Title: "告警"}, NotifySourceAlert, 42)
+	if len(deliveries) != 2 || len(repo.deliveries) != 2 {
+		t.Fatalf("deliveries = %d, recorded = %d; want 2 (disabled channel skipped)", len(deliveries), len(repo.deliveries))
+	}
+
+	success, failed := repo.deliveries[0], repo.deliveries[1]
+	if success.ChannelID != 1 || success.Status != model.DeliveryStatusSuccess || success.Attempts != 1 || success.Error != "" {
+		t.Errorf("success delivery = %+v", success)
+	}
+	if failed.ChannelID != 2 || failed.Status != model.DeliveryStatusFailed || failed.Attempts != 3 || failed.Error == "" {
+		t.Errorf("failed delivery = %+v, want 3 attempts with error", failed)
+	}
+	for _, d := range repo.deliveries {
+		if d.Source != NotifySourceAlert || d.SourceID != 42 || d.Title != "告警" || d.ChannelType != notify.TypeWebhook {
+			t.Errorf("delivery metadata = %+v", d)
+		}
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("requests = %d, want 1 + 3 retries", got)
+	}
+}
+
+func TestNotificationPermanentFailureIsNotRetried(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+	}))
+	defer server.Close()
+
+	channel := &model.NotificationChannel{ID: 1, Name: "钉钉", Type: notify.TypeDingTalk, URL: server.URL, Secret: "SEC", Status: 1}
+	svc, repo := newTestNotificationService(&config.NotifyConfig{RetryAttempts: 3, RetryBackoffMs: 1}, channel)
+
+	delivery, err := svc.TestChannel(1)
+	if err != nil {
+		t.Fatalf("TestChannel() error = %v", err)
+	}
+	if delivery.Status != model.DeliveryStatusFailed || delivery.Attempts != 1 || !strings.Contains(delivery.Error, "310000") {
+		t.Errorf("delivery = %+v, want one failed attempt with robot error", delivery)
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].Source != NotifySourceTest {
+		t.Errorf("recorded = %+v, want one test delivery", repo.deliveries)
+	}
+}
+
+func TestNotificationSendEmailRetriesThroughSMTP(t *testing.T) {
+	server, err := smtpstub.NewServer(smtpstub.Options{FailTimes: 1})
+	if err != nil {
+		t.Fatalf("start smtpstub: %v", err)
+	}
+	defer server.Close()
+
+	svc, repo := newTestNotificationService(&config.NotifyConfig{
+		TimeoutMs:      2000,
+		RetryAttempts:  3,
+		RetryBackoffMs: 1,
+		SMTP:           config.SMTPConfig{Host: "127.0.0.1", Port: server.Addr().Port, From: "sentinel@example.com"},
+	})
+
+	delivery := svc.SendEmail([]string{"duty@example.com"}, notify.Message{Title: "值班提醒"}, NotifySourceAlert, 7)
+	if delivery.Status != model.DeliveryStatusSuccess || delivery.Attempts != 2 {
+		t.Errorf("delivery = %+v, want success on the second attempt", delivery)
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].ChannelID != 0 || repo.deliveries[0].ChannelType != notify.TypeEmail {
+		t.Errorf("recorded = %+v, want one email delivery with channel 0", repo.deliveries)
+	}
+	if mails := server.Mails(); len(mails) != 1 || mails[0].To[0] != "duty@example.com" {
+		t.Errorf("mails = %+v", mails)
+	}
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+const (
+	// MaxEscalationTiers 升级策略最多层级数
+	MaxEscalationTiers = 10
+	// MaxEscalationDelay 升级层级最大延迟（分钟），即 7 天
+	MaxEscalationDelay = 7 * 24 * 60
+	// DefaultRotationHours 值班表默认轮换周期（小时），即每周交接
+	DefaultRotationHours = 168
+	// MaxRotationHours 值班表最大轮换周期（小时）
+	MaxRotationHours = 720
+)
+
+// EscalationPolicyParams 升级策略参数，字段为 nil 表示不设置/不修改
+type EscalationPolicyParams struct {
+	Name        *string
+	Description *string
+	Tiers       []model.EscalationTier
+	Status      *int
+}
+
+// OnCallScheduleParams 值班表参数，字段为 nil 表示不设置/不修改
+type OnCallScheduleParams struct {
+	Name          *string
+	UserIDs       []uint64
+	RotationHours *int
+	HandoffAt     *time.Time
+	Status        *int
+}
+
+// OnCallService 值班和升级策略服务接口
+type OnCallService interface {
+	CreatePolicy(params *EscalationPolicyParams) (*model.EscalationPolicy, error)
+	GetPolicy(id uint64) (*model.EscalationPolicy, error)
+	ListPolicies() ([]*model.EscalationPolicy, error)
+	UpdatePolicy(id uint64, params *EscalationPolicyParams) (*model.EscalationPolicy, error)
+	DeletePolicy(id uint64) error
+
+	CreateSchedule(params *OnCallScheduleParams) (*model.OnCallSchedule, error)
+	GetSchedule(id uint64) (*model.OnCallSchedule, error)
+	ListSchedules() ([]*model.OnCallSchedule, error)
+	UpdateSchedule(id uint64, params *OnCallScheduleParams) (*model.OnCallSchedule, error)
+	DeleteSchedule(id uint64) error
+	CreateOverride(scheduleID, userID uint64, start, end time.Time) (*model.OnCallOverride, error)
+	ListOverrides(scheduleID uint64) ([]*model.OnCallOverride, error)
+	DeleteOverride(scheduleID, overrideID uint64) error
+	CurrentOnCall(scheduleID uint64, at time.Time) (*model.User, error)
+	TierUsers(tier model.EscalationTier, at time.Time) ([]*model.User, error)
+}
+
+type onCallService struct {
+	policyRepo   repository.EscalationPolicyRepository
+	scheduleRepo repository.OnCallScheduleRepository
+	userRepo     repository.UserRepository
+	channelRepo  repository.NotificationChannelRepository
+}
+
+// NewOnCallService 创建值班和升级策略服务实例
+func NewOnCallService(
+	policyRepo repository.EscalationPolicyRepository,
+	scheduleRepo repository.OnCallScheduleRepository,
+	userRepo repository.UserRepository,
+	channelRepo repository.NotificationChannelRepository,
+) OnCallService {
+	return &onCallService{
+		policyRepo:   policyRepo,
+		scheduleRepo: scheduleRepo,
+		userRepo:     userRepo,
+		channelRepo:  channelRepo,
+	}
+}
+
+// CreatePolicy 创建升级策略
+func (s *onCallService) CreatePolicy(params *EscalationPolicyParams) (*model.EscalationPolicy, error) {
+	if params == nil || params.Name == nil || params.Tiers == nil {
+		return nil, errors.New("策略名称和升级层级不能为空")
+	}
+
+	policy := &model.EscalationPolicy{Status: 1} // 正常状态
+	if err := s.applyPolicyParams(policy, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Create(policy); err != nil {
+		return nil, errors.New("创建升级策略失败")
+	}
+	return policy, nil
+}
+
+// GetPolicy 根据 ID 获取升级策略
+func (s *onCallService) GetPolicy(id uint64) (*model.EscalationPolicy, error) {
+	return s.policyRepo.GetByID(id)
+}
+
+// ListPolicies 获取所有升级策略
+func (s *onCallService) ListPolicies() ([]*model.EscalationPolicy, error) {
+	return s.policyRepo.GetAll()
+}
+
+// UpdatePolicy 更新升级策略
+func (s *onCallService) UpdatePolicy(id uint64, params *EscalationPolicyParams) (*model.EscalationPolicy, error) {
+	policy, err := s.policyRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("升级策略不存在")
+	}
+
+	if err := s.applyPolicyParams(policy, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Update(policy); err != nil {
+		return nil, errors.New("更新升级策略失败")
+	}
+	return policy, nil
+}
+
+// DeletePolicy 删除升级策略，仍被告警规则引用时不允许删除
+func (s *onCallService) DeletePolicy(id uint64) error {
+	if _, err := s.policyRepo.GetByID(id); err != nil {
+		return errors.New("升级策略不存在")
+	}
+
+	count, err := s.policyRepo.CountRules(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("仍有%d条告警规则使用该策略，无法删除", count)
+	}
+	return s.policyRepo.Delete(id)
+}
+
+// CreateSchedule 创建值班表
+func (s *onCallService) CreateSchedule(params *OnCallScheduleParams) (*model.OnCallSchedule, error) {
+	if params == nil || params.Name == nil || params.UserIDs == nil {
+		return nil, errors.New("值班表名称和值班用户不能为空")
+	}
+
+	schedule := &model.OnCallSchedule{
+		RotationHours: DefaultRotationHours,
+		HandoffAt:     time.Now(),
+		Status:        1, // 正常状态
+	}
+	if err := s.applyScheduleParams(schedule, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, errors.New("创建值班表失败")
+	}
+	return schedule, nil
+}
+
+// GetSchedule 根据 ID 获取值班表
+func (s *onCallService) GetSchedule(id uint64) (*model.OnCallSchedule, error) {
+	return s.scheduleRepo.GetByID(id)
+}
+
+// ListSchedules 获取所有值班表
+func (s *onCallService) ListSchedules() ([]*model.OnCallSchedule, error) {
+	return s.scheduleRepo.GetAll()
+}
+
+// UpdateSchedule 更新值班表
+func (s *onCallService) UpdateSchedule(id uint64, params *OnCallScheduleParams) (*model.OnCallSchedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("值班表不存在")
+	}
+
+	if err := s.applyScheduleParams(schedule, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return nil, errors.New("更新值班表失败")
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule 删除值班表，仍被升级策略引用时不允许删除
+func (s *onCallService) DeleteSchedule(id uint64) error {
+	if _, err := s.scheduleRepo.GetByID(id); err != nil {
+		return errors.New("值班表不存在")
+	}
+
+	policies, err := s.policyRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		for _, tier := range policy.Tiers {
+			for _, scheduleID := range tier.ScheduleIDs {
+				if scheduleID == id {
+					return fmt.Errorf("升级策略「%s」仍在使用该值班表，无法删除", policy.Name)
+				}
+			}
+		}
+	}
+	return s.scheduleRepo.Delete(id)
+}
+
+// CreateOverride 为值班表创建临时替班
+func (s *onCallService) CreateOverride(scheduleID, userID uint64, start, end time.Time) (*model.OnCallOverride, error) {
+	if _, err := s.scheduleRepo.GetByID(scheduleID); err != nil {
+		return nil, errors.New("值班表不存在")
+	}
+	if !end.After(start) {
+		return nil, errors.New("结束时间必须晚于开始时间")
+	}
+	if err := s.validateUsers([]uint64{userID}); err != nil {
+		return nil, err
+	}
+
+	override := &model.OnCallOverride{
+		ScheduleID: scheduleID,
+		UserID:     userID,
+		StartAt:    start,
+		EndAt:      end,
+	}
+	if err := s.scheduleRepo.CreateOverride(override); err != nil {
+		return nil, errors.New("创建替班失败")
+	}
+	return override, nil
+}
+
+// ListOverrides 获取值班表当前和未来的替班
+func (s *onCallService) ListOverrides(scheduleID uint64) ([]*model.OnCallOverride, error) {
+	return s.scheduleRepo.GetOverrides(scheduleID, time.Now())
+}
+
+// DeleteOverride 删除替班
+func (s *onCallService) DeleteOverride(scheduleID, overrideID uint64) error {
+	override, err := s.scheduleRepo.GetOverrideByID(overrideID)
+	if err != nil || override.ScheduleID != scheduleID {
+		return errors.New("替班不存在")
+	}
+	return s.scheduleRepo.DeleteOverride(overrideID)
+}
+
+// CurrentOnCall 获取值班表在 at 时刻的值班用户，替班优先于轮值
+func (s *onCallService) CurrentOnCall(scheduleID uint64, at time.Time) (*model.User, error) {
+	schedule, err := s.scheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return nil, errors.New("值班表不存在")
+	}
+	if schedule.Status != 1 {
+		return nil, errors.New("值班表已禁用")
+	}
+
+	userID := rotationUserID(schedule, at)
+	if override, err := s.scheduleRepo.GetActiveOverride(scheduleID, at); err == nil {
+		userID = override.UserID
+	}
+	if userID == 0 {
+		return nil, errors.New("值班表没有值班用户")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("值班用户不存在")
+	}
+	return user, nil
+}
+
+// TierUsers 获取升级层级在 at 时刻需要通知的用户（指定用户和各值班表的值班用户，去重，跳过禁用用户）
+func (s *onCallService) TierUsers(tier model.EscalationTier, at time.Time) ([]*model.User, error) {
+	users := make([]*model.User, 0, len(tier.UserIDs)+len(tier.ScheduleIDs))
+	seen := make(map[uint64]bool)
+	add := func(user *model.User) {
+		if user == nil || seen[user.ID] || user.Status != 1 {
+			return
+		}
+		seen[user.ID] = true
+		users = append(users, user)
+	}
+
+	for _, userID := range tier.UserIDs {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			continue
+		}
+		add(user)
+	}
+
+	var errs []string
+	for _, scheduleID := range tier.ScheduleIDs {
+		user, err := s.CurrentOnCall(scheduleID, at)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("值班表#%d: %s", scheduleID, err.Error()))
+			continue
+		}
+		add(user)
+	}
+
+	if len(users) == 0 && len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "；"))
+	}
+	return users, nil
+}
+
+// rotationUserID 按轮换周期计算 at 时刻轮到的用户，HandoffAt 之前按周期向前推算
+func rotationUserID(schedule *model.OnCallSchedule, at time.Time) uint64 {
+	n := len(schedule.UserIDs)
+	if n == 0 {
+		return 0
+	}
+	period := time.Duration(schedule.RotationHours) * time.Hour
+	if period <= 0 {
+		return schedule.UserIDs[0]
+	}
+
+	elapsed := at.Sub(schedule.HandoffAt)
+	shift := int64(elapsed / period)
+	if elapsed < 0 && elapsed%period != 0 {
+		shift--
+	}
+	index := int((shift%int64(n) + int64(n)) % int64(n))
+	return schedule.UserIDs[index]
+}
+
+// applyPolicyParams 将参数写入升级策略并校验
+func (s *onCallService) applyPolicyParams(policy *model.EscalationPolicy, params *EscalationPolicyParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("策略名称不能为空")
+		}
+		policy.Name = name
+	}
+	if params.Description != nil {
+		policy.Description = strings.TrimSpace(*params.Description)
+	}
+	if params.Tiers != nil {
+		if err := s.validateTiers(params.Tiers); err != nil {
+			return err
+		}
+		policy.Tiers = model.EscalationTiers(params.Tiers)
+	}
+	if params.Status != nil {
+		if *params.Status != 1 && *params.Status != 2 {
+			return errors.New("状态必须为1或2")
+		}
+		policy.Status = *params.Status
+	}
+	return nil
+}
+
+// validateTiers 校验升级层级：延迟递增，每级至少一个通知对象且对象存在
+func (s *onCallService) validateTiers(tiers []model.EscalationTier) error {
+	if len(tiers) == 0 || len(tiers) > MaxEscalationTiers {
+		return fmt.Errorf("升级层级数必须在1到%d之间", MaxEscalationTiers)
+	}
+
+	for i, tier := range tiers {
+		if tier.DelayMinutes < 0 || tier.DelayMinutes > MaxEscalationDelay {
+			return fmt.Errorf("第%d级的延迟必须在0到%d分钟之间", i+1, MaxEscalationDelay)
+		}
+		if i > 0 && tier.DelayMinutes <= tiers[i-1].DelayMinutes {
+			return fmt.Errorf("第%d级的延迟必须大于第%d级", i+1, i)
+		}
+		if len(tier.ChannelIDs) == 0 && len(tier.UserIDs) == 0 && len(tier.ScheduleIDs) == 0 {
+			return fmt.Errorf("第%d级至少需要一个通知渠道、用户或值班表", i+1)
+		}
+
+		if len(tier.ChannelIDs) > 0 {
+			channels, err := s.channelRepo.GetByIDs(tier.ChannelIDs)
+			if err != nil {
+				return err
+			}
+			if len(channels) != len(uniqueIDs(tier.ChannelIDs)) {
+				return fmt.Errorf("第%d级包含不存在的通知渠道", i+1)
+			}
+		}
+		if err := s.validateUsers(tier.UserIDs); err != nil {
+			return fmt.Errorf("第%d级%s", i+1, err.Error())
+		}
+		for _, scheduleID := range tier.ScheduleIDs {
+			if _, err := s.scheduleRepo.GetByID(scheduleID); err != nil {
+				return fmt.Errorf("第%d级包含不存在的值班表", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// applyScheduleParams 将参数写入值班表并校验
+func (s *onCallService) applyScheduleParams(schedule *model.OnCallSchedule, params *OnCallScheduleParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("值班表名称不能为空")
+		}
+		schedule.Name = name
+	}
+	if params.UserIDs != nil {
+		if len(params.UserIDs) == 0 {
+			return errors.New("值班用户不能为空")
+		}
+		if err := s.validateUsers(params.UserIDs); err != nil {
+			return err
+		}
+		schedule.UserIDs = model.IDList(params.UserIDs)
+	}
+	if params.RotationHours != nil {
+		if *params.RotationHours < 1 || *params.RotationHours > MaxRotationHours {
+			return fmt.Errorf("轮换周期必须在1到%d小时之间", MaxRotationHours)
+		}
+		schedule.RotationHours = *params.RotationHours
+	}
+	if params.HandoffAt != nil {
+		schedule.HandoffAt = *params.HandoffAt
+	}
+	if params.Status != nil {
+		if *params.Status != 1 && *params.Status != 2 {
+			return errors.New("状态必须为1或2")
+		}
+		schedule.Status = *params.Status
+	}
+	return nil
+}
+
+// validateUsers 校验用户存在
+func (s *onCallService) validateUsers(userIDs []uint64) error {
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			return fmt.Errorf("用户%d不存在", userID)
+		}
+	}
+	return nil
+}
+
+// uniqueIDs 去重，保持原顺序
+func uniqueIDs(ids []uint64) []uint64 {
+	result := make([]uint64, 0, len(ids))
+	seen := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
@@ -1,27 +1,39 @@
 package service
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+
 	"sentinel-opinion-monitor/internal/model"
 	"sentinel-opinion-monitor/internal/repository"
 )
 
+// OpinionFilter 舆情查询条件
+type OpinionFilter = repository.OpinionFilter
+
 // OpinionService 舆情业务逻辑接口
 type OpinionService interface {
 	GetOpinionByID(id uint64) (*model.Opinion, error)
-	GetAllOpinions() ([]*model.Opinion, error)
+	GetAllOpinions(filter OpinionFilter) ([]*model.Opinion, error)
+	ListOpinions(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error)
 	CreateOpinion(opinion *model.Opinion) error
 	UpdateOpinion(opinion *model.Opinion) error
 	DeleteOpinion(id uint64) error
+	GetSentimentStats(filter OpinionFilter) ([]*repository.SentimentCount, error)
 }
 
 type opinionService struct {
-	repo repository.OpinionRepository
+	repo              repository.OpinionRepository
+	enrichmentService EnrichmentService
+	authorService     AuthorService
 }
 
 // NewOpinionService 创建舆情业务逻辑实例
-func NewOpinionService(repo repository.OpinionRepository) OpinionService {
+func NewOpinionService(repo repository.OpinionRepository, enrichmentService EnrichmentService, authorService AuthorService) OpinionService {
 	return &opinionService{
-		repo: repo,
+		repo:              repo,
+		enrichmentService: enrichmentService,
+		authorService:     authorService,
 	}
 }
 
@@ -30,18 +42,41 @@ func (s *opinionService) GetOpinionByID(id uint64) (*model.Opinion, error) {
 	return s.repo.GetByID(id)
 }
 
-// GetAllOpinions 获取所有舆情
-func (s *opinionService) GetAllOpinions() ([]*model.Opinion, error) {
-	return s.repo.GetAll()
+// GetAllOpinions 按条件获取所有舆情（不分页）
+func (s *opinionService) GetAllOpinions(filter OpinionFilter) ([]*model.Opinion, error) {
+	return s.repo.GetByFilter(filter)
+}
+
+// ListOpinions 按条件分页获取舆情
+func (s *opinionService) ListOpinions(filter OpinionFilter, page, pageSize int) ([]*model.Opinion, int64, error) {
+	return s.repo.List(filter, page, pageSize)
 }
 
-// CreateOpinion 创建舆情
+// CreateOpinion 创建舆情，入库前进行富化（情感、实体、主题）并关联作者
+// 处置字段只能通过处置流程修改，创建时重置为待处理
 func (s *opinionService) CreateOpinion(opinion *model.Opinion) error {
+	opinion.HandlingStatus = model.HandlingStatusNew
+	opinion.AssigneeID = 0
+	opinion.DueAt = nil
+	opinion.HandledAt = nil
+	if !IsValidPriority(opinion.Priority) {
+		opinion.Priority = model.PriorityMedium
+	}
+	opinion.ContentHash = opinionContentHash(opinion.Source, opinion.Content)
+
+	opinions := []*model.Opinion{opinion}
+	if err := s.enrichmentService.EnrichOpinions(opinions); err != nil {
+		return err
+	}
+	if err := s.authorService.Resolve(opinions); err != nil {
+		return err
+	}
 	return s.repo.Create(opinion)
 }
 
 // UpdateOpinion 更新舆情
 func (s *opinionService) UpdateOpinion(opinion *model.Opinion) error {
+	opinion.ContentHash = opinionContentHash(opinion.Source, opinion.Content)
 	return s.repo.Update(opinion)
 }
 
@@ -50,3 +85,13 @@ func (s *opinionService) DeleteOpinion(id uint64) error {
 	return s.repo.Delete(id)
 }
 
+// GetSentimentStats 按情感标签聚合舆情
+func (s *opinionService) GetSentimentStats(filter OpinionFilter) ([]*repository.SentimentCount, error) {
+	return s.repo.CountBySentiment(filter)
+}
+
+// opinionContentHash 计算来源和内容的 SHA1，与 MySQL 的 SHA1(CONCAT(source, '\n', content)) 结果一致
+func opinionContentHash(source, content string) string {
+	sum := sha1.Sum([]byte(source + "\n" + content))
+	return hex.EncodeToString(sum[:])
+}
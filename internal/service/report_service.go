@@ -0,0 +1,663 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/notify"
+	"sentinel-opinion-monitor/internal/report"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// maxReportTopN 简报中负面舆情、话题和关键词最多列出的数量
+const maxReportTopN = 50
+
+// ReportTemplateParams 简报模板参数，字段为 nil 表示不设置/不修改
+type ReportTemplateParams struct {
+	ScenarioID *uint64
+	Name       *string
+	Frequency  *string
+	Sections   []string
+	Formats    []string
+	TopN       *int
+	Emails     []string
+	ChannelIDs []uint64
+	Status     *int
+}
+
+// ReportService 场景简报服务接口
+type ReportService interface {
+	CreateTemplate(userID uint64, params *ReportTemplateParams) (*model.ReportTemplate, error)
+	GetTemplate(id uint64) (*model.ReportTemplate, error)
+	ListTemplates(scenarioID uint64) ([]*model.ReportTemplate, error)
+	UpdateTemplate(id uint64, params *ReportTemplateParams) (*model.ReportTemplate, error)
+	DeleteTemplate(id uint64) error
+	Run(templateID uint64, start, end time.Time, deliver bool) (*model.Report, error)
+	RunDue(now time.Time) (int, error)
+	ListReports(filter repository.ReportFilter, page, pageSize int) ([]*model.Report, int64, error)
+	GetReport(id uint64) (*model.Report, error)
+	ReportFile(id uint64, format string) (string, string, error)
+}
+
+type reportService struct {
+	cfg                 config.ReportConfig
+	templateRepo        repository.ReportTemplateRepository
+	reportRepo          repository.ReportRepository
+	scenarioRepo        repository.ScenarioRepository
+	opinionRepo         repository.OpinionRepository
+	statsService        StatsService
+	trendingService     TrendingService
+	notificationService NotificationService
+}
+
+// NewReportService 创建场景简报服务实例
+func NewReportService(
+	cfg *config.ReportConfig,
+	templateRepo repository.ReportTemplateRepository,
+	reportRepo repository.ReportRepository,
+	scenarioRepo repository.ScenarioRepository,
+	opinionRepo repository.OpinionRepository,
+	statsService StatsService,
+	trendingService TrendingService,
+	notificationService NotificationService,
+) ReportService {
+	s := &reportService{
+		templateRepo:        templateRepo,
+		reportRepo:          reportRepo,
+		scenarioRepo:        scenarioRepo,
+		opinionRepo:         opinionRepo,
+		statsService:        statsService,
+		trendingService:     trendingService,
+		notificationService: notificationService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.StorageDir == "" {
+		s.cfg.StorageDir = "data/reports"
+	}
+	s.cfg.BaseURL = strings.TrimRight(s.cfg.BaseURL, "/")
+	if s.cfg.MaxAttachmentMB <= 0 {
+		s.cfg.MaxAttachmentMB = 10
+	}
+	return s
+}
+
+// CreateTemplate 创建简报模板，未指定章节和格式时包含全部章节、同时生成 XLSX 和 PDF
+func (s *reportService) CreateTemplate(userID uint64, params *ReportTemplateParams) (*model.ReportTemplate, error) {
+	if params == nil || params.ScenarioID == nil || params.Name == nil || params.Frequency == nil {
+		return nil, errors.New("场景、模板名称和周期不能为空")
+	}
+
+	tmpl := &model.ReportTemplate{
+		Sections:  model.StringList(report.AllSections),
+		Formats:   model.StringList{model.ReportFormatXLSX, model.ReportFormatPDF},
+		TopN:      10,
+		Status:    1,
+		CreatedBy: userID,
+	}
+	if err := s.applyParams(tmpl, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(tmpl); err != nil {
+		return nil, errors.New("创建简报模板失败")
+	}
+	return tmpl, nil
+}
+
+// GetTemplate 根据 ID 获取简报模板
+func (s *reportService) GetTemplate(id uint64) (*model.ReportTemplate, error) {
+	return s.templateRepo.GetByID(id)
+}
+
+// ListTemplates 获取简报模板，scenarioID 为 0 时返回全部
+func (s *reportService) ListTemplates(scenarioID uint64) ([]*model.ReportTemplate, error) {
+	return s.templateRepo.GetAll(scenarioID)
+}
+
+// UpdateTemplate 更新简报模板，修改周期后从下一个完整周期开始自动生成
+func (s *reportService) UpdateTemplate(id uint64, params *ReportTemplateParams) (*model.ReportTemplate, error) {
+	tmpl, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("简报模板不存在")
+	}
+
+	frequency := tmpl.Frequency
+	if err := s.applyParams(tmpl, params); err != nil {
+		return nil, err
+	}
+	if tmpl.Frequency != frequency {
+		tmpl.LastPeriodEnd = nil
+	}
+
+	if err := s.templateRepo.Update(tmpl); err != nil {
+		return nil, errors.New("更新简报模板失败")
+	}
+	return tmpl, nil
+}
+
+// DeleteTemplate 删除简报模板，已生成的简报保留
+func (s *reportService) DeleteTemplate(id uint64) error {
+	if _, err := s.templateRepo.GetByID(id); err != nil {
+		return errors.New("简报模板不存在")
+	}
+	return s.templateRepo.Delete(id)
+}
+
+// Run 按模板立即生成一份简报，start 和 end 为零值时使用模板周期的最近一个完整周期；
+// deliver 为 true 时生成成功后发送给模板配置的收件人和通知渠道
+func (s *reportService) Run(templateID uint64, start, end time.Time, deliver bool) (*model.Report, error) {
+	tmpl, err := s.templateRepo.GetByID(templateID)
+	if err != nil {
+		return nil, errors.New("简报模板不存在")
+	}
+
+	frequency := tmpl.Frequency
+	if start.IsZero() && end.IsZero() {
+		start, end = ReportPeriod(tmpl.Frequency, time.Now())
+	} else {
+		if end.IsZero() {
+			end = time.Now()
+		}
+		if start.IsZero() {
+			periodStart, periodEnd := ReportPeriod(tmpl.Frequency, end)
+			start = end.Add(-periodEnd.Sub(periodStart))
+		}
+		if !start.Before(end) {
+			return nil, errors.New("开始时间必须早于结束时间")
+		}
+		if end.Sub(start) > 366*24*time.Hour {
+			return nil, errors.New("简报的时间范围不能超过 366 天")
+		}
+		frequency = ""
+	}
+
+	rpt, err := s.generate(tmpl, frequency, start, end)
+	if err != nil {
+		return rpt, err
+	}
+	if deliver {
+		s.deliver(tmpl, rpt)
+	}
+	return rpt, nil
+}
+
+// RunDue 为所有启用的模板生成最近一个尚未生成的完整周期的简报并发送，返回生成成功的份数；
+// 生成失败的周期在下次执行时重试
+func (s *reportService) RunDue(now time.Time) (int, error) {
+	templates, err := s.templateRepo.GetByStatus(1)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	var errs []string
+	for _, tmpl := range templates {
+		start, end := ReportPeriod(tmpl.Frequency, now)
+		if tmpl.LastPeriodEnd != nil && !tmpl.LastPeriodEnd.Before(end) {
+			continue
+		}
+
+		rpt, err := s.generate(tmpl, tmpl.Frequency, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("模板%d: %v", tmpl.ID, err))
+			continue
+		}
+		s.deliver(tmpl, rpt)
+
+		tmpl.LastPeriodEnd = &end
+		if err := s.templateRepo.Update(tmpl); err != nil {
+			errs = append(errs, fmt.Sprintf("模板%d: %v", tmpl.ID, err))
+		}
+		generated++
+	}
+	if len(errs) > 0 {
+		return generated, errors.New(strings.Join(errs, "; "))
+	}
+	return generated, nil
+}
+
+// ListReports 按条件分页获取已生成的简报
+func (s *reportService) ListReports(filter repository.ReportFilter, page, pageSize int) ([]*model.Report, int64, error) {
+	reports, total, err := s.reportRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, rpt := range reports {
+		fillReportFormats(rpt)
+	}
+	return reports, total, nil
+}
+
+// GetReport 根据 ID 获取简报
+func (s *reportService) GetReport(id uint64) (*model.Report, error) {
+	rpt, err := s.reportRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	fillReportFormats(rpt)
+	return rpt, nil
+}
+
+// ReportFile 返回简报文件的本地路径和下载文件名
+func (s *reportService) ReportFile(id uint64, format string) (string, string, error) {
+	rpt, err := s.reportRepo.GetByID(id)
+	if err != nil {
+		return "", "", errors.New("简报不存在")
+	}
+	if rpt.Status != model.ReportStatusSuccess {
+		return "", "", errors.New("简报尚未生成成功")
+	}
+
+	var file string
+	switch format {
+	case model.ReportFormatXLSX:
+		file = rpt.XLSXFile
+	case model.ReportFormatPDF:
+		file = rpt.PDFFile
+	default:
+		return "", "", errors.New("无效的文件格式，可选值: xlsx, pdf")
+	}
+	if file == "" {
+		return "", "", fmt.Errorf("简报没有生成 %s 文件", format)
+	}
+
+	path := filepath.Join(s.cfg.StorageDir, filepath.FromSlash(file))
+	if _, err := os.Stat(path); err != nil {
+		return "", "", errors.New("简报文件不存在")
+	}
+	return path, reportFilename(rpt, format), nil
+}
+
+// generate 汇总 [start, end) 的数据，按模板渲染并保存简报文件；frequency 为空表示自定义时间范围
+func (s *reportService) generate(tmpl *model.ReportTemplate, frequency string, start, end time.Time) (*model.Report, error) {
+	scenario, err := s.scenarioRepo.GetByID(tmpl.ScenarioID)
+	if err != nil {
+		return nil, fmt.Errorf("场景%d不存在", tmpl.ScenarioID)
+	}
+
+	rpt := &model.Report{
+		TemplateID:  tmpl.ID,
+		ScenarioID:  tmpl.ScenarioID,
+		Title:       reportTitle(scenario.Name, frequency, start, end),
+		Frequency:   frequency,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      model.ReportStatusGenerating,
+	}
+	if err := s.reportRepo.Create(rpt); err != nil {
+		return nil, errors.New("创建简报记录失败")
+	}
+
+	if err := s.render(tmpl, scenario, rpt); err != nil {
+		rpt.Status = model.ReportStatusFailed
+		rpt.Error = truncateRunes(err.Error(), 1000)
+		if updateErr := s.reportRepo.Update(rpt); updateErr != nil {
+			return rpt, updateErr
+		}
+		return rpt, err
+	}
+
+	now := time.Now()
+	rpt.Status = model.ReportStatusSuccess
+	rpt.GeneratedAt = &now
+	if err := s.reportRepo.Update(rpt); err != nil {
+		return rpt, errors.New("更新简报记录失败")
+	}
+	fillReportFormats(rpt)
+	return rpt, nil
+}
+
+// render 收集简报数据并写入各格式的文件
+func (s *reportService) render(tmpl *model.ReportTemplate, scenario *model.Scenario, rpt *model.Report) error {
+	data, err := s.collect(tmpl, scenario, rpt)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.cfg.StorageDir, fmt.Sprint(rpt.ScenarioID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建简报目录失败: %w", err)
+	}
+	for _, format := range tmpl.Formats {
+		var (
+			content []byte
+			err     error
+		)
+		switch format {
+		case model.ReportFormatXLSX:
+			content, err = report.RenderXLSX(data)
+		case model.ReportFormatPDF:
+			content, err = report.RenderPDF(data)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("生成 %s 文件失败: %w", format, err)
+		}
+
+		name := fmt.Sprintf("%d.%s", rpt.ID, format)
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return fmt.Errorf("保存 %s 文件失败: %w", format, err)
+		}
+		file := fmt.Sprintf("%d/%s", rpt.ScenarioID, name)
+		if format == model.ReportFormatXLSX {
+			rpt.XLSXFile, rpt.XLSXSize = file, int64(len(content))
+		} else {
+			rpt.PDFFile, rpt.PDFSize = file, int64(len(content))
+		}
+	}
+	return nil
+}
+
+// collect 从统计汇总、舆情和话题中收集模板所需章节的数据
+func (s *reportService) collect(tmpl *model.ReportTemplate, scenario *model.Scenario, rpt *model.Report) (*report.Data, error) {
+	start, end := rpt.PeriodStart, rpt.PeriodEnd
+	data := &report.Data{
+		Title:        rpt.Title,
+		ScenarioName: scenario.Name,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		GeneratedAt:  time.Now(),
+		Sections:     tmpl.Sections,
+		TrendDaily:   end.Sub(start) > 2*24*time.Hour,
+	}
+
+	if data.Has(report.SectionSummary) || data.Has(report.SectionTrend) || data.Has(report.SectionChannels) {
+		interval := StatsIntervalHour
+		if data.TrendDaily {
+			interval = StatsIntervalDay
+		}
+		stats, err := s.statsService.GetScenarioStats(scenario.ID, StatsQuery{Start: start, End: end, Interval: interval, Top: tmpl.TopN})
+		if err != nil {
+			return nil, err
+		}
+
+		data.Summary.Total = stats.Total
+		for _, item := range stats.BySentiment {
+			switch sentiment.Label(item.Key) {
+			case sentiment.LabelPositive:
+				data.Summary.Positive = item.Count
+			case sentiment.LabelNeutral:
+				data.Summary.Neutral = item.Count
+			case sentiment.LabelNegative:
+				data.Summary.Negative = item.Count
+			}
+		}
+		if stats.Comparison != nil && stats.Comparison.Total != nil {
+			data.Summary.PreviousTotal = stats.Comparison.Total.Previous
+			data.Summary.ChangeRate = stats.Comparison.Total.ChangeRate
+		}
+		data.Summary.TopKeywords = reportItems(stats.TopKeywords)
+		data.Channels = reportItems(stats.ByChannel)
+		data.Trend = make([]report.Point, len(stats.Series))
+		for i, p := range stats.Series {
+			data.Trend[i] = report.Point{Time: p.Time, Count: p.Count}
+		}
+	}
+
+	if data.Has(report.SectionNegative) {
+		opinions, err := s.opinionRepo.TopByEngagement(repository.OpinionFilter{
+			ScenarioID: scenario.ID,
+			Sentiment:  string(sentiment.LabelNegative),
+			StartTime:  &start,
+			EndTime:    &end,
+		}, tmpl.TopN)
+		if err != nil {
+			return nil, errors.New("获取负面舆情失败")
+		}
+		for _, o := range opinions {
+			data.Negatives = append(data.Negatives, report.Opinion{
+				Time:       o.CreatedAt,
+				Source:     o.Source,
+				Author:     o.Author,
+				Content:    o.Content,
+				Score:      o.SentimentScore,
+				Engagement: o.LikeCount + o.CommentCount + o.ShareCount,
+			})
+		}
+	}
+
+	if data.Has(report.SectionTopics) {
+		// 话题只保留最近一次聚类的结果，与统计周期有交集时才列出
+		topics, err := s.trendingService.GetTopics(scenario.ID, tmpl.TopN)
+		if err != nil {
+			return nil, errors.New("获取热门话题失败")
+		}
+		for _, t := range topics {
+			if !t.WindowStart.Before(end) || !t.WindowEnd.After(start) {
+				continue
+			}
+			keywords := make([]string, 0, len(t.Keywords))
+			for _, k := range t.Keywords {
+				keywords = append(keywords, k.Word)
+			}
+			data.Topics = append(data.Topics, report.Topic{
+				Label:        t.Label,
+				Size:         t.Size,
+				AvgSentiment: t.AvgSentiment,
+				Keywords:     keywords,
+			})
+		}
+	}
+	return data, nil
+}
+
+// deliver 把简报发送给模板配置的收件人（附带简报文件）和通知渠道（附带下载链接），发送结果记入发送记录
+func (s *reportService) deliver(tmpl *model.ReportTemplate, rpt *model.Report) {
+	if len(tmpl.Emails) == 0 && len(tmpl.ChannelIDs) == 0 {
+		return
+	}
+
+	msg := s.reportMessage(rpt)
+	if len(tmpl.ChannelIDs) > 0 {
+		s.notificationService.Send(tmpl.ChannelIDs, msg, NotifySourceReport, rpt.ID)
+	}
+	if len(tmpl.Emails) > 0 {
+		if rpt.XLSXSize+rpt.PDFSize <= int64(s.cfg.MaxAttachmentMB)*1024*1024 {
+			msg.Attachments = s.attachments(rpt)
+		}
+		s.notificationService.SendEmail(tmpl.Emails, msg, NotifySourceReport, rpt.ID)
+	}
+}
+
+// reportMessage 简报通知消息
+func (s *reportService) reportMessage(rpt *model.Report) notify.Message {
+	content := fmt.Sprintf("统计周期：%s ~ %s\n格式：%s",
+		rpt.PeriodStart.Format("2006-01-02 15:04"), rpt.PeriodEnd.Format("2006-01-02 15:04"), strings.Join(rpt.Formats, ", "))
+
+	msg := notify.Message{
+		Title:   rpt.Title,
+		Content: content,
+		Time:    time.Now(),
+		Data: map[string]interface{}{
+			"report_id":    rpt.ID,
+			"template_id":  rpt.TemplateID,
+			"scenario_id":  rpt.ScenarioID,
+			"period_start": rpt.PeriodStart,
+			"period_end":   rpt.PeriodEnd,
+			"formats":      rpt.Formats,
+		},
+	}
+	// 下载链接优先指向 PDF
+	if s.cfg.BaseURL != "" && len(rpt.Formats) > 0 {
+		format := model.ReportFormatPDF
+		if rpt.PDFFile == "" {
+			format = model.ReportFormatXLSX
+		}
+		msg.Link = fmt.Sprintf("%s/api/v1/reports/%d/download?format=%s", s.cfg.BaseURL, rpt.ID, format)
+	}
+	return msg
+}
+
+// attachments 读取简报文件作为邮件附件，读取失败的文件跳过
+func (s *reportService) attachments(rpt *model.Report) []notify.Attachment {
+	files := []struct {
+		format      string
+		file        string
+		contentType string
+	}{
+		{model.ReportFormatPDF, rpt.PDFFile, "application/pdf"},
+		{model.ReportFormatXLSX, rpt.XLSXFile, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	}
+
+	var attachments []notify.Attachment
+	for _, f := range files {
+		if f.file == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.cfg.StorageDir, filepath.FromSlash(f.file)))
+		if err != nil {
+			continue
+		}
+		attachments = append(attachments, notify.Attachment{
+			Filename:    reportFilename(rpt, f.format),
+			ContentType: f.contentType,
+			Data:        content,
+		})
+	}
+	return attachments
+}
+
+// applyParams 校验参数并写入简报模板
+func (s *reportService) applyParams(tmpl *model.ReportTemplate, params *ReportTemplateParams) error {
+	if params == nil {
+		return nil
+	}
+	if params.ScenarioID != nil {
+		if _, err := s.scenarioRepo.GetByID(*params.ScenarioID); err != nil {
+			return errors.New("场景不存在")
+		}
+		tmpl.ScenarioID = *params.ScenarioID
+	}
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("模板名称不能为空")
+		}
+		tmpl.Name = name
+	}
+	if params.Frequency != nil {
+		switch *params.Frequency {
+		case model.ReportFrequencyDaily, model.ReportFrequencyWeekly, model.ReportFrequencyMonthly:
+			tmpl.Frequency = *params.Frequency
+		default:
+			return errors.New("无效的简报周期，可选值: daily, weekly, monthly")
+		}
+	}
+	if params.Sections != nil {
+		sections := trimStrings(params.Sections)
+		if len(sections) == 0 {
+			return errors.New("简报至少需要包含一个章节")
+		}
+		for _, section := range sections {
+			if !report.IsValidSection(section) {
+				return fmt.Errorf("无效的章节: %s，可选值: %s", section, strings.Join(report.AllSections, ", "))
+			}
+		}
+		tmpl.Sections = sections
+	}
+	if params.Formats != nil {
+		formats := trimStrings(params.Formats)
+		if len(formats) == 0 {
+			return errors.New("简报至少需要生成一种文件格式")
+		}
+		for _, format := range formats {
+			if format != model.ReportFormatXLSX && format != model.ReportFormatPDF {
+				return errors.New("无效的文件格式，可选值: xlsx, pdf")
+			}
+		}
+		tmpl.Formats = formats
+	}
+	if params.TopN != nil {
+		if *params.TopN < 1 || *params.TopN > maxReportTopN {
+			return fmt.Errorf("列出数量必须在 1~%d 之间", maxReportTopN)
+		}
+		tmpl.TopN = *params.TopN
+	}
+	if params.Emails != nil {
+		emails := trimStrings(params.Emails)
+		for _, email := range emails {
+			if _, err := mail.ParseAddress(email); err != nil {
+				return fmt.Errorf("无效的邮箱地址: %s", email)
+			}
+		}
+		tmpl.Emails = emails
+	}
+	if params.ChannelIDs != nil {
+		tmpl.ChannelIDs = uniqueIDs(params.ChannelIDs)
+	}
+	if params.Status != nil {
+		if *params.Status != 1 && *params.Status != 2 {
+			return errors.New("无效的状态")
+		}
+		tmpl.Status = *params.Status
+	}
+	return nil
+}
+
+// ReportPeriod 返回 now 之前最近一个完整周期 [start, end)：日报为前一天，周报为上周一至本周一，月报为上个月
+func ReportPeriod(frequency string, now time.Time) (time.Time, time.Time) {
+	now = now.In(time.Local)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch frequency {
+	case model.ReportFrequencyWeekly:
+		weekday := int(today.Weekday()+6) % 7 // 周一为 0
+		end := today.AddDate(0, 0, -weekday)
+		return end.AddDate(0, 0, -7), end
+	case model.ReportFrequencyMonthly:
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		return end.AddDate(0, -1, 0), end
+	default:
+		return today.AddDate(0, 0, -1), today
+	}
+}
+
+// reportTitle 简报标题，如「某品牌舆情日报（2024-01-15）」
+func reportTitle(scenarioName, frequency string, start, end time.Time) string {
+	switch frequency {
+	case model.ReportFrequencyDaily:
+		return fmt.Sprintf("%s舆情日报（%s）", scenarioName, start.Format("2006-01-02"))
+	case model.ReportFrequencyWeekly:
+		return fmt.Sprintf("%s舆情周报（%s ~ %s）", scenarioName, start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	case model.ReportFrequencyMonthly:
+		return fmt.Sprintf("%s舆情月报（%s）", scenarioName, start.Format("2006年01月"))
+	default:
+		return fmt.Sprintf("%s舆情简报（%s ~ %s）", scenarioName, start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+	}
+}
+
+// reportFilename 简报文件的下载文件名
+func reportFilename(rpt *model.Report, format string) string {
+	name := strings.NewReplacer("/", "-", "\\", "-", ":", "", "\"", "").Replace(rpt.Title)
+	return name + "." + format
+}
+
+// reportItems 把统计维度转换为简报中的分布项
+func reportItems(items []*StatsItem) []report.Item {
+	result := make([]report.Item, len(items))
+	for i, item := range items {
+		result[i] = report.Item{Name: item.Name, Count: item.Count, Ratio: item.Ratio}
+	}
+	return result
+}
+
+// fillReportFormats 根据已保存的文件填充可下载的格式
+func fillReportFormats(rpt *model.Report) {
+	rpt.Formats = make([]string, 0, 2)
+	if rpt.XLSXFile != "" {
+		rpt.Formats = append(rpt.Formats, model.ReportFormatXLSX)
+	}
+	if rpt.PDFFile != "" {
+		rpt.Formats = append(rpt.Formats, model.ReportFormatPDF)
+	}
+}
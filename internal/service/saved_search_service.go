@@ -0,0 +1,619 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/notify"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// savedSearchSnippetLength 只按筛选条件查询时摘要的长度（字符数）
+const savedSearchSnippetLength = 120
+
+// SavedSearchParams 保存的检索参数，字段为 nil 表示不设置/不修改
+type SavedSearchParams struct {
+	Name        *string
+	Query       *string
+	Mode        *string
+	Sort        *string
+	Filter      *OpinionFilter
+	RecentHours *int
+	Visibility  *string
+}
+
+// SubscriptionParams 订阅参数
+type SubscriptionParams struct {
+	Frequency  string
+	Channels   []string
+	ChannelIDs []uint64
+}
+
+// SavedSearchService 保存的检索和订阅服务接口
+type SavedSearchService interface {
+	Create(userID uint64, params *SavedSearchParams) (*model.SavedSearch, error)
+	Get(id, userID uint64) (*model.SavedSearch, error)
+	List(userID uint64, mineOnly bool) ([]*model.SavedSearch, error)
+	Update(id, userID uint64, isAdmin bool, params *SavedSearchParams) (*model.SavedSearch, error)
+	Delete(id, userID uint64, isAdmin bool) error
+	Run(id, userID uint64, page, pageSize int, facets bool) (*SearchResult, error)
+	Subscribe(id, userID uint64, isAdmin bool, params *SubscriptionParams) (*model.SavedSearchSubscription, error)
+	Unsubscribe(id, userID uint64) error
+	DeliverDue(now time.Time) (int, error)
+}
+
+type savedSearchService struct {
+	cfg                 config.SavedSearchConfig
+	savedSearchRepo     repository.SavedSearchRepository
+	opinionRepo         repository.OpinionRepository
+	userRepo            repository.UserRepository
+	searchService       SearchService
+	notificationService NotificationService
+	inboxService        InboxService
+}
+
+// NewSavedSearchService 创建保存的检索服务实例
+func NewSavedSearchService(
+	cfg *config.SavedSearchConfig,
+	savedSearchRepo repository.SavedSearchRepository,
+	opinionRepo repository.OpinionRepository,
+	userRepo repository.UserRepository,
+	searchService SearchService,
+	notificationService NotificationService,
+	inboxService InboxService,
+) SavedSearchService {
+	s := &savedSearchService{
+		savedSearchRepo:     savedSearchRepo,
+		opinionRepo:         opinionRepo,
+		userRepo:            userRepo,
+		searchService:       searchService,
+		notificationService: notificationService,
+		inboxService:        inboxService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.LookbackMinutes <= 0 {
+		s.cfg.LookbackMinutes = 60
+	}
+	if s.cfg.MaxMatches <= 0 {
+		s.cfg.MaxMatches = 200
+	}
+	if s.cfg.DigestItems <= 0 {
+		s.cfg.DigestItems = 10
+	}
+	if s.cfg.DailyHour < 0 || s.cfg.DailyHour > 23 {
+		s.cfg.DailyHour = 9
+	}
+	// 去重记录至少要覆盖每日汇总的检查范围（一天加回看时长）
+	if minHours := 24 + (s.cfg.LookbackMinutes+59)/60; s.cfg.RetentionHours < minHours {
+		s.cfg.RetentionHours = minHours
+	}
+	return s
+}
+
+// Create 创建保存的检索
+func (s *savedSearchService) Create(userID uint64, params *SavedSearchParams) (*model.SavedSearch, error) {
+	if params == nil || params.Name == nil {
+		return nil, errors.New("名称不能为空")
+	}
+
+	saved := &model.SavedSearch{Visibility: model.SavedSearchPrivate, Filter: "{}", CreatedBy: userID}
+	if err := s.applyParams(saved, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.savedSearchRepo.Create(saved); err != nil {
+		return nil, errors.New("保存检索失败")
+	}
+	s.fill(saved, nil)
+	return saved, nil
+}
+
+// Get 获取保存的检索，仅创建人可见的检索对其他用户视为不存在
+func (s *savedSearchService) Get(id, userID uint64) (*model.SavedSearch, error) {
+	saved, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || !savedSearchVisible(saved, userID) {
+		return nil, errors.New("保存的检索不存在")
+	}
+	sub, _ := s.savedSearchRepo.GetSubscription(id, userID)
+	s.fill(saved, sub)
+	return saved, nil
+}
+
+// List 获取用户可见的检索（自己创建的和团队可见的），附带当前用户的订阅
+func (s *savedSearchService) List(userID uint64, mineOnly bool) ([]*model.SavedSearch, error) {
+	searches, err := s.savedSearchRepo.ListVisible(userID, mineOnly)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.savedSearchRepo.GetSubscriptionsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	bySearch := make(map[uint64]*model.SavedSearchSubscription, len(subs))
+	for _, sub := range subs {
+		bySearch[sub.SavedSearchID] = sub
+	}
+	for _, saved := range searches {
+		s.fill(saved, bySearch[saved.ID])
+	}
+	return searches, nil
+}
+
+// Update 更新保存的检索，只有创建人和管理员可以修改；改为仅创建人可见时删除其他用户的订阅
+func (s *savedSearchService) Update(id, userID uint64, isAdmin bool, params *SavedSearchParams) (*model.SavedSearch, error) {
+	saved, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || !savedSearchVisible(saved, userID) && !isAdmin {
+		return nil, errors.New("保存的检索不存在")
+	}
+	if saved.CreatedBy != userID && !isAdmin {
+		return nil, errors.New("只有创建人或管理员可以修改保存的检索")
+	}
+
+	wasTeam := saved.Visibility == model.SavedSearchTeam
+	if err := s.applyParams(saved, params); err != nil {
+		return nil, err
+	}
+
+	if err := s.savedSearchRepo.Update(saved); err != nil {
+		return nil, errors.New("更新保存的检索失败")
+	}
+	if wasTeam && saved.Visibility == model.SavedSearchPrivate {
+		if err := s.savedSearchRepo.DeleteOtherSubscriptions(saved.ID, saved.CreatedBy); err != nil {
+			appLogger.Get().Warn("删除其他用户的检索订阅失败", zap.Uint64("saved_search_id", saved.ID), zap.Error(err))
+		}
+	}
+
+	sub, _ := s.savedSearchRepo.GetSubscription(id, userID)
+	s.fill(saved, sub)
+	return saved, nil
+}
+
+// Delete 删除保存的检索及其订阅，只有创建人和管理员可以删除
+func (s *savedSearchService) Delete(id, userID uint64, isAdmin bool) error {
+	saved, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || !savedSearchVisible(saved, userID) && !isAdmin {
+		return errors.New("保存的检索不存在")
+	}
+	if saved.CreatedBy != userID && !isAdmin {
+		return errors.New("只有创建人或管理员可以删除保存的检索")
+	}
+	return s.savedSearchRepo.Delete(id)
+}
+
+// Run 执行保存的检索，返回结果同舆情检索
+func (s *savedSearchService) Run(id, userID uint64, page, pageSize int, facets bool) (*SearchResult, error) {
+	saved, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || !savedSearchVisible(saved, userID) {
+		return nil, errors.New("保存的检索不存在")
+	}
+	filter, err := savedSearchFilter(saved, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(saved, filter, saved.Sort, page, pageSize, facets)
+}
+
+// Subscribe 订阅保存的检索（已订阅时更新推送方式和渠道），只有管理员可以推送到通知渠道
+func (s *savedSearchService) Subscribe(id, userID uint64, isAdmin bool, params *SubscriptionParams) (*model.SavedSearchSubscription, error) {
+	saved, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || !savedSearchVisible(saved, userID) {
+		return nil, errors.New("保存的检索不存在")
+	}
+	if params == nil {
+		return nil, errors.New("推送方式不能为空")
+	}
+
+	switch params.Frequency {
+	case model.SubscriptionRealtime, model.SubscriptionHourly, model.SubscriptionDaily:
+	default:
+		return nil, errors.New("无效的推送方式，可选值: realtime, hourly, daily")
+	}
+	channels := trimStrings(params.Channels)
+	seen := make(map[string]bool, len(channels))
+	unique := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		if channel != model.SubscriptionChannelInbox && channel != model.SubscriptionChannelEmail {
+			return nil, errors.New("无效的推送渠道，可选值: inbox, email")
+		}
+		if !seen[channel] {
+			seen[channel] = true
+			unique = append(unique, channel)
+		}
+	}
+	channelIDs := uniqueIDs(params.ChannelIDs)
+	if len(channelIDs) > 0 && !isAdmin {
+		return nil, errors.New("只有管理员可以推送到通知渠道")
+	}
+	for _, channelID := range channelIDs {
+		if _, err := s.notificationService.GetChannel(channelID); err != nil {
+			return nil, fmt.Errorf("通知渠道%d不存在", channelID)
+		}
+	}
+	if len(unique) == 0 && len(channelIDs) == 0 {
+		unique = []string{model.SubscriptionChannelInbox}
+	}
+	if seen[model.SubscriptionChannelEmail] {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil || user.Email == "" {
+			return nil, errors.New("当前用户没有设置邮箱，无法通过邮件推送")
+		}
+	}
+
+	sub, err := s.savedSearchRepo.GetSubscription(id, userID)
+	if err != nil {
+		sub = &model.SavedSearchSubscription{SavedSearchID: id, UserID: userID}
+	}
+	sub.Frequency = params.Frequency
+	sub.Channels = unique
+	sub.ChannelIDs = channelIDs
+	if err := s.savedSearchRepo.SaveSubscription(sub); err != nil {
+		return nil, errors.New("订阅失败")
+	}
+	return sub, nil
+}
+
+// Unsubscribe 取消订阅
+func (s *savedSearchService) Unsubscribe(id, userID uint64) error {
+	sub, err := s.savedSearchRepo.GetSubscription(id, userID)
+	if err != nil {
+		return errors.New("没有订阅该检索")
+	}
+	return s.savedSearchRepo.DeleteSubscription(sub.ID)
+}
+
+// DeliverDue 检查到期的订阅（实时订阅每次检查，每小时汇总在每个整点后检查一次，每日汇总在每天的推送时刻后检查一次），
+// 把尚未推送过的新结果推送给订阅用户，返回推送的订阅数
+func (s *savedSearchService) DeliverDue(now time.Time) (int, error) {
+	if deleted, err := s.savedSearchRepo.DeleteMatchesBefore(now.Add(-time.Duration(s.cfg.RetentionHours) * time.Hour)); err != nil {
+		appLogger.Get().Warn("清理检索订阅推送记录失败", zap.Error(err))
+	} else if deleted > 0 {
+		appLogger.Get().Info("已清理过期的检索订阅推送记录", zap.Int64("deleted", deleted))
+	}
+
+	subs, err := s.savedSearchRepo.GetAllSubscriptions()
+	if err != nil {
+		return 0, err
+	}
+	var due []*model.SavedSearchSubscription
+	var searchIDs []uint64
+	for _, sub := range subs {
+		if subscriptionDue(sub, now, s.cfg.DailyHour) {
+			due = append(due, sub)
+			searchIDs = append(searchIDs, sub.SavedSearchID)
+		}
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+	searches, err := s.savedSearchRepo.GetByIDs(uniqueIDs(searchIDs))
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[uint64]*model.SavedSearch, len(searches))
+	for _, saved := range searches {
+		byID[saved.ID] = saved
+	}
+
+	delivered := 0
+	for _, sub := range due {
+		saved := byID[sub.SavedSearchID]
+		if saved == nil || !savedSearchVisible(saved, sub.UserID) {
+			continue
+		}
+		ok, err := s.deliver(saved, sub, now)
+		if err != nil {
+			appLogger.Get().Error("检查检索订阅失败",
+				zap.Uint64("subscription_id", sub.ID),
+				zap.Uint64("saved_search_id", saved.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver 检查订阅的新结果并推送，没有新结果时只更新检查时间
+func (s *savedSearchService) deliver(saved *model.SavedSearch, sub *model.SavedSearchSubscription, now time.Time) (bool, error) {
+	user, err := s.userRepo.GetByID(sub.UserID)
+	if err != nil {
+		return false, err
+	}
+	if user.Status != 1 {
+		return false, nil
+	}
+
+	filter, err := savedSearchFilter(saved, now)
+	if err != nil {
+		return false, err
+	}
+	// 从上次检查之前回看一段时间，覆盖晚于舆情入库产生的命中记录；不推送订阅之前入库的舆情
+	since := sub.CreatedAt
+	if sub.LastCheckedAt != nil {
+		if t := sub.LastCheckedAt.Add(-time.Duration(s.cfg.LookbackMinutes) * time.Minute); t.After(since) {
+			since = t
+		}
+	}
+	if filter.StartTime == nil || filter.StartTime.Before(since) {
+		filter.StartTime = &since
+	}
+
+	result, err := s.execute(saved, filter, SearchSortTime, 1, s.cfg.MaxMatches, false)
+	if err != nil {
+		return false, err
+	}
+	ids := make([]uint64, len(result.List))
+	for i, hit := range result.List {
+		ids[i] = hit.ID
+	}
+	matched, err := s.savedSearchRepo.GetMatchedOpinionIDs(sub.ID, ids)
+	if err != nil {
+		return false, err
+	}
+	skip := make(map[uint64]bool, len(matched))
+	for _, id := range matched {
+		skip[id] = true
+	}
+	var hits []*SearchHit
+	for _, hit := range result.List {
+		if !skip[hit.ID] {
+			hits = append(hits, hit)
+		}
+	}
+
+	if len(hits) == 0 {
+		return false, s.savedSearchRepo.UpdateSubscriptionProgress(sub.ID, now, nil)
+	}
+
+	msg := s.subscriptionMessage(saved, sub, hits, result.Total > int64(len(result.List)), now)
+	if hasString(sub.Channels, model.SubscriptionChannelInbox) {
+		s.inboxService.Notify([]uint64{sub.UserID}, model.UserNotification{
+			Type:       model.InboxTypeSavedSearch,
+			Title:      msg.Title,
+			Content:    truncateRunes(msg.Content, 1000),
+			SourceType: model.InboxSourceSavedSearch,
+			SourceID:   saved.ID,
+		})
+	}
+	if hasString(sub.Channels, model.SubscriptionChannelEmail) && user.Email != "" {
+		s.notificationService.SendEmail([]string{user.Email}, msg, NotifySourceSavedSearch, saved.ID)
+	}
+	if len(sub.ChannelIDs) > 0 {
+		s.notificationService.Send(sub.ChannelIDs, msg, NotifySourceSavedSearch, saved.ID)
+	}
+
+	matches := make([]*model.SavedSearchMatch, len(hits))
+	for i, hit := range hits {
+		matches[i] = &model.SavedSearchMatch{SubscriptionID: sub.ID, OpinionID: hit.ID}
+	}
+	if err := s.savedSearchRepo.CreateMatches(matches); err != nil {
+		return true, err
+	}
+	return true, s.savedSearchRepo.UpdateSubscriptionProgress(sub.ID, now, &now)
+}
+
+// subscriptionMessage 订阅推送消息，列出最新的若干条结果
+func (s *savedSearchService) subscriptionMessage(saved *model.SavedSearch, sub *model.SavedSearchSubscription, hits []*SearchHit, truncated bool, now time.Time) notify.Message {
+	count := fmt.Sprintf("%d", len(hits))
+	if truncated {
+		count += "+"
+	}
+
+	var b strings.Builder
+	for i, hit := range hits {
+		if i >= s.cfg.DigestItems {
+			fmt.Fprintf(&b, "……等 %s 条\n", count)
+			break
+		}
+		text := strings.Join(strings.Fields(hit.Content), " ")
+		fmt.Fprintf(&b, "[%s] %s：%s\n", hit.Source, hit.Author, truncateRunes(text, 60))
+	}
+
+	opinionIDs := make([]uint64, len(hits))
+	for i, hit := range hits {
+		opinionIDs[i] = hit.ID
+	}
+	return notify.Message{
+		Title:   fmt.Sprintf("订阅的检索「%s」有 %s 条新结果", saved.Name, count),
+		Content: strings.TrimRight(b.String(), "\n"),
+		Time:    now,
+		Data: map[string]interface{}{
+			"saved_search_id": saved.ID,
+			"subscription_id": sub.ID,
+			"frequency":       sub.Frequency,
+			"opinion_ids":     opinionIDs,
+		},
+	}
+}
+
+// execute 有检索词时调用舆情检索，否则按筛选条件分页查询（按入库时间倒序）
+func (s *savedSearchService) execute(saved *model.SavedSearch, filter OpinionFilter, sort string, page, pageSize int, facets bool) (*SearchResult, error) {
+	if saved.Query != "" {
+		return s.searchService.Search(SearchRequest{
+			Query:    saved.Query,
+			Mode:     saved.Mode,
+			Sort:     sort,
+			Filter:   filter,
+			Facets:   facets,
+			Page:     page,
+			PageSize: pageSize,
+		})
+	}
+
+	opinions, total, err := s.opinionRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, errors.New("查询舆情失败")
+	}
+	result := &SearchResult{
+		List:     make([]*SearchHit, len(opinions)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Engine:   SearchEngineFilter,
+	}
+	for i, opinion := range opinions {
+		result.List[i] = &SearchHit{
+			Opinion: opinion,
+			Snippet: highlightSnippet(opinion.Content, nil, savedSearchSnippetLength),
+		}
+	}
+	return result, nil
+}
+
+// applyParams 校验参数并写入保存的检索
+func (s *savedSearchService) applyParams(saved *model.SavedSearch, params *SavedSearchParams) error {
+	if params == nil {
+		return nil
+	}
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			return errors.New("名称不能为空")
+		}
+		saved.Name = name
+	}
+	if params.Query != nil {
+		saved.Query = strings.TrimSpace(*params.Query)
+	}
+	if params.Mode != nil {
+		saved.Mode = *params.Mode
+	}
+	if params.Sort != nil {
+		saved.Sort = *params.Sort
+	}
+	if saved.Query != "" {
+		if utf8.RuneCountInString(saved.Query) > maxSearchQueryLength {
+			return errors.New("检索词不能超过 200 个字符")
+		}
+		if saved.Mode == "" {
+			saved.Mode = SearchModeNatural
+		}
+		if _, err := parseSearchQuery(saved.Query, saved.Mode); err != nil {
+			return err
+		}
+	} else {
+		saved.Mode = ""
+	}
+	if saved.Sort != "" && saved.Sort != SearchSortRelevance && saved.Sort != SearchSortTime {
+		return errors.New("无效的排序方式，可选值: relevance, time")
+	}
+	if params.Filter != nil {
+		if err := validateSavedFilter(params.Filter); err != nil {
+			return err
+		}
+		data, err := json.Marshal(params.Filter)
+		if err != nil {
+			return errors.New("无效的筛选条件")
+		}
+		saved.Filter = string(data)
+	}
+	if params.RecentHours != nil {
+		if *params.RecentHours < 0 {
+			return errors.New("最近小时数不能为负数")
+		}
+		saved.RecentHours = *params.RecentHours
+	}
+	if params.Visibility != nil {
+		if *params.Visibility != model.SavedSearchPrivate && *params.Visibility != model.SavedSearchTeam {
+			return errors.New("无效的可见范围，可选值: private, team")
+		}
+		saved.Visibility = *params.Visibility
+	}
+	return nil
+}
+
+// fill 填充筛选条件和当前用户的订阅
+func (s *savedSearchService) fill(saved *model.SavedSearch, sub *model.SavedSearchSubscription) {
+	saved.Conditions = map[string]interface{}{}
+	_ = json.Unmarshal([]byte(saved.Filter), &saved.Conditions)
+	saved.Subscription = sub
+}
+
+// validateSavedFilter 校验筛选条件
+func validateSavedFilter(filter *OpinionFilter) error {
+	if filter.Sentiment != "" && !sentiment.IsValidLabel(filter.Sentiment) {
+		return errors.New("无效的情感标签，可选值: positive, neutral, negative")
+	}
+	for _, status := range filter.HandlingStatuses {
+		if !IsValidHandlingStatus(status) {
+			return errors.New("无效的处置状态，可选值: new, in_progress, responded, ignored, escalated")
+		}
+	}
+	if filter.Priority != "" && !IsValidPriority(filter.Priority) {
+		return errors.New("无效的优先级，可选值: low, medium, high, urgent")
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && !filter.StartTime.Before(*filter.EndTime) {
+		return errors.New("开始时间必须早于结束时间")
+	}
+	return nil
+}
+
+// savedSearchFilter 解析保存的筛选条件，设置了最近小时数时把开始时间限制在 now 之前的该时长内
+func savedSearchFilter(saved *model.SavedSearch, now time.Time) (OpinionFilter, error) {
+	var filter OpinionFilter
+	if saved.Filter != "" {
+		if err := json.Unmarshal([]byte(saved.Filter), &filter); err != nil {
+			return filter, errors.New("保存的筛选条件无效")
+		}
+	}
+	if saved.RecentHours > 0 {
+		start := now.Add(-time.Duration(saved.RecentHours) * time.Hour)
+		if filter.StartTime == nil || filter.StartTime.Before(start) {
+			filter.StartTime = &start
+		}
+	}
+	return filter, nil
+}
+
+// savedSearchVisible 用户是否可以查看检索
+func savedSearchVisible(saved *model.SavedSearch, userID uint64) bool {
+	return saved.CreatedBy == userID || saved.Visibility == model.SavedSearchTeam
+}
+
+// subscriptionDue 订阅是否到了检查时间
+func subscriptionDue(sub *model.SavedSearchSubscription, now time.Time, dailyHour int) bool {
+	var boundary time.Time
+	switch sub.Frequency {
+	case model.SubscriptionRealtime:
+		return true
+	case model.SubscriptionHourly:
+		boundary = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	case model.SubscriptionDaily:
+		boundary = time.Date(now.Year(), now.Month(), now.Day(), dailyHour, 0, 0, 0, now.Location())
+		if now.Before(boundary) {
+			return false
+		}
+	default:
+		return false
+	}
+	last := sub.CreatedAt
+	if sub.LastCheckedAt != nil {
+		last = *sub.LastCheckedAt
+	}
+	return last.Before(boundary)
+}
+
+// hasString 判断列表中是否包含指定字符串
+func hasString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
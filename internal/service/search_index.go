@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/search"
+)
+
+// fulltextRecheckInterval 发现没有全文索引后，间隔多久再尝试使用全文索引（索引可能已补建）
+const fulltextRecheckInterval = 5 * time.Minute
+
+// SearchIndex 舆情检索后端接口
+// 实现有 MySQL（FULLTEXT 索引，必要时降级为 LIKE 匹配）和内嵌索引（search.Index）。
+// 返回的命中可以不带舆情内容，由 SearchService 按 ID 加载。
+type SearchIndex interface {
+	Search(query search.Query) (*search.Result, error)
+}
+
+type mysqlSearchIndex struct {
+	ngramTokenSize int
+	opinionRepo    repository.OpinionRepository
+
+	mu                sync.Mutex
+	indexMissingUntil time.Time // 在此之前直接使用 LIKE 匹配
+}
+
+// newMySQLSearchIndex 创建基于 MySQL 的检索后端
+func newMySQLSearchIndex(ngramTokenSize int, opinionRepo repository.OpinionRepository) SearchIndex {
+	return &mysqlSearchIndex{
+		ngramTokenSize: ngramTokenSize,
+		opinionRepo:    opinionRepo,
+	}
+}
+
+// Search 使用 FULLTEXT 索引检索；没有全文索引或检索词短于 ngram 长度时降级为 LIKE 子串匹配，按入库时间倒序
+func (x *mysqlSearchIndex) Search(query search.Query) (*search.Result, error) {
+	if !hasShortTerm(query.Terms, x.ngramTokenSize) && x.fulltextAvailable() {
+		fulltext := repository.FulltextQuery{
+			Against:     fulltextAgainst(query),
+			BooleanMode: query.Boolean,
+			ByTime:      query.ByTime,
+		}
+		scored, total, err := x.opinionRepo.Search(fulltext, query.Filter, query.Page, query.PageSize)
+		if err == nil {
+			result := &search.Result{Total: total, Engine: SearchEngineFulltext, Hits: make([]*search.Hit, len(scored))}
+			for i, item := range scored {
+				opinion := item.Opinion
+				result.Hits[i] = &search.Hit{ID: opinion.ID, Score: item.Score, Opinion: &opinion}
+			}
+			if query.Facets {
+				if result.Facets, err = x.opinionRepo.SearchFacets(fulltext, query.Filter, query.FacetSize); err != nil {
+					return nil, err
+				}
+			}
+			return result, nil
+		}
+		if !errors.Is(err, repository.ErrFulltextIndexMissing) {
+			return nil, err
+		}
+		x.markIndexMissing()
+	}
+
+	like := likeQuery(query.Terms)
+	opinions, total, err := x.opinionRepo.SearchLike(like, query.Filter, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	result := &search.Result{Total: total, Engine: SearchEngineLike, Hits: make([]*search.Hit, len(opinions))}
+	for i, opinion := range opinions {
+		result.Hits[i] = &search.Hit{ID: opinion.ID, Opinion: opinion}
+	}
+	if query.Facets {
+		if result.Facets, err = x.opinionRepo.SearchLikeFacets(like, query.Filter, query.FacetSize); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// fulltextAvailable 最近没有发现缺少全文索引时返回 true
+func (x *mysqlSearchIndex) fulltextAvailable() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return time.Now().After(x.indexMissingUntil)
+}
+
+// markIndexMissing 记录缺少全文索引，一段时间内直接使用 LIKE 匹配
+func (x *mysqlSearchIndex) markIndexMissing() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.indexMissingUntil = time.Now().Add(fulltextRecheckInterval)
+	appLogger.Get().Warn("opinions.content 上没有 FULLTEXT 索引，舆情检索降级为 LIKE 匹配")
+}
+
+// hasShortTerm 是否有短于 ngram 长度的词语（全文索引无法匹配）
+func hasShortTerm(terms []search.Term, ngramSize int) bool {
+	for _, term := range terms {
+		if utf8.RuneCountInString(term.Text) < ngramSize {
+			return true
+		}
+	}
+	return false
+}
+
+// fulltextAgainst 构造 MATCH ... AGAINST 的检索串
+func fulltextAgainst(query search.Query) string {
+	parts := make([]string, 0, len(query.Terms))
+	for _, term := range query.Terms {
+		if !query.Boolean {
+			parts = append(parts, term.Text)
+			continue
+		}
+		part := term.Text
+		if term.Phrase {
+			part = `"` + part + `"`
+		} else if term.Wildcard {
+			part += "*"
+		}
+		if term.Op != 0 {
+			part = string(term.Op) + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// likeQuery 构造降级时的 LIKE 条件；有必须包含的词语时，可选词语只影响相关度，LIKE 匹配时忽略
+func likeQuery(terms []search.Term) repository.LikeQuery {
+	var like repository.LikeQuery
+	for _, term := range terms {
+		switch term.Op {
+		case '+':
+			like.Must = append(like.Must, term.Text)
+		case '-':
+			like.MustNot = append(like.MustNot, term.Text)
+		default:
+			like.Should = append(like.Should, term.Text)
+		}
+	}
+	if len(like.Must) > 0 {
+		like.Should = nil
+	}
+	return like
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/search"
+)
+
+// searchSyncOverlap 增量同步时从上次进度之前多长时间开始，覆盖提交较晚的事务（重复写入的文档会被覆盖）
+const searchSyncOverlap = 5 * time.Second
+
+// SearchIndexer 内嵌检索索引同步服务接口
+type SearchIndexer interface {
+	Sync() (int, error)
+	Rebuild() (int, error)
+}
+
+type searchIndexer struct {
+	batchSize   int
+	index       *search.Index
+	opinionRepo repository.OpinionRepository
+	hitRepo     repository.OpinionHitRepository
+}
+
+// NewSearchIndexer 创建内嵌检索索引同步服务实例
+func NewSearchIndexer(cfg *config.SearchConfig, index *search.Index, opinionRepo repository.OpinionRepository, hitRepo repository.OpinionHitRepository) SearchIndexer {
+	x := &searchIndexer{
+		index:       index,
+		opinionRepo: opinionRepo,
+		hitRepo:     hitRepo,
+	}
+	if cfg != nil {
+		x.batchSize = cfg.BatchSize
+	}
+	if x.batchSize <= 0 {
+		x.batchSize = 500
+	}
+	return x
+}
+
+// Sync 增量同步上次进度之后更新的舆情（新增、富化、处置等）和新增命中记录对应的舆情，返回写入的舆情数。
+// 采集、导入等任务写入 MySQL 后，由 Web 服务定期调用同步到内嵌索引
+func (x *searchIndexer) Sync() (int, error) {
+	cp, err := x.index.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+
+	// 新索引的第一次同步会写入全部舆情及其当前的命中记录，不需要再同步之前的命中记录
+	if cp.OpinionsUntil.IsZero() && cp.HitsUntil.IsZero() {
+		cp.HitsUntil = time.Now()
+	}
+
+	written := 0
+	since, afterID := syncStart(cp.OpinionsUntil), uint64(0)
+	for {
+		opinions, err := x.opinionRepo.GetUpdatedAfter(since, afterID, x.batchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(opinions) == 0 {
+			break
+		}
+		docs, err := x.documents(opinions)
+		if err != nil {
+			return written, err
+		}
+		last := opinions[len(opinions)-1]
+		if last.UpdatedAt.After(cp.OpinionsUntil) {
+			cp.OpinionsUntil = last.UpdatedAt
+		}
+		if err := x.index.Write(docs, nil, cp); err != nil {
+			return written, err
+		}
+		written += len(docs)
+		if len(opinions) < x.batchSize {
+			break
+		}
+		since, afterID = last.UpdatedAt, last.ID
+	}
+
+	since, afterID = syncStart(cp.HitsUntil), 0
+	for {
+		hits, err := x.hitRepo.GetCreatedAfter(since, afterID, x.batchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(hits) == 0 {
+			break
+		}
+		ids := make([]uint64, 0, len(hits))
+		for _, hit := range hits {
+			ids = append(ids, hit.OpinionID)
+		}
+		ids = uniqueIDs(ids)
+		opinions, err := x.opinionRepo.GetByIDs(ids)
+		if err != nil {
+			return written, err
+		}
+		docs, err := x.documents(opinions)
+		if err != nil {
+			return written, err
+		}
+		// 命中记录对应的舆情已删除时从索引中删除
+		found := make(map[uint64]bool, len(opinions))
+		for _, opinion := range opinions {
+			found[opinion.ID] = true
+		}
+		var deleted []uint64
+		for _, id := range ids {
+			if !found[id] {
+				deleted = append(deleted, id)
+			}
+		}
+		last := hits[len(hits)-1]
+		if last.CreatedAt.After(cp.HitsUntil) {
+			cp.HitsUntil = last.CreatedAt
+		}
+		if err := x.index.Write(docs, deleted, cp); err != nil {
+			return written, err
+		}
+		written += len(docs)
+		if len(hits) < x.batchSize {
+			break
+		}
+		since, afterID = last.CreatedAt, last.ID
+	}
+	return written, nil
+}
+
+// Rebuild 按 ID 顺序把全部舆情写入索引，再增量同步重建期间的变更，返回写入的舆情数。
+// 用于写入 search.Create 创建的新索引版本
+func (x *searchIndexer) Rebuild() (int, error) {
+	start := time.Now()
+	written := 0
+	var afterID uint64
+	for {
+		opinions, err := x.opinionRepo.GetBatchAfterID(afterID, x.batchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(opinions) == 0 {
+			break
+		}
+		docs, err := x.documents(opinions)
+		if err != nil {
+			return written, err
+		}
+		if err := x.index.Write(docs, nil, search.Checkpoint{}); err != nil {
+			return written, err
+		}
+		written += len(docs)
+		if len(opinions) < x.batchSize {
+			break
+		}
+		afterID = opinions[len(opinions)-1].ID
+	}
+
+	if err := x.index.Write(nil, nil, search.Checkpoint{OpinionsUntil: start, HitsUntil: start}); err != nil {
+		return written, err
+	}
+	synced, err := x.Sync()
+	return written + synced, err
+}
+
+// documents 加载舆情的命中记录并构造索引文档
+func (x *searchIndexer) documents(opinions []*model.Opinion) ([]*search.Document, error) {
+	ids := make([]uint64, len(opinions))
+	for i, opinion := range opinions {
+		ids[i] = opinion.ID
+	}
+	hits, err := x.hitRepo.GetByOpinionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byOpinion := make(map[uint64][]*model.OpinionHit)
+	for _, hit := range hits {
+		byOpinion[hit.OpinionID] = append(byOpinion[hit.OpinionID], hit)
+	}
+
+	docs := make([]*search.Document, len(opinions))
+	for i, opinion := range opinions {
+		docs[i] = search.NewDocument(opinion, byOpinion[opinion.ID])
+	}
+	return docs, nil
+}
+
+// syncStart 增量同步的起始时间
+func syncStart(until time.Time) time.Time {
+	if until.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return until.Add(-searchSyncOverlap)
+}
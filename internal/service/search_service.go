@@ -0,0 +1,488 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+	"sentinel-opinion-monitor/internal/search"
+
+	"go.uber.org/zap"
+)
+
+// 检索模式
+const (
+	SearchModeNatural = "natural" // 自然语言模式，按相关度排序
+	SearchModeBoolean = "boolean" // 布尔模式：+必须包含 -不能包含 "短语" 前缀*
+	SearchModePhrase  = "phrase"  // 整个检索串作为短语
+)
+
+// 排序方式
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortTime      = "time"
+)
+
+// 检索方式
+const (
+	SearchEngineFulltext = "fulltext"    // FULLTEXT（ngram）索引
+	SearchEngineLike     = "like"        // LIKE 子串匹配（没有全文索引或检索词过短时）
+	SearchEngineEmbedded = search.Engine // 内嵌索引
+	SearchEngineFilter   = "filter"      // 没有检索词，只按筛选条件查询（保存的检索）
+)
+
+const maxSearchQueryLength = 200
+
+// SearchRequest 舆情检索请求
+type SearchRequest struct {
+	Query    string
+	Mode     string
+	Sort     string
+	Filter   OpinionFilter
+	Facets   bool // 是否返回按来源、情感和监测组的分面统计
+	Page     int
+	PageSize int
+}
+
+// SearchHit 检索结果中的一条舆情
+type SearchHit struct {
+	*model.Opinion
+	Score   float64 `json:"score"`   // 相关度得分，LIKE 匹配时为 0
+	Snippet string  `json:"snippet"` // 高亮摘要，已做 HTML 转义，命中的词语以 <em> 标记
+}
+
+// SearchResult 舆情检索结果
+type SearchResult struct {
+	List     []*SearchHit              `json:"list"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+	Mode     string                    `json:"mode"`
+	Engine   string                    `json:"engine"`
+	Facets   *repository.OpinionFacets `json:"facets,omitempty"`
+}
+
+// SearchService 舆情全文检索服务接口
+type SearchService interface {
+	Search(req SearchRequest) (*SearchResult, error)
+}
+
+type searchService struct {
+	cfg         config.SearchConfig
+	opinionRepo repository.OpinionRepository
+	groupRepo   repository.MonitoringGroupRepository
+	mysqlIndex  SearchIndex
+
+	embedded *search.Index // 未启用内嵌索引时为 nil
+
+	mu            sync.Mutex
+	embeddedReady bool // 内嵌索引完成过一次同步后才用于检索
+}
+
+// NewSearchService 创建舆情全文检索服务实例
+// 启用内嵌索引（search.Init）时，在后台定期把新增和变更的舆情同步到内嵌索引，并在索引重建后切换到新版本
+func NewSearchService(cfg *config.SearchConfig, opinionRepo repository.OpinionRepository, hitRepo repository.OpinionHitRepository, groupRepo repository.MonitoringGroupRepository) SearchService {
+	s := &searchService{
+		opinionRepo: opinionRepo,
+		groupRepo:   groupRepo,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.NgramTokenSize <= 0 {
+		s.cfg.NgramTokenSize = 2
+	}
+	if s.cfg.SnippetLength <= 0 {
+		s.cfg.SnippetLength = 120
+	}
+	if s.cfg.FacetSize <= 0 {
+		s.cfg.FacetSize = 10
+	}
+	if s.cfg.SyncIntervalSeconds <= 0 {
+		s.cfg.SyncIntervalSeconds = 10
+	}
+	s.mysqlIndex = newMySQLSearchIndex(s.cfg.NgramTokenSize, opinionRepo)
+
+	if index := search.Get(); index != nil {
+		s.embedded = index
+		go s.syncEmbedded(NewSearchIndexer(&s.cfg, index, opinionRepo, hitRepo))
+	}
+	return s
+}
+
+// Search 检索舆情内容并结合列表筛选条件，返回带相关度和高亮摘要的结果
+// 内嵌索引可用且支持该检索时使用内嵌索引，否则使用 MySQL：没有全文索引或检索词短于 ngram 长度时
+// 降级为 LIKE 子串匹配，按入库时间倒序
+func (s *searchService) Search(req SearchRequest) (*SearchResult, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return nil, errors.New("请输入检索词")
+	}
+	if utf8.RuneCountInString(req.Query) > maxSearchQueryLength {
+		return nil, errors.New("检索词不能超过 200 个字符")
+	}
+	if req.Mode == "" {
+		req.Mode = SearchModeNatural
+	}
+	if req.Sort == "" {
+		req.Sort = SearchSortRelevance
+	}
+	if req.Sort != SearchSortRelevance && req.Sort != SearchSortTime {
+		return nil, errors.New("无效的排序方式，可选值: relevance, time")
+	}
+
+	parsed, err := parseSearchQuery(req.Query, req.Mode)
+	if err != nil {
+		return nil, err
+	}
+	query := search.Query{
+		Terms:     parsed.terms,
+		Boolean:   req.Mode != SearchModeNatural,
+		Filter:    req.Filter,
+		ByTime:    req.Sort == SearchSortTime,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+		Facets:    req.Facets,
+		FacetSize: s.cfg.FacetSize,
+	}
+
+	var res *search.Result
+	if s.embedded != nil && s.isEmbeddedReady() {
+		res, err = s.embedded.Search(query)
+		if err != nil && !errors.Is(err, search.ErrUnsupported) {
+			appLogger.Get().Warn("内嵌索引检索失败，改用 MySQL 检索", zap.Error(err))
+		}
+	}
+	if res == nil {
+		if res, err = s.mysqlIndex.Search(query); err != nil {
+			return nil, errors.New("检索舆情失败")
+		}
+	}
+
+	list, err := s.loadHits(res, parsed.highlights)
+	if err != nil {
+		return nil, errors.New("检索舆情失败")
+	}
+	result := &SearchResult{
+		List:     list,
+		Total:    res.Total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Mode:     req.Mode,
+		Engine:   res.Engine,
+		Facets:   res.Facets,
+	}
+	if result.Facets != nil {
+		s.fillGroupNames(result.Facets.Group)
+	}
+	return result, nil
+}
+
+// loadHits 加载命中舆情（内嵌索引只返回 ID）并生成高亮摘要；索引中已删除的舆情从结果中去掉
+func (s *searchService) loadHits(res *search.Result, highlights []string) ([]*SearchHit, error) {
+	var ids []uint64
+	for _, hit := range res.Hits {
+		if hit.Opinion == nil {
+			ids = append(ids, hit.ID)
+		}
+	}
+	loaded := make(map[uint64]*model.Opinion, len(ids))
+	if len(ids) > 0 {
+		opinions, err := s.opinionRepo.GetByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, opinion := range opinions {
+			loaded[opinion.ID] = opinion
+		}
+	}
+
+	list := make([]*SearchHit, 0, len(res.Hits))
+	var deleted []uint64
+	for _, hit := range res.Hits {
+		opinion := hit.Opinion
+		if opinion == nil {
+			if opinion = loaded[hit.ID]; opinion == nil {
+				deleted = append(deleted, hit.ID)
+				continue
+			}
+		}
+		list = append(list, &SearchHit{
+			Opinion: opinion,
+			Score:   hit.Score,
+			Snippet: highlightSnippet(opinion.Content, highlights, s.cfg.SnippetLength),
+		})
+	}
+	if len(deleted) > 0 && s.embedded != nil {
+		res.Total -= int64(len(deleted))
+		if err := s.embedded.Delete(deleted); err != nil {
+			appLogger.Get().Warn("从内嵌索引删除舆情失败", zap.Uint64s("opinion_ids", deleted), zap.Error(err))
+		}
+	}
+	return list, nil
+}
+
+// fillGroupNames 填充监测组分面的监测组名称
+func (s *searchService) fillGroupNames(counts []*repository.FacetCount) {
+	ids := make([]uint64, 0, len(counts))
+	for _, count := range counts {
+		if id, err := strconv.ParseUint(count.Key, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	groups, err := s.groupRepo.GetByIDs(ids)
+	if err != nil {
+		return
+	}
+	names := make(map[string]string, len(groups))
+	for _, group := range groups {
+		names[strconv.FormatUint(group.ID, 10)] = group.Name
+	}
+	for _, count := range counts {
+		count.Name = names[count.Key]
+	}
+}
+
+// syncEmbedded 定期同步内嵌索引，直到索引关闭
+func (s *searchService) syncEmbedded(indexer SearchIndexer) {
+	ticker := time.NewTicker(time.Duration(s.cfg.SyncIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		if switched, err := s.embedded.Reload(); err != nil {
+			appLogger.Get().Warn("切换内嵌索引版本失败", zap.Error(err))
+		} else if switched {
+			appLogger.Get().Info("已切换到重建的内嵌索引", zap.String("generation", s.embedded.Generation()))
+		}
+
+		start := time.Now()
+		written, err := indexer.Sync()
+		switch {
+		case errors.Is(err, search.ErrClosed):
+			return
+		case err != nil:
+			appLogger.Get().Warn("同步内嵌索引失败", zap.Int("written", written), zap.Error(err))
+		default:
+			if !s.isEmbeddedReady() {
+				appLogger.Get().Info("内嵌索引同步完成", zap.Int("written", written), zap.Duration("elapsed", time.Since(start)))
+			}
+			s.mu.Lock()
+			s.embeddedReady = true
+			s.mu.Unlock()
+		}
+
+		select {
+		case <-s.embedded.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isEmbeddedReady 内嵌索引是否已完成过一次同步
+func (s *searchService) isEmbeddedReady() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.embeddedReady
+}
+
+// searchQuery 解析后的检索条件
+type searchQuery struct {
+	terms      []search.Term
+	highlights []string // 需要高亮的词语
+}
+
+// parseSearchQuery 按检索模式解析检索串
+func parseSearchQuery(text, mode string) (*searchQuery, error) {
+	q := &searchQuery{}
+	switch mode {
+	case SearchModeNatural:
+		for _, word := range strings.Fields(text) {
+			q.terms = append(q.terms, search.Term{Text: word})
+		}
+	case SearchModePhrase:
+		phrase := strings.Join(strings.Fields(strings.ReplaceAll(text, `"`, " ")), " ")
+		if phrase == "" {
+			return nil, errors.New("请输入检索词")
+		}
+		q.terms = []search.Term{{Text: phrase, Op: '+', Phrase: true}}
+	case SearchModeBoolean:
+		q.terms = tokenizeBooleanQuery(text)
+	default:
+		return nil, errors.New("无效的检索模式，可选值: natural, boolean, phrase")
+	}
+
+	seen := make(map[string]bool)
+	for _, term := range q.terms {
+		if term.Op == '-' {
+			continue
+		}
+		if key := strings.ToLower(term.Text); !seen[key] {
+			seen[key] = true
+			q.highlights = append(q.highlights, term.Text)
+		}
+	}
+	if len(q.highlights) == 0 {
+		return nil, errors.New("至少需要一个不带 - 的检索词")
+	}
+	return q, nil
+}
+
+// tokenizeBooleanQuery 解析布尔模式的检索串：支持 +词语、-词语、"短语" 和词语末尾的 *，忽略括号和其他运算符
+func tokenizeBooleanQuery(text string) []search.Term {
+	var terms []search.Term
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			i++
+			continue
+		}
+
+		var op rune
+		for i < len(runes) && strings.ContainsRune("+-~<>@", runes[i]) {
+			if runes[i] == '+' || runes[i] == '-' {
+				op = runes[i]
+			}
+			i++
+		}
+		if i >= len(runes) {
+			break
+		}
+
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			phrase := strings.Join(strings.Fields(string(runes[i+1:end])), " ")
+			if phrase != "" {
+				terms = append(terms, search.Term{Text: phrase, Op: op, Phrase: true})
+			}
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+			end++
+		}
+		word := string(runes[i:end])
+		wildcard := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word != "" {
+			terms = append(terms, search.Term{Text: word, Op: op, Wildcard: wildcard})
+		}
+		i = end
+	}
+	return terms
+}
+
+// highlightSnippet 截取内容中第一个命中位置附近 length 个字符作为摘要，对摘要做 HTML 转义，
+// 命中的词语（不区分大小写）以 <em> 标记；没有命中时取内容开头
+func highlightSnippet(content string, terms []string, length int) string {
+	runes := []rune(content)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		if r == '\n' || r == '\r' || r == '\t' {
+			runes[i] = ' '
+		}
+		lower[i] = unicode.ToLower(runes[i])
+	}
+
+	patterns := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		if term != "" {
+			patterns = append(patterns, []rune(strings.ToLower(term)))
+		}
+	}
+	// 优先匹配较长的词语
+	sort.Slice(patterns, func(i, j int) bool { return len(patterns[i]) > len(patterns[j]) })
+
+	type span struct{ start, end int }
+	var spans []span
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, pattern := range patterns {
+			if hasRunePrefix(lower[i:], pattern) {
+				matched = len(pattern)
+				break
+			}
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		spans = append(spans, span{i, i + matched})
+		i += matched
+	}
+
+	start := 0
+	if len(spans) > 0 {
+		start = spans[0].start - length/4
+	}
+	end := start + length
+	if end > len(runes) {
+		end = len(runes)
+		start = end - length
+	}
+	if start < 0 {
+		start = 0
+	}
+	// 不截断摘要末尾命中的词语
+	for _, sp := range spans {
+		if sp.start < end && sp.end > end {
+			end = sp.end
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, sp := range spans {
+		if sp.end <= start || sp.start >= end {
+			continue
+		}
+		s, e := sp.start, sp.end
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:s])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[s:e])))
+		b.WriteString("</em>")
+		pos = e
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// hasRunePrefix 判断 s 是否以 prefix 开头
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/segment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	appLogger "sentinel-opinion-monitor/internal/pkg/logger"
+	"sentinel-opinion-monitor/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// segmenterReloadInterval 用户词典（监测组关键词/排除词）的刷新间隔
+	segmenterReloadInterval = time.Minute
+	// defaultKeywordTopK 默认每条舆情保存的关键词数量
+	defaultKeywordTopK = 10
+)
+
+// SegmentService 分词服务接口
+type SegmentService interface {
+	GetSegmenter() (*segment.Segmenter, error)
+	GetSegmenterWith(words []string) (*segment.Segmenter, error)
+	Cut(text string) ([]string, error)
+	ExtractKeywords(text string) (model.TermList, error)
+}
+
+type segmentService struct {
+	groupRepo repository.MonitoringGroupRepository
+	cfg       config.SegmentConfig
+
+	dictOnce sync.Once
+	dict     *segment.Dictionary
+
+	mu        sync.Mutex
+	segmenter *segment.Segmenter
+	loadedAt  time.Time
+}
+
+// NewSegmentService 创建分词服务实例
+func NewSegmentService(groupRepo repository.MonitoringGroupRepository, cfg *config.SegmentConfig) SegmentService {
+	s := &segmentService{groupRepo: groupRepo}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.KeywordTopK <= 0 {
+		s.cfg.KeywordTopK = defaultKeywordTopK
+	}
+	return s
+}
+
+// GetSegmenter 获取分词器，用户词典由所有监测组关键词和排除词自动构建并定期刷新
+func (s *segmentService) GetSegmenter() (*segment.Segmenter, error) {
+	dict := s.dictionary()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.segmenter != nil && time.Since(s.loadedAt) < segmenterReloadInterval {
+		return s.segmenter, nil
+	}
+
+	terms, err := s.groupRepo.GetAllTerms()
+	if err != nil {
+		if s.segmenter != nil {
+			// 刷新失败时继续使用旧的分词器
+			appLogger.Get().Warn("刷新分词用户词典失败", zap.Error(err))
+			return s.segmenter, nil
+		}
+		return nil, err
+	}
+
+	s.segmenter = segment.NewSegmenter(dict, terms)
+	s.loadedAt = time.Now()
+	return s.segmenter, nil
+}
+
+// GetSegmenterWith 在所有监测组关键词和排除词之外加入额外的用户词创建分词器（不缓存），
+// 用于试算尚未保存的关键词和排除词
+func (s *segmentService) GetSegmenterWith(words []string) (*segment.Segmenter, error) {
+	dict := s.dictionary()
+	terms, err := s.groupRepo.GetAllTerms()
+	if err != nil {
+		return nil, err
+	}
+	return segment.NewSegmenter(dict, append(terms, words...)), nil
+}
+
+// dictionary 获取基础词典，配置了词典文件时首次调用加载，加载失败时使用内置词典
+func (s *segmentService) dictionary() *segment.Dictionary {
+	s.dictOnce.Do(func() {
+		s.dict = segment.DefaultDictionary()
+		if s.cfg.DictPath != "" {
+			dict, err := segment.LoadDictionaryFile(s.cfg.DictPath)
+			if err != nil {
+				appLogger.Get().Warn("加载分词词典失败，使用内置词典", zap.String("path", s.cfg.DictPath), zap.Error(err))
+				return
+			}
+			s.dict = dict
+		}
+	})
+	return s.dict
+}
+
+// Cut 分词
+func (s *segmentService) Cut(text string) ([]string, error) {
+	segmenter, err := s.GetSegmenter()
+	if err != nil {
+		return nil, err
+	}
+	return segmenter.Cut(text), nil
+}
+
+// ExtractKeywords 按配置的方法提取关键词
+func (s *segmentService) ExtractKeywords(text string) (model.TermList, error) {
+	segmenter, err := s.GetSegmenter()
+	if err != nil {
+		return nil, err
+	}
+
+	keywords := segmenter.Extract(text, s.cfg.KeywordTopK, s.cfg.KeywordMethod)
+	terms := make(model.TermList, 0, len(keywords))
+	for _, k := range keywords {
+		terms = append(terms, model.WeightedTerm{Word: k.Word, Weight: k.Weight})
+	}
+	return terms, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// SentimentService 情感分析服务接口
+type SentimentService interface {
+	CreateLexiconEntry(scenarioID uint64, word, wordType string, weight float64) (*model.SentimentLexicon, error)
+	GetLexiconEntries(scenarioID uint64) ([]*model.SentimentLexicon, error)
+	UpdateLexiconEntry(id uint64, wordType string, weight *float64) error
+	DeleteLexiconEntry(id uint64) error
+	GetAnalyzer(scenarioID uint64) (*sentiment.Analyzer, error)
+	Analyze(scenarioID uint64, text string) (*sentiment.Result, error)
+}
+
+type sentimentService struct {
+	lexiconRepo  repository.SentimentLexiconRepository
+	scenarioRepo repository.ScenarioRepository
+}
+
+// NewSentimentService 创建情感分析服务实例
+func NewSentimentService(lexiconRepo repository.SentimentLexiconRepository, scenarioRepo repository.ScenarioRepository) SentimentService {
+	return &sentimentService{
+		lexiconRepo:  lexiconRepo,
+		scenarioRepo: scenarioRepo,
+	}
+}
+
+// CreateLexiconEntry 创建自定义词条，scenarioID 为 0 表示全局词条
+func (s *sentimentService) CreateLexiconEntry(scenarioID uint64, word, wordType string, weight float64) (*model.SentimentLexicon, error) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return nil, errors.New("词语不能为空")
+	}
+	if wordType == "" {
+		wordType = string(sentiment.WordTypeSentiment)
+	}
+	if err := validateLexiconEntry(wordType, weight); err != nil {
+		return nil, err
+	}
+
+	if scenarioID > 0 {
+		if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+			return nil, errors.New("场景不存在")
+		}
+	}
+
+	if _, err := s.lexiconRepo.GetByScenarioAndWord(scenarioID, word); err == nil {
+		return nil, errors.New("词条已存在")
+	}
+
+	entry := &model.SentimentLexicon{
+		ScenarioID: scenarioID,
+		Word:       word,
+		Type:       wordType,
+		Weight:     weight,
+	}
+	if err := s.lexiconRepo.Create(entry); err != nil {
+		return nil, errors.New("创建词条失败")
+	}
+	return entry, nil
+}
+
+// GetLexiconEntries 获取指定范围的自定义词条
+func (s *sentimentService) GetLexiconEntries(scenarioID uint64) ([]*model.SentimentLexicon, error) {
+	return s.lexiconRepo.GetByScenarioID(scenarioID)
+}
+
+// UpdateLexiconEntry 更新自定义词条
+func (s *sentimentService) UpdateLexiconEntry(id uint64, wordType string, weight *float64) error {
+	entry, err := s.lexiconRepo.GetByID(id)
+	if err != nil {
+		return errors.New("词条不存在")
+	}
+
+	if wordType != "" {
+		entry.Type = wordType
+	}
+	if weight != nil {
+		entry.Weight = *weight
+	}
+	if err := validateLexiconEntry(entry.Type, entry.Weight); err != nil {
+		return err
+	}
+
+	return s.lexiconRepo.Update(entry)
+}
+
+// DeleteLexiconEntry 删除自定义词条
+func (s *sentimentService) DeleteLexiconEntry(id uint64) error {
+	return s.lexiconRepo.Delete(id)
+}
+
+// GetAnalyzer 获取场景的情感分析器：内置词典 + 全局自定义词条 + 场景自定义词条
+func (s *sentimentService) GetAnalyzer(scenarioID uint64) (*sentiment.Analyzer, error) {
+	entries, err := s.lexiconRepo.GetEffective(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	lexicon := sentiment.DefaultLexicon()
+	for _, entry := range entries {
+		lexicon.Set(entry.Word, sentiment.WordType(entry.Type), entry.Weight)
+	}
+	return sentiment.NewAnalyzer(lexicon), nil
+}
+
+// Analyze 使用场景词典分析文本情感，scenarioID 为 0 时使用全局词典
+func (s *sentimentService) Analyze(scenarioID uint64, text string) (*sentiment.Result, error) {
+	analyzer, err := s.GetAnalyzer(scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	result := analyzer.Analyze(text)
+	return &result, nil
+}
+
+// validateLexiconEntry 校验词条类型和权重
+func validateLexiconEntry(wordType string, weight float64) error {
+	switch sentiment.WordType(wordType) {
+	case sentiment.WordTypeSentiment:
+		if weight < -5 || weight > 5 {
+			return errors.New("情感词权重必须在-5到5之间")
+		}
+	case sentiment.WordTypeNegation:
+	case sentiment.WordTypeDegree:
+		if weight <= 0 || weight > 5 {
+			return errors.New("程度副词倍数必须大于0且不超过5")
+		}
+	default:
+		return errors.New("无效的词条类型，可选值: sentiment, negation, degree")
+	}
+	return nil
+}
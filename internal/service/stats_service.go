@@ -0,0 +1,535 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/sentiment"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+// 统计时间粒度
+const (
+	StatsIntervalHour = "hour"
+	StatsIntervalDay  = "day"
+)
+
+const (
+	maxHourBuckets = 24 * 31 // 按小时统计最多 31 天
+	maxDayBuckets  = 366     // 按天统计最多 366 天
+
+	// MaxComparisonScenarios 一次对比最多包含的场景数
+	MaxComparisonScenarios = 10
+)
+
+// StatsQuery 场景统计查询条件，时间为零值时使用默认范围（按小时最近 24 小时，按天最近 30 天）
+type StatsQuery struct {
+	Start    time.Time
+	End      time.Time
+	Interval string // hour（默认）或 day
+	Top      int    // 作者和关键词排行的数量，默认 10
+}
+
+// StatsPoint 时间序列中的一个桶
+type StatsPoint struct {
+	Time  time.Time `json:"time"`
+	Count int64     `json:"count"`
+}
+
+// StatsItem 维度取值的舆情数
+type StatsItem struct {
+	Key   string  `json:"key"`
+	Name  string  `json:"name"`
+	Count int64   `json:"count"`
+	Ratio float64 `json:"ratio"` // 占场景总声量的比例
+}
+
+// StatsChange 与上一周期相比的变化
+type StatsChange struct {
+	Current    int64    `json:"current"`
+	Previous   int64    `json:"previous"`
+	Change     int64    `json:"change"`
+	ChangeRate *float64 `json:"change_rate"` // 上一周期为 0 时为 null
+}
+
+// StatsComparison 环比（与紧邻的等长上一周期比较）
+type StatsComparison struct {
+	PreviousStart time.Time    `json:"previous_start"`
+	PreviousEnd   time.Time    `json:"previous_end"`
+	Total         *StatsChange `json:"total"`
+	Negative      *StatsChange `json:"negative"`
+}
+
+// ScenarioStats 场景统计看板数据
+type ScenarioStats struct {
+	ScenarioID  uint64           `json:"scenario_id"`
+	Interval    string           `json:"interval"`
+	StartTime   time.Time        `json:"start_time"`
+	EndTime     time.Time        `json:"end_time"`
+	Total       int64            `json:"total"`
+	Series      []*StatsPoint    `json:"series"`
+	ByChannel   []*StatsItem     `json:"by_channel"`
+	ByGroup     []*StatsItem     `json:"by_group"`
+	BySentiment []*StatsItem     `json:"by_sentiment"`
+	TopAuthors  []*StatsItem     `json:"top_authors"`
+	TopKeywords []*StatsItem     `json:"top_keywords"`
+	Comparison  *StatsComparison `json:"comparison"`
+}
+
+// SentimentMix 情感分布
+type SentimentMix struct {
+	Positive      int64   `json:"positive"`
+	Neutral       int64   `json:"neutral"`
+	Negative      int64   `json:"negative"`
+	NegativeRatio float64 `json:"negative_ratio"` // 负面占比
+	NetSentiment  float64 `json:"net_sentiment"`  // 净情感 (正面-负面)/总声量，取值 -1~1
+}
+
+// ScenarioVoice 对比中单个场景的声量数据
+type ScenarioVoice struct {
+	ScenarioID   uint64        `json:"scenario_id"`
+	Name         string        `json:"name"`
+	TagCode      string        `json:"tag_code"`
+	TagName      string        `json:"tag_name"`
+	Total        int64         `json:"total"`
+	ShareOfVoice float64       `json:"share_of_voice"` // 占所有对比场景声量之和的比例
+	Sentiment    *SentimentMix `json:"sentiment"`
+	ByChannel    []*StatsItem  `json:"by_channel"` // Ratio 为占该场景声量的比例
+	Series       []*StatsPoint `json:"series"`
+	Change       *StatsChange  `json:"change"` // 声量环比
+}
+
+// ScenarioComparison 多场景对比数据
+type ScenarioComparison struct {
+	Interval           string           `json:"interval"`
+	StartTime          time.Time        `json:"start_time"`
+	EndTime            time.Time        `json:"end_time"`
+	Total              int64            `json:"total"`
+	Scenarios          []*ScenarioVoice `json:"scenarios"`
+	MissingScenarioIDs []uint64         `json:"missing_scenario_ids,omitempty"` // 已删除的场景
+}
+
+// StatsService 场景统计服务接口
+type StatsService interface {
+	Rollup(scenarioID uint64, now time.Time) (int, error)
+	RollupRange(scenarioID uint64, start, end time.Time) (int, error)
+	GetScenarioStats(scenarioID uint64, query StatsQuery) (*ScenarioStats, error)
+	CompareScenarios(scenarioIDs []uint64, query StatsQuery) (*ScenarioComparison, error)
+}
+
+type statsService struct {
+	cfg          config.StatsConfig
+	statsRepo    repository.StatsRepository
+	scenarioRepo repository.ScenarioRepository
+	groupRepo    repository.MonitoringGroupRepository
+	channelRepo  repository.ChannelRepository
+}
+
+// NewStatsService 创建场景统计服务实例
+func NewStatsService(
+	cfg *config.StatsConfig,
+	statsRepo repository.StatsRepository,
+	scenarioRepo repository.ScenarioRepository,
+	groupRepo repository.MonitoringGroupRepository,
+	channelRepo repository.ChannelRepository,
+) StatsService {
+	s := &statsService{
+		statsRepo:    statsRepo,
+		scenarioRepo: scenarioRepo,
+		groupRepo:    groupRepo,
+		channelRepo:  channelRepo,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.RollupLookbackHours <= 0 {
+		s.cfg.RollupLookbackHours = 48
+	}
+	if s.cfg.BackfillDays <= 0 {
+		s.cfg.BackfillDays = 30
+	}
+	return s
+}
+
+// Rollup 重算场景最近 RollupLookbackHours 小时（首次汇总时为 BackfillDays 天）的小时汇总，返回写入的行数
+func (s *statsService) Rollup(scenarioID uint64, now time.Time) (int, error) {
+	end := truncateHour(now).Add(time.Hour)
+	start := end.Add(-time.Duration(s.cfg.RollupLookbackHours) * time.Hour)
+
+	latest, err := s.statsRepo.LatestBucket(scenarioID)
+	if err != nil {
+		return 0, err
+	}
+	if latest == nil {
+		start = end.AddDate(0, 0, -s.cfg.BackfillDays)
+	}
+
+	return s.replaceRange(scenarioID, start, end)
+}
+
+// RollupRange 重算场景从 start 所在小时到 end 所在小时（含）的小时汇总，返回写入的行数
+// 用于导入历史舆情后补算超出 RollupLookbackHours 的时间段；场景还没有汇总过时先按首次汇总回溯
+func (s *statsService) RollupRange(scenarioID uint64, start, end time.Time) (int, error) {
+	latest, err := s.statsRepo.LatestBucket(scenarioID)
+	if err != nil {
+		return 0, err
+	}
+	if latest == nil {
+		if _, err := s.Rollup(scenarioID, time.Now()); err != nil {
+			return 0, err
+		}
+	}
+	return s.replaceRange(scenarioID, truncateHour(start), truncateHour(end).Add(time.Hour))
+}
+
+// replaceRange 从命中记录重新计算 [start, end) 内的小时汇总并替换原有数据
+func (s *statsService) replaceRange(scenarioID uint64, start, end time.Time) (int, error) {
+	rollups, err := s.statsRepo.Aggregate(scenarioID, start, end)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.statsRepo.ReplaceRange(scenarioID, start, end, rollups); err != nil {
+		return 0, err
+	}
+	return len(rollups), nil
+}
+
+// GetScenarioStats 从小时汇总表读取场景统计看板数据
+func (s *statsService) GetScenarioStats(scenarioID uint64, query StatsQuery) (*ScenarioStats, error) {
+	if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, errors.New("场景不存在")
+	}
+	start, end, err := normalizeStatsRange(&query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	daily := query.Interval == StatsIntervalDay
+
+	total, err := s.statsRepo.Sum(scenarioID, model.StatDimTotal, "", start, end)
+	if err != nil {
+		return nil, errors.New("获取统计数据失败")
+	}
+	series, err := s.statsRepo.Series(scenarioID, model.StatDimTotal, "", start, end, daily)
+	if err != nil {
+		return nil, errors.New("获取统计数据失败")
+	}
+
+	stats := &ScenarioStats{
+		ScenarioID: scenarioID,
+		Interval:   query.Interval,
+		StartTime:  start,
+		EndTime:    end,
+		Total:      total,
+		Series:     fillStatsSeries(series, start, end, daily),
+	}
+
+	breakdowns := []struct {
+		dimension string
+		limit     int
+		target    *[]*StatsItem
+		name      func(key string) string
+	}{
+		{model.StatDimChannel, 0, &stats.ByChannel, s.channelNamer()},
+		{model.StatDimGroup, 0, &stats.ByGroup, s.groupNamer(scenarioID)},
+		{model.StatDimSentiment, 0, &stats.BySentiment, nil},
+		{model.StatDimAuthor, query.Top, &stats.TopAuthors, nil},
+		{model.StatDimKeyword, query.Top, &stats.TopKeywords, nil},
+	}
+	for _, b := range breakdowns {
+		rows, err := s.statsRepo.Breakdown(scenarioID, b.dimension, start, end, b.limit)
+		if err != nil {
+			return nil, errors.New("获取统计数据失败")
+		}
+		items := make([]*StatsItem, 0, len(rows))
+		for _, row := range rows {
+			item := &StatsItem{Key: row.DimKey, Name: row.DimKey, Count: row.Count}
+			if b.name != nil {
+				item.Name = b.name(row.DimKey)
+			}
+			if total > 0 {
+				item.Ratio = float64(row.Count) / float64(total)
+			}
+			items = append(items, item)
+		}
+		*b.target = items
+	}
+
+	comparison, err := s.compare(scenarioID, start, end, total)
+	if err != nil {
+		return nil, errors.New("获取统计数据失败")
+	}
+	stats.Comparison = comparison
+	return stats, nil
+}
+
+// CompareScenarios 从小时汇总表读取多个场景的声量份额、情感分布、渠道构成和走势，
+// 已删除的场景记入 MissingScenarioIDs
+func (s *statsService) CompareScenarios(scenarioIDs []uint64, query StatsQuery) (*ScenarioComparison, error) {
+	scenarioIDs = uniqueIDs(scenarioIDs)
+	if len(scenarioIDs) < 2 || len(scenarioIDs) > MaxComparisonScenarios {
+		return nil, fmt.Errorf("对比的场景数必须在 2~%d 之间", MaxComparisonScenarios)
+	}
+	start, end, err := normalizeStatsRange(&query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	daily := query.Interval == StatsIntervalDay
+
+	scenarios, err := s.scenarioRepo.GetAll()
+	if err != nil {
+		return nil, errors.New("获取场景失败")
+	}
+	byID := make(map[uint64]*model.Scenario, len(scenarios))
+	for _, scenario := range scenarios {
+		byID[scenario.ID] = scenario
+	}
+
+	comparison := &ScenarioComparison{
+		Interval:  query.Interval,
+		StartTime: start,
+		EndTime:   end,
+		Scenarios: make([]*ScenarioVoice, 0, len(scenarioIDs)),
+	}
+	channelName := s.channelNamer()
+	for _, id := range scenarioIDs {
+		scenario, ok := byID[id]
+		if !ok {
+			comparison.MissingScenarioIDs = append(comparison.MissingScenarioIDs, id)
+			continue
+		}
+		voice, err := s.scenarioVoice(scenario, start, end, daily, channelName)
+		if err != nil {
+			return nil, errors.New("获取统计数据失败")
+		}
+		comparison.Total += voice.Total
+		comparison.Scenarios = append(comparison.Scenarios, voice)
+	}
+	if len(comparison.Scenarios) == 0 {
+		return nil, errors.New("对比的场景不存在")
+	}
+
+	if comparison.Total > 0 {
+		for _, voice := range comparison.Scenarios {
+			voice.ShareOfVoice = float64(voice.Total) / float64(comparison.Total)
+		}
+	}
+	return comparison, nil
+}
+
+// scenarioVoice 读取单个场景在 [start, end) 内的声量、情感、渠道、走势和环比
+func (s *statsService) scenarioVoice(scenario *model.Scenario, start, end time.Time, daily bool, channelName func(string) string) (*ScenarioVoice, error) {
+	voice := &ScenarioVoice{
+		ScenarioID: scenario.ID,
+		Name:       scenario.Name,
+		TagCode:    scenario.Tag.Code,
+		TagName:    scenario.Tag.Name,
+		Sentiment:  &SentimentMix{},
+	}
+
+	total, err := s.statsRepo.Sum(scenario.ID, model.StatDimTotal, "", start, end)
+	if err != nil {
+		return nil, err
+	}
+	voice.Total = total
+
+	series, err := s.statsRepo.Series(scenario.ID, model.StatDimTotal, "", start, end, daily)
+	if err != nil {
+		return nil, err
+	}
+	voice.Series = fillStatsSeries(series, start, end, daily)
+
+	prevStart := start.Add(-end.Sub(start))
+	prevTotal, err := s.statsRepo.Sum(scenario.ID, model.StatDimTotal, "", prevStart, start)
+	if err != nil {
+		return nil, err
+	}
+	voice.Change = newStatsChange(total, prevTotal)
+
+	sentiments, err := s.statsRepo.Breakdown(scenario.ID, model.StatDimSentiment, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range sentiments {
+		switch sentiment.Label(row.DimKey) {
+		case sentiment.LabelPositive:
+			voice.Sentiment.Positive += row.Count
+		case sentiment.LabelNegative:
+			voice.Sentiment.Negative += row.Count
+		default:
+			voice.Sentiment.Neutral += row.Count
+		}
+	}
+	if total > 0 {
+		voice.Sentiment.NegativeRatio = float64(voice.Sentiment.Negative) / float64(total)
+		voice.Sentiment.NetSentiment = float64(voice.Sentiment.Positive-voice.Sentiment.Negative) / float64(total)
+	}
+
+	channels, err := s.statsRepo.Breakdown(scenario.ID, model.StatDimChannel, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	voice.ByChannel = make([]*StatsItem, 0, len(channels))
+	for _, row := range channels {
+		item := &StatsItem{Key: row.DimKey, Name: channelName(row.DimKey), Count: row.Count}
+		if total > 0 {
+			item.Ratio = float64(row.Count) / float64(total)
+		}
+		voice.ByChannel = append(voice.ByChannel, item)
+	}
+	return voice, nil
+}
+
+// compare 计算总声量和负面声量相对上一周期的变化
+func (s *statsService) compare(scenarioID uint64, start, end time.Time, total int64) (*StatsComparison, error) {
+	prevStart := start.Add(-end.Sub(start))
+	prevTotal, err := s.statsRepo.Sum(scenarioID, model.StatDimTotal, "", prevStart, start)
+	if err != nil {
+		return nil, err
+	}
+	negative, err := s.statsRepo.Sum(scenarioID, model.StatDimSentiment, string(sentiment.LabelNegative), start, end)
+	if err != nil {
+		return nil, err
+	}
+	prevNegative, err := s.statsRepo.Sum(scenarioID, model.StatDimSentiment, string(sentiment.LabelNegative), prevStart, start)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatsComparison{
+		PreviousStart: prevStart,
+		PreviousEnd:   start,
+		Total:         newStatsChange(total, prevTotal),
+		Negative:      newStatsChange(negative, prevNegative),
+	}, nil
+}
+
+// channelNamer 将舆情来源映射为渠道名称，来源不是已登记渠道的代码或名称时原样返回
+func (s *statsService) channelNamer() func(string) string {
+	names := make(map[string]string)
+	if channels, err := s.channelRepo.GetAll(); err == nil {
+		for _, channel := range channels {
+			names[strings.ToLower(channel.Code)] = channel.Name
+			names[strings.ToLower(channel.Name)] = channel.Name
+		}
+	}
+	return func(source string) string {
+		if name, ok := names[strings.ToLower(source)]; ok {
+			return name
+		}
+		return source
+	}
+}
+
+// groupNamer 将监测组ID映射为名称，监测组已删除时返回空字符串
+func (s *statsService) groupNamer(scenarioID uint64) func(string) string {
+	names := make(map[string]string)
+	if groups, err := s.groupRepo.GetByScenarioID(scenarioID); err == nil {
+		for _, group := range groups {
+			names[strconv.FormatUint(group.ID, 10)] = group.Name
+		}
+	}
+	return func(key string) string {
+		return names[key]
+	}
+}
+
+// normalizeStatsRange 校验查询条件，填充默认值并把时间范围对齐到桶边界
+func normalizeStatsRange(query *StatsQuery, now time.Time) (time.Time, time.Time, error) {
+	if query.Interval == "" {
+		query.Interval = StatsIntervalHour
+	}
+	if query.Interval != StatsIntervalHour && query.Interval != StatsIntervalDay {
+		return time.Time{}, time.Time{}, errors.New("无效的统计粒度，可选值: hour, day")
+	}
+	if query.Top <= 0 {
+		query.Top = 10
+	}
+	if query.Top > 100 {
+		query.Top = 100
+	}
+	daily := query.Interval == StatsIntervalDay
+
+	end := query.End
+	if end.IsZero() {
+		end = now
+	}
+	start := query.Start
+	if start.IsZero() {
+		if daily {
+			start = end.AddDate(0, 0, -30)
+		} else {
+			start = end.Add(-24 * time.Hour)
+		}
+	}
+
+	start = truncateBucket(start, daily)
+	if aligned := truncateBucket(end, daily); !aligned.Equal(end) {
+		end = nextBucket(aligned, daily)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("开始时间必须早于结束时间")
+	}
+
+	buckets := 0
+	for t := start; t.Before(end); t = nextBucket(t, daily) {
+		buckets++
+	}
+	if !daily && buckets > maxHourBuckets {
+		return time.Time{}, time.Time{}, errors.New("按小时统计的时间范围不能超过 31 天")
+	}
+	if daily && buckets > maxDayBuckets {
+		return time.Time{}, time.Time{}, errors.New("按天统计的时间范围不能超过 366 天")
+	}
+	return start, end, nil
+}
+
+// fillStatsSeries 补齐没有数据的桶
+func fillStatsSeries(series []*repository.BucketCount, start, end time.Time, daily bool) []*StatsPoint {
+	counts := make(map[int64]int64, len(series))
+	for _, point := range series {
+		counts[point.Bucket.Unix()] = point.Count
+	}
+	var points []*StatsPoint
+	for t := start; t.Before(end); t = nextBucket(t, daily) {
+		points = append(points, &StatsPoint{Time: t, Count: counts[t.Unix()]})
+	}
+	return points
+}
+
+// newStatsChange 计算变化量和变化率
+func newStatsChange(current, previous int64) *StatsChange {
+	change := &StatsChange{Current: current, Previous: previous, Change: current - previous}
+	if previous > 0 {
+		rate := float64(current-previous) / float64(previous)
+		change.ChangeRate = &rate
+	}
+	return change
+}
+
+// truncateBucket 对齐到所在小时或所在日的开始（本地时区）
+func truncateBucket(t time.Time, daily bool) time.Time {
+	if daily {
+		t = t.In(time.Local)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+	return truncateHour(t)
+}
+
+// nextBucket 下一个桶的开始时间
+func nextBucket(t time.Time, daily bool) time.Time {
+	if daily {
+		return t.AddDate(0, 0, 1)
+	}
+	return t.Add(time.Hour)
+}
+
+// truncateHour 对齐到所在小时的开始（本地时区）
+func truncateHour(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/pkg/redis"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+const (
+	// streamTicketPrefix 推送连接票据在 Redis 中的键前缀
+	streamTicketPrefix = "stream:ticket:"
+	// streamTicketDefaultTTL 未配置时推送连接票据的有效期
+	streamTicketDefaultTTL = 30 * time.Second
+)
+
+// StreamTicket 推送连接票据兑换出的用户信息
+type StreamTicket struct {
+	UserID   uint64   `json:"user_id"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+// StreamTicketService 实时推送连接票据服务
+// 浏览器的 EventSource 和 WebSocket 无法设置请求头，连接前先用 JWT 换取短期票据，
+// 再通过查询参数携带票据建立连接，避免长期有效的 JWT 出现在地址和访问日志中
+type StreamTicketService interface {
+	Issue(ticket *StreamTicket) (string, time.Duration, error)
+	Redeem(ticket string) (*StreamTicket, error)
+}
+
+type streamTicketService struct {
+	ttl time.Duration
+}
+
+// NewStreamTicketService 创建实时推送连接票据服务实例
+func NewStreamTicketService(cfg *config.StreamConfig) StreamTicketService {
+	ttl := streamTicketDefaultTTL
+	if cfg != nil && cfg.TicketTTLSeconds > 0 {
+		ttl = time.Duration(cfg.TicketTTLSeconds) * time.Second
+	}
+	return &streamTicketService{ttl: ttl}
+}
+
+// Issue 为当前用户签发一次性票据，返回票据和有效期
+func (s *streamTicketService) Issue(ticket *StreamTicket) (string, time.Duration, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return "", 0, errors.New("实时推送未启用")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", 0, err
+	}
+	value, err := json.Marshal(ticket)
+	if err != nil {
+		return "", 0, err
+	}
+
+	token := hex.EncodeToString(buf)
+	if err := client.Set(redis.GetContext(), streamTicketPrefix+token, value, s.ttl).Err(); err != nil {
+		return "", 0, err
+	}
+	return token, s.ttl, nil
+}
+
+// Redeem 兑换票据，票据只能使用一次，过期或已使用的票据返回错误
+func (s *streamTicketService) Redeem(ticket string) (*StreamTicket, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return nil, errors.New("实时推送未启用")
+	}
+
+	// 读取和删除在同一事务中执行，同一票据并发兑换时只有一个成功
+	ctx := redis.GetContext()
+	key := streamTicketPrefix + ticket
+	pipe := client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	value, err := get.Bytes()
+	if err != nil {
+		return nil, errors.New("票据无效或已过期")
+	}
+	var result StreamTicket
+	if err := json.Unmarshal(value, &result); err != nil {
+		return nil, errors.New("票据无效或已过期")
+	}
+	return &result, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"sentinel-opinion-monitor/internal/analysis/trending"
+	"sentinel-opinion-monitor/internal/config"
+	"sentinel-opinion-monitor/internal/model"
+	"sentinel-opinion-monitor/internal/repository"
+)
+
+const (
+	// topicMaxTerms 每个话题保留的中心词数量
+	topicMaxTerms = 5
+	// topicLabelTerms 话题名称使用的中心词数量
+	topicLabelTerms = 3
+	// topicRepresentatives 每个话题保留的代表性舆情数量
+	topicRepresentatives = 3
+	// maxTopics 每个场景保留的话题数量
+	maxTopics = 50
+)
+
+// TrendingService 热词和话题服务接口
+type TrendingService interface {
+	RefreshScenario(scenarioID uint64, now time.Time) error
+	GetTrendingTerms(scenarioID uint64, limit int) ([]*model.TrendingTerm, error)
+	GetTopics(scenarioID uint64, limit int) ([]*model.OpinionTopic, error)
+}
+
+type trendingService struct {
+	cfg            config.TrendingConfig
+	trendingRepo   repository.TrendingRepository
+	hitRepo        repository.OpinionHitRepository
+	opinionRepo    repository.OpinionRepository
+	scenarioRepo   repository.ScenarioRepository
+	segmentService SegmentService
+}
+
+// NewTrendingService 创建热词和话题服务实例
+func NewTrendingService(
+	cfg *config.TrendingConfig,
+	trendingRepo repository.TrendingRepository,
+	hitRepo repository.OpinionHitRepository,
+	opinionRepo repository.OpinionRepository,
+	scenarioRepo repository.ScenarioRepository,
+	segmentService SegmentService,
+) TrendingService {
+	s := &trendingService{
+		trendingRepo:   trendingRepo,
+		hitRepo:        hitRepo,
+		opinionRepo:    opinionRepo,
+		scenarioRepo:   scenarioRepo,
+		segmentService: segmentService,
+	}
+	if cfg != nil {
+		s.cfg = *cfg
+	}
+	if s.cfg.WindowMinutes <= 0 {
+		s.cfg.WindowMinutes = 60
+	}
+	if s.cfg.BaselineWindows <= 0 {
+		s.cfg.BaselineWindows = 24
+	}
+	if s.cfg.MinCount <= 0 {
+		s.cfg.MinCount = 3
+	}
+	if s.cfg.TopK <= 0 {
+		s.cfg.TopK = 50
+	}
+	if s.cfg.TopicWindowMinutes <= 0 {
+		s.cfg.TopicWindowMinutes = 360
+	}
+	if s.cfg.TopicThreshold <= 0 {
+		s.cfg.TopicThreshold = 0.3
+	}
+	if s.cfg.TopicMinSize <= 0 {
+		s.cfg.TopicMinSize = 2
+	}
+	return s
+}
+
+// RefreshScenario 重新计算场景热词和话题：
+// 以 window_minutes 为窗口统计出现各词的舆情数，与之前 baseline_windows 个窗口比较得到突发词；
+// 对 topic_window_minutes 内的舆情按关键词聚类得到话题，话题热度为规模加上其中心词的突发得分
+func (s *trendingService) RefreshScenario(scenarioID uint64, now time.Time) error {
+	window := time.Duration(s.cfg.WindowMinutes) * time.Minute
+	topicWindow := time.Duration(s.cfg.TopicWindowMinutes) * time.Minute
+
+	span := window * time.Duration(s.cfg.BaselineWindows+1)
+	if topicWindow > span {
+		span = topicWindow
+	}
+
+	docs, err := s.hitRepo.GetDocuments(scenarioID, 0, now.Add(-span), now)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]int)
+	baseline := make([]map[string]int, s.cfg.BaselineWindows)
+	for i := range baseline {
+		baseline[i] = make(map[string]int)
+	}
+
+	topicStart := now.Add(-topicWindow)
+	topicDocs := make([]trending.Document, 0)
+	sentiments := make(map[uint64]float64)
+	for _, doc := range docs {
+		terms, err := s.documentTerms(doc)
+		if err != nil {
+			return err
+		}
+
+		idx := int(now.Sub(doc.CreatedAt) / window)
+		var counts map[string]int
+		if idx == 0 {
+			counts = current
+		} else if idx <= len(baseline) {
+			counts = baseline[idx-1]
+		}
+		if counts != nil {
+			for term := range terms {
+				counts[term]++
+			}
+		}
+
+		if !doc.CreatedAt.Before(topicStart) {
+			topicDocs = append(topicDocs, trending.Document{ID: doc.OpinionID, Terms: terms})
+			sentiments[doc.OpinionID] = doc.SentimentScore
+		}
+	}
+
+	windowStart := now.Add(-window)
+	bursts := trending.DetectBursts(current, baseline, s.cfg.MinCount)
+	burstScores := make(map[string]float64, len(bursts))
+	terms := make([]*model.TrendingTerm, 0, s.cfg.TopK)
+	for i, burst := range bursts {
+		burstScores[burst.Term] = burst.Score
+		if i >= s.cfg.TopK {
+			continue
+		}
+		terms = append(terms, &model.TrendingTerm{
+			ScenarioID:   scenarioID,
+			Term:         burst.Term,
+			Count:        burst.Count,
+			BaselineMean: burst.Mean,
+			BaselineStd:  burst.StdDev,
+			Score:        burst.Score,
+			WindowStart:  windowStart,
+			WindowEnd:    now,
+		})
+	}
+
+	clusters := trending.ClusterDocuments(topicDocs, trending.ClusterOptions{
+		Threshold: s.cfg.TopicThreshold,
+		MinSize:   s.cfg.TopicMinSize,
+		MaxTerms:  topicMaxTerms,
+	})
+	topics := make([]*model.OpinionTopic, 0, len(clusters))
+	for _, cluster := range clusters {
+		topics = append(topics, buildTopic(scenarioID, cluster, burstScores, sentiments, topicStart, now))
+	}
+	sortTopics(topics)
+	if len(topics) > maxTopics {
+		topics = topics[:maxTopics]
+	}
+
+	if err := s.trendingRepo.ReplaceTerms(scenarioID, terms); err != nil {
+		return err
+	}
+	return s.trendingRepo.ReplaceTopics(scenarioID, topics)
+}
+
+// documentTerms 获取舆情关键词向量，未提取过关键词的舆情即时提取
+func (s *trendingService) documentTerms(doc *repository.HitDocument) (map[string]float64, error) {
+	keywords := doc.Keywords
+	if len(keywords) == 0 {
+		extracted, err := s.segmentService.ExtractKeywords(doc.Content)
+		if err != nil {
+			return nil, err
+		}
+		keywords = extracted
+	}
+
+	terms := make(map[string]float64, len(keywords))
+	for _, keyword := range keywords {
+		terms[keyword.Word] = keyword.Weight
+	}
+	return terms, nil
+}
+
+// buildTopic 将聚类结果转换为话题
+func buildTopic(scenarioID uint64, cluster trending.Cluster, burstScores map[string]float64, sentiments map[uint64]float64, start, end time.Time) *model.OpinionTopic {
+	score := float64(len(cluster.Members))
+	keywords := make(model.TermList, 0, len(cluster.Terms))
+	labels := make([]string, 0, topicLabelTerms)
+	for _, term := range cluster.Terms {
+		keywords = append(keywords, model.WeightedTerm{Word: term.Word, Weight: term.Weight})
+		if len(labels) < topicLabelTerms {
+			labels = append(labels, term.Word)
+		}
+		score += burstScores[term.Word]
+	}
+
+	var sentiment float64
+	for _, id := range cluster.Members {
+		sentiment += sentiments[id]
+	}
+
+	representatives := cluster.Members
+	if len(representatives) > topicRepresentatives {
+		representatives = representatives[:topicRepresentatives]
+	}
+
+	return &model.OpinionTopic{
+		ScenarioID:        scenarioID,
+		Label:             strings.Join(labels, " "),
+		Keywords:          keywords,
+		Size:              len(cluster.Members),
+		Score:             math.Round(score*10000) / 10000,
+		AvgSentiment:      math.Round(sentiment/float64(len(cluster.Members))*10000) / 10000,
+		RepresentativeIDs: model.IDList(representatives),
+		WindowStart:       start,
+		WindowEnd:         end,
+	}
+}
+
+// sortTopics 按热度得分降序排列话题
+func sortTopics(topics []*model.OpinionTopic) {
+	sort.SliceStable(topics, func(i, j int) bool {
+		return topics[i].Score > topics[j].Score
+	})
+}
+
+// GetTrendingTerms 获取场景热词
+func (s *trendingService) GetTrendingTerms(scenarioID uint64, limit int) ([]*model.TrendingTerm, error) {
+	if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, errors.New("场景不存在")
+	}
+	return s.trendingRepo.GetTerms(scenarioID, limit)
+}
+
+// GetTopics 获取场景话题及其代表性舆情
+func (s *trendingService) GetTopics(scenarioID uint64, limit int) ([]*model.OpinionTopic, error) {
+	if _, err := s.scenarioRepo.GetByID(scenarioID); err != nil {
+		return nil, errors.New("场景不存在")
+	}
+
+	topics, err := s.trendingRepo.GetTopics(scenarioID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0)
+	for _, topic := range topics {
+		ids = append(ids, topic.RepresentativeIDs...)
+	}
+	opinions, err := s.opinionRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint64]*model.Opinion, len(opinions))
+	for _, opinion := range opinions {
+		byID[opinion.ID] = opinion
+	}
+	for _, topic := range topics {
+		topic.Opinions = make([]*model.Opinion, 0, len(topic.RepresentativeIDs))
+		for _, id := range topic.RepresentativeIDs {
+			if opinion, ok := byID[id]; ok {
+				topic.Opinions = append(topic.Opinions, opinion)
+			}
+		}
+	}
+	return topics, nil
+}